@@ -3,26 +3,41 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
+	"net"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/caboose-desktop/internal/core/config"
+	"github.com/caboose-desktop/internal/core/crash"
 	"github.com/caboose-desktop/internal/core/database"
+	"github.com/caboose-desktop/internal/core/debugger"
+	"github.com/caboose-desktop/internal/core/events"
 	"github.com/caboose-desktop/internal/core/exceptions"
 	"github.com/caboose-desktop/internal/core/git"
+	corelog "github.com/caboose-desktop/internal/core/log"
 	"github.com/caboose-desktop/internal/core/metrics"
 	"github.com/caboose-desktop/internal/core/process"
+	"github.com/caboose-desktop/internal/core/redis"
 	"github.com/caboose-desktop/internal/core/security"
 	"github.com/caboose-desktop/internal/core/ssh"
 	"github.com/caboose-desktop/internal/core/workers"
 	"github.com/caboose-desktop/internal/models"
 	"github.com/caboose-desktop/internal/plugin"
-	_ "github.com/caboose-desktop/internal/plugins/rails" // Auto-register Rails plugin
+	"github.com/caboose-desktop/internal/plugins/generic"
+	"github.com/caboose-desktop/internal/plugins/rails" // Also auto-registers the Rails plugin
+	"github.com/google/go-dap"
 	"github.com/google/uuid"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -40,8 +55,32 @@ type ProcessInfo struct {
 	CPU         float64   `json:"cpu"`
 	Memory      int64     `json:"memory"`
 	AutoRestart bool      `json:"autoRestart"`
+	AutoStart   bool      `json:"autoStart"`
 	Color       string    `json:"color,omitempty"`
 	StartedAt   time.Time `json:"startedAt,omitempty"`
+
+	// Environment is the process's environment with secret-looking values masked
+	Environment map[string]string `json:"environment,omitempty"`
+}
+
+// ProcessSummary aggregates the state of all managed processes
+type ProcessSummary struct {
+	Total            int            `json:"total"`
+	ByStatus         map[string]int `json:"byStatus"`
+	TotalCPU         float64        `json:"totalCpu"`
+	TotalMemory      int64          `json:"totalMemory"`
+	AutoRestartCount int            `json:"autoRestartCount"`
+	OldestProcess    string         `json:"oldestProcess,omitempty"`
+	OldestUptime     string         `json:"oldestUptime,omitempty"`
+	OldestStartedAt  *time.Time     `json:"oldestStartedAt,omitempty"`
+}
+
+// ProcessBatchResult reports the outcome of starting or stopping several processes
+type ProcessBatchResult struct {
+	Action    string            `json:"action"` // "start", "stop", "restart" or "auto-start"
+	Succeeded []string          `json:"succeeded"`
+	Failed    map[string]string `json:"failed"`  // Process name -> error
+	Skipped   []string          `json:"skipped"` // Already in the requested state
 }
 
 // LogEntry represents a log line sent to the frontend
@@ -50,7 +89,12 @@ type LogEntry struct {
 	Process   string    `json:"process"`
 	Content   string    `json:"content"`
 	Level     string    `json:"level"`
+	Stream    string    `json:"stream,omitempty"` // stdout, stderr or pty
 	Timestamp time.Time `json:"timestamp"`
+
+	// Matches are [start,end) byte offsets of search hits in Content, set when
+	// GetLogs is asked to highlight
+	Matches [][2]int `json:"matches,omitempty"`
 }
 
 // App struct holds the application state and Wails runtime context
@@ -58,24 +102,57 @@ type App struct {
 	ctx              context.Context
 	processManager   *process.Manager
 	databaseManager  *database.Manager
+	namedDatabases   map[string]*database.Manager // Additional connections, keyed by name
+	namedDbMu        sync.Mutex
 	exceptionTracker *exceptions.Tracker
 	metricsTracker   *metrics.Tracker
+	metricsAlerts    *metrics.Alerter // Threshold rules from [[metrics.alerts]]
+	eventJournal     *events.Journal
+	consoleOutput    *events.OutputBatcher // Coalesces console:output
+	logThrottle      *events.Throttle      // Caps process:log events per process
+	metricsHistory   *metrics.History // nil when history persistence is off
+	historyMu        sync.Mutex
+	sqlSources       map[string]context.CancelFunc // Running framework SQL sources, keyed by type
+	sqlSourceMu      sync.Mutex
 	workerPool       *workers.Pool
+	introspections   map[string]context.CancelFunc // Multi-table introspection batches in flight, keyed by the caller's token
+	introspectMu     sync.Mutex
 	rateLimiter      *security.RateLimiter
+	queryGuard       atomic.Pointer[security.QueryGuard] // Destructive keywords from the config
+	envRedactor      atomic.Pointer[security.Redactor]   // Env var patterns masked in logs and process details
+	logRules         atomic.Pointer[corelog.LevelRules]  // Log level reclassification rules from the config
+	logForwarder     atomic.Pointer[corelog.Forwarder]   // Serves log lines on [log] forward_socket
+	logForwardSpec   string                              // forward_socket the forwarder was started for
+	logForwardMu     sync.Mutex
+	testRunning      atomic.Bool                         // A RunTests run is in progress
+	installRunning   atomic.Bool                         // An InstallDependencies run is in progress
+	testWatch        plugin.TestStream                   // Parses the StartTestWatch process's output
+	testWatchMu      sync.Mutex
+	// Processes suspended for memory pressure, resumed when it eases
+	autoSuspended    map[string]bool
+	suspendMu        sync.Mutex
+	suspendPolicy    atomic.Pointer[config.SuspendConfig] // [suspend] from the config, read by watchMemoryPressure
+	autoStartResult  atomic.Pointer[ProcessBatchResult]   // Outcome of the project's auto-start, once finished
 	sshManager       *ssh.Manager
 	gitManager       *git.Manager
+	debugSessions    *debugger.SessionManager
+	configSaver      *config.Saver // Coalesces .caboose.toml writes
+	redisClient      *redis.Client // nil until ConnectRedis
+	redisMu          sync.Mutex
 	config           *config.Config
 	projectDir       string
 	logMu            sync.RWMutex
 	logs             []LogEntry
 	logBuffer        int
 	logIdCounter     int64
+	logClocks        map[string]logClock // Keyed by process, guarded by logMu
 
 	// Plugin Architecture
 	pluginRegistry   *plugin.Registry
 	pluginDetector   *plugin.Detector
 	currentPlugin    plugin.FrameworkPlugin
 	frameworkName    string
+	genericParser    *generic.Parser
 }
 
 // NewApp creates a new App application struct
@@ -86,15 +163,72 @@ func NewApp() *App {
 
 	return &App{
 		logs:             make([]LogEntry, 0),
+		logClocks:        make(map[string]logClock),
 		logBuffer:        10000,
 		databaseManager:  database.NewManager(),
+		namedDatabases:   make(map[string]*database.Manager),
+		sqlSources:       make(map[string]context.CancelFunc),
+		introspections:   make(map[string]context.CancelFunc),
+		autoSuspended:    make(map[string]bool),
+		debugSessions:    debugger.NewSessionManager(),
+		configSaver:      config.NewSaver(500 * time.Millisecond),
 		exceptionTracker: exceptions.NewTracker(),
 		metricsTracker:   metrics.NewTracker(),
+		metricsAlerts:    metrics.NewAlerter(),
+		eventJournal:     events.NewJournal(eventJournalSize),
 		workerPool:       workers.NewPool(0), // 0 = use CPU count
 		rateLimiter:      security.NewRateLimiter(),
 		pluginRegistry:   registry,
 		pluginDetector:   detector,
+		genericParser:    generic.NewParser(),
+	}
+}
+
+// eventJournalSize is how many recent frontend events are kept for replay
+const eventJournalSize = 1000
+
+const (
+	// consoleFlushInterval is how long console output is coalesced before it's emitted
+	consoleFlushInterval = 50 * time.Millisecond
+
+	// maxConsoleLinesPerSecond caps console:output per process; the rest is dropped
+	maxConsoleLinesPerSecond = 2000
+
+	// maxLogEventsPerSecond caps process:log events per process. Lines over the cap
+	// are still kept for GetLogs, they just aren't streamed.
+	maxLogEventsPerSecond = 1000
+)
+
+// Streamed output is left out of the event journal: it would crowd out the
+// state changes the journal is for, and logs can be fetched with GetLogs
+var unjournaledEvents = map[string]bool{
+	"process:log":    true,
+	"console:output": true,
+	"ssh:output":     true,
+	"debug:output":   true,
+	"test:result":    true, // One per example; a red run would flood the journal
+}
+
+// emit sends an event to the frontend and records it in the event journal
+func (a *App) emit(eventType string, data interface{}) {
+	if !unjournaledEvents[eventType] {
+		a.eventJournal.Record(eventType, data)
 	}
+	runtime.EventsEmit(a.ctx, eventType, data)
+}
+
+// emitRedacted sends an event to the frontend but records journaled in the
+// event journal instead of data, for events that carry user input
+func (a *App) emitRedacted(eventType string, data, journaled interface{}) {
+	a.eventJournal.Record(eventType, journaled)
+	runtime.EventsEmit(a.ctx, eventType, data)
+}
+
+// GetRecentEvents returns journaled events emitted after since, oldest first,
+// optionally limited to the given types. A frontend that mounts late calls it
+// to catch up on state changes it missed.
+func (a *App) GetRecentEvents(since time.Time, types []string) []events.Event {
+	return a.eventJournal.Since(since, types)
 }
 
 // startup is called when the app starts. The context is saved
@@ -102,31 +236,55 @@ func NewApp() *App {
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 
+	// A panic in a background goroutine is logged and surfaced instead of
+	// taking the app down
+	crash.SetHandler(func(report crash.Report) {
+		a.emit("app:internal-error", report)
+	})
+
+	// Emit console output for interactive consoles in batches, so a flood
+	// (e.g. a verbose db:seed) can't swamp the frontend
+	a.consoleOutput = events.NewOutputBatcher(consoleFlushInterval, maxConsoleLinesPerSecond, func(name, data string) {
+		a.emit("console:output", map[string]interface{}{
+			"process": name,
+			"content": data,
+		})
+	})
+
 	// Initialize process manager
 	a.processManager = process.NewManager()
+	a.wireProcessManager()
 
-	// Set up event callbacks
-	a.processManager.OnStatusChange = func(name string, status models.ProcessStatus) {
-		runtime.EventsEmit(a.ctx, "process:status", map[string]interface{}{
-			"name":   name,
-			"status": string(status),
+	a.logThrottle = events.NewThrottle(maxLogEventsPerSecond, a.emitLogsSuppressed)
+
+	// Surface database connection loss/recovery from the health check
+	a.databaseManager.OnConnectionLost = func(err error) {
+		log.Printf("[ERROR] Database connection lost: %v", err)
+		a.emit("database:connection-lost", map[string]interface{}{
+			"error": security.SanitizeError(err, false).Error(),
 		})
 	}
-
-	a.processManager.OnLog = func(name string, line string) {
-		a.addLog(name, line, "info")
+	a.databaseManager.OnReconnected = func() {
+		log.Printf("[Database] Connection re-established")
+		a.emit("database:reconnected", a.databaseManager.GetStatus())
 	}
-
-	a.processManager.OnConsoleOutput = func(name string, data string) {
-		// Emit console output event for interactive consoles
-		runtime.EventsEmit(a.ctx, "console:output", map[string]interface{}{
-			"process": name,
-			"content": data,
+	a.debugSessions.OnEvent = func(event string, data map[string]interface{}) {
+		a.emit(event, data)
+	}
+	a.configSaver.OnError = func(path string, err error) {
+		log.Printf("[ERROR] Failed to save config %s: %v", path, err)
+		a.emit("config:save-failed", map[string]interface{}{
+			"path":  path,
+			"error": err.Error(),
 		})
 	}
 
 	// Try to load project config from current directory or detect project
-	a.loadProjectConfig()
+	if cwd, err := os.Getwd(); err != nil {
+		log.Printf("[ERROR] Failed to determine working directory: %v", err)
+	} else {
+		a.loadProjectConfig(cwd)
+	}
 
 	// Initialize SSH manager with config (after config is loaded)
 	if a.config != nil {
@@ -134,20 +292,21 @@ func (a *App) startup(ctx context.Context) {
 
 		// Set up SSH event callbacks
 		a.sshManager.OnOutput = func(sessionID, data string) {
-			runtime.EventsEmit(a.ctx, "ssh:output", map[string]interface{}{
+			a.emit("ssh:output", map[string]interface{}{
 				"sessionId": sessionID,
 				"content":   data,
 			})
 		}
 
-		a.sshManager.OnDisconnect = func(sessionID string) {
-			runtime.EventsEmit(a.ctx, "ssh:disconnect", map[string]interface{}{
+		a.sshManager.OnDisconnect = func(sessionID, reason string) {
+			a.emit("ssh:disconnect", map[string]interface{}{
 				"sessionId": sessionID,
+				"reason":    reason,
 			})
 		}
 
 		a.sshManager.OnHealthUpdate = func(sessionID string, health models.SSHHealth) {
-			runtime.EventsEmit(a.ctx, "ssh:health", health)
+			a.emit("ssh:health", health)
 		}
 	}
 
@@ -155,6 +314,11 @@ func (a *App) startup(ctx context.Context) {
 	if a.projectDir != "" {
 		a.gitManager = git.NewManager(a.projectDir)
 	}
+	if version, err := git.CheckGitAvailable(); err != nil {
+		log.Printf("[Git] %v; git features are disabled", err)
+	} else {
+		log.Printf("[Git] Using git %s", version)
+	}
 
 	// Start metrics collection ticker
 	go func() {
@@ -162,47 +326,84 @@ func (a *App) startup(ctx context.Context) {
 		defer ticker.Stop()
 		for range ticker.C {
 			if a.metricsTracker != nil {
-				a.metricsTracker.RecordTimeSeriesPoint()
+				point := a.metricsTracker.RecordTimeSeriesPoint()
+				a.evaluateMetricAlerts(point)
+
+				a.historyMu.Lock()
+				if a.metricsHistory != nil {
+					if err := a.metricsHistory.Add(point); err != nil {
+						log.Printf("[ERROR] Failed to save metrics history: %v", err)
+					}
+				}
+				a.historyMu.Unlock()
 			}
 		}
 	}()
+
+	go a.watchMemoryPressure()
 }
 
 // shutdown is called when the app is closing
 func (a *App) shutdown(ctx context.Context) {
+	if err := a.configSaver.Flush(); err != nil {
+		log.Printf("[ERROR] Failed to save config: %v", err)
+	}
+	// Detach before the debugged processes are stopped under the sessions
+	a.debugSessions.CloseAll()
 	if a.processManager != nil {
 		a.processManager.Shutdown()
 	}
 	if a.databaseManager != nil {
 		a.databaseManager.Disconnect()
 	}
+	a.namedDbMu.Lock()
+	for _, manager := range a.namedDatabases {
+		manager.Disconnect()
+	}
+	a.namedDbMu.Unlock()
 	if a.sshManager != nil {
 		a.sshManager.Shutdown()
 	}
+	a.DisconnectRedis()
+	a.stopSQLSources()
+	a.stopLogForwarding()
+	a.cancelAllIntrospection()
+	a.closeMetricsHistory()
 	if a.workerPool != nil {
 		// Give workers 5 seconds to finish
 		a.workerPool.CloseWithTimeout(5 * time.Second)
 	}
 }
 
-// loadProjectConfig loads configuration from the project directory
-func (a *App) loadProjectConfig() error {
-	// Try current working directory first
-	cwd, err := os.Getwd()
-	if err != nil {
-		return err
+// loadProjectConfig makes dir the project directory and loads its
+// configuration
+func (a *App) loadProjectConfig(dir string) error {
+	// Changes still waiting belong to the previous project's file
+	if err := a.configSaver.Flush(); err != nil {
+		log.Printf("[ERROR] Failed to save config: %v", err)
 	}
 
-	a.projectDir = cwd
-	cfg, err := config.Load(cwd)
+	a.projectDir = dir
+	a.processManager.SetProjectDir(dir)
+	cfg, err := config.Load(dir)
 	if err != nil {
 		return err
 	}
 
 	a.config = cfg
+	a.applyAutoSaveConfig()
 
 	// Detect framework using plugin system
 	a.detectFramework()
+	a.applyDatabaseConfig()
+	a.applyLogConfig()
+	a.applySecurityConfig()
+	a.applyMetricsConfig()
+	a.applySuspendConfig()
+	a.openMetricsHistory()
+	a.restartSQLSources()
+
+	a.autoStartResult.Store(nil)
 
 	// If no processes configured, try to detect and add defaults
 	if len(cfg.Processes) == 0 {
@@ -213,11 +414,103 @@ func (a *App) loadProjectConfig() error {
 			procConfig.Name = name
 			a.processManager.AddProcess(procConfig)
 		}
+		go a.autoStartProcesses(a.processManager)
 	}
 
 	return nil
 }
 
+// openMetricsHistory loads the project's persisted metrics history and seeds
+// the chart with it. Any history open for a previous project is flushed first.
+func (a *App) openMetricsHistory() {
+	a.closeMetricsHistory()
+
+	if a.config == nil || !a.config.Metrics.PersistHistory || a.projectDir == "" {
+		return
+	}
+
+	path, err := config.MetricsHistoryPath(a.projectDir)
+	if err != nil {
+		log.Printf("[ERROR] Metrics history unavailable: %v", err)
+		return
+	}
+
+	retention := a.config.Metrics.RetentionDays
+	if retention <= 0 {
+		retention = 7
+	}
+	downsampled := a.config.Metrics.DownsampledRetentionDays
+	if downsampled <= 0 {
+		downsampled = 30
+	}
+
+	history, err := metrics.OpenHistory(path,
+		time.Duration(retention)*24*time.Hour,
+		time.Duration(downsampled)*24*time.Hour)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load metrics history: %v", err)
+		return
+	}
+
+	a.metricsTracker.SeedTimeSeries(history.Recent(24))
+
+	a.historyMu.Lock()
+	a.metricsHistory = history
+	a.historyMu.Unlock()
+}
+
+// closeMetricsHistory writes out and detaches the open metrics history
+func (a *App) closeMetricsHistory() {
+	a.historyMu.Lock()
+	defer a.historyMu.Unlock()
+
+	if a.metricsHistory == nil {
+		return
+	}
+	if err := a.metricsHistory.Flush(); err != nil {
+		log.Printf("[ERROR] Failed to save metrics history: %v", err)
+	}
+	a.metricsHistory = nil
+}
+
+// applyDatabaseConfig pushes database settings from the config into the
+// database manager and the framework plugin
+func (a *App) applyDatabaseConfig() {
+	if a.config == nil {
+		return
+	}
+
+	threshold := a.config.Database.SlowQueryThreshold
+	if threshold > 0 {
+		a.databaseManager.SetSlowQueryThreshold(threshold)
+
+		// If plugin supports SetSlowQueryThreshold, call it
+		if p, ok := a.currentPlugin.(interface{ SetSlowQueryThreshold(float64) }); ok {
+			p.SetSlowQueryThreshold(threshold)
+		}
+	}
+
+	// If plugin supports SetN1Detection, call it
+	if p, ok := a.currentPlugin.(interface{ SetN1Detection(bool) }); ok {
+		p.SetN1Detection(a.config.Database.EnableN1Detection)
+	}
+
+	a.databaseManager.SetKeepWarmInterval(a.keepWarmInterval())
+}
+
+// keepWarmInterval returns the configured database keep-warm interval
+func (a *App) keepWarmInterval() time.Duration {
+	if a.config == nil {
+		return 0
+	}
+	return time.Duration(a.config.Database.KeepWarmInterval) * time.Second
+}
+
+// n1DetectionEnabled reports whether N+1 detection is enabled in the config
+func (a *App) n1DetectionEnabled() bool {
+	return a.config == nil || a.config.Database.EnableN1Detection
+}
+
 // detectFramework detects the framework using the plugin system
 func (a *App) detectFramework() {
 	if a.projectDir == "" {
@@ -235,12 +528,23 @@ func (a *App) detectFramework() {
 			railsPlugin.SetProjectPath(a.projectDir)
 		}
 
+		// Framework-specific exception guidance
+		var guidance []exceptions.GuidanceRule
+		if p, ok := detectedPlugin.(interface {
+			GuidanceRules() []exceptions.GuidanceRule
+		}); ok {
+			guidance = p.GuidanceRules()
+		}
+		if err := a.exceptionTracker.SetFrameworkGuidance(detectedPlugin.Name(), guidance); err != nil {
+			log.Printf("[Plugin] Invalid exception guidance from %s: %v", detectedPlugin.Name(), err)
+		}
+
 		log.Printf("[Plugin] Detected framework: %s (v%s)",
 			detectedPlugin.Name(), detectedPlugin.Version())
 
 		// Emit framework detected event to frontend
 		if a.ctx != nil {
-			runtime.EventsEmit(a.ctx, "framework:detected", map[string]interface{}{
+			a.emit("framework:detected", map[string]interface{}{
 				"name":    detectedPlugin.Name(),
 				"version": detectedPlugin.Version(),
 			})
@@ -248,6 +552,7 @@ func (a *App) detectFramework() {
 	} else {
 		log.Printf("[Plugin] No framework detected, using generic mode")
 		a.frameworkName = "generic"
+		a.exceptionTracker.SetFrameworkGuidance("", nil)
 	}
 }
 
@@ -279,6 +584,7 @@ func (a *App) addRailsProcesses() {
 			AutoRestart: true,
 			UsePTY:      true,
 			Color:       "#ef4444", // red
+			Port:        3000,
 		},
 		{
 			Name:        "sidekiq",
@@ -361,7 +667,7 @@ func (a *App) addAndSaveProcesses(processes []models.ProcessConfig) {
 	}
 
 	// Save to config file for persistence
-	if err := a.config.Save(a.projectDir); err != nil {
+	if err := a.saveConfig(); err != nil {
 		fmt.Printf("Warning: failed to save config: %v\n", err)
 	}
 }
@@ -374,6 +680,7 @@ func (a *App) GetProcesses() []ProcessInfo {
 
 	processes := a.processManager.GetAllProcesses()
 	result := make([]ProcessInfo, len(processes))
+	var pids []int
 
 	for i, p := range processes {
 		uptime := ""
@@ -390,17 +697,68 @@ func (a *App) GetProcesses() []ProcessInfo {
 			PID:         p.PID,
 			Uptime:      uptime,
 			AutoRestart: p.AutoRestart,
+			AutoStart:   p.AutoStart,
 			Color:       p.Color,
+			Port:        p.Port,
+			Environment: a.redactor().RedactEnv(p.Environment),
 		}
 
 		if p.StartedAt != nil {
 			result[i].StartedAt = *p.StartedAt
 		}
+		if p.PID > 0 {
+			pids = append(pids, p.PID)
+		}
+	}
+
+	// Best effort: usage stays zero where ps isn't available
+	if usage, err := metrics.ProcessesUsage(pids); err == nil {
+		for i := range result {
+			if u, ok := usage[result[i].PID]; ok {
+				result[i].CPU = u.CPU
+				result[i].Memory = u.Memory
+			}
+		}
 	}
 
 	return result
 }
 
+// GetProcessSummary returns process counts by status, total resource usage,
+// and the longest-running process, so the frontend doesn't re-aggregate on every poll
+func (a *App) GetProcessSummary() ProcessSummary {
+	summary := ProcessSummary{
+		ByStatus: map[string]int{
+			string(models.ProcessStatusRunning):  0,
+			string(models.ProcessStatusStarting): 0,
+			string(models.ProcessStatusStopping): 0,
+			string(models.ProcessStatusStopped):  0,
+			string(models.ProcessStatusCrashed):  0,
+			string(models.ProcessStatusSuspended): 0,
+		},
+	}
+
+	for _, p := range a.GetProcesses() {
+		summary.Total++
+		summary.ByStatus[p.Status]++
+		summary.TotalCPU += p.CPU
+		summary.TotalMemory += p.Memory
+		if p.AutoRestart {
+			summary.AutoRestartCount++
+		}
+
+		if p.Status == string(models.ProcessStatusRunning) && !p.StartedAt.IsZero() &&
+			(summary.OldestStartedAt == nil || p.StartedAt.Before(*summary.OldestStartedAt)) {
+			startedAt := p.StartedAt
+			summary.OldestStartedAt = &startedAt
+			summary.OldestProcess = p.Name
+			summary.OldestUptime = p.Uptime
+		}
+	}
+
+	return summary
+}
+
 // GetProcess returns information about a specific process
 func (a *App) GetProcess(name string) (*ProcessInfo, error) {
 	if a.processManager == nil {
@@ -424,9 +782,12 @@ func (a *App) GetProcess(name string) (*ProcessInfo, error) {
 		Status:      string(p.Status),
 		Command:     formatCommand(p.Command, p.Args),
 		PID:         p.PID,
+		Port:        p.Port,
 		Uptime:      uptime,
 		AutoRestart: p.AutoRestart,
+		AutoStart:   p.AutoStart,
 		Color:       p.Color,
+		Environment: a.redactor().RedactEnv(p.Environment),
 	}
 
 	if p.StartedAt != nil {
@@ -444,43 +805,55 @@ func (a *App) StartProcess(name string) error {
 
 	err := a.processManager.Start(name)
 	if err != nil {
-		runtime.EventsEmit(a.ctx, "process:error", map[string]interface{}{
-			"name":  name,
-			"error": err.Error(),
-		})
+		a.emitStartError(name, err)
 		return err
 	}
 
 	return nil
 }
 
-// StopProcess stops a process by name
-func (a *App) StopProcess(name string) error {
-	if a.processManager == nil {
-		return fmt.Errorf("process manager not initialized")
+// emitStartError tells the frontend a process failed to start
+func (a *App) emitStartError(name string, err error) {
+	// Lets the frontend offer to stop whatever holds the port and retry
+	var conflict *process.PortInUseError
+	if errors.As(err, &conflict) {
+		a.emit("process:port-conflict", conflict)
 	}
+	a.emit("process:error", map[string]interface{}{
+		"name":  name,
+		"error": err.Error(),
+	})
+}
 
-	err := a.processManager.Stop(name)
+// KillPortOwner stops whatever process listens on a port, so a process that
+// failed its port check can be started again. Killing an unknown process
+// needs explicit confirmation.
+func (a *App) KillPortOwner(port int, confirmed bool) error {
+	if port <= 0 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	if !confirmed {
+		return fmt.Errorf("stopping the process on port %d requires confirmation", port)
+	}
+
+	pid, err := process.KillPortOwner(port)
 	if err != nil {
-		runtime.EventsEmit(a.ctx, "process:error", map[string]interface{}{
-			"name":  name,
-			"error": err.Error(),
-		})
 		return err
 	}
 
+	log.Printf("[AUDIT] KillPortOwner: port=%d, pid=%d", port, pid)
 	return nil
 }
 
-// RestartProcess restarts a process by name
-func (a *App) RestartProcess(name string) error {
+// StopProcess stops a process by name
+func (a *App) StopProcess(name string) error {
 	if a.processManager == nil {
 		return fmt.Errorf("process manager not initialized")
 	}
 
-	err := a.processManager.Restart(name)
+	err := a.processManager.Stop(name)
 	if err != nil {
-		runtime.EventsEmit(a.ctx, "process:error", map[string]interface{}{
+		a.emit("process:error", map[string]interface{}{
 			"name":  name,
 			"error": err.Error(),
 		})
@@ -490,944 +863,4039 @@ func (a *App) RestartProcess(name string) error {
 	return nil
 }
 
-// StartAllProcesses starts all configured processes
-func (a *App) StartAllProcesses() error {
+// CancelProcessStart kills a process that is still waiting for its health
+// check, e.g. one stuck on a prompt
+func (a *App) CancelProcessStart(name string) error {
 	if a.processManager == nil {
 		return fmt.Errorf("process manager not initialized")
 	}
 
-	processes := a.processManager.GetAllProcesses()
-	var lastErr error
-
-	for _, p := range processes {
-		if p.Status != models.ProcessStatusRunning {
-			if err := a.processManager.Start(p.Name); err != nil {
-				lastErr = err
-			}
-		}
-	}
+	log.Printf("[AUDIT] CancelProcessStart: name=%s", name)
 
-	return lastErr
+	return a.processManager.CancelStart(name)
 }
 
-// StopAllProcesses stops all running processes
-func (a *App) StopAllProcesses() error {
+// SuspendProcess pauses a running process with SIGSTOP, keeping its state
+// but freeing the CPU until ResumeProcess
+func (a *App) SuspendProcess(name string) error {
 	if a.processManager == nil {
 		return fmt.Errorf("process manager not initialized")
 	}
 
-	processes := a.processManager.GetAllProcesses()
-	var lastErr error
-
-	for _, p := range processes {
-		if p.Status == models.ProcessStatusRunning {
-			if err := a.processManager.Stop(p.Name); err != nil {
-				lastErr = err
-			}
-		}
+	if err := a.processManager.Suspend(name); err != nil {
+		return err
 	}
 
-	return lastErr
+	// A manual suspend isn't undone when memory pressure eases
+	a.suspendMu.Lock()
+	delete(a.autoSuspended, name)
+	a.suspendMu.Unlock()
+
+	return nil
 }
 
-// AddProcess adds a new process configuration and saves to config file
-func (a *App) AddProcess(config map[string]interface{}) error {
+// ResumeProcess continues a suspended process
+func (a *App) ResumeProcess(name string) error {
 	if a.processManager == nil {
 		return fmt.Errorf("process manager not initialized")
 	}
 
-	// Rate limit process creation
-	if !a.rateLimiter.Allow("process") {
-		return fmt.Errorf("rate limit exceeded: too many process operations")
+	if err := a.processManager.Resume(name); err != nil {
+		return err
 	}
 
-	name, _ := config["name"].(string)
-	command, _ := config["command"].(string)
-	argsRaw, _ := config["args"].([]interface{})
-	workingDir, _ := config["workingDir"].(string)
-	autoRestart, _ := config["autoRestart"].(bool)
-	usePTY, _ := config["usePty"].(bool)
-	color, _ := config["color"].(string)
+	a.suspendMu.Lock()
+	delete(a.autoSuspended, name)
+	a.suspendMu.Unlock()
 
-	// SECURITY: Validate command is in whitelist
-	if err := security.ValidateCommand(command); err != nil {
-		log.Printf("[SECURITY] Blocked command: %s", command)
-		return fmt.Errorf("security error: %w", err)
-	}
+	return nil
+}
 
-	args := make([]string, len(argsRaw))
-	for i, arg := range argsRaw {
-		args[i], _ = arg.(string)
-	}
+// memoryPressureInterval is how often system memory is checked for the
+// auto-suspend policy
+const memoryPressureInterval = 15 * time.Second
+
+// memoryResumeMargin is how far below the threshold memory use must fall
+// before auto-suspended processes are resumed, so they don't flap
+const memoryResumeMargin = 5.0
+
+// watchMemoryPressure applies the [suspend] policy: when system memory use
+// goes over the threshold the listed running processes are suspended, and
+// once it drops back below the threshold minus memoryResumeMargin the ones
+// suspended this way are resumed
+func (a *App) watchMemoryPressure() {
+	ticker := time.NewTicker(memoryPressureInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		policy := a.suspendPolicy.Load()
+		if policy == nil || a.processManager == nil || policy.MemoryThreshold <= 0 {
+			continue
+		}
+		threshold := policy.MemoryThreshold
 
-	// SECURITY: Validate arguments don't contain shell metacharacters
-	if err := security.ValidateArguments(args); err != nil {
-		log.Printf("[SECURITY] Blocked dangerous arguments: %v", args)
-		return fmt.Errorf("security error: %w", err)
-	}
+		used, err := a.metricsTracker.SystemMemory()
+		if err != nil {
+			continue
+		}
 
-	if workingDir == "" {
-		workingDir = a.projectDir
-	}
+		a.suspendMu.Lock()
+		switch {
+		case used >= threshold:
+			for _, name := range policy.Processes {
+				p, ok := a.processManager.GetProcess(name)
+				if !ok || p.Status != models.ProcessStatusRunning {
+					continue
+				}
+				if err := a.processManager.Suspend(name); err != nil {
+					log.Printf("Warning: failed to suspend %s: %v", name, err)
+					continue
+				}
+				a.autoSuspended[name] = true
+				log.Printf("Warning: memory at %.0f%%, suspended %s", used, name)
+				a.emit("process:auto-suspended", map[string]interface{}{
+					"name":   name,
+					"memory": used,
+				})
+			}
 
-	// SECURITY: Validate working directory path
-	validatedDir, err := security.ValidateProjectPath(workingDir)
-	if err != nil {
-		log.Printf("[SECURITY] Invalid working directory: %s", workingDir)
-		return fmt.Errorf("invalid working directory: %w", err)
+		case used < threshold-memoryResumeMargin:
+			for name := range a.autoSuspended {
+				if err := a.processManager.Resume(name); err != nil {
+					log.Printf("Warning: failed to resume %s: %v", name, err)
+				}
+				delete(a.autoSuspended, name)
+			}
+		}
+		a.suspendMu.Unlock()
 	}
+}
 
-	procConfig := models.ProcessConfig{
-		Name:        name,
-		Command:     command,
-		Args:        args,
-		WorkingDir:  validatedDir,
-		AutoRestart: autoRestart,
-		UsePTY:      usePTY,
-		Color:       color,
+// wireProcessManager connects the process manager's callbacks to the
+// frontend events and the log pipeline. A project switch replaces the
+// manager, so every callback is set here, for both.
+func (a *App) wireProcessManager() {
+	a.processManager.OnStatusChange = func(name string, status models.ProcessStatus) {
+		a.emit("process:status", map[string]interface{}{
+			"name":   name,
+			"status": string(status),
+		})
 	}
-
-	// Log process creation for audit
-	log.Printf("[AUDIT] AddProcess: name=%s, command=%s, args=%v", name, command, args)
-
-	// Add to process manager
-	if err := a.processManager.AddProcess(procConfig); err != nil {
-		return err
+	a.processManager.OnLog = func(name string, line string, stream models.LogStream) {
+		a.ingestLog(name, line, stream)
 	}
-
-	// Save to config file for persistence
-	if a.config != nil {
-		if a.config.Processes == nil {
-			a.config.Processes = make(map[string]models.ProcessConfig)
-		}
-		a.config.Processes[name] = procConfig
-		if err := a.config.Save(a.projectDir); err != nil {
-			// Log error but don't fail - process is still added to manager
-			log.Printf("Warning: failed to save config: %v", err)
-		}
+	a.processManager.OnConsoleOutput = a.consoleOutput.Write
+	a.processManager.OnStartTimeout = a.emitStartTimeout
+	a.processManager.OnLogFlood = func(stats models.ProcessLogStats) {
+		log.Printf("Warning: process %s is flooding output: %.0f lines/s, %.0f errors/s",
+			stats.Name, stats.LinesPerSecond, stats.ErrorsPerSecond)
+		a.emit("process:log-flood", stats)
+	}
+	a.processManager.OnPortDetected = a.emitPortDetected
+	a.processManager.OnStartError = func(name string, err error) {
+		log.Printf("[ERROR] Auto-restart of %s failed: %v", name, err)
+		a.emitStartError(name, err)
 	}
+}
 
-	return nil
+// emitStartTimeout tells the frontend a process was killed for not becoming ready
+func (a *App) emitStartTimeout(name string, timeout time.Duration) {
+	log.Printf("[ERROR] Process %s not ready after %s, killed", name, timeout)
+	a.emit("process:start-timeout", map[string]interface{}{
+		"name":    name,
+		"timeout": int(timeout.Seconds()),
+	})
 }
 
-// RemoveProcess removes a process configuration and saves to config file
-func (a *App) RemoveProcess(name string) error {
+// emitPortDetected tells the frontend the port a process reported binding to
+func (a *App) emitPortDetected(name string, port int) {
+	a.emit("process:port-detected", map[string]interface{}{
+		"name": name,
+		"port": port,
+	})
+}
+
+// RestartProcess restarts a process by name
+func (a *App) RestartProcess(name string) error {
 	if a.processManager == nil {
 		return fmt.Errorf("process manager not initialized")
 	}
 
-	// Stop the process first if running
-	p, exists := a.processManager.GetProcess(name)
-	if exists && p.Status == models.ProcessStatusRunning {
-		a.processManager.Stop(name)
-	}
-
-	// Remove from process manager
-	if err := a.processManager.RemoveProcess(name); err != nil {
+	err := a.processManager.Restart(name)
+	if err != nil {
+		a.emit("process:error", map[string]interface{}{
+			"name":  name,
+			"error": err.Error(),
+		})
 		return err
 	}
 
-	// Remove from config file for persistence
-	if a.config != nil && a.config.Processes != nil {
-		delete(a.config.Processes, name)
-		if err := a.config.Save(a.projectDir); err != nil {
-			// Log error but don't fail - process is still removed from manager
-			fmt.Printf("Warning: failed to save config: %v\n", err)
-		}
-	}
-
 	return nil
 }
 
-// WriteToPTY writes input to a process PTY (for interactive terminals)
-func (a *App) WriteToPTY(name string, input string) error {
+// GetProcessLogStats returns each process's output and error line rates over
+// the last 10 seconds
+func (a *App) GetProcessLogStats() ([]models.ProcessLogStats, error) {
 	if a.processManager == nil {
-		return fmt.Errorf("process manager not initialized")
+		return nil, fmt.Errorf("process manager not initialized")
 	}
 
-	// Rate limit PTY writes
-	if !a.rateLimiter.Allow("pty") {
-		log.Printf("[SECURITY] Rate limit exceeded for PTY write")
-		return fmt.Errorf("rate limit exceeded: too many PTY write requests")
-	}
-
-	// SECURITY: Sanitize PTY input
-	sanitized, err := security.SanitizePTYInput(input)
-	if err != nil {
-		log.Printf("[SECURITY] Blocked dangerous PTY input: %v", err)
-		return fmt.Errorf("security error: %w", err)
-	}
-
-	// Log PTY writes for audit
-	log.Printf("[AUDIT] WriteToPTY: process=%s, input=%q", name, sanitized[:min(50, len(sanitized))])
-
-	return a.processManager.WriteToPTY(name, []byte(sanitized))
+	return a.processManager.GetLogStats(), nil
 }
 
-// ResizePTY resizes a process PTY window
-func (a *App) ResizePTY(name string, rows, cols int) error {
+// GetProcessHistory returns the start/stop/crash/restart history of a process
+func (a *App) GetProcessHistory(name string) ([]models.ProcessEvent, error) {
 	if a.processManager == nil {
-		return fmt.Errorf("process manager not initialized")
+		return nil, fmt.Errorf("process manager not initialized")
 	}
 
-	return a.processManager.ResizePTY(name, uint16(rows), uint16(cols))
+	return a.processManager.GetHistory(name)
 }
 
-// addLog adds a log entry and emits event to frontend
-func (a *App) addLog(processName, content, level string) {
-	a.logMu.Lock()
-	defer a.logMu.Unlock()
+// processBatchConcurrency caps how many processes a batch starts or stops at once
+const processBatchConcurrency = 4
 
-	a.logIdCounter++
-	entry := LogEntry{
-		ID:        fmt.Sprintf("%d", a.logIdCounter),
-		Process:   processName,
-		Content:   content,
-		Level:     level,
-		Timestamp: time.Now(),
+// StartAllProcesses starts all configured processes that aren't running
+func (a *App) StartAllProcesses() (*ProcessBatchResult, error) {
+	if a.processManager == nil {
+		return nil, fmt.Errorf("process manager not initialized")
 	}
 
-	a.logs = append(a.logs, entry)
+	return a.StartProcesses(a.processNames())
+}
 
-	// Trim logs if buffer exceeded
-	if len(a.logs) > a.logBuffer {
-		a.logs = a.logs[len(a.logs)-a.logBuffer:]
+// StopAllProcesses stops all running processes
+func (a *App) StopAllProcesses() (*ProcessBatchResult, error) {
+	if a.processManager == nil {
+		return nil, fmt.Errorf("process manager not initialized")
 	}
 
-	// Emit log event to frontend
-	runtime.EventsEmit(a.ctx, "process:log", entry)
+	return a.StopProcesses(a.processNames())
 }
 
-// GetLogs returns logs with optional filtering
-func (a *App) GetLogs(filter map[string]interface{}) []LogEntry {
-	a.logMu.RLock()
-	defer a.logMu.RUnlock()
+// StartProcesses starts the named processes concurrently, emitting
+// process:batch-progress as each one finishes
+func (a *App) StartProcesses(names []string) (*ProcessBatchResult, error) {
+	return a.runProcessBatch("start", names, a.processManager.Start, func(p *models.Process) bool {
+		return p.Status == models.ProcessStatusRunning || p.Status == models.ProcessStatusSuspended
+	})
+}
 
-	processFilter, _ := filter["process"].(string)
-	levelFilter, _ := filter["level"].(string)
-	limitRaw, _ := filter["limit"].(float64)
-	limit := int(limitRaw)
-	if limit == 0 {
-		limit = 100
+// StopProcesses stops the named processes concurrently, emitting
+// process:batch-progress as each one finishes. A process that is still
+// starting has its start cancelled.
+func (a *App) StopProcesses(names []string) (*ProcessBatchResult, error) {
+	manager := a.processManager
+	stop := func(name string) error {
+		if p, ok := manager.GetProcess(name); ok && p.Status == models.ProcessStatusStarting {
+			// Falls through to Stop if it became ready meanwhile
+			if err := manager.CancelStart(name); err == nil {
+				return nil
+			}
+		}
+		return manager.Stop(name)
 	}
+	return a.runProcessBatch("stop", names, stop, func(p *models.Process) bool {
+		return p.Status == models.ProcessStatusStopped || p.Status == models.ProcessStatusCrashed ||
+			p.Status == models.ProcessStatusStartTimeout
+	})
+}
 
-	result := make([]LogEntry, 0, limit)
-
-	// Iterate backwards for most recent logs
-	for i := len(a.logs) - 1; i >= 0 && len(result) < limit; i-- {
-		log := a.logs[i]
+// RestartAllProcesses stops every running process, waits for all of them to
+// stop, then starts every process in dependency order: a process starts once
+// the processes in its DependsOn are ready. A process whose stop fails is not
+// restarted, and neither is anything depending on it. Progress is emitted as
+// process:batch-progress for both phases.
+func (a *App) RestartAllProcesses() (*ProcessBatchResult, error) {
+	if a.processManager == nil {
+		return nil, fmt.Errorf("process manager not initialized")
+	}
 
-		if processFilter != "" && log.Process != processFilter {
-			continue
+	// The whole restart counts as one process operation
+	if !a.rateLimiter.Allow("process") {
+		return nil, fmt.Errorf("rate limit exceeded: too many process operations")
+	}
+
+	result := &ProcessBatchResult{
+		Action:    "restart",
+		Succeeded: []string{},
+		Failed:    make(map[string]string),
+		Skipped:   []string{},
+	}
+
+	// A project switch replaces the manager; finish against this one
+	manager := a.processManager
+	processes := manager.GetAllProcesses()
+	order, blocked := process.StartOrder(processes)
+	for name, reason := range blocked {
+		result.Failed[name] = reason
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, processBatchConcurrency)
+	report := func(phase, name string, completed, total int, err error) {
+		progress := map[string]interface{}{
+			"action":    "restart",
+			"phase":     phase,
+			"name":      name,
+			"success":   err == nil,
+			"completed": completed,
+			"total":     total,
 		}
-		if levelFilter != "" && log.Level != levelFilter {
-			continue
+		if err != nil {
+			result.Failed[name] = err.Error()
+			progress["error"] = err.Error()
 		}
+		a.emit("process:batch-progress", progress)
+	}
 
-		result = append(result, log)
+	// Stop phase: everything that isn't stopped yet, concurrently
+	running := make([]string, 0, len(processes))
+	for _, p := range processes {
+		if p.Status != models.ProcessStatusStopped {
+			running = append(running, p.Name)
+		}
 	}
+	stopped := 0
+	for _, name := range running {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
 
-	// Reverse to get chronological order
-	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
-		result[i], result[j] = result[j], result[i]
+			taskResult := a.workerPool.SubmitAndWait("stop-"+name, func(ctx context.Context) (interface{}, error) {
+				return nil, manager.Stop(name)
+			})
+			err := taskResult.Error
+			if p, ok := manager.GetProcess(name); err == nil && ok && p.Status == models.ProcessStatusRunning {
+				err = fmt.Errorf("process %s is still running", name)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			stopped++
+			report("stop", name, stopped, len(running), err)
+		}(name)
 	}
+	wg.Wait()
 
-	return result
-}
+	// Start phase: each process waits for its dependencies to be ready
+	a.startInDependencyOrder(manager, order, result, &mu, func(name string, completed, total int, err error) {
+		report("start", name, completed, total, err)
+	})
 
-// ClearLogs clears all stored logs
-func (a *App) ClearLogs() error {
-	a.logMu.Lock()
-	defer a.logMu.Unlock()
+	sort.Strings(result.Succeeded)
 
-	a.logs = make([]LogEntry, 0)
-	runtime.EventsEmit(a.ctx, "logs:cleared", nil)
-	return nil
+	log.Printf("[AUDIT] restart processes: %d succeeded, %d failed",
+		len(result.Succeeded), len(result.Failed))
+
+	return result, nil
 }
 
-// GetProjectInfo returns information about the current project
-func (a *App) GetProjectInfo() map[string]interface{} {
-	info := map[string]interface{}{
-		"directory": a.projectDir,
+// startInDependencyOrder starts manager's processes in order (as from StartOrder),
+// each once the processes in its DependsOn are ready, at most
+// processBatchConcurrency at a time. Processes already in result.Failed are
+// not started, and neither is anything depending on them. report is called
+// with mu held as each process becomes ready or fails, and must record
+// failures in result.Failed; ready processes are added to result.Succeeded.
+func (a *App) startInDependencyOrder(manager *process.Manager, order []string, result *ProcessBatchResult, mu *sync.Mutex, report func(name string, completed, total int, err error)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, processBatchConcurrency)
+
+	ready := make(map[string]chan struct{}, len(order))
+	for _, name := range order {
+		ready[name] = make(chan struct{})
+	}
+	started, toStart := 0, 0
+	mu.Lock()
+	for _, name := range order {
+		if _, failed := result.Failed[name]; !failed {
+			toStart++
+		}
 	}
+	mu.Unlock()
+
+	for _, name := range order {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer close(ready[name])
+
+			var err error
+			p, ok := manager.GetProcess(name)
+			if !ok {
+				err = fmt.Errorf("process %s not found", name)
+			} else {
+				for _, dep := range p.DependsOn {
+					<-ready[dep]
+					mu.Lock()
+					_, failed := result.Failed[dep]
+					mu.Unlock()
+					if failed {
+						err = fmt.Errorf("dependency %s failed", dep)
+						break
+					}
+				}
+			}
 
-	if a.config != nil {
-		info["framework"] = a.config.Framework
-		info["projectName"] = a.config.ProjectName
-	}
+			mu.Lock()
+			_, alreadyFailed := result.Failed[name]
+			mu.Unlock()
+			if alreadyFailed {
+				return // Already reported
+			}
 
-	return info
-}
+			if err == nil {
+				sem <- struct{}{}
+				taskResult := a.workerPool.SubmitAndWait("start-"+name, func(ctx context.Context) (interface{}, error) {
+					return nil, manager.Start(name)
+				})
+				<-sem
+				err = taskResult.Error
+			}
+			if err == nil {
+				err = manager.WaitReady(name)
+			}
 
-// SetProjectDirectory changes the project directory
-func (a *App) SetProjectDirectory(dir string) error {
-	// SECURITY: Validate project directory path
-	validatedDir, err := security.ValidateProjectPath(dir)
-	if err != nil {
-		log.Printf("[SECURITY] Invalid project directory: %s, error: %v", dir, err)
-		return fmt.Errorf("invalid project directory: %w", err)
+			mu.Lock()
+			defer mu.Unlock()
+			started++
+			if err == nil {
+				result.Succeeded = append(result.Succeeded, name)
+			}
+			report(name, started, toStart, err)
+		}(name)
 	}
+	wg.Wait()
+}
 
-	// Log directory change for audit
-	log.Printf("[AUDIT] SetProjectDirectory: old=%s, new=%s", a.projectDir, validatedDir)
-
-	// Stop all running processes first
-	if a.processManager != nil {
-		a.processManager.Shutdown()
+// autoStartProcesses starts the processes configured with auto_start, and
+// the processes they depend on, in dependency order. Progress is emitted as
+// process:batch-progress with the "auto-start" action, then the outcome as
+// process:auto-start-finished, so the UI can follow the boot sequence. The
+// first auto-start runs before the UI is listening, so the outcome is also
+// kept for GetAutoStartResult.
+func (a *App) autoStartProcesses(manager *process.Manager) {
+	defer crash.Guard("process auto-start")
+
+	processes := manager.GetAllProcesses()
+	byName := make(map[string]*models.Process, len(processes))
+	for _, p := range processes {
+		byName[p.Name] = p
 	}
 
-	// Reinitialize
-	a.processManager = process.NewManager()
-	a.processManager.OnStatusChange = func(name string, status models.ProcessStatus) {
-		runtime.EventsEmit(a.ctx, "process:status", map[string]interface{}{
-			"name":   name,
-			"status": string(status),
-		})
+	wanted := make(map[string]bool)
+	var want func(name string)
+	want = func(name string) {
+		p, ok := byName[name]
+		if !ok || wanted[name] {
+			return
+		}
+		wanted[name] = true
+		for _, dep := range p.DependsOn {
+			want(dep)
+		}
+	}
+	for _, p := range processes {
+		if p.AutoStart {
+			want(p.Name)
+		}
 	}
-	a.processManager.OnLog = func(name string, line string) {
-		a.addLog(name, line, "info")
+	if len(wanted) == 0 {
+		return
 	}
 
-	a.projectDir = validatedDir
-	return a.loadProjectConfig()
-}
+	result := &ProcessBatchResult{
+		Action:    "auto-start",
+		Succeeded: []string{},
+		Failed:    make(map[string]string),
+		Skipped:   []string{},
+	}
 
-// SelectProjectDirectory opens a directory picker dialog
-func (a *App) SelectProjectDirectory() (string, error) {
-	dir, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
-		Title: "Select Project Directory",
-	})
-	if err != nil {
-		return "", err
+	report := func(name string, completed, total int, err error) {
+		progress := map[string]interface{}{
+			"action":    "auto-start",
+			"name":      name,
+			"success":   err == nil,
+			"completed": completed,
+			"total":     total,
+		}
+		if err != nil {
+			result.Failed[name] = err.Error()
+			progress["error"] = err.Error()
+		}
+		a.emit("process:batch-progress", progress)
 	}
 
-	if dir != "" {
-		if err := a.SetProjectDirectory(dir); err != nil {
-			return "", err
+	all, blocked := process.StartOrder(processes)
+	var blockedNames []string
+	for name := range blocked {
+		if wanted[name] {
+			blockedNames = append(blockedNames, name)
+		}
+	}
+	sort.Strings(blockedNames)
+	for i, name := range blockedNames {
+		report(name, i+1, len(wanted), errors.New(blocked[name]))
+	}
+	order := make([]string, 0, len(wanted))
+	for _, name := range all {
+		if wanted[name] {
+			order = append(order, name)
 		}
 	}
 
-	return dir, nil
-}
+	// Blocked processes count towards the progress already reported
+	var mu sync.Mutex
+	a.startInDependencyOrder(manager, order, result, &mu, func(name string, completed, total int, err error) {
+		report(name, len(blockedNames)+completed, len(blockedNames)+total, err)
+	})
 
-// Helper functions
+	sort.Strings(result.Succeeded)
 
-func formatUptime(d time.Duration) string {
-	days := int(d.Hours()) / 24
-	hours := int(d.Hours()) % 24
-	minutes := int(d.Minutes()) % 60
+	log.Printf("[AUDIT] auto-start processes: %d succeeded, %d failed",
+		len(result.Succeeded), len(result.Failed))
 
-	if days > 0 {
-		return fmt.Sprintf("%dd %dh", days, hours)
-	}
-	if hours > 0 {
-		return fmt.Sprintf("%dh %dm", hours, minutes)
-	}
-	return fmt.Sprintf("%dm", minutes)
+	a.autoStartResult.Store(result)
+	a.emit("process:auto-start-finished", result)
 }
 
-func formatCommand(cmd string, args []string) string {
-	result := cmd
-	for _, arg := range args {
-		result += " " + arg
-	}
-	return result
+// GetAutoStartResult returns the outcome of the project's auto-start, or nil
+// while it is still running or if no process is configured to auto-start
+func (a *App) GetAutoStartResult() *ProcessBatchResult {
+	return a.autoStartResult.Load()
 }
 
-func detectProcessType(command string) string {
-	switch command {
-	case "bundle", "rails", "ruby":
-		return "rails"
-	case "npm", "yarn", "pnpm", "node":
-		return "frontend"
-	case "sidekiq", "good_job", "delayed_job":
-		return "worker"
-	case "postgres", "mysql", "redis":
-		return "database"
-	case "docker", "docker-compose":
-		return "docker"
-	default:
-		return "custom"
+// processNames returns the names of all managed processes
+func (a *App) processNames() []string {
+	processes := a.processManager.GetAllProcesses()
+	names := make([]string, 0, len(processes))
+	for _, p := range processes {
+		names = append(names, p.Name)
 	}
+	return names
 }
 
-// StartRailsConsole starts an interactive Rails console process
-func (a *App) StartRailsConsole() error {
+// runProcessBatch applies action to each process through the worker pool, at
+// most processBatchConcurrency at a time. Processes for which done reports
+// true are skipped. Failures are collected per process rather than aborting
+// the batch.
+func (a *App) runProcessBatch(action string, names []string, apply func(name string) error, done func(p *models.Process) bool) (*ProcessBatchResult, error) {
 	if a.processManager == nil {
-		return fmt.Errorf("process manager not initialized")
+		return nil, fmt.Errorf("process manager not initialized")
 	}
 
-	// Stop existing console if running
-	if p, exists := a.processManager.GetProcess("rails-console"); exists {
-		if p.Status == models.ProcessStatusRunning {
-			a.processManager.Stop("rails-console")
-		}
-		a.processManager.RemoveProcess("rails-console")
+	// The whole batch counts as one process operation
+	if !a.rateLimiter.Allow("process") {
+		return nil, fmt.Errorf("rate limit exceeded: too many process operations")
 	}
 
-	// Create rails console config
-	config := models.ProcessConfig{
-		Name:       "rails-console",
-		Command:    "bundle",
-		Args:       []string{"exec", "rails", "console"},
-		WorkingDir: a.projectDir,
-		UsePTY:     true,
-		Color:      "#ef4444", // red
+	result := &ProcessBatchResult{
+		Action:    action,
+		Succeeded: []string{},
+		Failed:    make(map[string]string),
+		Skipped:   []string{},
 	}
 
-	// Add and start
-	if err := a.processManager.AddProcess(config); err != nil {
-		return err
+	pending := make([]string, 0, len(names))
+	for _, name := range names {
+		p, ok := a.processManager.GetProcess(name)
+		if !ok {
+			result.Failed[name] = fmt.Sprintf("process %s not found", name)
+			continue
+		}
+		if done(p) {
+			result.Skipped = append(result.Skipped, name)
+			continue
+		}
+		pending = append(pending, name)
 	}
 
-	return a.processManager.Start("rails-console")
-}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, processBatchConcurrency)
+	completed := 0
 
-// StopRailsConsole stops the Rails console process
-func (a *App) StopRailsConsole() error {
-	if a.processManager == nil {
-		return fmt.Errorf("process manager not initialized")
+	for _, name := range pending {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			taskResult := a.workerPool.SubmitAndWait(action+"-"+name, func(ctx context.Context) (interface{}, error) {
+				return nil, apply(name)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			completed++
+			progress := map[string]interface{}{
+				"action":    action,
+				"name":      name,
+				"success":   taskResult.Error == nil,
+				"completed": completed,
+				"total":     len(pending),
+			}
+			if taskResult.Error != nil {
+				result.Failed[name] = taskResult.Error.Error()
+				progress["error"] = taskResult.Error.Error()
+			} else {
+				result.Succeeded = append(result.Succeeded, name)
+			}
+			a.emit("process:batch-progress", progress)
+		}(name)
 	}
+	wg.Wait()
 
-	return a.processManager.Stop("rails-console")
+	sort.Strings(result.Succeeded)
+	sort.Strings(result.Skipped)
+
+	log.Printf("[AUDIT] %s processes: %d succeeded, %d failed, %d skipped",
+		action, len(result.Succeeded), len(result.Failed), len(result.Skipped))
+
+	return result, nil
 }
 
-// IsRailsConsoleRunning checks if Rails console is running
-func (a *App) IsRailsConsoleRunning() bool {
-	if a.processManager == nil {
-		return false
+// GetProcessTemplates returns ready-made processes for the add-process form:
+// the detected framework's suggestions first, then ones found by inspecting
+// the project (package.json scripts, bundler dev servers and watchers)
+func (a *App) GetProcessTemplates() []models.ProcessTemplate {
+	templates := []models.ProcessTemplate{}
+	if a.projectDir == "" {
+		return templates
 	}
 
-	p, exists := a.processManager.GetProcess("rails-console")
-	return exists && p.Status == models.ProcessStatusRunning
+	if provider, ok := a.currentPlugin.(plugin.ProcessTemplateProvider); ok {
+		templates = append(templates, provider.ProcessTemplates(a.projectDir)...)
+	}
+	templates = append(templates, process.DetectTemplates(a.projectDir)...)
+
+	// The framework's template wins when both offer the same process
+	seen := make(map[string]bool, len(templates))
+	result := templates[:0]
+	for _, t := range templates {
+		if !seen[t.ID] {
+			seen[t.ID] = true
+			result = append(result, t)
+		}
+	}
+	return result
 }
 
-// WriteToRailsConsole writes input to the Rails console PTY
-func (a *App) WriteToRailsConsole(input string) error {
+// SuggestProcessColor returns a readable color for a new process that is
+// easy to tell apart from the existing processes' colors
+func (a *App) SuggestProcessColor() (string, error) {
 	if a.processManager == nil {
-		return fmt.Errorf("process manager not initialized")
+		return "", fmt.Errorf("process manager not initialized")
 	}
-
-	return a.processManager.WriteToPTY("rails-console", []byte(input))
+	return a.processManager.SuggestColor(), nil
 }
 
-// ResizeRailsConsole resizes the Rails console PTY window
-func (a *App) ResizeRailsConsole(rows, cols int) error {
-	if a.processManager == nil {
-		return fmt.Errorf("process manager not initialized")
+// CheckDependencies reports whether the project's gems and npm packages are
+// installed, so a missing `bundle install` can be offered before a process
+// fails to start
+func (a *App) CheckDependencies() []models.DependencyStatus {
+	statuses := []models.DependencyStatus{}
+	if a.projectDir == "" {
+		return statuses
 	}
 
-	return a.processManager.ResizePTY("rails-console", uint16(rows), uint16(cols))
+	if checker, ok := a.currentPlugin.(plugin.DependencyChecker); ok {
+		statuses = append(statuses, checker.CheckDependencies(a.projectDir)...)
+	}
+	if status := process.CheckNodeDependencies(a.projectDir); status != nil {
+		statuses = append(statuses, *status)
+	}
+	return statuses
+}
+
+// installTimeout bounds a dependency install
+const installTimeout = 15 * time.Minute
+
+// InstallDependencies runs the install command CheckDependencies gave for a
+// package manager ("bundler", "npm", ...) as a one-off process, streaming
+// its output like any process, and emits dependencies:installed when it
+// exits. Returns the process name, or "" when the check found everything
+// installed and there is nothing to do.
+func (a *App) InstallDependencies(manager string) (string, error) {
+	if a.processManager == nil {
+		return "", fmt.Errorf("process manager not initialized")
+	}
+
+	if !a.rateLimiter.Allow("process") {
+		return "", fmt.Errorf("rate limit exceeded: too many process operations")
+	}
+
+	// The command comes from the check, never from the caller
+	var install []string
+	for _, status := range a.CheckDependencies() {
+		if status.Manager == manager {
+			if status.Satisfied {
+				log.Printf("InstallDependencies: %s dependencies are already installed", manager)
+				return "", nil
+			}
+			install = status.InstallCommand
+			break
+		}
+	}
+	if len(install) == 0 {
+		return "", fmt.Errorf("no dependencies to install for %s", manager)
+	}
+
+	// SECURITY: Validate command is in whitelist
+	if err := security.ValidateCommand(install[0]); err != nil {
+		log.Printf("[SECURITY] Blocked install command: %s", install[0])
+		return "", fmt.Errorf("security error: %w", err)
+	}
+
+	if !a.installRunning.CompareAndSwap(false, true) {
+		return "", fmt.Errorf("an install is already running")
+	}
+
+	name := "install-" + manager
+	config := models.ProcessConfig{
+		Name:       name,
+		Command:    install[0],
+		Args:       install[1:],
+		WorkingDir: a.projectDir,
+		Color:      "#3b82f6", // blue
+	}
+
+	log.Printf("[AUDIT] InstallDependencies: manager=%s, command=%v", manager, install)
+
+	go func() {
+		defer a.installRunning.Store(false)
+
+		result, err := a.processManager.RunOnce(config, installTimeout)
+		if err != nil {
+			log.Printf("[ERROR] Install %s failed to start: %v", name, err)
+			a.emit("dependencies:installed", map[string]interface{}{
+				"name":    name,
+				"manager": manager,
+				"error":   err.Error(),
+			})
+			return
+		}
+
+		a.emit("dependencies:installed", map[string]interface{}{
+			"name":     name,
+			"manager":  manager,
+			"exitCode": result.ExitCode,
+			"timedOut": result.TimedOut,
+			"duration": result.Duration,
+		})
+	}()
+
+	return name, nil
+}
+
+// AddProcess adds a new process configuration and saves to config file
+func (a *App) AddProcess(config map[string]interface{}) error {
+	if a.processManager == nil {
+		return fmt.Errorf("process manager not initialized")
+	}
+
+	// Rate limit process creation
+	if !a.rateLimiter.Allow("process") {
+		return fmt.Errorf("rate limit exceeded: too many process operations")
+	}
+
+	name, _ := config["name"].(string)
+	command, _ := config["command"].(string)
+	argsRaw, _ := config["args"].([]interface{})
+	workingDir, _ := config["workingDir"].(string)
+	autoRestart, _ := config["autoRestart"].(bool)
+	autoStart, _ := config["autoStart"].(bool)
+	usePTY, _ := config["usePty"].(bool)
+	color, _ := config["color"].(string)
+	envFilesRaw, _ := config["envFiles"].([]interface{})
+	dependsOnRaw, _ := config["dependsOn"].([]interface{})
+	environmentRaw, _ := config["environment"].(map[string]interface{})
+
+	// SECURITY: Validate command is in whitelist
+	if err := security.ValidateCommand(command); err != nil {
+		log.Printf("[SECURITY] Blocked command: %s", command)
+		return fmt.Errorf("security error: %w", err)
+	}
+
+	args := make([]string, len(argsRaw))
+	for i, arg := range argsRaw {
+		args[i], _ = arg.(string)
+	}
+
+	// SECURITY: Validate arguments don't contain shell metacharacters
+	if err := security.ValidateArguments(args); err != nil {
+		log.Printf("[SECURITY] Blocked dangerous arguments: %v", args)
+		return fmt.Errorf("security error: %w", err)
+	}
+
+	if workingDir == "" {
+		workingDir = a.projectDir
+	}
+
+	// SECURITY: dotenv files must stay inside the working directory
+	envFiles := make([]string, 0, len(envFilesRaw))
+	for _, raw := range envFilesRaw {
+		file, _ := raw.(string)
+		if err := security.ValidateRelativePath(file); err != nil {
+			log.Printf("[SECURITY] Invalid env file: %s", file)
+			return fmt.Errorf("invalid env file: %w", err)
+		}
+		envFiles = append(envFiles, file)
+	}
+
+	dependsOn := make([]string, 0, len(dependsOnRaw))
+	for _, raw := range dependsOnRaw {
+		if dep, _ := raw.(string); dep != "" && dep != name {
+			dependsOn = append(dependsOn, dep)
+		}
+	}
+
+	var environment map[string]string
+	for key, raw := range environmentRaw {
+		if err := security.ValidateEnvName(key); err != nil {
+			return err
+		}
+		if environment == nil {
+			environment = make(map[string]string, len(environmentRaw))
+		}
+		value, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("environment variable %s must be a string", key)
+		}
+		environment[key] = value
+	}
+
+	// SECURITY: Validate working directory path. A template such as
+	// {{projectDir}}/frontend is saved as written and expanded at each start,
+	// so it's only checked to resolve inside the project here.
+	validatedDir := workingDir
+	if process.IsWorkingDirTemplate(workingDir) {
+		if _, err := process.ExpandWorkingDir(workingDir, a.projectDir, environment); err != nil {
+			log.Printf("[SECURITY] Invalid working directory: %s", workingDir)
+			return fmt.Errorf("invalid working directory: %w", err)
+		}
+	} else {
+		var err error
+		validatedDir, err = security.ValidateProjectPath(workingDir)
+		if err != nil {
+			log.Printf("[SECURITY] Invalid working directory: %s", workingDir)
+			return fmt.Errorf("invalid working directory: %w", err)
+		}
+	}
+
+	port := getInt(config, "port")
+	if port < 0 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+
+	// An explicit color may repeat another process's; only an automatic one
+	// is kept distinct
+	if color == "" {
+		color = a.processManager.SuggestColor()
+	} else if err := process.ValidateColor(color); err != nil {
+		return err
+	}
+
+	var healthCheck *models.HealthCheck
+	if raw, ok := config["healthCheck"].(map[string]interface{}); ok {
+		healthCheck = &models.HealthCheck{
+			Port:       getInt(raw, "port"),
+			LogPattern: getString(raw, "logPattern"),
+			Timeout:    getInt(raw, "timeout"),
+		}
+	}
+
+	procConfig := models.ProcessConfig{
+		Name:        name,
+		Command:     command,
+		Args:        args,
+		WorkingDir:  validatedDir,
+		Environment: environment,
+		EnvFiles:    envFiles,
+		AutoRestart: autoRestart,
+		AutoStart:   autoStart,
+		UsePTY:      usePTY,
+		Color:       color,
+		HealthCheck: healthCheck,
+		DependsOn:   dependsOn,
+		Port:        port,
+	}
+
+	// Log process creation for audit
+	log.Printf("[AUDIT] AddProcess: name=%s, command=%s, args=%v, env=%s",
+		name, command, args, a.redactor().FormatEnv(environment))
+
+	// Add to process manager
+	if err := a.processManager.AddProcess(procConfig); err != nil {
+		return err
+	}
+
+	// Save to config file for persistence
+	if a.config != nil {
+		if a.config.Processes == nil {
+			a.config.Processes = make(map[string]models.ProcessConfig)
+		}
+		a.config.Processes[name] = procConfig
+		if err := a.saveConfig(); err != nil {
+			// Log error but don't fail - process is still added to manager
+			log.Printf("Warning: failed to save config: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// RemoveProcess removes a process configuration and saves to config file
+func (a *App) RemoveProcess(name string) error {
+	if a.processManager == nil {
+		return fmt.Errorf("process manager not initialized")
+	}
+
+	// Stop the process first if running
+	p, exists := a.processManager.GetProcess(name)
+	if exists && p.Status == models.ProcessStatusRunning {
+		a.processManager.Stop(name)
+	}
+
+	// Remove from process manager
+	if err := a.processManager.RemoveProcess(name); err != nil {
+		return err
+	}
+
+	// Remove from config file for persistence
+	if a.config != nil && a.config.Processes != nil {
+		delete(a.config.Processes, name)
+		if err := a.saveConfig(); err != nil {
+			// Log error but don't fail - process is still removed from manager
+			fmt.Printf("Warning: failed to save config: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// WriteToPTY writes input to a process PTY (for interactive terminals)
+func (a *App) WriteToPTY(name string, input string) error {
+	if a.processManager == nil {
+		return fmt.Errorf("process manager not initialized")
+	}
+
+	// Rate limit PTY writes
+	if !a.rateLimiter.Allow("pty") {
+		log.Printf("[SECURITY] Rate limit exceeded for PTY write")
+		return fmt.Errorf("rate limit exceeded: too many PTY write requests")
+	}
+
+	// SECURITY: Sanitize PTY input
+	sanitized, err := security.SanitizePTYInput(input)
+	if err != nil {
+		log.Printf("[SECURITY] Blocked dangerous PTY input: %v", err)
+		return fmt.Errorf("security error: %w", err)
+	}
+
+	if a.holdMultilinePaste(name, sanitized) {
+		return nil
+	}
+
+	// Log PTY writes for audit
+	log.Printf("[AUDIT] WriteToPTY: process=%s, input=%q", name, sanitized[:min(50, len(sanitized))])
+
+	return a.processManager.WriteToPTY(name, []byte(sanitized))
+}
+
+// maxUnconfirmedPasteLines is the most line breaks console input may contain
+// before it is held for confirmation
+const maxUnconfirmedPasteLines = 2
+
+// holdMultilinePaste reports whether input for an interactive console should
+// be reviewed before it runs, and if so emits pty:multiline-paste for the
+// frontend to ask. Each line of a paste executes as soon as it arrives, so a
+// large paste into a production console can't be stopped once sent.
+func (a *App) holdMultilinePaste(name, input string) bool {
+	normalized := strings.ReplaceAll(input, "\r\n", "\n")
+	lines := strings.Count(normalized, "\n") + strings.Count(normalized, "\r")
+	if lines <= maxUnconfirmedPasteLines || !a.isConsoleProcess(name) {
+		return false
+	}
+
+	log.Printf("[SECURITY] Holding %d-line paste into %s for confirmation", lines, name)
+	// SECURITY: Pasted console input often holds secrets; the journal keeps
+	// only its size
+	a.emitRedacted("pty:multiline-paste", map[string]interface{}{
+		"name":  name,
+		"input": input,
+		"lines": lines,
+	}, map[string]interface{}{
+		"name":     name,
+		"bytes":    len(input),
+		"lines":    lines,
+		"redacted": true,
+	})
+	return true
+}
+
+// isConsoleProcess reports whether a process is an interactive framework console
+func (a *App) isConsoleProcess(name string) bool {
+	if name == "rails-console" {
+		return true
+	}
+
+	p, exists := a.processManager.GetProcess(name)
+	if !exists {
+		return false
+	}
+	for i, arg := range p.Args {
+		if arg == "console" {
+			return true
+		}
+		// rails c
+		if arg == "c" && (i > 0 && p.Args[i-1] == "rails" || i == 0 && p.Command == "rails") {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfirmPaste forwards a multi-line paste held by WriteToPTY after the user
+// has reviewed it
+func (a *App) ConfirmPaste(name string, input string) error {
+	if a.processManager == nil {
+		return fmt.Errorf("process manager not initialized")
+	}
+
+	if !a.rateLimiter.Allow("pty") {
+		log.Printf("[SECURITY] Rate limit exceeded for PTY write")
+		return fmt.Errorf("rate limit exceeded: too many PTY write requests")
+	}
+
+	// SECURITY: Sanitize PTY input
+	sanitized, err := security.SanitizePTYInput(input)
+	if err != nil {
+		log.Printf("[SECURITY] Blocked dangerous PTY input: %v", err)
+		return fmt.Errorf("security error: %w", err)
+	}
+
+	log.Printf("[AUDIT] ConfirmPaste: process=%s, bytes=%d, input=%q", name, len(sanitized), sanitized[:min(50, len(sanitized))])
+
+	return a.processManager.WriteToPTY(name, []byte(sanitized))
+}
+
+// ResizePTY resizes a process PTY window
+func (a *App) ResizePTY(name string, rows, cols int) error {
+	if a.processManager == nil {
+		return fmt.Errorf("process manager not initialized")
+	}
+
+	return a.processManager.ResizePTY(name, uint16(rows), uint16(cols))
+}
+
+// GetPTYScrollback returns up to maxBytes of a process's recent raw terminal
+// output, escape sequences included, for repainting a terminal on mount or
+// reconnect
+func (a *App) GetPTYScrollback(name string, maxBytes int) (string, error) {
+	if a.processManager == nil {
+		return "", fmt.Errorf("process manager not initialized")
+	}
+
+	data, err := a.processManager.Scrollback(name, maxBytes)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ingestLog buffers a process output line, passes the parsed entry to the
+// framework plugin and feeds any exception it contains to the exception tracker
+func (a *App) ingestLog(processName, line string, stream models.LogStream) {
+	if processName == testWatchProcessName {
+		a.feedTestWatch(line)
+	}
+
+	entry := a.ParseLogWithPlugin(line)
+	level := models.LogLevelInfo
+	if entry != nil && entry.Level != "" {
+		level = entry.Level
+	}
+	level = a.logRules.Load().Apply(processName, line, level)
+	if entry != nil {
+		entry.Level = level
+	}
+
+	a.addLog(processName, line, string(level), string(stream), entry)
+	if entry == nil {
+		return
+	}
+	entry.ProcessName = processName
+	a.observeQueries(processName, entry)
+
+	if entry.Level == models.LogLevelError || entry.Exception != nil {
+		a.processManager.RecordLogError(processName)
+	}
+
+	if entry.Exception == nil {
+		return
+	}
+
+	id, isNew := a.exceptionTracker.TrackException(entry)
+	if isNew {
+		a.emit("exception:new", map[string]interface{}{
+			"id":      id,
+			"type":    entry.Exception.Type,
+			"message": entry.Exception.Message,
+			"process": processName,
+		})
+	}
+}
+
+// maxLogBufferSize bounds the configurable in-memory log buffer
+const maxLogBufferSize = 1000000
+
+// applyLogConfig sizes the in-memory log buffer from the config, dropping the
+// oldest lines if it shrank, and sets the log flood thresholds and level rules
+func (a *App) applyLogConfig() {
+	if a.config == nil {
+		return
+	}
+	a.logRules.Store(corelog.NewLevelRules(a.config.Log.Rules))
+	a.applyLogForwarding()
+	if a.processManager != nil {
+		a.processManager.SetLogFloodThresholds(a.config.Log.FloodLineRate, a.config.Log.FloodErrorRate)
+	}
+	if a.config.Log.BufferSize <= 0 {
+		return
+	}
+
+	a.logMu.Lock()
+	defer a.logMu.Unlock()
+
+	a.logBuffer = min(a.config.Log.BufferSize, maxLogBufferSize)
+	if len(a.logs) > a.logBuffer {
+		a.logs = append([]LogEntry(nil), a.logs[len(a.logs)-a.logBuffer:]...)
+	}
+}
+
+// applySecurityConfig builds the destructive query guard and the environment
+// redactor from the config
+func (a *App) applySecurityConfig() {
+	if a.config == nil {
+		return
+	}
+	a.queryGuard.Store(security.NewQueryGuard(a.config.Security.DestructiveKeywords))
+	a.envRedactor.Store(security.NewRedactor(a.config.Security.RedactEnvPatterns))
+}
+
+// applyMetricsConfig loads the metric alert rules from the config
+func (a *App) applyMetricsConfig() {
+	if a.config == nil {
+		return
+	}
+	a.metricsAlerts.SetRules(a.config.Metrics.Alerts)
+}
+
+// applySuspendConfig publishes the [suspend] policy to watchMemoryPressure,
+// which runs on its own goroutine and so can't read a.config
+func (a *App) applySuspendConfig() {
+	if a.config == nil {
+		return
+	}
+	policy := a.config.Suspend
+	policy.Processes = slices.Clone(policy.Processes)
+	a.suspendPolicy.Store(&policy)
+}
+
+// applyAutoSaveConfig sets how long config writes are coalesced for
+func (a *App) applyAutoSaveConfig() {
+	if a.config == nil {
+		return
+	}
+	a.configSaver.SetDelay(time.Duration(max(a.config.AutoSaveDelay, 0)) * time.Millisecond)
+}
+
+// saveConfig saves the project config; changes made in quick succession are
+// written once, after the configured auto-save delay
+func (a *App) saveConfig() error {
+	return a.configSaver.Schedule(a.config, a.projectDir)
+}
+
+// redactor returns the configured environment redactor, or one with the
+// default patterns before a config is loaded
+func (a *App) redactor() *security.Redactor {
+	if r := a.envRedactor.Load(); r != nil {
+		return r
+	}
+	return security.NewRedactor(nil)
+}
+
+// isDestructiveQuery checks a query against the configured destructive
+// keywords, or the defaults before a config is loaded
+func (a *App) isDestructiveQuery(query string) bool {
+	if guard := a.queryGuard.Load(); guard != nil {
+		return guard.IsDestructive(query)
+	}
+	return security.IsDestructiveQuery(query)
+}
+
+// SetLogBufferSize changes how many log lines are kept in memory and saves it
+func (a *App) SetLogBufferSize(n int) error {
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+	if n <= 0 || n > maxLogBufferSize {
+		return fmt.Errorf("log buffer size must be between 1 and %d", maxLogBufferSize)
+	}
+
+	a.config.Log.BufferSize = n
+	a.applyLogConfig()
+
+	log.Printf("[AUDIT] SetLogBufferSize: size=%d", n)
+
+	return a.saveConfig()
+}
+
+// GetLogRules returns the log level reclassification rules, in the order
+// they are tried
+func (a *App) GetLogRules() []config.LogRule {
+	if a.config == nil {
+		return []config.LogRule{}
+	}
+	return append([]config.LogRule{}, a.config.Log.Rules...)
+}
+
+// AddLogRule adds a rule giving lines that match a regular expression a
+// level, for one process or, when process is empty, all of them. It applies
+// to lines that arrive from now on, after the rules already configured.
+func (a *App) AddLogRule(pattern, level, process string) error {
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+
+	rule := config.LogRule{Pattern: pattern, Level: level, Process: process}
+	if err := corelog.ValidateRule(rule); err != nil {
+		return err
+	}
+	parsed, _ := corelog.ParseLevel(level)
+	rule.Level = string(parsed)
+
+	a.config.Log.Rules = append(a.config.Log.Rules, rule)
+	a.applyLogConfig()
+
+	log.Printf("[AUDIT] AddLogRule: pattern=%q level=%s process=%q", pattern, rule.Level, process)
+
+	return a.saveConfig()
+}
+
+// RemoveLogRule removes the log level rule at index, as listed by GetLogRules
+func (a *App) RemoveLogRule(index int) error {
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+	rules := a.config.Log.Rules
+	if index < 0 || index >= len(rules) {
+		return fmt.Errorf("log rule %d not found", index)
+	}
+
+	removed := rules[index]
+	a.config.Log.Rules = append(rules[:index:index], rules[index+1:]...)
+	a.applyLogConfig()
+
+	log.Printf("[AUDIT] RemoveLogRule: pattern=%q level=%s process=%q", removed.Pattern, removed.Level, removed.Process)
+
+	return a.saveConfig()
+}
+
+// addLog adds a log entry and emits event to frontend. The entry is stamped
+// with the time parsed from the line when there is one.
+func (a *App) addLog(processName, content, level, stream string, parsed *models.LogEntry) {
+	a.logMu.Lock()
+	defer a.logMu.Unlock()
+
+	a.logIdCounter++
+	entry := LogEntry{
+		ID:        fmt.Sprintf("%d", a.logIdCounter),
+		Process:   processName,
+		Content:   content,
+		Level:     level,
+		Stream:    stream,
+		Timestamp: a.reconcileLogTime(processName, parsed, time.Now()),
+	}
+
+	a.logs = append(a.logs, entry)
+
+	// Trim logs if buffer exceeded
+	if len(a.logs) > a.logBuffer {
+		a.logs = a.logs[len(a.logs)-a.logBuffer:]
+	}
+
+	// Emit log event to frontend
+	if a.logThrottle == nil || a.logThrottle.Allow(processName) {
+		a.emit("process:log", entry)
+	}
+	if f := a.logForwarder.Load(); f != nil {
+		f.Publish(forwardedLogEntry(entry, parsed))
+	}
+}
+
+// forwardedLogEntry is the entry sent to log forwarding clients: the stored
+// entry plus whatever the parser extracted from the line
+func forwardedLogEntry(entry LogEntry, parsed *models.LogEntry) *models.LogEntry {
+	forwarded := &models.LogEntry{
+		ID:          entry.ID,
+		Timestamp:   entry.Timestamp,
+		Raw:         entry.Content,
+		Level:       models.LogLevel(entry.Level),
+		Message:     entry.Content,
+		ProcessName: entry.Process,
+		Stream:      models.LogStream(entry.Stream),
+	}
+	if parsed != nil {
+		forwarded.HasTimestamp = parsed.HasTimestamp
+		forwarded.RequestID = parsed.RequestID
+		forwarded.Metadata = parsed.Metadata
+		forwarded.SQL = parsed.SQL
+		forwarded.Request = parsed.Request
+		forwarded.Exception = parsed.Exception
+	}
+	return forwarded
+}
+
+// applyLogForwarding starts, moves or stops the log forwarding socket to
+// match [log] forward_socket
+func (a *App) applyLogForwarding() {
+	spec := a.config.Log.ForwardSocket
+
+	a.logForwardMu.Lock()
+	defer a.logForwardMu.Unlock()
+
+	if spec == a.logForwardSpec {
+		return
+	}
+	if f := a.logForwarder.Swap(nil); f != nil {
+		f.Close()
+	}
+	a.logForwardSpec = spec
+	if spec == "" {
+		return
+	}
+
+	f, err := corelog.ListenForwarder(spec)
+	if err != nil {
+		log.Printf("[ERROR] Failed to start log forwarding: %v", err)
+		return
+	}
+	a.logForwarder.Store(f)
+	status := f.Status()
+	log.Printf("[AUDIT] Log forwarding started: %s %s", status.Network, status.Address)
+}
+
+// stopLogForwarding closes the log forwarding socket and its clients
+func (a *App) stopLogForwarding() {
+	a.logForwardMu.Lock()
+	defer a.logForwardMu.Unlock()
+
+	if f := a.logForwarder.Swap(nil); f != nil {
+		f.Close()
+	}
+	a.logForwardSpec = ""
+}
+
+// GetLogForwarding returns where logs are forwarded and to how many
+// clients, or nil when forwarding is off
+func (a *App) GetLogForwarding() *corelog.ForwardStatus {
+	f := a.logForwarder.Load()
+	if f == nil {
+		return nil
+	}
+	status := f.Status()
+	return &status
+}
+
+const (
+	// maxLogClockSkew is how far past the wall clock a line's own timestamp
+	// may be before it's taken as misparsed and ignored
+	maxLogClockSkew = time.Minute
+
+	// logClockCarry is how long after a timestamped line the process's
+	// untimestamped lines keep that line's offset from the wall clock
+	logClockCarry = 5 * time.Second
+)
+
+// logClock is how far behind the wall clock a process's output runs, as
+// measured from its last timestamped line
+type logClock struct {
+	offset time.Duration
+	seen   time.Time // Ingestion time of that line
+}
+
+// reconcileLogTime picks the timestamp for a line ingested at now. A time
+// parsed from the line wins; otherwise the line is shifted by the offset of
+// a timestamped line seen just before it, so a burst of buffered or tailed
+// output keeps its order. Caller must hold a.logMu.
+func (a *App) reconcileLogTime(processName string, parsed *models.LogEntry, now time.Time) time.Time {
+	if parsed != nil && parsed.HasTimestamp && !parsed.Timestamp.After(now.Add(maxLogClockSkew)) {
+		a.logClocks[processName] = logClock{offset: parsed.Timestamp.Sub(now), seen: now}
+		return parsed.Timestamp
+	}
+
+	if clock, ok := a.logClocks[processName]; ok && now.Sub(clock.seen) <= logClockCarry {
+		return now.Add(clock.offset)
+	}
+	return now
+}
+
+// emitLogsSuppressed streams a note standing in for log lines that were over
+// the event rate cap. The note isn't kept; the lines themselves are.
+func (a *App) emitLogsSuppressed(processName string, count int) {
+	a.logMu.Lock()
+	a.logIdCounter++
+	entry := LogEntry{
+		ID:        fmt.Sprintf("%d", a.logIdCounter),
+		Process:   processName,
+		Content:   events.SuppressedNote(count),
+		Level:     "warn",
+		Timestamp: time.Now(),
+	}
+	a.logMu.Unlock()
+
+	a.emit("process:log", entry)
+}
+
+// maxHighlightsPerEntry bounds the match ranges returned for one log line
+const maxHighlightsPerEntry = 100
+
+// compileLogSearch builds the matcher for a GetLogs search. The search is
+// case-insensitive unless caseSensitive is set, and a regex that doesn't
+// compile (often one still being typed) is searched for literally instead.
+func compileLogSearch(search string, useRegex, caseSensitive bool) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(search)
+	if useRegex {
+		if _, err := regexp.Compile(search); err == nil {
+			pattern = search
+		}
+	}
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.MustCompile(pattern)
+}
+
+// findLogMatches returns the non-empty match ranges of re in content
+func findLogMatches(re *regexp.Regexp, content string) [][2]int {
+	var matches [][2]int
+	for _, loc := range re.FindAllStringIndex(content, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		matches = append(matches, [2]int{loc[0], loc[1]})
+		if len(matches) == maxHighlightsPerEntry {
+			break
+		}
+	}
+	return matches
+}
+
+// GetLogs returns logs with optional filtering. A "search" term (a regular
+// expression when "regex" is set) limits results to matching lines, and
+// "highlight" adds the match ranges to each entry.
+func (a *App) GetLogs(filter map[string]interface{}) []LogEntry {
+	a.logMu.RLock()
+	defer a.logMu.RUnlock()
+
+	processFilter, _ := filter["process"].(string)
+	levelFilter, _ := filter["level"].(string)
+	streamFilter, _ := filter["stream"].(string)
+	search, _ := filter["search"].(string)
+	useRegex, _ := filter["regex"].(bool)
+	caseSensitive, _ := filter["caseSensitive"].(bool)
+	highlight, _ := filter["highlight"].(bool)
+	limitRaw, _ := filter["limit"].(float64)
+	limit := int(limitRaw)
+	if limit == 0 {
+		limit = 100
+	}
+
+	var searchRe *regexp.Regexp
+	if search != "" {
+		searchRe = compileLogSearch(search, useRegex, caseSensitive)
+	}
+
+	result := make([]LogEntry, 0, limit)
+
+	// Iterate backwards for most recent logs
+	for i := len(a.logs) - 1; i >= 0 && len(result) < limit; i-- {
+		log := a.logs[i]
+
+		if processFilter != "" && log.Process != processFilter {
+			continue
+		}
+		if levelFilter != "" && log.Level != levelFilter {
+			continue
+		}
+		if streamFilter != "" && log.Stream != streamFilter {
+			continue
+		}
+		if searchRe != nil {
+			if !searchRe.MatchString(log.Content) {
+				continue
+			}
+			if highlight {
+				log.Matches = findLogMatches(searchRe, log.Content)
+			}
+		}
+
+		result = append(result, log)
+	}
+
+	// Reverse to get chronological order
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result
+}
+
+// maxLinkScan bounds how much of a log line ExtractLogLinks looks at
+const maxLinkScan = 64 * 1024
+
+// ExtractLogLinks finds what can be clicked in a log line: web URLs, to open
+// in the browser, and file:line references to files in the project, to jump
+// to source. File links are given relative to the project root, with paths
+// printed from another checkout (e.g. a container's /app) mapped onto this
+// one; references to files that don't exist here are dropped.
+func (a *App) ExtractLogLinks(content string) []corelog.Link {
+	if len(content) > maxLinkScan {
+		content = content[:maxLinkScan]
+	}
+	candidates := corelog.ExtractLinks(content)
+
+	var root string
+	if a.projectDir != "" {
+		root, _ = security.ValidateProjectPath(a.projectDir)
+	}
+
+	links := make([]corelog.Link, 0, len(candidates))
+	for _, link := range candidates {
+		if link.Kind == "file" {
+			if root == "" {
+				continue
+			}
+			rel, ok := projectSourceFile(root, link.File)
+			if !ok {
+				continue
+			}
+			link.File = rel
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// projectSourceFile maps a file named in log output to a slash-separated path
+// relative to the project root, if it is a regular file inside the project
+func projectSourceFile(root, file string) (string, bool) {
+	rel, err := testSourcePath(root, file)
+	if err != nil {
+		return "", false
+	}
+
+	// SECURITY: Resolve symlinks so the link can't lead out of the project
+	resolved, err := security.ValidateProjectPath(filepath.Join(root, rel))
+	if err != nil {
+		return "", false
+	}
+	if r, err := filepath.Rel(root, resolved); err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	if info, err := os.Stat(resolved); err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// ClearLogs clears all stored logs
+func (a *App) ClearLogs() error {
+	a.logMu.Lock()
+	defer a.logMu.Unlock()
+
+	a.logs = make([]LogEntry, 0)
+	a.emit("logs:cleared", nil)
+	return nil
+}
+
+// GetProjectInfo returns information about the current project
+func (a *App) GetProjectInfo() map[string]interface{} {
+	info := map[string]interface{}{
+		"directory": a.projectDir,
+	}
+
+	if a.config != nil {
+		info["framework"] = a.config.Framework
+		info["projectName"] = a.config.ProjectName
+	}
+
+	return info
+}
+
+// GetGlobalConfig returns the machine-wide defaults from ~/.config/caboose/config.toml.
+// Process environments are redacted; SaveGlobalConfig doesn't save processes.
+func (a *App) GetGlobalConfig() (*config.Config, error) {
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return nil, err
+	}
+
+	redactor := security.NewRedactor(cfg.Security.RedactEnvPatterns)
+	for name, proc := range cfg.Processes {
+		proc.Environment = redactor.RedactEnv(proc.Environment)
+		cfg.Processes[name] = proc
+	}
+	return cfg, nil
+}
+
+// SaveGlobalConfig saves machine-wide defaults and reloads the merged project config
+func (a *App) SaveGlobalConfig(cfg *config.Config) error {
+	if cfg == nil {
+		return fmt.Errorf("config is required")
+	}
+
+	// Project identity and processes only make sense per project
+	cfg.Framework = ""
+	cfg.ProjectName = ""
+	cfg.Processes = nil
+
+	log.Printf("[AUDIT] SaveGlobalConfig")
+
+	if err := cfg.SaveGlobal(); err != nil {
+		return fmt.Errorf("failed to save global config: %w", err)
+	}
+
+	if a.config == nil || a.projectDir == "" {
+		return nil
+	}
+
+	// Reloading reads the project file, so write out any pending changes first
+	if err := a.configSaver.Flush(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	reloaded, err := config.Load(a.projectDir)
+	if err != nil {
+		return err
+	}
+
+	// Update in place: managers hold pointers into the current config
+	*a.config = *reloaded
+	a.applyDatabaseConfig()
+	a.applyLogConfig()
+	a.applySecurityConfig()
+	a.applyMetricsConfig()
+	a.applySuspendConfig()
+	a.applyAutoSaveConfig()
+	return nil
+}
+
+// SetProjectDirectory changes the project directory
+func (a *App) SetProjectDirectory(dir string) error {
+	// SECURITY: Validate project directory path
+	validatedDir, err := security.ValidateProjectPath(dir)
+	if err != nil {
+		log.Printf("[SECURITY] Invalid project directory: %s, error: %v", dir, err)
+		return fmt.Errorf("invalid project directory: %w", err)
+	}
+
+	// Log directory change for audit
+	log.Printf("[AUDIT] SetProjectDirectory: old=%s, new=%s", a.projectDir, validatedDir)
+
+	// Stop all running processes first
+	if a.processManager != nil {
+		a.processManager.Shutdown()
+	}
+
+	// Reinitialize
+	a.processManager = process.NewManager()
+	a.wireProcessManager()
+
+	if err := a.loadProjectConfig(validatedDir); err != nil {
+		return err
+	}
+
+	name := ""
+	if a.config != nil {
+		name = a.config.ProjectName
+	}
+	if err := config.RecordRecentProject(validatedDir, name); err != nil {
+		log.Printf("[ERROR] Failed to record recent project: %v", err)
+	}
+	return nil
+}
+
+// GetRecentProjects returns recently opened projects, most recent first.
+// Projects whose directory no longer exists are marked missing.
+func (a *App) GetRecentProjects() ([]config.RecentProject, error) {
+	return config.RecentProjects()
+}
+
+// OpenRecentProject switches to a project from the recent projects list
+func (a *App) OpenRecentProject(path string) error {
+	projects, err := config.RecentProjects()
+	if err != nil {
+		return err
+	}
+
+	for _, project := range projects {
+		if project.Path != path {
+			continue
+		}
+		if project.Missing {
+			return fmt.Errorf("project directory no longer exists: %s", path)
+		}
+		return a.SetProjectDirectory(path)
+	}
+	return fmt.Errorf("not a recent project: %s", path)
+}
+
+// SelectProjectDirectory opens a directory picker dialog
+func (a *App) SelectProjectDirectory() (string, error) {
+	dir, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Select Project Directory",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if dir != "" {
+		if err := a.SetProjectDirectory(dir); err != nil {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// Helper functions
+
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+func formatCommand(cmd string, args []string) string {
+	result := cmd
+	for _, arg := range args {
+		result += " " + arg
+	}
+	return result
+}
+
+func detectProcessType(command string) string {
+	switch command {
+	case "bundle", "rails", "ruby":
+		return "rails"
+	case "npm", "yarn", "pnpm", "node":
+		return "frontend"
+	case "sidekiq", "good_job", "delayed_job":
+		return "worker"
+	case "postgres", "mysql", "redis":
+		return "database"
+	case "docker", "docker-compose":
+		return "docker"
+	default:
+		return "custom"
+	}
+}
+
+// railsCommandTimeout bounds how long a one-off rails command may run
+const railsCommandTimeout = 10 * time.Minute
+
+// railsRunCounter makes one-off process names unique
+var railsRunCounter uint64
+
+// RunRailsCommand runs a one-off rails subcommand (runner, db:seed, a rake
+// task, ...) as a short-lived process and returns its output and exit code.
+// Tasks that destroy data (db:reset, db:seed:replant, ...) need explicit
+// confirmation.
+func (a *App) RunRailsCommand(subcommand string, args []string, confirmed bool) (*process.RunResult, error) {
+	if a.processManager == nil {
+		return nil, fmt.Errorf("process manager not initialized")
+	}
+
+	if !a.rateLimiter.Allow("process") {
+		return nil, fmt.Errorf("rate limit exceeded: too many process operations")
+	}
+
+	// SECURITY: Validate subcommand is in whitelist
+	if err := security.ValidateRailsSubcommand(subcommand, confirmed); err != nil {
+		log.Printf("[SECURITY] Blocked rails subcommand: %s", subcommand)
+		return nil, fmt.Errorf("security error: %w", err)
+	}
+
+	// SECURITY: Validate arguments don't contain shell metacharacters. The
+	// runner script is Ruby source and is exempt; it is passed straight to
+	// exec without a shell.
+	checked := args
+	if subcommand == "runner" && len(args) > 0 {
+		checked = args[1:]
+	}
+	if err := security.ValidateArguments(checked); err != nil {
+		log.Printf("[SECURITY] Blocked dangerous arguments: %v", args)
+		return nil, fmt.Errorf("security error: %w", err)
+	}
+
+	name := fmt.Sprintf("rails-run-%d", atomic.AddUint64(&railsRunCounter, 1))
+	config := models.ProcessConfig{
+		Name:       name,
+		Command:    "bundle",
+		Args:       append([]string{"exec", "rails", subcommand}, args...),
+		WorkingDir: a.projectDir,
+		Color:      "#f97316", // orange
+	}
+
+	// Log one-off commands for audit
+	log.Printf("[AUDIT] RunRailsCommand: name=%s, subcommand=%s, args=%v, destructive=%v", name, subcommand, args, security.DestructiveRailsTasks[subcommand])
+
+	result, err := a.processManager.RunOnce(config, railsCommandTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.TimedOut {
+		log.Printf("[ERROR] Rails command %s timed out after %s", name, railsCommandTimeout)
+	}
+
+	return result, nil
+}
+
+// StartRailsConsole starts an interactive Rails console process
+func (a *App) StartRailsConsole() error {
+	if a.processManager == nil {
+		return fmt.Errorf("process manager not initialized")
+	}
+
+	// Stop existing console if running
+	if p, exists := a.processManager.GetProcess("rails-console"); exists {
+		if p.Status == models.ProcessStatusRunning {
+			a.processManager.Stop("rails-console")
+		}
+		a.processManager.RemoveProcess("rails-console")
+	}
+
+	// Create rails console config
+	config := models.ProcessConfig{
+		Name:       "rails-console",
+		Command:    "bundle",
+		Args:       []string{"exec", "rails", "console"},
+		WorkingDir: a.projectDir,
+		UsePTY:     true,
+		Color:      "#ef4444", // red
+	}
+
+	// Add and start
+	if err := a.processManager.AddProcess(config); err != nil {
+		return err
+	}
+
+	return a.processManager.Start("rails-console")
+}
+
+// StopRailsConsole stops the Rails console process
+func (a *App) StopRailsConsole() error {
+	if a.processManager == nil {
+		return fmt.Errorf("process manager not initialized")
+	}
+
+	return a.processManager.Stop("rails-console")
+}
+
+// IsRailsConsoleRunning checks if Rails console is running
+func (a *App) IsRailsConsoleRunning() bool {
+	if a.processManager == nil {
+		return false
+	}
+
+	p, exists := a.processManager.GetProcess("rails-console")
+	return exists && p.Status == models.ProcessStatusRunning
+}
+
+// WriteToRailsConsole writes input to the Rails console PTY
+func (a *App) WriteToRailsConsole(input string) error {
+	if a.processManager == nil {
+		return fmt.Errorf("process manager not initialized")
+	}
+
+	if a.holdMultilinePaste("rails-console", input) {
+		return nil
+	}
+
+	return a.processManager.WriteToPTY("rails-console", []byte(input))
+}
+
+// ResizeRailsConsole resizes the Rails console PTY window
+func (a *App) ResizeRailsConsole(rows, cols int) error {
+	if a.processManager == nil {
+		return fmt.Errorf("process manager not initialized")
+	}
+
+	return a.processManager.ResizePTY("rails-console", uint16(rows), uint16(cols))
 }
 
 // ================== Database Methods ==================
 
-// ConnectDatabase connects to a database
-func (a *App) ConnectDatabase(configMap map[string]interface{}) error {
+// ConnectDatabase connects to a database
+func (a *App) ConnectDatabase(configMap map[string]interface{}) error {
+	if a.databaseManager == nil {
+		return fmt.Errorf("database manager not initialized")
+	}
+
+	config, err := connectionConfigFromMap(configMap)
+	if err != nil {
+		return err
+	}
+
+	// Log connection attempt (without password) for audit
+	log.Printf("[AUDIT] ConnectDatabase: driver=%s, host=%s, database=%s, ssl=%s, statement_timeout=%dms",
+		config.Driver, config.Host, config.Database, config.SSLMode, config.StatementTimeout)
+
+	if err := a.databaseManager.Connect(config); err != nil {
+		// Sanitize error before returning
+		log.Printf("[ERROR] Database connection failed: %v", err)
+		return security.SanitizeError(err, false)
+	}
+
+	// Clear password from memory
+	config.Password = ""
+
+	// Emit connection status event
+	a.emit("database:connected", a.databaseManager.GetStatus())
+
+	return nil
+}
+
+// DetectDatabaseConnection suggests connection settings from the framework's
+// own config (config/database.yml for Rails) to pre-fill the connection form.
+// It never connects.
+func (a *App) DetectDatabaseConnection() (*database.ConnectionConfig, error) {
+	detector, ok := a.currentPlugin.(interface {
+		DetectDatabaseConfig(projectPath, env string) (*database.ConnectionConfig, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("database detection not supported for framework: %s", a.frameworkName)
+	}
+
+	config, err := detector.DetectDatabaseConfig(a.projectDir, "")
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Database] Detected %s connection: driver=%s, host=%s, database=%s",
+		a.frameworkName, config.Driver, config.Host, config.Database)
+
+	return config, nil
+}
+
+// ParseConnectionURL parses a pasted connection URL (mysql://, postgres://,
+// a DATABASE_URL line) into settings for the connection form. Nothing is
+// connected or saved.
+func (a *App) ParseConnectionURL(rawURL string) (database.ConnectionConfig, error) {
+	config, err := database.ParseConnectionURL(rawURL)
+	if err != nil {
+		return database.ConnectionConfig{}, err
+	}
+
+	// SECURITY: Validate SSL mode
+	if err := security.ValidateSSLMode(config.SSLMode); err != nil {
+		log.Printf("[SECURITY] Invalid SSL mode in connection URL: %s", config.SSLMode)
+		return database.ConnectionConfig{}, fmt.Errorf("security error: %w", err)
+	}
+
+	return config, nil
+}
+
+// connectionConfigFromMap builds a connection config from frontend params
+func connectionConfigFromMap(configMap map[string]interface{}) (database.ConnectionConfig, error) {
+	sslMode := getString(configMap, "sslMode")
+
+	// SECURITY: Validate SSL mode
+	if err := security.ValidateSSLMode(sslMode); err != nil {
+		log.Printf("[SECURITY] Invalid SSL mode: %s", sslMode)
+		return database.ConnectionConfig{}, fmt.Errorf("security error: %w", err)
+	}
+
+	// SECURITY: Certificate files must be absolute paths to readable files
+	certFiles := map[string]string{
+		"sslRootCert": getString(configMap, "sslRootCert"),
+		"sslCert":     getString(configMap, "sslCert"),
+		"sslKey":      getString(configMap, "sslKey"),
+	}
+	for key, path := range certFiles {
+		if path == "" {
+			continue
+		}
+		resolved, err := security.ValidateCertFile(path)
+		if err != nil {
+			log.Printf("[SECURITY] Invalid %s: %s", key, path)
+			return database.ConnectionConfig{}, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		certFiles[key] = resolved
+	}
+
+	statementTimeout := getInt(configMap, "statementTimeout")
+	if statementTimeout < 0 {
+		return database.ConnectionConfig{}, fmt.Errorf("invalid statement timeout: %d", statementTimeout)
+	}
+
+	return database.ConnectionConfig{
+		Driver:      getString(configMap, "driver"),
+		Host:        getString(configMap, "host"),
+		Port:        getInt(configMap, "port"),
+		User:        getString(configMap, "user"),
+		Password:    getString(configMap, "password"),
+		Database:    getString(configMap, "database"),
+		SSLMode:     sslMode,
+		SSLRootCert: certFiles["sslRootCert"],
+		SSLCert:     certFiles["sslCert"],
+		SSLKey:      certFiles["sslKey"],
+		Name:        getString(configMap, "name"),
+		ReadOnly:    getBool(configMap, "readOnly"),
+
+		StatementTimeout: statementTimeout,
+	}, nil
+}
+
+// ConnectNamedDatabase opens an additional connection alongside the primary one,
+// e.g. staging next to production for schema comparison
+func (a *App) ConnectNamedDatabase(configMap map[string]interface{}) error {
+	config, err := connectionConfigFromMap(configMap)
+	if err != nil {
+		return err
+	}
+	if config.Name == "" {
+		return fmt.Errorf("connection name is required")
+	}
+
+	log.Printf("[AUDIT] ConnectNamedDatabase: name=%s, driver=%s, host=%s, database=%s, ssl=%s",
+		config.Name, config.Driver, config.Host, config.Database, config.SSLMode)
+
+	manager := database.NewManager()
+	manager.SetSlowQueryThreshold(a.databaseManager.SlowQueryThreshold())
+	manager.SetKeepWarmInterval(a.keepWarmInterval())
+	if err := manager.Connect(config); err != nil {
+		log.Printf("[ERROR] Database connection failed: %v", err)
+		return security.SanitizeError(err, false)
+	}
+	config.Password = ""
+
+	a.namedDbMu.Lock()
+	if existing, ok := a.namedDatabases[config.Name]; ok {
+		existing.Disconnect()
+	}
+	a.namedDatabases[config.Name] = manager
+	a.namedDbMu.Unlock()
+
+	return nil
+}
+
+// DisconnectNamedDatabase closes an additional connection opened with ConnectNamedDatabase
+func (a *App) DisconnectNamedDatabase(name string) error {
+	a.namedDbMu.Lock()
+	manager, ok := a.namedDatabases[name]
+	delete(a.namedDatabases, name)
+	a.namedDbMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("connection not found: %s", name)
+	}
+	return manager.Disconnect()
+}
+
+// databaseByName resolves a connection name to its manager. An empty name or
+// the primary connection's name refers to the primary connection.
+func (a *App) databaseByName(name string) (*database.Manager, error) {
+	a.namedDbMu.Lock()
+	manager, ok := a.namedDatabases[name]
+	a.namedDbMu.Unlock()
+	if ok {
+		return manager, nil
+	}
+
+	if a.databaseManager != nil {
+		status := a.databaseManager.GetStatus()
+		if status.Connected && (name == "" || name == status.Name) {
+			return a.databaseManager, nil
+		}
+	}
+
+	return nil, fmt.Errorf("connection not found: %s", name)
+}
+
+// CompareSchemas returns the structural differences between two connections
+// (tables, columns and indexes). Both schemas are introspected in parallel.
+// token identifies the call to CancelSchemaIntrospection.
+func (a *App) CompareSchemas(connA, connB, token string) (*database.SchemaDiff, error) {
+	managerA, err := a.databaseByName(connA)
+	if err != nil {
+		return nil, err
+	}
+	managerB, err := a.databaseByName(connB)
+	if err != nil {
+		return nil, err
+	}
+	if managerA == managerB {
+		return nil, fmt.Errorf("cannot compare a connection with itself")
+	}
+
+	ctx, done, err := a.introspectionContext(token)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	snapshot := func(manager *database.Manager) func(ctx context.Context) (interface{}, error) {
+		return func(ctx context.Context) (interface{}, error) {
+			return manager.GetSchemaSnapshot(ctx)
+		}
+	}
+	results := a.workerPool.BatchWithContext(ctx, []workers.Task{
+		{ID: "schema-a", Execute: snapshot(managerA)},
+		{ID: "schema-b", Execute: snapshot(managerB)},
+	})
+
+	for _, result := range results {
+		if err := introspectionError(result.Error); err != nil {
+			return nil, err
+		}
+	}
+
+	diff := database.DiffSchemas(
+		results[0].Data.(*database.SchemaSnapshot),
+		results[1].Data.(*database.SchemaSnapshot),
+	)
+	diff.SourceA = connA
+	diff.SourceB = connB
+
+	return diff, nil
+}
+
+// DisconnectDatabase disconnects from the database
+func (a *App) DisconnectDatabase() error {
+	if a.databaseManager == nil {
+		return fmt.Errorf("database manager not initialized")
+	}
+
+	err := a.databaseManager.Disconnect()
+
+	// Emit disconnection event
+	a.emit("database:disconnected", nil)
+
+	return err
+}
+
+// GetDatabaseStatus returns the current database connection status
+func (a *App) GetDatabaseStatus() database.DatabaseStatus {
+	if a.databaseManager == nil {
+		return database.DatabaseStatus{Connected: false}
+	}
+
+	return a.databaseManager.GetStatus()
+}
+
+// GetDatabaseTables returns all tables in the connected database
+func (a *App) GetDatabaseTables() ([]database.TableInfo, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	return a.databaseManager.GetTables(context.Background())
+}
+
+// GetERModel returns the connected database's tables, columns, primary keys
+// and foreign keys as nodes and edges for an entity-relationship diagram.
+// Tables are introspected in parallel on the worker pool, and the model is
+// cached for a few minutes or until the connection changes. token identifies
+// the call to CancelSchemaIntrospection.
+func (a *App) GetERModel(token string) (*database.ERModel, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	if model := a.databaseManager.CachedERModel(); model != nil {
+		return model, nil
+	}
+
+	ctx, done, err := a.introspectionContext(token)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	tables, err := a.databaseManager.GetTables(ctx)
+	if err != nil {
+		return nil, introspectionError(err)
+	}
+	indexes, err := a.databaseManager.GetIndexes(ctx)
+	if err != nil {
+		return nil, introspectionError(err)
+	}
+
+	tasks := make([]workers.Task, len(tables))
+	for i, table := range tables {
+		tasks[i] = workers.Task{
+			ID: "er-" + table.Name,
+			Execute: func(ctx context.Context) (interface{}, error) {
+				columns, err := a.databaseManager.GetColumns(ctx, table.Name)
+				if err != nil {
+					return nil, err
+				}
+				foreignKeys, err := a.databaseManager.GetForeignKeys(ctx, table.Name)
+				if err != nil {
+					return nil, err
+				}
+				return database.ERTable{Info: table, Columns: columns, ForeignKeys: foreignKeys}, nil
+			},
+		}
+	}
+
+	erTables := make([]database.ERTable, 0, len(tables))
+	for _, result := range a.workerPool.BatchWithContext(ctx, tasks) {
+		if err := introspectionError(result.Error); err != nil {
+			return nil, err
+		}
+		erTables = append(erTables, result.Data.(database.ERTable))
+	}
+
+	model := database.BuildERModel(a.databaseManager.GetStatus().Database, erTables, indexes)
+	a.databaseManager.CacheERModel(model)
+
+	return model, nil
+}
+
+// CancelSchemaIntrospection cancels the multi-table introspection started
+// with token (ER model, schema compare), e.g. when the user navigates away
+// from the view that asked for it. Its queries are abandoned, tables not yet
+// introspected are skipped, and the call fails as cancelled. Other
+// introspections in flight carry on.
+func (a *App) CancelSchemaIntrospection(token string) {
+	a.introspectMu.Lock()
+	defer a.introspectMu.Unlock()
+
+	// The call itself releases the token once it returns
+	if cancel, ok := a.introspections[token]; ok {
+		cancel()
+	}
+}
+
+// cancelAllIntrospection cancels every introspection in flight, on shutdown
+func (a *App) cancelAllIntrospection() {
+	a.introspectMu.Lock()
+	defer a.introspectMu.Unlock()
+
+	for _, cancel := range a.introspections {
+		cancel()
+	}
+}
+
+// introspectionContext returns the context for one introspection batch,
+// registered under token for CancelSchemaIntrospection, and the func that
+// releases it once the batch is done. An empty token can't be cancelled.
+func (a *App) introspectionContext(token string) (context.Context, func(), error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if token == "" {
+		return ctx, cancel, nil
+	}
+
+	a.introspectMu.Lock()
+	defer a.introspectMu.Unlock()
+
+	if _, exists := a.introspections[token]; exists {
+		cancel()
+		return nil, nil, fmt.Errorf("schema introspection %s is already running", token)
+	}
+	a.introspections[token] = cancel
+	return ctx, func() {
+		a.introspectMu.Lock()
+		delete(a.introspections, token)
+		a.introspectMu.Unlock()
+		cancel()
+	}, nil
+}
+
+// introspectionError turns a failed introspection task's error into the one
+// returned to the frontend
+func introspectionError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("schema introspection cancelled")
+	}
+	return security.SanitizeError(err, false)
+}
+
+// GetTableColumns returns columns for a specific table
+func (a *App) GetTableColumns(tableName string) ([]database.ColumnInfo, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	return a.databaseManager.GetColumns(context.Background(), tableName)
+}
+
+// GetTableView returns the saved data grid layout of a table on the current
+// connection, or an empty one if none was saved
+func (a *App) GetTableView(table string) (*config.TableView, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+	connection := a.databaseManager.ConnectionKey()
+	if connection == "" {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	if a.config != nil {
+		if view := a.config.Database.FindTableView(connection, table); view != nil {
+			saved := *view
+			return &saved, nil
+		}
+	}
+	return &config.TableView{Connection: connection, Table: table}, nil
+}
+
+// SaveTableView saves a table's data grid layout (column order, hidden
+// columns and sort) for the current connection. BrowseTable sorts by it
+// unless told otherwise.
+func (a *App) SaveTableView(table string, viewMap map[string]interface{}) error {
+	if a.databaseManager == nil {
+		return fmt.Errorf("database manager not initialized")
+	}
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+	connection := a.databaseManager.ConnectionKey()
+	if connection == "" {
+		return fmt.Errorf("not connected to database")
+	}
+	if table == "" {
+		return fmt.Errorf("table name is required")
+	}
+
+	view := config.TableView{
+		Connection:     connection,
+		Table:          table,
+		Columns:        getStrings(viewMap, "columns"),
+		Hidden:         getStrings(viewMap, "hidden"),
+		SortColumn:     getString(viewMap, "sortColumn"),
+		SortDescending: getBool(viewMap, "sortDescending"),
+	}
+
+	if existing := a.config.Database.FindTableView(connection, table); existing != nil {
+		*existing = view
+	} else {
+		a.config.Database.TableViews = append(a.config.Database.TableViews, view)
+	}
+	a.saveConfig()
+
+	return nil
+}
+
+// BrowseTable returns up to limit rows of a table for the data grid, sorted
+// by sortColumn, or by the table's saved view when sortColumn is empty
+func (a *App) BrowseTable(table, sortColumn string, descending bool, limit int) (*database.QueryResult, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	if !a.rateLimiter.Allow("query") {
+		return nil, fmt.Errorf("rate limit exceeded: too many query requests")
+	}
+
+	if sortColumn == "" && a.config != nil {
+		view := a.config.Database.FindTableView(a.databaseManager.ConnectionKey(), table)
+		if view != nil && view.SortColumn != "" && a.hasColumn(table, view.SortColumn) {
+			sortColumn, descending = view.SortColumn, view.SortDescending
+		}
+	}
+
+	result, err := a.databaseManager.BrowseTable(table, sortColumn, descending, limit)
+	if err != nil {
+		log.Printf("[ERROR] Browse table failed: %v", err)
+		return nil, security.SanitizeError(err, false)
+	}
+
+	return result, nil
+}
+
+// hasColumn reports whether a table still has a column, so a saved sort on
+// a dropped column is ignored rather than failing the browse
+func (a *App) hasColumn(table, column string) bool {
+	columns, err := a.databaseManager.GetColumns(context.Background(), table)
+	if err != nil {
+		return false
+	}
+	for _, col := range columns {
+		if col.Name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Bounds for GetColumnStats so profiling stays cheap on large tables
+const (
+	columnStatsTopN    = 20
+	columnStatsScanCap = 100000
+)
+
+// GetColumnStats returns value statistics for a column (using worker pool)
+func (a *App) GetColumnStats(table, column string) (*database.ColumnStats, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	result := a.workerPool.SubmitAndWait("column-stats", func(ctx context.Context) (interface{}, error) {
+		return a.databaseManager.GetColumnStats(table, column, columnStatsTopN, columnStatsScanCap)
+	})
+
+	if result.Error != nil {
+		log.Printf("[ERROR] Column stats failed: %v", result.Error)
+		return nil, security.SanitizeError(result.Error, false)
+	}
+
+	return result.Data.(*database.ColumnStats), nil
+}
+
+// QueryJSONPath returns rows of a table that have a value at a path inside a
+// JSON column, such as $.address.city or tags[0], with the value alongside.
+// The path is turned into the connected database's own JSON syntax.
+func (a *App) QueryJSONPath(table, column, jsonPath string, limit int) (*database.QueryResult, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	if !a.rateLimiter.Allow("query") {
+		log.Printf("[SECURITY] Rate limit exceeded for query")
+		return nil, fmt.Errorf("rate limit exceeded: too many query requests")
+	}
+
+	result := a.workerPool.SubmitAndWait("json-path-query", func(ctx context.Context) (interface{}, error) {
+		return a.databaseManager.QueryJSONPath(table, column, jsonPath, limit)
+	})
+
+	if result.Error != nil {
+		log.Printf("[ERROR] JSON path query failed: %v", result.Error)
+		return nil, security.SanitizeError(result.Error, false)
+	}
+
+	return result.Data.(*database.QueryResult), nil
+}
+
+// ExecuteDatabaseQuery executes a SQL query (using worker pool for heavy queries)
+func (a *App) ExecuteDatabaseQuery(query string, limit int) (*database.QueryResult, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	// Rate limit database queries
+	if !a.rateLimiter.Allow("query") {
+		log.Printf("[SECURITY] Rate limit exceeded for query")
+		return nil, fmt.Errorf("rate limit exceeded: too many query requests")
+	}
+
+	// SECURITY: Read-only connections take no writes, confirmed or not
+	if a.databaseManager.IsReadOnly() && !database.IsReadOnlyQuery(query) {
+		log.Printf("[SECURITY] Write rejected on read-only connection: %s", query[:min(50, len(query))])
+		return nil, database.ErrReadOnly
+	}
+
+	// SECURITY: Check if query is destructive
+	if a.isDestructiveQuery(query) {
+		log.Printf("[SECURITY] Destructive query detected: %s", query[:min(50, len(query))])
+		return nil, fmt.Errorf("destructive query requires explicit confirmation (use ConfirmAndExecuteQuery instead)")
+	}
+
+	// Log query execution for audit
+	log.Printf("[AUDIT] ExecuteQuery: query=%s...", query[:min(50, len(query))])
+
+	// For heavy queries, use worker pool
+	if limit > 1000 || len(query) > 500 {
+		result := a.runWatchedQuery("query-exec", query, func(ctx context.Context) (interface{}, error) {
+			return a.databaseManager.ExecuteQuery(query, limit)
+		})
+
+		if result.Error != nil {
+			// Sanitize error before returning
+			log.Printf("[ERROR] Query execution failed: %v", result.Error)
+			return nil, security.SanitizeError(result.Error, false)
+		}
+
+		return result.Data.(*database.QueryResult), nil
+	}
+
+	result, err := a.databaseManager.ExecuteQuery(query, limit)
+	if err != nil {
+		log.Printf("[ERROR] Query execution failed: %v", err)
+		return nil, security.SanitizeError(err, false)
+	}
+
+	return result, nil
+}
+
+// queryWarnInterval is how long a pooled query runs before the UI is told it
+// is slow, and how often it is reminded while the query keeps running
+const queryWarnInterval = 5 * time.Second
+
+// runWatchedQuery runs a query task on the worker pool. While it runs past
+// queryWarnInterval, database:query-slow is emitted with the elapsed time at
+// each interval, and database:query-timeout if the pool gives up on it. Both
+// carry an ID for the run so the UI can track it.
+func (a *App) runWatchedQuery(taskID, query string, fn func(ctx context.Context) (interface{}, error)) workers.TaskResult {
+	queryID := uuid.New().String()
+	preview := query[:min(100, len(query))]
+	start := time.Now()
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(queryWarnInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				select {
+				case <-done:
+					return // Finished as the tick fired
+				default:
+				}
+				a.emit("database:query-slow", map[string]interface{}{
+					"id":      queryID,
+					"query":   preview,
+					"elapsed": int(time.Since(start).Seconds()),
+					"timeout": int(workers.TaskTimeout.Seconds()),
+				})
+			}
+		}
+	}()
+
+	result := a.workerPool.SubmitAndWait(taskID, fn)
+	close(done)
+
+	if errors.Is(result.Error, context.DeadlineExceeded) {
+		log.Printf("[Database] Query %s timed out after %s: %s", queryID, workers.TaskTimeout, preview)
+		a.emit("database:query-timeout", map[string]interface{}{
+			"id":      queryID,
+			"query":   preview,
+			"timeout": int(workers.TaskTimeout.Seconds()),
+		})
+	}
+
+	return result
+}
+
+// ConfirmAndExecuteQuery executes a destructive query after explicit confirmation
+func (a *App) ConfirmAndExecuteQuery(query string, limit int, confirmed bool) (*database.QueryResult, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	// Rate limit
+	if !a.rateLimiter.Allow("query") {
+		return nil, fmt.Errorf("rate limit exceeded")
+	}
+
+	// SECURITY: Read-only connections take no writes, confirmed or not
+	if a.databaseManager.IsReadOnly() && !database.IsReadOnlyQuery(query) {
+		log.Printf("[SECURITY] Write rejected on read-only connection: %s", query[:min(50, len(query))])
+		return nil, database.ErrReadOnly
+	}
+
+	// Require explicit confirmation
+	if !confirmed {
+		return nil, fmt.Errorf("destructive query requires confirmation")
+	}
+
+	// Log destructive query execution
+	log.Printf("[AUDIT] DESTRUCTIVE QUERY CONFIRMED: %s", query)
+
+	result, err := a.databaseManager.ExecuteQuery(query, limit)
+	if err != nil {
+		log.Printf("[ERROR] Destructive query failed: %v", err)
+		return nil, security.SanitizeError(err, false)
+	}
+
+	return result, nil
+}
+
+// maintenanceTimeout bounds a table maintenance operation, which rewrites
+// the table and can take far longer than a query
+const maintenanceTimeout = 30 * time.Minute
+
+// RunTableMaintenance runs a maintenance operation on a table: optimize or
+// analyze on MySQL. They lock or rewrite the table, so they need explicit confirmation. Returns
+// the server's messages and the table's size before and after.
+func (a *App) RunTableMaintenance(table, operation string, confirmed bool) (*database.MaintenanceResult, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+	if a.workerPool == nil {
+		return nil, fmt.Errorf("worker pool not initialized")
+	}
+
+	// Rate limit
+	if !a.rateLimiter.Allow("query") {
+		return nil, fmt.Errorf("rate limit exceeded")
+	}
+
+	// SECURITY: Read-only connections take no maintenance, confirmed or not
+	if a.databaseManager.IsReadOnly() {
+		log.Printf("[SECURITY] Maintenance rejected on read-only connection: %s %s", operation, table)
+		return nil, database.ErrReadOnly
+	}
+
+	// Require explicit confirmation
+	if !confirmed {
+		return nil, fmt.Errorf("table maintenance requires confirmation")
+	}
+
+	log.Printf("[AUDIT] TABLE MAINTENANCE CONFIRMED: table=%s, operation=%s", table, operation)
+
+	result := a.workerPool.SubmitAndWaitTimeout("table-maintenance", maintenanceTimeout, func(ctx context.Context) (interface{}, error) {
+		return a.databaseManager.RunMaintenance(ctx, table, operation)
+	})
+	if result.Error != nil {
+		log.Printf("[ERROR] Table maintenance failed: %v", result.Error)
+		return nil, result.Error
+	}
+
+	return result.Data.(*database.MaintenanceResult), nil
+}
+
+const (
+	// scriptTimeout bounds a RunSQLFile run, which may hold a migration's
+	// worth of statements
+	scriptTimeout = 30 * time.Minute
+
+	// maxScriptSize bounds the SQL files RunSQLFile reads
+	maxScriptSize = 10 * 1024 * 1024
+)
+
+// RunSQLFile runs the statements of a SQL file in the project, in order and
+// in one transaction; see database.SplitScript for how the file is split.
+// With stopOnError the first failure rolls everything back, otherwise the
+// remaining statements still run and the successful ones are committed.
+// Like any destructive query, a script containing one needs confirmation.
+// Scripts with their own BEGIN/COMMIT/ROLLBACK are refused.
+func (a *App) RunSQLFile(path string, stopOnError, confirmed bool) (*database.ScriptResult, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+	if a.workerPool == nil {
+		return nil, fmt.Errorf("worker pool not initialized")
+	}
+	if a.projectDir == "" {
+		return nil, fmt.Errorf("no project directory")
+	}
+
+	// Rate limit
+	if !a.rateLimiter.Allow("query") {
+		return nil, fmt.Errorf("rate limit exceeded")
+	}
+
+	// SECURITY: Only files inside the project, symlinks resolved
+	root, err := security.ValidateProjectPath(a.projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(path) {
+		if err := security.ValidateRelativePath(path); err != nil {
+			return nil, fmt.Errorf("invalid SQL file: %w", err)
+		}
+		path = filepath.Join(root, path)
+	}
+	resolved, err := security.ValidateProjectPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		log.Printf("[SECURITY] RunSQLFile rejected path outside the project: %s", path)
+		return nil, fmt.Errorf("SQL file is not in the project: %s", path)
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SQL file: %w", err)
+	}
+	if info.Size() > maxScriptSize {
+		return nil, fmt.Errorf("SQL file is too large (%d bytes, limit %d)", info.Size(), maxScriptSize)
+	}
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SQL file: %w", err)
+	}
+
+	statements, err := database.SplitScript(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("no statements in %s", filepath.Base(resolved))
+	}
+
+	destructive := 0
+	for _, stmt := range statements {
+		// SECURITY: Read-only connections take no writes, confirmed or not
+		if a.databaseManager.IsReadOnly() && !database.IsReadOnlyQuery(stmt.SQL) {
+			log.Printf("[SECURITY] Write rejected on read-only connection: %s line %d", resolved, stmt.Line)
+			return nil, database.ErrReadOnly
+		}
+		if a.isDestructiveQuery(stmt.SQL) {
+			destructive++
+		}
+	}
+	if destructive > 0 && !confirmed {
+		return nil, fmt.Errorf("script has %d destructive statement(s) and requires confirmation", destructive)
+	}
+
+	log.Printf("[AUDIT] RunSQLFile: file=%s, statements=%d, destructive=%d, stopOnError=%t",
+		resolved, len(statements), destructive, stopOnError)
+
+	result := a.workerPool.SubmitAndWaitTimeout("sql-file", scriptTimeout, func(ctx context.Context) (interface{}, error) {
+		return a.databaseManager.RunScript(ctx, statements, stopOnError)
+	})
+	if result.Error != nil {
+		log.Printf("[ERROR] SQL file failed: %v", result.Error)
+		return nil, result.Error
+	}
+
+	return result.Data.(*database.ScriptResult), nil
+}
+
+// UpdateRow updates a single table row identified by its primary key, for
+// inline cell edits. Like any data change it needs explicit confirmation.
+func (a *App) UpdateRow(table string, pk map[string]interface{}, changes map[string]interface{}, confirmed bool) (int64, error) {
+	if a.databaseManager == nil {
+		return 0, fmt.Errorf("database manager not initialized")
+	}
+
+	// Rate limit
+	if !a.rateLimiter.Allow("query") {
+		return 0, fmt.Errorf("rate limit exceeded")
+	}
+
+	// Require explicit confirmation
+	if !confirmed {
+		return 0, fmt.Errorf("row update requires confirmation")
+	}
+
+	columns := make([]string, 0, len(changes))
+	for name := range changes {
+		columns = append(columns, name)
+	}
+	sort.Strings(columns)
+	log.Printf("[AUDIT] UPDATE ROW CONFIRMED: table=%s, pk=%v, columns=%s", table, pk, strings.Join(columns, ","))
+
+	affected, err := a.databaseManager.UpdateRow(table, pk, changes)
+	if err != nil {
+		log.Printf("[ERROR] Row update failed: %v", err)
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// InsertRow inserts a table row from the data browser and returns it as
+// stored, with defaults and any generated key filled in
+func (a *App) InsertRow(table string, values map[string]interface{}) (map[string]interface{}, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	// Rate limit
+	if !a.rateLimiter.Allow("query") {
+		return nil, fmt.Errorf("rate limit exceeded")
+	}
+
+	columns := make([]string, 0, len(values))
+	for name := range values {
+		columns = append(columns, name)
+	}
+	sort.Strings(columns)
+	log.Printf("[AUDIT] InsertRow: table=%s, columns=%s", table, strings.Join(columns, ","))
+
+	row, err := a.databaseManager.InsertRow(table, values)
+	if err != nil {
+		log.Printf("[ERROR] Row insert failed: %v", err)
+		return nil, err
+	}
+
+	return row, nil
+}
+
+// DeleteRow deletes a single table row identified by its primary key, after
+// explicit confirmation
+func (a *App) DeleteRow(table string, pk map[string]interface{}, confirmed bool) (int64, error) {
+	if a.databaseManager == nil {
+		return 0, fmt.Errorf("database manager not initialized")
+	}
+
+	// Rate limit
+	if !a.rateLimiter.Allow("query") {
+		return 0, fmt.Errorf("rate limit exceeded")
+	}
+
+	// Require explicit confirmation
+	if !confirmed {
+		return 0, fmt.Errorf("row delete requires confirmation")
+	}
+
+	log.Printf("[AUDIT] DELETE ROW CONFIRMED: table=%s, pk=%v", table, pk)
+
+	affected, err := a.databaseManager.DeleteRow(table, pk)
+	if err != nil {
+		log.Printf("[ERROR] Row delete failed: %v", err)
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// Helper function
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// ExplainDatabaseQuery returns the execution plan for a query (using worker pool)
+func (a *App) ExplainDatabaseQuery(query string) (*database.ExplainResult, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	// Use worker pool for EXPLAIN analysis
+	result := a.runWatchedQuery("query-explain", query, func(ctx context.Context) (interface{}, error) {
+		return a.databaseManager.ExplainQuery(query)
+	})
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return result.Data.(*database.ExplainResult), nil
+}
+
+// GetRunnableQuery returns a concrete example of a query fingerprint from the
+// statistics, with logged bind values filled in, ready to paste into the
+// editor. The fingerprint's last EXPLAIN is included when one was run.
+func (a *App) GetRunnableQuery(fingerprint string) (*database.RunnableQuery, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+	return a.databaseManager.RunnableQuery(fingerprint)
+}
+
+// FormatSQL pretty-prints a query for display in the editor (no database round-trip)
+func (a *App) FormatSQL(query string) (string, error) {
+	return database.FormatSQL(query)
+}
+
+// LintQuery warns about suspicious patterns in a query, such as OR 1=1 or a
+// commented-out WHERE clause, for the editor to show inline. Nothing is blocked
+// and the database isn't contacted.
+func (a *App) LintQuery(query string) []database.LintWarning {
+	return database.LintQuery(query)
+}
+
+// SaveDatabaseQuery saves a query to history
+func (a *App) SaveDatabaseQuery(name, sql string) *database.SavedQuery {
+	if a.databaseManager == nil {
+		return nil
+	}
+
+	query := a.databaseManager.SaveQuery(name, sql)
+
+	// Also save to config for persistence
+	if a.config != nil {
+		a.config.Database.SavedQueries = append(a.config.Database.SavedQueries, config.SavedQuery{
+			ID:        query.ID,
+			Name:      query.Name,
+			SQL:       query.SQL,
+			CreatedAt: query.CreatedAt,
+		})
+		a.saveConfig()
+	}
+
+	return query
+}
+
+// GetSavedQueries returns all saved queries
+func (a *App) GetSavedQueries() []database.SavedQuery {
+	if a.databaseManager == nil {
+		return []database.SavedQuery{}
+	}
+
+	return a.databaseManager.GetQueryHistory()
+}
+
+// DeleteSavedQuery deletes a saved query
+func (a *App) DeleteSavedQuery(id string) error {
+	if a.databaseManager == nil {
+		return fmt.Errorf("database manager not initialized")
+	}
+
+	err := a.databaseManager.DeleteQuery(id)
+	if err != nil {
+		return err
+	}
+
+	// Also remove from config
+	if a.config != nil {
+		newQueries := make([]config.SavedQuery, 0)
+		for _, q := range a.config.Database.SavedQueries {
+			if q.ID != id {
+				newQueries = append(newQueries, q)
+			}
+		}
+		a.config.Database.SavedQueries = newQueries
+		a.saveConfig()
+	}
+
+	return nil
+}
+
+// GetSavedConnections returns saved database connections (without passwords)
+func (a *App) GetSavedConnections() []config.DatabaseConnection {
+	if a.config == nil {
+		return []config.DatabaseConnection{}
+	}
+
+	return a.config.Database.Connections
+}
+
+// SaveDatabaseConnection saves a database connection config
+func (a *App) SaveDatabaseConnection(connMap map[string]interface{}) error {
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+
+	conn := config.DatabaseConnection{
+		Name:        getString(connMap, "name"),
+		Driver:      getString(connMap, "driver"),
+		Host:        getString(connMap, "host"),
+		Port:        getInt(connMap, "port"),
+		User:        getString(connMap, "user"),
+		Database:    getString(connMap, "database"),
+		SSLMode:     getString(connMap, "sslMode"),
+		SSLRootCert: getString(connMap, "sslRootCert"),
+		SSLCert:     getString(connMap, "sslCert"),
+		SSLKey:      getString(connMap, "sslKey"),
+
+		StatementTimeout: getInt(connMap, "statementTimeout"),
+	}
+	if conn.StatementTimeout < 0 {
+		return fmt.Errorf("invalid statement timeout: %d", conn.StatementTimeout)
+	}
+
+	// Check if connection with same name exists, update it
+	found := false
+	for i, c := range a.config.Database.Connections {
+		if c.Name == conn.Name {
+			a.config.Database.Connections[i] = conn
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		a.config.Database.Connections = append(a.config.Database.Connections, conn)
+	}
+
+	return a.saveConfig()
+}
+
+// DeleteSavedConnection deletes a saved database connection
+func (a *App) DeleteSavedConnection(name string) error {
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+
+	newConns := make([]config.DatabaseConnection, 0)
+	for _, c := range a.config.Database.Connections {
+		if c.Name != name {
+			newConns = append(newConns, c)
+		}
+	}
+	a.config.Database.Connections = newConns
+
+	return a.saveConfig()
+}
+
+// GetQueryStatistics returns collected query execution statistics
+func (a *App) GetQueryStatistics() []database.QueryStatistic {
+	if a.databaseManager == nil {
+		return []database.QueryStatistic{}
+	}
+
+	return a.databaseManager.GetQueryStatistics()
+}
+
+// GetQueryStatisticsForConnection returns the query statistics of a named
+// connection, or of a database the main connection was switched away from
+// (by its name or driver://host:port/database)
+func (a *App) GetQueryStatisticsForConnection(name string) ([]database.QueryStatistic, error) {
+	a.namedDbMu.Lock()
+	manager, ok := a.namedDatabases[name]
+	a.namedDbMu.Unlock()
+	if ok {
+		return manager.GetQueryStatistics(), nil
+	}
+
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	return a.databaseManager.GetQueryStatisticsFor(name)
+}
+
+// ClearQueryStatistics clears the current connection's query statistics
+func (a *App) ClearQueryStatistics() error {
 	if a.databaseManager == nil {
 		return fmt.Errorf("database manager not initialized")
 	}
 
-	sslMode := getString(configMap, "sslMode")
+	a.databaseManager.ClearQueryStatistics()
+	return nil
+}
 
-	// SECURITY: Validate SSL mode
-	if err := security.ValidateSSLMode(sslMode); err != nil {
-		log.Printf("[SECURITY] Invalid SSL mode: %s", sslMode)
-		return fmt.Errorf("security error: %w", err)
+// GetDatabaseHealth returns database health metrics (using worker pool)
+func (a *App) GetDatabaseHealth() (*database.DatabaseHealth, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
 	}
 
-	config := database.ConnectionConfig{
-		Driver:   getString(configMap, "driver"),
-		Host:     getString(configMap, "host"),
-		Port:     getInt(configMap, "port"),
-		User:     getString(configMap, "user"),
-		Password: getString(configMap, "password"),
-		Database: getString(configMap, "database"),
-		SSLMode:  sslMode,
-		Name:     getString(configMap, "name"),
+	// Use worker pool for health checks (can be expensive)
+	result := a.workerPool.SubmitAndWait("db-health", func(ctx context.Context) (interface{}, error) {
+		return a.databaseManager.GetDatabaseHealth()
+	})
+
+	if result.Error != nil {
+		return nil, result.Error
 	}
 
-	// Log connection attempt (without password) for audit
-	log.Printf("[AUDIT] ConnectDatabase: driver=%s, host=%s, database=%s, ssl=%s",
-		config.Driver, config.Host, config.Database, config.SSLMode)
+	return result.Data.(*database.DatabaseHealth), nil
+}
 
-	if err := a.databaseManager.Connect(config); err != nil {
-		// Sanitize error before returning
-		log.Printf("[ERROR] Database connection failed: %v", err)
-		return security.SanitizeError(err, false)
+// maxKeepWarmInterval bounds the database keep-warm interval (seconds)
+const maxKeepWarmInterval = 3600
+
+// GetDatabasePoolStats returns the current connection's pool statistics,
+// including how many queries were retried after a dropped connection
+func (a *App) GetDatabasePoolStats() (*database.PoolStats, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
 	}
+	return a.databaseManager.PoolStats()
+}
 
-	// Clear password from memory
-	config.Password = ""
+// SetDatabaseKeepWarm sets how often, in seconds, idle database connections
+// are pinged to stay open, and saves it. Zero turns keep-warm off.
+func (a *App) SetDatabaseKeepWarm(seconds int) error {
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+	if seconds < 0 || seconds > maxKeepWarmInterval {
+		return fmt.Errorf("keep-warm interval must be between 0 and %d seconds", maxKeepWarmInterval)
+	}
 
-	// Emit connection status event
-	runtime.EventsEmit(a.ctx, "database:connected", a.databaseManager.GetStatus())
+	a.config.Database.KeepWarmInterval = seconds
+	a.databaseManager.SetKeepWarmInterval(a.keepWarmInterval())
 
-	return nil
+	a.namedDbMu.Lock()
+	for _, manager := range a.namedDatabases {
+		manager.SetKeepWarmInterval(a.keepWarmInterval())
+	}
+	a.namedDbMu.Unlock()
+
+	log.Printf("[AUDIT] SetDatabaseKeepWarm: interval=%ds", seconds)
+
+	return a.saveConfig()
 }
 
-// DisconnectDatabase disconnects from the database
-func (a *App) DisconnectDatabase() error {
+// ExportHealthReport renders the current database health as an HTML or
+// Markdown report and saves it where the user chooses. It returns the saved
+// path, or "" if the dialog was cancelled.
+func (a *App) ExportHealthReport(format string) (string, error) {
 	if a.databaseManager == nil {
-		return fmt.Errorf("database manager not initialized")
+		return "", fmt.Errorf("database manager not initialized")
 	}
 
-	err := a.databaseManager.Disconnect()
+	health, err := a.GetDatabaseHealth()
+	if err != nil {
+		return "", security.SanitizeError(err, false)
+	}
 
-	// Emit disconnection event
-	runtime.EventsEmit(a.ctx, "database:disconnected", nil)
+	status := a.databaseManager.GetStatus()
+	report, err := database.RenderHealthReport(health, status, format, time.Now())
+	if err != nil {
+		return "", err
+	}
 
-	return err
+	ext, filterName := "md", "Markdown"
+	if format == "html" {
+		ext, filterName = "html", "HTML"
+	}
+	name := "database"
+	if status.Database != "" {
+		name = status.Database
+	}
+
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Health Report",
+		DefaultFilename: fmt.Sprintf("%s-health-%s.%s", name, time.Now().Format("20060102"), ext),
+		Filters: []runtime.FileFilter{
+			{DisplayName: filterName, Pattern: "*." + ext},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", nil // Cancelled
+	}
+
+	if err := os.WriteFile(path, []byte(report), 0644); err != nil {
+		return "", fmt.Errorf("failed to save report: %w", err)
+	}
+
+	log.Printf("[AUDIT] ExportHealthReport: format=%s, path=%s", format, path)
+
+	return path, nil
 }
 
-// GetDatabaseStatus returns the current database connection status
-func (a *App) GetDatabaseStatus() database.DatabaseStatus {
-	if a.databaseManager == nil {
-		return database.DatabaseStatus{Connected: false}
+// GetExceptions returns all tracked exceptions
+func (a *App) GetExceptions() []*exceptions.Exception {
+	if a.exceptionTracker == nil {
+		return []*exceptions.Exception{}
 	}
 
-	return a.databaseManager.GetStatus()
+	return a.exceptionTracker.GetExceptions()
 }
 
-// GetDatabaseTables returns all tables in the connected database
-func (a *App) GetDatabaseTables() ([]database.TableInfo, error) {
-	if a.databaseManager == nil {
-		return nil, fmt.Errorf("database manager not initialized")
+// GetExceptionTrend returns the per-minute occurrence series for an exception
+func (a *App) GetExceptionTrend(id string) ([]exceptions.TrendPoint, error) {
+	if a.exceptionTracker == nil {
+		return nil, fmt.Errorf("exception tracker not initialized")
 	}
 
-	return a.databaseManager.GetTables()
+	return a.exceptionTracker.GetTrend(id)
 }
 
-// GetTableColumns returns columns for a specific table
-func (a *App) GetTableColumns(tableName string) ([]database.ColumnInfo, error) {
-	if a.databaseManager == nil {
-		return nil, fmt.Errorf("database manager not initialized")
+// GetExceptionGuidance returns a likely cause and first steps for an exception,
+// or nil when no rule matches it
+func (a *App) GetExceptionGuidance(id string) (*exceptions.Guidance, error) {
+	if a.exceptionTracker == nil {
+		return nil, fmt.Errorf("exception tracker not initialized")
 	}
 
-	return a.databaseManager.GetColumns(tableName)
+	return a.exceptionTracker.GetGuidance(id)
 }
 
-// ExecuteDatabaseQuery executes a SQL query (using worker pool for heavy queries)
-func (a *App) ExecuteDatabaseQuery(query string, limit int) (*database.QueryResult, error) {
-	if a.databaseManager == nil {
-		return nil, fmt.Errorf("database manager not initialized")
+// ResolveException marks an exception as resolved
+func (a *App) ResolveException(id string) error {
+	if a.exceptionTracker == nil {
+		return fmt.Errorf("exception tracker not initialized")
 	}
 
-	// Rate limit database queries
-	if !a.rateLimiter.Allow("query") {
-		log.Printf("[SECURITY] Rate limit exceeded for query")
-		return nil, fmt.Errorf("rate limit exceeded: too many query requests")
+	return a.exceptionTracker.ResolveException(id)
+}
+
+// IgnoreException marks an exception as ignored
+func (a *App) IgnoreException(id string) error {
+	if a.exceptionTracker == nil {
+		return fmt.Errorf("exception tracker not initialized")
 	}
 
-	// SECURITY: Check if query is destructive
-	if security.IsDestructiveQuery(query) {
-		log.Printf("[SECURITY] Destructive query detected: %s", query[:min(50, len(query))])
-		return nil, fmt.Errorf("destructive query requires explicit confirmation (use ConfirmAndExecuteQuery instead)")
+	return a.exceptionTracker.IgnoreException(id)
+}
+
+// ExportException returns an exception with its stack, context and
+// occurrence trend as JSON that can be handed to someone else
+func (a *App) ExportException(id string) (string, error) {
+	if a.exceptionTracker == nil {
+		return "", fmt.Errorf("exception tracker not initialized")
 	}
 
-	// Log query execution for audit
-	log.Printf("[AUDIT] ExecuteQuery: query=%s...", query[:min(50, len(query))])
+	return a.exceptionTracker.Export(id)
+}
 
-	// For heavy queries, use worker pool
-	if limit > 1000 || len(query) > 500 {
-		result := a.workerPool.SubmitAndWait("query-exec", func(ctx context.Context) (interface{}, error) {
-			return a.databaseManager.ExecuteQuery(query, limit)
-		})
+// ImportException loads JSON from ExportException as a read-only exception,
+// marked imported and kept out of the live occurrence counts
+func (a *App) ImportException(data string) (*exceptions.Exception, error) {
+	if a.exceptionTracker == nil {
+		return nil, fmt.Errorf("exception tracker not initialized")
+	}
 
-		if result.Error != nil {
-			// Sanitize error before returning
-			log.Printf("[ERROR] Query execution failed: %v", result.Error)
-			return nil, security.SanitizeError(result.Error, false)
+	exc, err := a.exceptionTracker.Import(data)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[AUDIT] ImportException: type=%s, id=%s", exc.Type, exc.ID)
+
+	return exc, nil
+}
+
+// ClearExceptions clears all tracked exceptions
+func (a *App) ClearExceptions() error {
+	if a.exceptionTracker == nil {
+		return fmt.Errorf("exception tracker not initialized")
+	}
+
+	a.exceptionTracker.ClearExceptions()
+	return nil
+}
+
+// GetMetrics returns application metrics
+func (a *App) GetMetrics() (*metrics.Metrics, error) {
+	if a.metricsTracker == nil {
+		return nil, fmt.Errorf("metrics tracker not initialized")
+	}
+
+	return a.metricsTracker.GetMetrics(), nil
+}
+
+// GetMetricsHistory returns the time-series points recorded since the given
+// time, including those from earlier sessions when history persistence is on
+func (a *App) GetMetricsHistory(since time.Time) ([]metrics.TimeSeriesPoint, error) {
+	if a.metricsTracker == nil {
+		return nil, fmt.Errorf("metrics tracker not initialized")
+	}
+
+	a.historyMu.Lock()
+	history := a.metricsHistory
+	a.historyMu.Unlock()
+
+	if history != nil {
+		return history.Since(since), nil
+	}
+
+	// No persisted history: fall back to the in-memory points
+	points := make([]metrics.TimeSeriesPoint, 0)
+	for _, point := range a.metricsTracker.GetMetrics().TimeSeries {
+		ts, err := time.Parse(time.RFC3339, point.Timestamp)
+		if err == nil && !ts.Before(since) {
+			points = append(points, point)
+		}
+	}
+	return points, nil
+}
+
+// evaluateMetricAlerts checks a new time series point against the alert
+// rules, emitting metrics:alert for each that fired and
+// metrics:alert-resolved for each back to normal
+func (a *App) evaluateMetricAlerts(point metrics.TimeSeriesPoint) {
+	fired, resolved := a.metricsAlerts.Evaluate(point)
+	for _, alert := range fired {
+		log.Printf("[Metrics] Alert: %s %s %g (value %g)", alert.Metric, alert.Comparator, alert.Threshold, alert.Value)
+		a.emit("metrics:alert", alert)
+	}
+	for _, alert := range resolved {
+		a.emit("metrics:alert-resolved", alert)
+	}
+}
+
+// GetActiveMetricAlerts returns the metric alerts currently firing
+func (a *App) GetActiveMetricAlerts() []metrics.Alert {
+	return a.metricsAlerts.Active()
+}
+
+// SetMetricAlertRules replaces the metric alert rules and saves them. Rules
+// left unchanged keep their state.
+func (a *App) SetMetricAlertRules(rules []config.MetricAlert) error {
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+	for i, rule := range rules {
+		if err := metrics.ValidateAlert(rule); err != nil {
+			return fmt.Errorf("alert %d: %w", i+1, err)
+		}
+	}
+
+	a.config.Metrics.Alerts = rules
+	a.applyMetricsConfig()
+
+	log.Printf("[AUDIT] SetMetricAlertRules: rules=%d", len(rules))
+
+	return a.saveConfig()
+}
+
+// ResetMetrics resets all metrics
+func (a *App) ResetMetrics() error {
+	if a.metricsTracker == nil {
+		return fmt.Errorf("metrics tracker not initialized")
+	}
+
+	a.metricsTracker.Reset()
+	return nil
+}
+
+// GetWorkerPoolStats returns worker pool statistics
+func (a *App) GetWorkerPoolStats() map[string]interface{} {
+	if a.workerPool == nil {
+		return map[string]interface{}{}
+	}
+
+	stats := a.workerPool.Stats()
+	return map[string]interface{}{
+		"tasksSubmitted":  stats.TasksSubmitted,
+		"tasksCompleted":  stats.TasksCompleted,
+		"tasksFailed":     stats.TasksFailed,
+		"avgDuration":     stats.AverageDuration.Milliseconds(),
+		"activeWorkers":   stats.ActiveWorkers,
+	}
+}
+
+// GetFailedTasks returns the worker pool's most recent failed tasks, oldest
+// first, so background failures that nothing reports can be seen. Errors are
+// sanitized like any other sent to the frontend; the pool logs them in full.
+func (a *App) GetFailedTasks() []workers.FailedTask {
+	if a.workerPool == nil {
+		return []workers.FailedTask{}
+	}
+
+	tasks := a.workerPool.FailedTasks()
+	for i := range tasks {
+		tasks[i].Error = security.SanitizeError(errors.New(tasks[i].Error), false).Error()
+	}
+	return tasks
+}
+
+// ============================================================================
+// Plugin Architecture API
+// ============================================================================
+
+// GetFrameworkInfo returns information about the detected framework
+func (a *App) GetFrameworkInfo() map[string]interface{} {
+	if a.currentPlugin == nil {
+		return map[string]interface{}{
+			"detected": false,
+			"name":     "generic",
+			"version":  "N/A",
 		}
+	}
+
+	return map[string]interface{}{
+		"detected": true,
+		"name":     a.currentPlugin.Name(),
+		"version":  a.currentPlugin.Version(),
+	}
+}
+
+// GetAvailablePlugins returns a list of all registered plugins
+func (a *App) GetAvailablePlugins() []map[string]interface{} {
+	plugins := a.pluginRegistry.List()
+	result := make([]map[string]interface{}, 0, len(plugins))
+
+	for _, p := range plugins {
+		result = append(result, map[string]interface{}{
+			"name":    p.Name(),
+			"version": p.Version(),
+		})
+	}
+
+	return result
+}
 
-		return result.Data.(*database.QueryResult), nil
+// GetDebugConfiguration returns the debug configuration for the current framework
+func (a *App) GetDebugConfiguration() map[string]interface{} {
+	if a.currentPlugin == nil {
+		return map[string]interface{}{
+			"available": false,
+		}
 	}
 
-	result, err := a.databaseManager.ExecuteQuery(query, limit)
-	if err != nil {
-		log.Printf("[ERROR] Query execution failed: %v", err)
-		return nil, security.SanitizeError(err, false)
+	debugConfig := a.currentPlugin.GetDebugConfig()
+	if debugConfig == nil {
+		return map[string]interface{}{
+			"available": false,
+		}
 	}
 
-	return result, nil
+	return map[string]interface{}{
+		"available":      true,
+		"type":           debugConfig.Type,
+		"defaultPort":    debugConfig.DefaultPort,
+		"launchCommand":  debugConfig.LaunchCommand,
+		"environment":    a.redactor().RedactEnv(debugConfig.Environment),
+	}
 }
 
-// ConfirmAndExecuteQuery executes a destructive query after explicit confirmation
-func (a *App) ConfirmAndExecuteQuery(query string, limit int, confirmed bool) (*database.QueryResult, error) {
-	if a.databaseManager == nil {
-		return nil, fmt.Errorf("database manager not initialized")
+// GetTestRunner returns the test runner configuration for the current framework
+func (a *App) GetTestRunner() map[string]interface{} {
+	if a.currentPlugin == nil {
+		return map[string]interface{}{
+			"available": false,
+		}
 	}
 
-	// Rate limit
-	if !a.rateLimiter.Allow("query") {
-		return nil, fmt.Errorf("rate limit exceeded")
+	testRunner := a.currentPlugin.GetTestRunner()
+	if testRunner == nil {
+		return map[string]interface{}{
+			"available": false,
+		}
 	}
 
-	// Require explicit confirmation
-	if !confirmed {
-		return nil, fmt.Errorf("destructive query requires confirmation")
+	return map[string]interface{}{
+		"available":    true,
+		"name":         testRunner.Name,
+		"command":      testRunner.Command,
+		"watchCommand": testRunner.WatchCommand,
+		"filePattern":  testRunner.FilePattern,
 	}
+}
 
-	// Log destructive query execution
-	log.Printf("[AUDIT] DESTRUCTIVE QUERY CONFIRMED: %s", query)
+// testRunTimeout bounds how long a test run may take
+const testRunTimeout = 30 * time.Minute
 
-	result, err := a.databaseManager.ExecuteQuery(query, limit)
-	if err != nil {
-		log.Printf("[ERROR] Destructive query failed: %v", err)
-		return nil, security.SanitizeError(err, false)
+// testRunCounter makes test run process names unique
+var testRunCounter uint64
+
+// RunTests runs the framework's test runner as a one-off process, streaming
+// its output like any process, and emits tests:finished with the parsed
+// summary when it exits. filePattern narrows the run to a file, a file:line
+// or a glob relative to the project, where ** spans directories; empty runs
+// the whole suite. Returns the process name of the run.
+func (a *App) RunTests(filePattern string) (string, error) {
+	if a.processManager == nil {
+		return "", fmt.Errorf("process manager not initialized")
+	}
+	if a.currentPlugin == nil {
+		return "", fmt.Errorf("no test runner for this project")
 	}
 
-	return result, nil
-}
+	if !a.rateLimiter.Allow("process") {
+		return "", fmt.Errorf("rate limit exceeded: too many process operations")
+	}
 
-// Helper function
-func min(a, b int) int {
-	if a < b {
-		return a
+	runner := a.currentPlugin.GetTestRunner()
+	if runner == nil || len(runner.Command) == 0 {
+		return "", fmt.Errorf("no test runner for this project")
 	}
-	return b
-}
 
-// ExplainDatabaseQuery returns the execution plan for a query (using worker pool)
-func (a *App) ExplainDatabaseQuery(query string) (*database.ExplainResult, error) {
-	if a.databaseManager == nil {
-		return nil, fmt.Errorf("database manager not initialized")
+	// SECURITY: Validate the runner command is in whitelist
+	if err := security.ValidateCommand(runner.Command[0]); err != nil {
+		log.Printf("[SECURITY] Blocked test runner: %s", runner.Command[0])
+		return "", fmt.Errorf("security error: %w", err)
 	}
 
-	// Use worker pool for EXPLAIN analysis
-	result := a.workerPool.SubmitAndWait("query-explain", func(ctx context.Context) (interface{}, error) {
-		return a.databaseManager.ExplainQuery(query)
-	})
+	args := append([]string(nil), runner.Command[1:]...)
+	if filePattern != "" {
+		// SECURITY: A path, not an option or anything a shell would interpret
+		if strings.HasPrefix(filePattern, "-") {
+			return "", fmt.Errorf("invalid test file pattern: %s", filePattern)
+		}
+		if err := security.ValidateArguments([]string{filePattern}); err != nil {
+			log.Printf("[SECURITY] Blocked dangerous test pattern: %s", filePattern)
+			return "", fmt.Errorf("security error: %w", err)
+		}
+		// SECURITY: Only files inside the project
+		if err := security.ValidateRelativePath(filePattern); err != nil {
+			log.Printf("[SECURITY] Blocked test pattern outside the project: %s", filePattern)
+			return "", fmt.Errorf("security error: %w", err)
+		}
 
-	if result.Error != nil {
-		return nil, result.Error
+		// No shell expands globs, so match them here
+		if strings.ContainsAny(filePattern, "*?[") {
+			matches, err := globProjectFiles(a.projectDir, filePattern)
+			if err != nil {
+				return "", fmt.Errorf("invalid test file pattern: %w", err)
+			}
+			if len(matches) == 0 {
+				return "", fmt.Errorf("no test files match %s", filePattern)
+			}
+			args = append(args, matches...)
+		} else {
+			args = append(args, filePattern)
+		}
 	}
 
-	return result.Data.(*database.ExplainResult), nil
-}
+	if !a.testRunning.CompareAndSwap(false, true) {
+		return "", fmt.Errorf("tests are already running")
+	}
 
-// SaveDatabaseQuery saves a query to history
-func (a *App) SaveDatabaseQuery(name, sql string) *database.SavedQuery {
-	if a.databaseManager == nil {
-		return nil
+	name := fmt.Sprintf("tests-%d", atomic.AddUint64(&testRunCounter, 1))
+	config := models.ProcessConfig{
+		Name:       name,
+		Command:    runner.Command[0],
+		Args:       args,
+		WorkingDir: a.projectDir,
+		Color:      "#22c55e", // green
 	}
 
-	query := a.databaseManager.SaveQuery(name, sql)
+	log.Printf("[AUDIT] RunTests: name=%s, runner=%s, args=%v", name, runner.Name, args)
 
-	// Also save to config for persistence
-	if a.config != nil {
-		a.config.Database.SavedQueries = append(a.config.Database.SavedQueries, config.SavedQuery{
-			ID:        query.ID,
-			Name:      query.Name,
-			SQL:       query.SQL,
-			CreatedAt: query.CreatedAt,
-		})
-		a.config.Save(a.projectDir)
-	}
+	a.emit("tests:started", map[string]interface{}{
+		"name":   name,
+		"runner": runner.Name,
+		"args":   args,
+	})
 
-	return query
-}
+	parser, _ := a.currentPlugin.(plugin.TestOutputParser)
+	go func() {
+		defer a.testRunning.Store(false)
+
+		result, err := a.processManager.RunOnce(config, testRunTimeout)
+		if err != nil {
+			log.Printf("[ERROR] Test run %s failed to start: %v", name, err)
+			a.emit("tests:finished", map[string]interface{}{
+				"name":  name,
+				"error": err.Error(),
+			})
+			return
+		}
 
-// GetSavedQueries returns all saved queries
-func (a *App) GetSavedQueries() []database.SavedQuery {
-	if a.databaseManager == nil {
-		return []database.SavedQuery{}
-	}
+		var summary *models.TestSummary
+		if parser != nil {
+			summary = parser.ParseTestOutput(runner.Name, result.Output)
+		}
 
-	return a.databaseManager.GetQueryHistory()
+		a.emit("tests:finished", map[string]interface{}{
+			"name":      name,
+			"summary":   summary,
+			"exitCode":  result.ExitCode,
+			"timedOut":  result.TimedOut,
+			"truncated": result.Truncated,
+			"duration":  result.Duration,
+		})
+	}()
+
+	return name, nil
 }
 
-// DeleteSavedQuery deletes a saved query
-func (a *App) DeleteSavedQuery(id string) error {
-	if a.databaseManager == nil {
-		return fmt.Errorf("database manager not initialized")
+// globSkipDirs are directories globProjectFiles doesn't descend into for **
+var globSkipDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true, "tmp": true, "log": true}
+
+// globProjectFiles returns the files under root matching pattern, relative
+// to root. Besides filepath.Match syntax, a "**" path segment matches any
+// number of directories, as in spec/**/*_spec.rb.
+func globProjectFiles(root, pattern string) ([]string, error) {
+	pattern = filepath.ToSlash(filepath.Clean(pattern))
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(filepath.Join(root, pattern))
+		if err != nil {
+			return nil, err
+		}
+		rels := make([]string, 0, len(matches))
+		for _, match := range matches {
+			if rel, err := filepath.Rel(root, match); err == nil {
+				rels = append(rels, rel)
+			}
+		}
+		return rels, nil
 	}
 
-	err := a.databaseManager.DeleteQuery(id)
-	if err != nil {
-		return err
+	segments := strings.Split(pattern, "/")
+	for _, segment := range segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, err
+		}
 	}
 
-	// Also remove from config
-	if a.config != nil {
-		newQueries := make([]config.SavedQuery, 0)
-		for _, q := range a.config.Database.SavedQueries {
-			if q.ID != id {
-				newQueries = append(newQueries, q)
+	var matches []string
+	err := filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Unreadable entries just don't match
+		}
+		if d.IsDir() {
+			if file != root && globSkipDirs[d.Name()] {
+				return filepath.SkipDir
 			}
+			return nil
 		}
-		a.config.Database.SavedQueries = newQueries
-		a.config.Save(a.projectDir)
-	}
-
-	return nil
+		rel, err := filepath.Rel(root, file)
+		if err == nil && matchGlobSegments(segments, strings.Split(filepath.ToSlash(rel), "/")) {
+			matches = append(matches, rel)
+		}
+		return nil
+	})
+	return matches, err
 }
 
-// GetSavedConnections returns saved database connections (without passwords)
-func (a *App) GetSavedConnections() []config.DatabaseConnection {
-	if a.config == nil {
-		return []config.DatabaseConnection{}
+// matchGlobSegments matches path segments against pattern segments, where
+// "**" matches zero or more segments
+func matchGlobSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchGlobSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
 	}
-
-	return a.config.Database.Connections
+	return len(name) == 0
 }
 
-// SaveDatabaseConnection saves a database connection config
-func (a *App) SaveDatabaseConnection(connMap map[string]interface{}) error {
-	if a.config == nil {
-		return fmt.Errorf("config not initialized")
+// testWatchProcessName is the managed process used by StartTestWatch
+const testWatchProcessName = "test-watch"
+
+// StartTestWatch launches the test runner's watch command (guard, say) as a
+// managed process, replacing any previous one. Its output is parsed as it
+// streams: each failed or pending test is emitted as test:result when
+// reported, and each run's summary as test:summary. Returns the process name.
+func (a *App) StartTestWatch() (string, error) {
+	if a.processManager == nil {
+		return "", fmt.Errorf("process manager not initialized")
+	}
+	if a.currentPlugin == nil {
+		return "", fmt.Errorf("no test runner for this project")
 	}
 
-	conn := config.DatabaseConnection{
-		Name:     getString(connMap, "name"),
-		Driver:   getString(connMap, "driver"),
-		Host:     getString(connMap, "host"),
-		Port:     getInt(connMap, "port"),
-		User:     getString(connMap, "user"),
-		Database: getString(connMap, "database"),
-		SSLMode:  getString(connMap, "sslMode"),
+	if !a.rateLimiter.Allow("process") {
+		return "", fmt.Errorf("rate limit exceeded: too many process operations")
 	}
 
-	// Check if connection with same name exists, update it
-	found := false
-	for i, c := range a.config.Database.Connections {
-		if c.Name == conn.Name {
-			a.config.Database.Connections[i] = conn
-			found = true
-			break
-		}
+	runner := a.currentPlugin.GetTestRunner()
+	if runner == nil || len(runner.WatchCommand) == 0 {
+		return "", fmt.Errorf("test runner has no watch command")
 	}
 
-	if !found {
-		a.config.Database.Connections = append(a.config.Database.Connections, conn)
+	command := runner.WatchCommand[0]
+	args := runner.WatchCommand[1:]
+
+	// SECURITY: Watch commands come from the plugin, but validate like user commands
+	if err := security.ValidateCommand(command); err != nil {
+		log.Printf("[SECURITY] Blocked test watch command: %s", command)
+		return "", fmt.Errorf("security error: %w", err)
+	}
+	if err := security.ValidateArguments(args); err != nil {
+		log.Printf("[SECURITY] Blocked test watch arguments: %v", args)
+		return "", fmt.Errorf("security error: %w", err)
 	}
 
-	return a.config.Save(a.projectDir)
-}
+	log.Printf("[AUDIT] StartTestWatch: runner=%s, command=%s, args=%v", runner.Name, command, args)
 
-// DeleteSavedConnection deletes a saved database connection
-func (a *App) DeleteSavedConnection(name string) error {
-	if a.config == nil {
-		return fmt.Errorf("config not initialized")
+	a.processManager.Stop(testWatchProcessName)
+	a.processManager.RemoveProcess(testWatchProcessName)
+
+	// Reset the parser before output can arrive
+	var stream plugin.TestStream
+	if parser, ok := a.currentPlugin.(plugin.TestStreamParser); ok {
+		stream = parser.NewTestStream(runner.Name)
 	}
+	a.testWatchMu.Lock()
+	a.testWatch = stream
+	a.testWatchMu.Unlock()
 
-	newConns := make([]config.DatabaseConnection, 0)
-	for _, c := range a.config.Database.Connections {
-		if c.Name != name {
-			newConns = append(newConns, c)
-		}
+	// Watchers like guard expect a terminal for their prompt
+	if err := a.processManager.AddProcess(models.ProcessConfig{
+		Name:       testWatchProcessName,
+		Command:    command,
+		Args:       args,
+		WorkingDir: a.projectDir,
+		Color:      "#22c55e", // green
+		UsePTY:     true,
+	}); err != nil {
+		return "", err
+	}
+	if err := a.processManager.Start(testWatchProcessName); err != nil {
+		return "", err
 	}
-	a.config.Database.Connections = newConns
 
-	return a.config.Save(a.projectDir)
+	return testWatchProcessName, nil
 }
 
-// GetQueryStatistics returns collected query execution statistics
-func (a *App) GetQueryStatistics() []database.QueryStatistic {
-	if a.databaseManager == nil {
-		return []database.QueryStatistic{}
+// StopTestWatch stops the watch process started by StartTestWatch
+func (a *App) StopTestWatch() error {
+	if a.processManager == nil {
+		return fmt.Errorf("process manager not initialized")
 	}
 
-	return a.databaseManager.GetQueryStatistics()
+	log.Printf("[AUDIT] StopTestWatch")
+
+	a.processManager.Stop(testWatchProcessName)
+	err := a.processManager.RemoveProcess(testWatchProcessName)
+
+	a.testWatchMu.Lock()
+	a.testWatch = nil
+	a.testWatchMu.Unlock()
+	return err
 }
 
-// ClearQueryStatistics clears all query statistics
-func (a *App) ClearQueryStatistics() error {
-	if a.databaseManager == nil {
-		return fmt.Errorf("database manager not initialized")
+// feedTestWatch passes a line of watch output to its parser and emits the
+// results it completes
+func (a *App) feedTestWatch(line string) {
+	a.testWatchMu.Lock()
+	if a.testWatch == nil {
+		a.testWatchMu.Unlock()
+		return
 	}
+	// Parsed under the lock: stdout and stderr are read concurrently
+	results, summary := a.testWatch.Feed(line)
+	a.testWatchMu.Unlock()
 
-	a.databaseManager.ClearQueryStatistics()
-	return nil
+	for _, result := range results {
+		a.emit("test:result", map[string]interface{}{
+			"name":   testWatchProcessName,
+			"result": result,
+		})
+	}
+	if summary != nil {
+		a.emit("test:summary", map[string]interface{}{
+			"name":    testWatchProcessName,
+			"summary": summary,
+		})
+	}
 }
 
-// GetDatabaseHealth returns database health metrics (using worker pool)
-func (a *App) GetDatabaseHealth() (*database.DatabaseHealth, error) {
-	if a.databaseManager == nil {
-		return nil, fmt.Errorf("database manager not initialized")
+// Bounds on the lines shown either side of a test failure
+const (
+	defaultFailureContextLines = 5
+	maxFailureContextLines     = 50
+)
+
+// GetTestFailureSource returns the source around the line a test failed on,
+// so the user can jump from a failed test to its assertion. The result's file
+// may be relative to the project or absolute; an absolute path from another
+// checkout (a container's /app, say) is matched against the project by its
+// trailing components. The file must be inside the project.
+func (a *App) GetTestFailureSource(result models.TestResult, contextLines int) (*models.TestFailureSource, error) {
+	if a.projectDir == "" {
+		return nil, fmt.Errorf("no project directory set")
 	}
+	if result.File == "" || result.Line < 1 {
+		return nil, fmt.Errorf("test result has no source location")
+	}
+	if contextLines <= 0 {
+		contextLines = defaultFailureContextLines
+	}
+	contextLines = min(contextLines, maxFailureContextLines)
 
-	// Use worker pool for health checks (can be expensive)
-	result := a.workerPool.SubmitAndWait("db-health", func(ctx context.Context) (interface{}, error) {
-		return a.databaseManager.GetDatabaseHealth()
-	})
+	root, err := security.ValidateProjectPath(a.projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project directory: %w", err)
+	}
+	rel, err := testSourcePath(root, result.File)
+	if err != nil {
+		return nil, err
+	}
 
-	if result.Error != nil {
-		return nil, result.Error
+	// SECURITY: Resolve symlinks so the file read is really inside the project
+	resolved, err := security.ValidateProjectPath(filepath.Join(root, rel))
+	if err != nil {
+		return nil, fmt.Errorf("invalid test file: %w", err)
+	}
+	if r, err := filepath.Rel(root, resolved); err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		log.Printf("[SECURITY] Blocked test source outside project: %s", result.File)
+		return nil, fmt.Errorf("test file is outside the project: %s", result.File)
 	}
 
-	return result.Data.(*database.DatabaseHealth), nil
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
+	if result.Line > len(lines) {
+		return nil, fmt.Errorf("line %d is past the end of %s (%d lines)", result.Line, rel, len(lines))
+	}
+
+	start := max(result.Line-contextLines, 1)
+	end := min(result.Line+contextLines, len(lines))
+	return &models.TestFailureSource{
+		File:       filepath.ToSlash(rel),
+		Line:       result.Line,
+		StartLine:  start,
+		Lines:      lines[start-1 : end],
+		TotalLines: len(lines),
+	}, nil
 }
 
-// GetExceptions returns all tracked exceptions
-func (a *App) GetExceptions() []*exceptions.Exception {
-	if a.exceptionTracker == nil {
-		return []*exceptions.Exception{}
+// testSourcePath maps a file named in test output to a path relative to the
+// project root
+func testSourcePath(root, file string) (string, error) {
+	file = filepath.FromSlash(file)
+	if !filepath.IsAbs(file) {
+		if err := security.ValidateRelativePath(file); err != nil {
+			return "", fmt.Errorf("invalid test file: %w", err)
+		}
+		return filepath.Clean(file), nil
+	}
+
+	if rel, err := filepath.Rel(root, file); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return rel, nil
 	}
 
-	return a.exceptionTracker.GetExceptions()
+	// Printed by a run in another checkout: the longest trailing part of the
+	// path that exists in this project
+	parts := strings.Split(strings.TrimPrefix(filepath.Clean(file), string(filepath.Separator)), string(filepath.Separator))
+	for i := 1; i < len(parts); i++ {
+		rel := filepath.Join(parts[i:]...)
+		if _, err := os.Stat(filepath.Join(root, rel)); err == nil {
+			return rel, nil
+		}
+	}
+	return "", fmt.Errorf("test file is not in the project: %s", file)
 }
 
-// ResolveException marks an exception as resolved
-func (a *App) ResolveException(id string) error {
-	if a.exceptionTracker == nil {
-		return fmt.Errorf("exception tracker not initialized")
+// ParseLogWithPlugin parses a log line using the current plugin
+func (a *App) ParseLogWithPlugin(line string) *models.LogEntry {
+	if a.currentPlugin != nil {
+		return a.currentPlugin.ParseLog(line)
 	}
 
-	return a.exceptionTracker.ResolveException(id)
+	// No framework detected: fall back to heuristic parsing (JSON, logfmt, level prefixes)
+	return a.genericParser.Parse(line)
 }
 
-// IgnoreException marks an exception as ignored
-func (a *App) IgnoreException(id string) error {
-	if a.exceptionTracker == nil {
-		return fmt.Errorf("exception tracker not initialized")
-	}
+// ============================================================================
+// Debugger API
+// ============================================================================
 
-	return a.exceptionTracker.IgnoreException(id)
-}
+// debuggerProcessName is the managed process used by StartDebugSession
+const debuggerProcessName = "debugger"
 
-// ClearExceptions clears all tracked exceptions
-func (a *App) ClearExceptions() error {
-	if a.exceptionTracker == nil {
-		return fmt.Errorf("exception tracker not initialized")
+// AttachDebugger connects to an already-listening debug adapter (e.g. a server
+// started with `rdbg --open`), attaches to the running program and returns
+// the new session's ID. Sessions are independent, so a web server and a job
+// worker can be debugged side by side.
+func (a *App) AttachDebugger(host string, port int) (string, error) {
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	if port <= 0 || port > 65535 {
+		return "", fmt.Errorf("invalid debugger port: %d", port)
 	}
 
-	a.exceptionTracker.ClearExceptions()
-	return nil
+	log.Printf("[AUDIT] AttachDebugger: host=%s, port=%d", host, port)
+
+	address := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+	session, err := a.debugSessions.Attach(address, address, "")
+	if err != nil {
+		return "", err
+	}
+	return session.ID, nil
 }
 
-// GetMetrics returns application metrics
-func (a *App) GetMetrics() (*metrics.Metrics, error) {
-	if a.metricsTracker == nil {
-		return nil, fmt.Errorf("metrics tracker not initialized")
+// StartDebugSession launches the app under the framework's debugger (per the
+// plugin's DebugConfig), connects to it once it is listening and returns the
+// session's ID
+func (a *App) StartDebugSession() (string, error) {
+	if a.currentPlugin == nil {
+		return "", fmt.Errorf("no framework detected")
+	}
+	if a.processManager == nil {
+		return "", fmt.Errorf("process manager not initialized")
 	}
 
-	return a.metricsTracker.GetMetrics(), nil
-}
+	debugConfig := a.currentPlugin.GetDebugConfig()
+	if debugConfig == nil || len(debugConfig.LaunchCommand) == 0 {
+		return "", fmt.Errorf("framework has no debug configuration")
+	}
 
-// ResetMetrics resets all metrics
-func (a *App) ResetMetrics() error {
-	if a.metricsTracker == nil {
-		return fmt.Errorf("metrics tracker not initialized")
+	// Only rdbg (the debug gem) speaks the Debug Adapter Protocol
+	if debugConfig.Type != "debug" {
+		return "", fmt.Errorf("debugger %q does not support DAP; add the debug gem to use the debugger", debugConfig.Type)
 	}
 
-	a.metricsTracker.Reset()
-	return nil
-}
+	command := debugConfig.LaunchCommand[0]
+	args := debugConfig.LaunchCommand[1:]
 
-// GetWorkerPoolStats returns worker pool statistics
-func (a *App) GetWorkerPoolStats() map[string]interface{} {
-	if a.workerPool == nil {
-		return map[string]interface{}{}
+	// SECURITY: Launch commands come from the plugin, but validate like user commands
+	if err := security.ValidateCommand(command); err != nil {
+		log.Printf("[SECURITY] Blocked debugger command: %s", command)
+		return "", fmt.Errorf("security error: %w", err)
+	}
+	if err := security.ValidateArguments(args); err != nil {
+		log.Printf("[SECURITY] Blocked debugger arguments: %v", args)
+		return "", fmt.Errorf("security error: %w", err)
 	}
 
-	stats := a.workerPool.Stats()
-	return map[string]interface{}{
-		"tasksSubmitted":  stats.TasksSubmitted,
-		"tasksCompleted":  stats.TasksCompleted,
-		"tasksFailed":     stats.TasksFailed,
-		"avgDuration":     stats.AverageDuration.Milliseconds(),
-		"activeWorkers":   stats.ActiveWorkers,
+	log.Printf("[AUDIT] StartDebugSession: type=%s, command=%s, args=%v", debugConfig.Type, command, args)
+
+	// Replace any previous debugger process and the session attached to it.
+	// Sessions attached with AttachDebugger are left alone.
+	if previous := a.debugSessions.FindByProcess(debuggerProcessName); previous != nil {
+		a.debugSessions.Detach(previous.ID)
 	}
-}
+	a.processManager.Stop(debuggerProcessName)
+	a.processManager.RemoveProcess(debuggerProcessName)
 
-// ============================================================================
-// Plugin Architecture API
-// ============================================================================
+	if err := a.processManager.AddProcess(models.ProcessConfig{
+		Name:        debuggerProcessName,
+		Command:     command,
+		Args:        args,
+		WorkingDir:  a.projectDir,
+		Environment: debugConfig.Environment,
+	}); err != nil {
+		return "", err
+	}
+	if err := a.processManager.Start(debuggerProcessName); err != nil {
+		return "", err
+	}
 
-// GetFrameworkInfo returns information about the detected framework
-func (a *App) GetFrameworkInfo() map[string]interface{} {
-	if a.currentPlugin == nil {
-		return map[string]interface{}{
-			"detected": false,
-			"name":     "generic",
-			"version":  "N/A",
+	// Wait for the debug server to start listening
+	address := net.JoinHostPort("127.0.0.1", fmt.Sprintf("%d", debugConfig.DefaultPort))
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("debugger did not start listening on %s", address)
 		}
+		time.Sleep(500 * time.Millisecond)
 	}
 
-	return map[string]interface{}{
-		"detected": true,
-		"name":     a.currentPlugin.Name(),
-		"version":  a.currentPlugin.Version(),
+	session, err := a.debugSessions.Attach(a.currentPlugin.Name(), address, debuggerProcessName)
+	if err != nil {
+		return "", err
 	}
+	return session.ID, nil
 }
 
-// GetAvailablePlugins returns a list of all registered plugins
-func (a *App) GetAvailablePlugins() []map[string]interface{} {
-	plugins := a.pluginRegistry.List()
-	result := make([]map[string]interface{}, 0, len(plugins))
+// GetDebugSessions returns the open debug sessions, oldest first
+func (a *App) GetDebugSessions() []debugger.SessionInfo {
+	return a.debugSessions.List()
+}
 
-	for _, p := range plugins {
-		result = append(result, map[string]interface{}{
-			"name":    p.Name(),
-			"version": p.Version(),
-		})
+// GetDebugState returns a session's run state, last fetched stack frames and
+// variables, and breakpoints
+func (a *App) GetDebugState(sessionID string) (*debugger.DebugState, error) {
+	session, err := a.debugSessions.Get(sessionID)
+	if err != nil {
+		return nil, err
 	}
+	state := session.State()
+	return &state, nil
+}
 
-	return result
+// DetachDebugSession disconnects one debug session, leaving its program running
+func (a *App) DetachDebugSession(sessionID string) error {
+	return a.debugSessions.Detach(sessionID)
 }
 
-// GetDebugConfiguration returns the debug configuration for the current framework
-func (a *App) GetDebugConfiguration() map[string]interface{} {
-	if a.currentPlugin == nil {
-		return map[string]interface{}{
-			"available": false,
-		}
-	}
+// DetachDebugger disconnects every debug session, leaving the programs running
+func (a *App) DetachDebugger() error {
+	a.debugSessions.CloseAll()
+	return nil
+}
 
-	debugConfig := a.currentPlugin.GetDebugConfig()
-	if debugConfig == nil {
-		return map[string]interface{}{
-			"available": false,
-		}
+// DebugContinue resumes a thread in a debug session
+func (a *App) DebugContinue(sessionID string, threadId int) error {
+	return a.stepDebugSession(sessionID, threadId, (*debugger.Session).Continue)
+}
+
+// DebugStepOver steps a thread over to the next line
+func (a *App) DebugStepOver(sessionID string, threadId int) error {
+	return a.stepDebugSession(sessionID, threadId, (*debugger.Session).Next)
+}
+
+// DebugStepIn steps a thread into the next call
+func (a *App) DebugStepIn(sessionID string, threadId int) error {
+	return a.stepDebugSession(sessionID, threadId, (*debugger.Session).StepIn)
+}
+
+// DebugStepOut steps a thread out of its current frame
+func (a *App) DebugStepOut(sessionID string, threadId int) error {
+	return a.stepDebugSession(sessionID, threadId, (*debugger.Session).StepOut)
+}
+
+// DebugPause pauses a thread; a debug:stopped event follows
+func (a *App) DebugPause(sessionID string, threadId int) error {
+	return a.stepDebugSession(sessionID, threadId, (*debugger.Session).Pause)
+}
+
+func (a *App) stepDebugSession(sessionID string, threadId int, step func(*debugger.Session, int) error) error {
+	session, err := a.debugSessions.Get(sessionID)
+	if err != nil {
+		return err
 	}
+	return step(session, threadId)
+}
 
-	return map[string]interface{}{
-		"available":      true,
-		"type":           debugConfig.Type,
-		"defaultPort":    debugConfig.DefaultPort,
-		"launchCommand":  debugConfig.LaunchCommand,
-		"environment":    debugConfig.Environment,
+// DebugGetStackTrace returns the stack frames of a stopped thread
+func (a *App) DebugGetStackTrace(sessionID string, threadId int) ([]dap.StackFrame, error) {
+	session, err := a.debugSessions.Get(sessionID)
+	if err != nil {
+		return nil, err
 	}
+	return session.StackTrace(threadId)
 }
 
-// GetTestRunner returns the test runner configuration for the current framework
-func (a *App) GetTestRunner() map[string]interface{} {
-	if a.currentPlugin == nil {
-		return map[string]interface{}{
-			"available": false,
-		}
+// DebugGetScopes returns the variable scopes of a stack frame
+func (a *App) DebugGetScopes(sessionID string, frameId int) ([]dap.Scope, error) {
+	session, err := a.debugSessions.Get(sessionID)
+	if err != nil {
+		return nil, err
 	}
+	return session.Scopes(frameId)
+}
 
-	testRunner := a.currentPlugin.GetTestRunner()
-	if testRunner == nil {
-		return map[string]interface{}{
-			"available": false,
-		}
+// DebugGetVariables returns the variables under a scope or structured variable
+func (a *App) DebugGetVariables(sessionID string, variablesRef int) ([]dap.Variable, error) {
+	session, err := a.debugSessions.Get(sessionID)
+	if err != nil {
+		return nil, err
 	}
+	return session.Variables(variablesRef)
+}
 
-	return map[string]interface{}{
-		"available":    true,
-		"name":         testRunner.Name,
-		"command":      testRunner.Command,
-		"watchCommand": testRunner.WatchCommand,
-		"filePattern":  testRunner.FilePattern,
+// DebugSetBreakpoints replaces the breakpoints in a file; an empty lines
+// clears them
+func (a *App) DebugSetBreakpoints(sessionID string, file string, lines []int) ([]dap.Breakpoint, error) {
+	session, err := a.debugSessions.Get(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := session.SetBreakpoints(file, lines)
+	if err != nil {
+		return nil, err
 	}
+	return resp.Body.Breakpoints, nil
 }
 
-// ParseLogWithPlugin parses a log line using the current plugin
-func (a *App) ParseLogWithPlugin(line string) *models.LogEntry {
-	if a.currentPlugin != nil {
-		return a.currentPlugin.ParseLog(line)
+// DebugEvaluate evaluates an expression in a stack frame of a debug session
+func (a *App) DebugEvaluate(sessionID string, expression string, frameId int) (map[string]interface{}, error) {
+	session, err := a.debugSessions.Get(sessionID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Fallback to generic parsing
-	return &models.LogEntry{
-		Raw:       line,
-		Message:   line,
-		Timestamp: time.Now(),
-		Level:     models.LogLevelInfo,
+	log.Printf("[AUDIT] DebugEvaluate: session=%s, expression=%s", sessionID, expression)
+
+	resp, err := session.Evaluate(expression, frameId)
+	if err != nil {
+		return nil, err
 	}
+	return map[string]interface{}{
+		"result":             resp.Body.Result,
+		"type":               resp.Body.Type,
+		"variablesReference": resp.Body.VariablesReference,
+	}, nil
 }
 
 // ============================================================================
@@ -1442,21 +4910,19 @@ func (a *App) GetSmartRecommendations() ([]models.SmartRecommendation, error) {
 
 	// Get query statistics
 	stats := a.databaseManager.GetQueryStatistics()
+	slowThreshold := a.databaseManager.SlowQueryThreshold()
 
 	// Get N+1 warnings (if Rails plugin is available)
 	_ = []models.N1Warning{} // TODO: Implement N+1 warning collection from Rails plugin
 
-	// Get EXPLAIN results for problematic queries
-	_ = make(map[string]*database.ExplainResult) // TODO: Could cache EXPLAIN results from previous analyses
-
-	// Generate recommendations using the Rails recommendation engine
-	// For now, create a simple recommendation engine instance
-	// In a full implementation, this would be part of the Rails plugin
-	recommendations := []models.SmartRecommendation{}
+	// Index recommendations come from the EXPLAIN results of queries that
+	// have been explained
+	recommendations := rails.NewRecommendationEngine().IndexRecommendations(a.databaseManager.CachedExplains())
 
 	// Generate basic recommendations from query stats
+	n1Enabled := a.n1DetectionEnabled()
 	for _, stat := range stats {
-		if stat.Issue == "n+1" {
+		if n1Enabled && stat.Issue == "n+1" {
 			// Create N+1 recommendation
 			rec := models.SmartRecommendation{
 				ID:          stat.ID,
@@ -1481,10 +4947,10 @@ func (a *App) GetSmartRecommendations() ([]models.SmartRecommendation, error) {
 			recommendations = append(recommendations, rec)
 		}
 
-		if stat.AvgTime > 100 {
+		if stat.AvgTime > slowThreshold {
 			// Create slow query recommendation
 			severity := "medium"
-			if stat.AvgTime > 500 {
+			if stat.AvgTime > slowThreshold*5 {
 				severity = "high"
 			}
 
@@ -1512,36 +4978,213 @@ func (a *App) GetSmartRecommendations() ([]models.SmartRecommendation, error) {
 		}
 	}
 
-	return recommendations, nil
+	return recommendations, nil
+}
+
+// GetN1Warnings returns detected N+1 query patterns
+func (a *App) GetN1Warnings() ([]models.N1Warning, error) {
+	// TODO: Implement N+1 warning collection from Rails plugin
+	// For now, return warnings inferred from query statistics
+	warnings := []models.N1Warning{}
+
+	if a.databaseManager == nil || !a.n1DetectionEnabled() {
+		return warnings, nil
+	}
+
+	stats := a.databaseManager.GetQueryStatistics()
+	for _, stat := range stats {
+		if stat.Issue == "n+1" {
+			warning := models.N1Warning{
+				Fingerprint:   stat.Fingerprint,
+				Table:         "", // TODO: Extract from SQL
+				Count:         stat.Count,
+				TotalDuration: stat.TotalTime,
+				Confidence:    75,
+				Suggestion:    "Use .includes() or .joins() for eager loading",
+				Examples:      []string{stat.SQL},
+			}
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return warnings, nil
+}
+
+// GetDuplicateQueries returns queries repeated within recent requests that are
+// too infrequent to be N+1 patterns, e.g. the current user loaded twice
+func (a *App) GetDuplicateQueries() ([]models.DuplicateQuery, error) {
+	source, ok := a.currentPlugin.(interface {
+		DuplicateQueries() []models.DuplicateQuery
+	})
+	if !ok {
+		return []models.DuplicateQuery{}, nil
+	}
+
+	return source.DuplicateQueries(), nil
+}
+
+// observeQueries records a parsed log entry's SQL in the query statistics and
+// lets the framework group queries by request for duplicate detection
+func (a *App) observeQueries(source string, entry *models.LogEntry) {
+	if entry.SQL != nil && a.databaseManager != nil {
+		a.databaseManager.RecordQueryExecution(entry.SQL.Query, entry.SQL.Duration)
+	}
+
+	if observer, ok := a.currentPlugin.(interface {
+		ObserveLog(processName string, entry *models.LogEntry)
+	}); ok {
+		observer.ObserveLog(source, entry)
+	}
+}
+
+// GetSQLSources returns the SQL sources the detected framework offers and
+// whether each is enabled
+func (a *App) GetSQLSources() map[string]bool {
+	sources := make(map[string]bool)
+	provider, ok := a.currentPlugin.(plugin.SQLSourceProvider)
+	if !ok {
+		return sources
+	}
+
+	a.sqlSourceMu.Lock()
+	defer a.sqlSourceMu.Unlock()
+
+	for _, sourceType := range provider.SQLSourceTypes() {
+		_, running := a.sqlSources[sourceType]
+		sources[sourceType] = running
+	}
+	return sources
+}
+
+// EnableSQLSource starts analyzing queries from a framework SQL source, such
+// as the Rails development log of a server started outside Caboose, and saves
+// the choice. Queries from processes Caboose runs are already analyzed, so a
+// log source for the same server would count them twice.
+func (a *App) EnableSQLSource(sourceType string) error {
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+
+	if err := a.startSQLSource(sourceType); err != nil {
+		return err
+	}
+
+	enabled := false
+	for _, existing := range a.config.Database.SQLSources {
+		if existing == sourceType {
+			enabled = true
+			break
+		}
+	}
+	if !enabled {
+		a.config.Database.SQLSources = append(a.config.Database.SQLSources, sourceType)
+	}
+
+	log.Printf("[AUDIT] EnableSQLSource: %s", sourceType)
+
+	return a.saveConfig()
+}
+
+// DisableSQLSource stops a framework SQL source and saves the choice
+func (a *App) DisableSQLSource(sourceType string) error {
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+
+	a.sqlSourceMu.Lock()
+	if cancel, running := a.sqlSources[sourceType]; running {
+		cancel()
+		delete(a.sqlSources, sourceType)
+	}
+	a.sqlSourceMu.Unlock()
+
+	remaining := a.config.Database.SQLSources[:0]
+	for _, existing := range a.config.Database.SQLSources {
+		if existing != sourceType {
+			remaining = append(remaining, existing)
+		}
+	}
+	a.config.Database.SQLSources = remaining
+
+	log.Printf("[AUDIT] DisableSQLSource: %s", sourceType)
+
+	return a.saveConfig()
+}
+
+// startSQLSource runs a framework SQL source in the background, unless it is
+// already running
+func (a *App) startSQLSource(sourceType string) error {
+	provider, ok := a.currentPlugin.(plugin.SQLSourceProvider)
+	if !ok {
+		return fmt.Errorf("SQL sources not supported for framework: %s", a.frameworkName)
+	}
+
+	a.sqlSourceMu.Lock()
+	defer a.sqlSourceMu.Unlock()
+
+	if _, running := a.sqlSources[sourceType]; running {
+		return nil
+	}
+
+	source, err := provider.NewSQLSource(sourceType, a.projectDir)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.sqlSources[sourceType] = cancel
+
+	name := "sql-source:" + sourceType
+	go func() {
+		if err := source.Run(ctx, func(entry *models.LogEntry) {
+			a.observeQueries(name, entry)
+		}); err != nil {
+			log.Printf("[ERROR] SQL source %s stopped: %v", sourceType, err)
+		}
+	}()
+
+	log.Printf("[Database] Started SQL source: %s", sourceType)
+	return nil
 }
 
-// GetN1Warnings returns detected N+1 query patterns
-func (a *App) GetN1Warnings() ([]models.N1Warning, error) {
-	// TODO: Implement N+1 warning collection from Rails plugin
-	// For now, return warnings inferred from query statistics
-	warnings := []models.N1Warning{}
+// restartSQLSources stops any running SQL sources and starts the ones the
+// current project's config enables
+func (a *App) restartSQLSources() {
+	a.stopSQLSources()
 
-	if a.databaseManager == nil {
-		return warnings, nil
+	if a.config == nil {
+		return
 	}
-
-	stats := a.databaseManager.GetQueryStatistics()
-	for _, stat := range stats {
-		if stat.Issue == "n+1" {
-			warning := models.N1Warning{
-				Fingerprint:   stat.Fingerprint,
-				Table:         "", // TODO: Extract from SQL
-				Count:         stat.Count,
-				TotalDuration: stat.TotalTime,
-				Confidence:    75,
-				Suggestion:    "Use .includes() or .joins() for eager loading",
-				Examples:      []string{stat.SQL},
-			}
-			warnings = append(warnings, warning)
+	for _, sourceType := range a.config.Database.SQLSources {
+		if err := a.startSQLSource(sourceType); err != nil {
+			log.Printf("[ERROR] Failed to start SQL source %s: %v", sourceType, err)
 		}
 	}
+}
 
-	return warnings, nil
+// stopSQLSources stops every running SQL source
+func (a *App) stopSQLSources() {
+	a.sqlSourceMu.Lock()
+	defer a.sqlSourceMu.Unlock()
+
+	for sourceType, cancel := range a.sqlSources {
+		cancel()
+		delete(a.sqlSources, sourceType)
+	}
+}
+
+// SetN1Detection enables or disables N+1 query detection and saves the setting
+func (a *App) SetN1Detection(enabled bool) error {
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+
+	a.config.Database.EnableN1Detection = enabled
+	a.applyDatabaseConfig()
+
+	log.Printf("[AUDIT] SetN1Detection: enabled=%t", enabled)
+
+	return a.saveConfig()
 }
 
 // GetRequestQueryGroups returns queries grouped by HTTP request
@@ -1629,6 +5272,56 @@ func (a *App) IgnoreQueryPattern(fingerprint string) error {
 	return nil
 }
 
+// ExportRecommendationAsMigration renders a recommendation's fix as a Rails migration.
+// Index recommendations produce an add_index/remove_index migration, other fixes a
+// commented code snippet.
+func (a *App) ExportRecommendationAsMigration(recID string) (*rails.MigrationFile, error) {
+	rec, err := a.findRecommendation(recID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rails.GenerateMigration(*rec, rails.DetectMigrationVersion(a.projectDir), time.Now()), nil
+}
+
+// WriteRecommendationMigration generates the migration for a recommendation and
+// writes it into the project's db/migrate directory
+func (a *App) WriteRecommendationMigration(recID string) (*rails.MigrationFile, error) {
+	if a.projectDir == "" {
+		return nil, fmt.Errorf("no project directory set")
+	}
+
+	migration, err := a.ExportRecommendationAsMigration(recID)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := rails.WriteMigration(a.projectDir, migration)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[AUDIT] WriteRecommendationMigration: recommendation=%s, path=%s", recID, path)
+
+	return migration, nil
+}
+
+// findRecommendation looks up a recommendation by ID from the current analysis
+func (a *App) findRecommendation(recID string) (*models.SmartRecommendation, error) {
+	recommendations, err := a.GetSmartRecommendations()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range recommendations {
+		if recommendations[i].ID == recID {
+			return &recommendations[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("recommendation not found: %s", recID)
+}
+
 // CompareQueryPlans compares two query execution plans
 func (a *App) CompareQueryPlans(originalSQL, optimizedSQL string) (*models.QueryComparison, error) {
 	if a.databaseManager == nil {
@@ -1646,6 +5339,11 @@ func (a *App) CompareQueryPlans(originalSQL, optimizedSQL string) (*models.Query
 		return nil, fmt.Errorf("failed to explain optimized query: %w", err)
 	}
 
+	return compareExplains(originalSQL, beforeExplain, optimizedSQL, afterExplain), nil
+}
+
+// compareExplains measures the improvement from one plan to another
+func compareExplains(originalSQL string, beforeExplain *database.ExplainResult, optimizedSQL string, afterExplain *database.ExplainResult) *models.QueryComparison {
 	// Calculate improvements
 	timeReduction := 0.0
 	if beforeExplain.ExecutionTime > 0 {
@@ -1679,9 +5377,273 @@ func (a *App) CompareQueryPlans(originalSQL, optimizedSQL string) (*models.Query
 			RowsReduction:    rowsReduction,
 			ScoreImprovement: scoreImprovement,
 		},
+	}
+}
+
+// SetQueryBaseline explains a query on the worker pool and keeps the plan as
+// its baseline, so it can be compared after a change such as a new index
+// with CompareToBaseline
+func (a *App) SetQueryBaseline(query string) (*database.PlanBaseline, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	result := a.runWatchedQuery("query-baseline", query, func(ctx context.Context) (interface{}, error) {
+		return a.databaseManager.ExplainQuery(query)
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return a.databaseManager.SetPlanBaseline(query, result.Data.(*database.ExplainResult)), nil
+}
+
+// CompareToBaseline explains a query on the worker pool and compares the
+// plan with the baseline SetQueryBaseline stored for it
+func (a *App) CompareToBaseline(query string) (*models.QueryComparison, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	baseline, ok := a.databaseManager.GetPlanBaseline(query)
+	if !ok {
+		return nil, fmt.Errorf("no baseline for this query; set one first")
+	}
+
+	result := a.runWatchedQuery("query-explain", query, func(ctx context.Context) (interface{}, error) {
+		return a.databaseManager.ExplainQuery(query)
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return compareExplains(baseline.SQL, baseline.Explain, query, result.Data.(*database.ExplainResult)), nil
+}
+
+// ClearQueryBaseline forgets the baseline stored for a query
+func (a *App) ClearQueryBaseline(query string) error {
+	if a.databaseManager == nil {
+		return fmt.Errorf("database manager not initialized")
+	}
+
+	a.databaseManager.ClearPlanBaseline(query)
+	return nil
+}
+
+// SaveOptimization compares an original and optimized query and saves the pair
+// with its improvement metrics, so optimizations can be tracked over time
+func (a *App) SaveOptimization(originalSQL, optimizedSQL, note string) (*models.OptimizationSession, error) {
+	if a.config == nil {
+		return nil, fmt.Errorf("config not initialized")
+	}
+
+	comparison, err := a.CompareQueryPlans(originalSQL, optimizedSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	session := config.OptimizationSession{
+		ID:               uuid.New().String(),
+		OriginalSQL:      originalSQL,
+		OptimizedSQL:     optimizedSQL,
+		Note:             note,
+		CreatedAt:        time.Now().Format(time.RFC3339),
+		BeforeTime:       comparison.Before.EstimatedTime,
+		BeforeRows:       comparison.Before.RowsExamined,
+		BeforeScore:      comparison.Before.PerformanceScore,
+		AfterTime:        comparison.After.EstimatedTime,
+		AfterRows:        comparison.After.RowsExamined,
+		AfterScore:       comparison.After.PerformanceScore,
+		TimeReduction:    comparison.Improvement.TimeReduction,
+		RowsReduction:    comparison.Improvement.RowsReduction,
+		ScoreImprovement: comparison.Improvement.ScoreImprovement,
+	}
+
+	a.config.Database.Optimizations = append(a.config.Database.Optimizations, session)
+	if err := a.saveConfig(); err != nil {
+		return nil, err
+	}
+
+	return &models.OptimizationSession{
+		ID:           session.ID,
+		OriginalSQL:  session.OriginalSQL,
+		OptimizedSQL: session.OptimizedSQL,
+		Note:         session.Note,
+		CreatedAt:    session.CreatedAt,
+		Comparison:   *comparison,
 	}, nil
 }
 
+// GetOptimizations returns saved optimization sessions, most recent first
+func (a *App) GetOptimizations() []models.OptimizationSession {
+	if a.config == nil {
+		return []models.OptimizationSession{}
+	}
+
+	saved := a.config.Database.Optimizations
+	sessions := make([]models.OptimizationSession, 0, len(saved))
+	for i := len(saved) - 1; i >= 0; i-- {
+		s := saved[i]
+		sessions = append(sessions, models.OptimizationSession{
+			ID:           s.ID,
+			OriginalSQL:  s.OriginalSQL,
+			OptimizedSQL: s.OptimizedSQL,
+			Note:         s.Note,
+			CreatedAt:    s.CreatedAt,
+			Comparison: models.QueryComparison{
+				Before: models.QueryExecution{
+					SQL:              s.OriginalSQL,
+					EstimatedTime:    s.BeforeTime,
+					RowsExamined:     s.BeforeRows,
+					PerformanceScore: s.BeforeScore,
+				},
+				After: models.QueryExecution{
+					SQL:              s.OptimizedSQL,
+					EstimatedTime:    s.AfterTime,
+					RowsExamined:     s.AfterRows,
+					PerformanceScore: s.AfterScore,
+				},
+				Improvement: models.Improvement{
+					TimeReduction:    s.TimeReduction,
+					RowsReduction:    s.RowsReduction,
+					ScoreImprovement: s.ScoreImprovement,
+				},
+			},
+		})
+	}
+
+	return sessions
+}
+
+// DeleteOptimization deletes a saved optimization session
+func (a *App) DeleteOptimization(id string) error {
+	if a.config == nil {
+		return fmt.Errorf("config not initialized")
+	}
+
+	filtered := make([]config.OptimizationSession, 0)
+	for _, s := range a.config.Database.Optimizations {
+		if s.ID != id {
+			filtered = append(filtered, s)
+		}
+	}
+	if len(filtered) == len(a.config.Database.Optimizations) {
+		return fmt.Errorf("optimization not found: %s", id)
+	}
+	a.config.Database.Optimizations = filtered
+
+	return a.saveConfig()
+}
+
+// AggregateResultColumn computes count, distinct, sum, avg, min or max over a
+// column of a result the frontend already has, without querying again
+func (a *App) AggregateResultColumn(result *database.QueryResult, column, op string) (*database.ColumnAggregate, error) {
+	return database.AggregateColumn(result, column, op)
+}
+
+// DiffQueryResults runs two read-only queries and compares their rows, to
+// confirm a rewritten query returns the same data as the original
+func (a *App) DiffQueryResults(sqlA, sqlB string, limit int) (*database.ResultDiff, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	if !a.rateLimiter.Allow("query") {
+		log.Printf("[SECURITY] Rate limit exceeded for query")
+		return nil, fmt.Errorf("rate limit exceeded: too many query requests")
+	}
+
+	// SECURITY: Both queries are executed, so neither may modify data. The
+	// configured keywords can only add to the defaults here.
+	for _, query := range []string{sqlA, sqlB} {
+		if security.IsDestructiveQuery(query) || a.isDestructiveQuery(query) {
+			log.Printf("[SECURITY] Destructive query rejected for diff: %s", query[:min(50, len(query))])
+			return nil, fmt.Errorf("only read-only queries can be compared")
+		}
+	}
+
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	log.Printf("[AUDIT] DiffQueryResults: a=%s..., b=%s...", sqlA[:min(50, len(sqlA))], sqlB[:min(50, len(sqlB))])
+
+	resultA, err := a.executeReadOnly(sqlA, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute first query: %w", err)
+	}
+	resultB, err := a.executeReadOnly(sqlB, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute second query: %w", err)
+	}
+
+	return database.DiffQueryResults(resultA, resultB), nil
+}
+
+// OpenResultCursor runs a read-only SELECT and holds its result open so it can
+// be paged with FetchCursorPage without re-running the query
+func (a *App) OpenResultCursor(query string) (string, error) {
+	if a.databaseManager == nil {
+		return "", fmt.Errorf("database manager not initialized")
+	}
+
+	if !a.rateLimiter.Allow("query") {
+		return "", fmt.Errorf("rate limit exceeded: too many queries")
+	}
+
+	// SECURITY: Cursors are read-only
+	if security.IsDestructiveQuery(query) || a.isDestructiveQuery(query) {
+		log.Printf("[SECURITY] Blocked destructive query in cursor: %s", query[:min(100, len(query))])
+		return "", fmt.Errorf("cursors only support read-only queries")
+	}
+
+	id, err := a.databaseManager.OpenCursor(query)
+	if err != nil {
+		log.Printf("[ERROR] Open cursor failed: %v", err)
+		return "", security.SanitizeError(err, false)
+	}
+
+	return id, nil
+}
+
+// FetchCursorPage returns the next page of rows from an open cursor
+func (a *App) FetchCursorPage(cursorID string, size int) (*database.CursorPage, error) {
+	if a.databaseManager == nil {
+		return nil, fmt.Errorf("database manager not initialized")
+	}
+
+	page, err := a.databaseManager.FetchCursorPage(cursorID, size)
+	if err != nil {
+		return nil, security.SanitizeError(err, false)
+	}
+
+	return page, nil
+}
+
+// CloseResultCursor releases an open cursor and its connection
+func (a *App) CloseResultCursor(cursorID string) error {
+	if a.databaseManager == nil {
+		return fmt.Errorf("database manager not initialized")
+	}
+
+	return a.databaseManager.CloseCursor(cursorID)
+}
+
+// executeReadOnly runs a query that must return rows, surfacing driver errors
+func (a *App) executeReadOnly(query string, limit int) (*database.QueryResult, error) {
+	result, err := a.databaseManager.ExecuteQuery(query, limit)
+	if err != nil {
+		return nil, security.SanitizeError(err, false)
+	}
+	if result.Error != "" {
+		return nil, security.SanitizeError(fmt.Errorf("%s", result.Error), false)
+	}
+	if !result.IsSelect {
+		return nil, fmt.Errorf("query does not return rows")
+	}
+	return result, nil
+}
+
 // Helper functions for SQL parsing
 func extractTableFromSQL(sql string) string {
 	sql = strings.ToUpper(sql)
@@ -1699,31 +5661,176 @@ func extractTableFromSQL(sql string) string {
 			return strings.ToLower(words[0])
 		}
 	}
-	if idx := strings.Index(sql, "UPDATE "); idx != -1 {
-		rest := sql[idx+7:]
-		words := strings.Fields(rest)
-		if len(words) > 0 {
-			return strings.ToLower(words[0])
-		}
+	if idx := strings.Index(sql, "UPDATE "); idx != -1 {
+		rest := sql[idx+7:]
+		words := strings.Fields(rest)
+		if len(words) > 0 {
+			return strings.ToLower(words[0])
+		}
+	}
+	return ""
+}
+
+func extractOperationFromSQL(sql string) string {
+	sql = strings.ToUpper(strings.TrimSpace(sql))
+	if strings.HasPrefix(sql, "SELECT") {
+		return "SELECT"
+	}
+	if strings.HasPrefix(sql, "INSERT") {
+		return "INSERT"
+	}
+	if strings.HasPrefix(sql, "UPDATE") {
+		return "UPDATE"
+	}
+	if strings.HasPrefix(sql, "DELETE") {
+		return "DELETE"
+	}
+	return ""
+}
+
+// ============================================================================
+// Redis API Methods
+// ============================================================================
+
+// maxRedisScanKeys bounds the keys returned by one GetRedisKeys call
+const maxRedisScanKeys = 10000
+
+// maxRedisKeyLength bounds key names and patterns passed to Redis
+const maxRedisKeyLength = 1024
+
+// ConnectRedis connects to a Redis server, replacing any open connection
+func (a *App) ConnectRedis(config redis.ConnectionConfig) error {
+	config.Host = strings.TrimSpace(config.Host)
+	if config.Host == "" {
+		config.Host = "localhost"
+	}
+	if strings.ContainsAny(config.Host, " \t/") {
+		return fmt.Errorf("invalid host: %s", config.Host)
+	}
+	if config.Port == 0 {
+		config.Port = 6379
+	}
+	if config.Port < 1 || config.Port > 65535 {
+		return fmt.Errorf("invalid port: %d", config.Port)
+	}
+	if config.DB < 0 {
+		return fmt.Errorf("invalid database index: %d", config.DB)
+	}
+
+	a.DisconnectRedis()
+
+	client := redis.NewClient()
+	if err := client.Connect(config); err != nil {
+		return security.SanitizeError(err, false)
+	}
+
+	log.Printf("[AUDIT] ConnectRedis: host=%s, port=%d, db=%d", config.Host, config.Port, config.DB)
+
+	a.redisMu.Lock()
+	a.redisClient = client
+	a.redisMu.Unlock()
+
+	return nil
+}
+
+// DisconnectRedis closes the Redis connection, if any
+func (a *App) DisconnectRedis() error {
+	a.redisMu.Lock()
+	client := a.redisClient
+	a.redisClient = nil
+	a.redisMu.Unlock()
+
+	if client == nil {
+		return nil
+	}
+	return client.Close()
+}
+
+// connectedRedis returns the connected Redis client
+func (a *App) connectedRedis() (*redis.Client, error) {
+	a.redisMu.Lock()
+	defer a.redisMu.Unlock()
+
+	if a.redisClient == nil || !a.redisClient.IsConnected() {
+		return nil, fmt.Errorf("not connected to redis")
+	}
+	return a.redisClient, nil
+}
+
+// GetRedisKeys returns up to limit keys matching a glob pattern such as
+// "cache:*". The keyspace is walked with SCAN on the worker pool, so a large
+// database is never blocked by KEYS.
+func (a *App) GetRedisKeys(pattern string, limit int) (*redis.KeyScan, error) {
+	client, err := a.connectedRedis()
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.rateLimiter.Allow("query") {
+		return nil, fmt.Errorf("rate limit exceeded: too many query requests")
+	}
+	if len(pattern) > maxRedisKeyLength {
+		return nil, fmt.Errorf("pattern is too long (max %d bytes)", maxRedisKeyLength)
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	limit = min(limit, maxRedisScanKeys)
+
+	result := a.workerPool.SubmitAndWait("redis-scan", func(ctx context.Context) (interface{}, error) {
+		return client.Keys(pattern, limit)
+	})
+	if result.Error != nil {
+		log.Printf("[ERROR] Redis scan failed: %v", result.Error)
+		return nil, security.SanitizeError(result.Error, false)
 	}
-	return ""
+
+	return result.Data.(*redis.KeyScan), nil
 }
 
-func extractOperationFromSQL(sql string) string {
-	sql = strings.ToUpper(strings.TrimSpace(sql))
-	if strings.HasPrefix(sql, "SELECT") {
-		return "SELECT"
+// GetRedisValue returns a key's type, TTL and value; collections are cut to
+// their first elements
+func (a *App) GetRedisValue(key string) (*redis.Value, error) {
+	client, err := a.connectedRedis()
+	if err != nil {
+		return nil, err
 	}
-	if strings.HasPrefix(sql, "INSERT") {
-		return "INSERT"
+	if key == "" || len(key) > maxRedisKeyLength {
+		return nil, fmt.Errorf("invalid key")
 	}
-	if strings.HasPrefix(sql, "UPDATE") {
-		return "UPDATE"
+
+	result := a.workerPool.SubmitAndWait("redis-get", func(ctx context.Context) (interface{}, error) {
+		return client.Get(key)
+	})
+	if result.Error != nil {
+		return nil, security.SanitizeError(result.Error, false)
 	}
-	if strings.HasPrefix(sql, "DELETE") {
-		return "DELETE"
+
+	return result.Data.(*redis.Value), nil
+}
+
+// GetRedisTTL returns a key's remaining time to live in seconds: -1 if it
+// never expires, -2 if it doesn't exist
+func (a *App) GetRedisTTL(key string) (int64, error) {
+	client, err := a.connectedRedis()
+	if err != nil {
+		return 0, err
 	}
-	return ""
+	if key == "" || len(key) > maxRedisKeyLength {
+		return 0, fmt.Errorf("invalid key")
+	}
+
+	return client.TTL(key)
+}
+
+// GetRedisInfo returns the server's INFO fields grouped by section
+func (a *App) GetRedisInfo() (map[string]map[string]string, error) {
+	client, err := a.connectedRedis()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Info()
 }
 
 // ============================================================================
@@ -1747,6 +5854,22 @@ func getInt(m map[string]interface{}, key string) int {
 	return 0
 }
 
+func getBool(m map[string]interface{}, key string) bool {
+	v, _ := m[key].(bool)
+	return v
+}
+
+func getStrings(m map[string]interface{}, key string) []string {
+	raw, _ := m[key].([]interface{})
+	values := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
 // ============================================================================
 // SSH API Methods
 // ============================================================================
@@ -1768,6 +5891,64 @@ func (a *App) SaveSSHServer(server models.SSHServer) error {
 		return fmt.Errorf("config not loaded")
 	}
 
+	a.upsertSSHServer(server)
+	return a.saveConfig()
+}
+
+// ImportSSHConfig reads host definitions from ~/.ssh/config for preview. Hosts
+// already saved (same host, port and user) are left out. Nothing is saved
+// until the chosen servers are passed to SaveSSHServers.
+func (a *App) ImportSSHConfig() ([]models.SSHServer, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	configPath := filepath.Join(home, ".ssh", "config")
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no SSH config found at %s", configPath)
+	}
+
+	servers, err := ssh.ParseSSHConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := []models.SSHServer{}
+	for _, server := range servers {
+		saved := false
+		for _, existing := range a.GetSSHServers() {
+			if existing.Host == server.Host && existing.Port == server.Port && existing.Username == server.Username {
+				saved = true
+				break
+			}
+		}
+		if !saved {
+			preview = append(preview, server)
+		}
+	}
+
+	return preview, nil
+}
+
+// SaveSSHServers adds or updates several SSH server configurations at once,
+// e.g. the hosts chosen from an ImportSSHConfig preview
+func (a *App) SaveSSHServers(servers []models.SSHServer) error {
+	if a.config == nil {
+		return fmt.Errorf("config not loaded")
+	}
+
+	for _, server := range servers {
+		a.upsertSSHServer(server)
+	}
+
+	log.Printf("[AUDIT] SaveSSHServers: saved %d server(s)", len(servers))
+
+	return a.saveConfig()
+}
+
+// upsertSSHServer adds or replaces a server in the config, filling in defaults
+func (a *App) upsertSSHServer(server models.SSHServer) {
 	// Validate
 	if server.ID == "" {
 		server.ID = uuid.New().String()
@@ -1789,8 +5970,6 @@ func (a *App) SaveSSHServer(server models.SSHServer) error {
 	if !found {
 		a.config.SSH.SavedServers = append(a.config.SSH.SavedServers, server)
 	}
-
-	return a.config.Save(a.projectDir)
 }
 
 // DeleteSSHServer removes an SSH server configuration
@@ -1806,7 +5985,7 @@ func (a *App) DeleteSSHServer(id string) error {
 		}
 	}
 	a.config.SSH.SavedServers = filtered
-	return a.config.Save(a.projectDir)
+	return a.saveConfig()
 }
 
 // ConnectSSH establishes an SSH connection to a saved server
@@ -1831,7 +6010,79 @@ func (a *App) ConnectSSH(serverID string) (string, error) {
 	server.LastConnected = &now
 	a.SaveSSHServer(*server)
 
-	return a.sshManager.CreateSession(*server)
+	sessionID, err := a.sshManager.CreateSession(*server)
+	var locked *ssh.PassphraseRequiredError
+	if errors.As(err, &locked) {
+		a.emit("ssh:key-passphrase-required", map[string]interface{}{
+			"serverId": serverID,
+			"keyPath":  locked.KeyPath,
+		})
+	}
+	var unknown *ssh.UnknownHostKeyError
+	if errors.As(err, &unknown) {
+		a.emit("ssh:host-key-unknown", map[string]interface{}{
+			"serverId":    serverID,
+			"host":        unknown.Hostname,
+			"keyType":     unknown.Key.Type(),
+			"fingerprint": unknown.Fingerprint(),
+		})
+	}
+	return sessionID, err
+}
+
+// TrustSSHHostKey adds the host key a ConnectSSH was refused for to
+// known_hosts, after the user has compared its fingerprint. The entry is
+// hashed when ssh.hash_known_hosts is set.
+func (a *App) TrustSSHHostKey(fingerprint string) error {
+	if a.sshManager == nil {
+		return fmt.Errorf("ssh manager not initialized")
+	}
+
+	host, err := a.sshManager.TrustHostKey(fingerprint)
+	if err != nil {
+		log.Printf("[SECURITY] TrustSSHHostKey failed: fingerprint=%s, error=%v", fingerprint, err)
+		return err
+	}
+
+	log.Printf("[AUDIT] TrustSSHHostKey: host=%s, fingerprint=%s", host, fingerprint)
+
+	return nil
+}
+
+// UnlockSSHKey decrypts a saved server's passphrase protected private key so
+// ConnectSSH can use it. The decrypted key is kept in memory only, for every
+// server using the same key file, until the app exits; the passphrase is
+// not stored or logged.
+func (a *App) UnlockSSHKey(serverID, passphrase string) error {
+	if a.config == nil {
+		return fmt.Errorf("config not loaded")
+	}
+	if a.sshManager == nil {
+		return fmt.Errorf("ssh manager not initialized")
+	}
+
+	var server *models.SSHServer
+	for _, s := range a.config.SSH.SavedServers {
+		if s.ID == serverID {
+			server = &s
+			break
+		}
+	}
+	if server == nil {
+		return fmt.Errorf("server not found: %s", serverID)
+	}
+	if server.PrivateKeyPath == "" {
+		return fmt.Errorf("server %s does not use a private key", server.Name)
+	}
+
+	if err := a.sshManager.UnlockKey(server.PrivateKeyPath, passphrase); err != nil {
+		log.Printf("[SECURITY] UnlockSSHKey failed: server=%s, key=%s", server.Name, server.PrivateKeyPath)
+		return err
+	}
+
+	log.Printf("[AUDIT] UnlockSSHKey: server=%s, key=%s", server.Name, server.PrivateKeyPath)
+
+	return nil
 }
 
 // DisconnectSSH closes an SSH session
@@ -1870,6 +6121,29 @@ func (a *App) ExportSSHSession(sessionID string, format string) (string, error)
 	return exportSSHLogsPlainText(logs), nil
 }
 
+// RunSSHCommand runs a single non-interactive command on an SSH session's
+// server, e.g. for health checks, without typing into its terminal
+func (a *App) RunSSHCommand(sessionID string, command string) (*models.SSHCommandResult, error) {
+	if a.sshManager == nil {
+		return nil, fmt.Errorf("ssh manager not initialized")
+	}
+
+	log.Printf("[AUDIT] RunSSHCommand: session=%s command=%q", sessionID, command)
+
+	start := time.Now()
+	stdout, stderr, exitCode, err := a.sshManager.RunCommand(sessionID, command)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SSHCommandResult{
+		Stdout:   stdout,
+		Stderr:   stderr,
+		ExitCode: exitCode,
+		Duration: time.Since(start).Milliseconds(),
+	}, nil
+}
+
 // GetSSHSessions returns all active SSH sessions
 func (a *App) GetSSHSessions() []models.SSHSession {
 	return a.sshManager.GetAllSessions()
@@ -1905,9 +6179,28 @@ func exportSSHLogsPlainText(logs []models.SSHSessionLog) string {
 // Git Integration API Methods
 // ============================================================================
 
+// IsGitAvailable reports whether git is installed, so the frontend can hide
+// the git UI entirely when it isn't
+func (a *App) IsGitAvailable() bool {
+	_, err := git.CheckGitAvailable()
+	return err == nil
+}
+
+// gitReady returns the error git methods fail with before running git: no
+// project is open, or git isn't installed
+func (a *App) gitReady() error {
+	if a.gitManager == nil {
+		return fmt.Errorf("git manager not initialized")
+	}
+	if _, err := git.CheckGitAvailable(); err != nil {
+		return err
+	}
+	return nil
+}
+
 // IsGitRepository checks if the current project is a git repository
 func (a *App) IsGitRepository() bool {
-	if a.gitManager == nil {
+	if a.gitReady() != nil {
 		return false
 	}
 	return a.gitManager.IsGitRepository()
@@ -1915,128 +6208,336 @@ func (a *App) IsGitRepository() bool {
 
 // GetGitStatus returns the current git repository status
 func (a *App) GetGitStatus() (*models.GitStatus, error) {
-	if a.gitManager == nil {
-		return nil, fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return nil, err
 	}
 	return a.gitManager.GetStatus()
 }
 
 // GetGitDiff returns the diff for files
 func (a *App) GetGitDiff(options models.GitDiffOptions) ([]models.GitDiff, error) {
-	if a.gitManager == nil {
-		return nil, fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return nil, err
 	}
 	return a.gitManager.GetDiff(options)
 }
 
+// GetDiffContext returns a line range of a file at a revision for expanding diff context
+func (a *App) GetDiffContext(filePath string, startLine, endLine int, ref string) (*models.GitDiffContext, error) {
+	if err := a.gitReady(); err != nil {
+		return nil, err
+	}
+	return a.gitManager.GetDiffContext(filePath, startLine, endLine, ref)
+}
+
 // GetGitBlame returns blame information for a file
 func (a *App) GetGitBlame(filePath string) (*models.GitBlameFile, error) {
-	if a.gitManager == nil {
-		return nil, fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return nil, err
 	}
 	return a.gitManager.GetBlame(filePath)
 }
 
+// GetGitBlameRange returns blame information for a range of lines in a file
+func (a *App) GetGitBlameRange(filePath string, startLine, endLine int) (*models.GitBlameFile, error) {
+	if err := a.gitReady(); err != nil {
+		return nil, err
+	}
+	return a.gitManager.GetBlameRange(filePath, startLine, endLine)
+}
+
+// GetGitCommitDetail returns full details of a commit, including changed files
+func (a *App) GetGitCommitDetail(hash string) (*models.GitCommit, error) {
+	if err := a.gitReady(); err != nil {
+		return nil, err
+	}
+	return a.gitManager.GetCommitDetail(hash)
+}
+
 // GetGitLog returns commit history
 func (a *App) GetGitLog(options models.GitLogOptions) ([]models.GitCommit, error) {
-	if a.gitManager == nil {
-		return nil, fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return nil, err
 	}
 	return a.gitManager.GetLog(options)
 }
 
 // StageFiles stages files for commit
 func (a *App) StageFiles(files []string) error {
-	if a.gitManager == nil {
-		return fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return err
 	}
 	return a.gitManager.Stage(files)
 }
 
 // UnstageFiles unstages files
 func (a *App) UnstageFiles(files []string) error {
-	if a.gitManager == nil {
-		return fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return err
 	}
 	return a.gitManager.Unstage(files)
 }
 
-// CommitChanges creates a git commit
-func (a *App) CommitChanges(options models.GitCommitOptions) error {
-	if a.gitManager == nil {
-		return fmt.Errorf("git manager not initialized")
+// StageAllFiles stages all changes in the working tree
+func (a *App) StageAllFiles() error {
+	if err := a.gitReady(); err != nil {
+		return err
+	}
+	return a.gitManager.StageAll()
+}
+
+// UnstageAllFiles unstages all staged changes
+func (a *App) UnstageAllFiles() error {
+	if err := a.gitReady(); err != nil {
+		return err
+	}
+	return a.gitManager.UnstageAll()
+}
+
+// DiscardAllChanges discards all unstaged changes and untracked files after explicit confirmation
+func (a *App) DiscardAllChanges(confirmed bool) error {
+	if err := a.gitReady(); err != nil {
+		return err
+	}
+
+	// Require explicit confirmation
+	if !confirmed {
+		return fmt.Errorf("discarding all changes requires confirmation")
+	}
+
+	// Log destructive operation for audit
+	log.Printf("[AUDIT] DiscardAllChanges: directory=%s", a.projectDir)
+
+	// Never clean away the project config, even when it's untracked
+	return a.gitManager.DiscardAllChanges([]string{config.ConfigFileName})
+}
+
+// ListUntrackedFiles returns untracked (and optionally ignored) files that can be cleaned
+func (a *App) ListUntrackedFiles(includeIgnored bool) ([]string, error) {
+	if err := a.gitReady(); err != nil {
+		return nil, err
+	}
+
+	paths, err := a.gitManager.ListUntracked(includeIgnored)
+	if err != nil {
+		return nil, err
+	}
+
+	// The project config is never cleaned, even when it's untracked
+	filtered := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if path != config.ConfigFileName {
+			filtered = append(filtered, path)
+		}
+	}
+	return filtered, nil
+}
+
+// CleanUntrackedFiles permanently removes untracked files after explicit confirmation.
+// Only paths currently reported by ListUntrackedFiles are accepted.
+func (a *App) CleanUntrackedFiles(paths []string, includeIgnored bool, confirmed bool) error {
+	if err := a.gitReady(); err != nil {
+		return err
+	}
+
+	// Require explicit confirmation
+	if !confirmed {
+		return fmt.Errorf("cleaning untracked files requires confirmation")
+	}
+
+	untracked, err := a.ListUntrackedFiles(includeIgnored)
+	if err != nil {
+		return err
+	}
+	allowed := make(map[string]bool, len(untracked))
+	for _, path := range untracked {
+		allowed[path] = true
+	}
+	for _, path := range paths {
+		if !allowed[path] {
+			return fmt.Errorf("not an untracked path: %s", path)
+		}
+	}
+
+	// Log destructive operation for audit
+	log.Printf("[AUDIT] CleanUntrackedFiles: directory=%s, includeIgnored=%t, paths=%v", a.projectDir, includeIgnored, paths)
+
+	return a.gitManager.CleanUntracked(paths, includeIgnored)
+}
+
+// CommitChanges creates a git commit. A commit git refuses, e.g. because a
+// hook rejected it, is reported in the result rather than as an error.
+func (a *App) CommitChanges(options models.GitCommitOptions) (*models.GitCommitResult, error) {
+	if err := a.gitReady(); err != nil {
+		return nil, err
 	}
 	return a.gitManager.Commit(options)
 }
 
+// GetCommitTemplate returns the repository's commit message template
+// (git's commit.template) for pre-filling the commit message
+func (a *App) GetCommitTemplate() (string, error) {
+	if err := a.gitReady(); err != nil {
+		return "", err
+	}
+	return a.gitManager.GetCommitTemplate()
+}
+
 // RevertFile reverts a file to HEAD
 func (a *App) RevertFile(filePath string) error {
-	if a.gitManager == nil {
-		return fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return err
 	}
 	return a.gitManager.RevertFile(filePath)
 }
 
 // RevertFileToCommit reverts a file to a specific commit
 func (a *App) RevertFileToCommit(filePath string, commitHash string) error {
-	if a.gitManager == nil {
-		return fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return err
 	}
 	return a.gitManager.RevertToCommit(filePath, commitHash)
 }
 
 // DiscardChanges discards all changes in a file
 func (a *App) DiscardChanges(filePath string) error {
-	if a.gitManager == nil {
-		return fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return err
 	}
 	return a.gitManager.DiscardChanges(filePath)
 }
 
 // GetConflictFile returns conflict information for a file
 func (a *App) GetConflictFile(filePath string) (*models.GitConflictFile, error) {
-	if a.gitManager == nil {
-		return nil, fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return nil, err
 	}
 	return a.gitManager.GetConflictFile(filePath)
 }
 
 // ResolveConflict resolves a conflict by accepting ours or theirs
 func (a *App) ResolveConflict(filePath string, resolution string) error {
-	if a.gitManager == nil {
-		return fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return err
 	}
 	return a.gitManager.ResolveConflict(filePath, resolution)
 }
 
+// PreviewConflictResolution returns a conflicted file's content with each
+// chosen conflict region resolved, without writing it
+func (a *App) PreviewConflictResolution(filePath string, choices []models.GitRegionChoice) (*models.GitConflictPreview, error) {
+	if err := a.gitReady(); err != nil {
+		return nil, err
+	}
+	return a.gitManager.PreviewConflictResolution(filePath, choices)
+}
+
+// ApplyConflictResolution writes a conflicted file's merged content and stages it
+func (a *App) ApplyConflictResolution(filePath, mergedContent string) error {
+	if err := a.gitReady(); err != nil {
+		return err
+	}
+	return a.gitManager.ApplyConflictResolution(filePath, mergedContent)
+}
+
+// CherryPick applies a commit onto the current branch
+func (a *App) CherryPick(hash string) (*models.GitMergeResult, error) {
+	if err := a.gitReady(); err != nil {
+		return nil, err
+	}
+	return a.gitManager.CherryPick(hash)
+}
+
+// AbortCherryPick abandons a cherry-pick that stopped on conflicts
+func (a *App) AbortCherryPick() error {
+	if err := a.gitReady(); err != nil {
+		return err
+	}
+	return a.gitManager.AbortCherryPick()
+}
+
+// RevertCommit reverts an entire commit with a new commit
+func (a *App) RevertCommit(hash string) (*models.GitMergeResult, error) {
+	if err := a.gitReady(); err != nil {
+		return nil, err
+	}
+	return a.gitManager.RevertCommit(hash)
+}
+
+// AbortRevert abandons a revert that stopped on conflicts
+func (a *App) AbortRevert() error {
+	if err := a.gitReady(); err != nil {
+		return err
+	}
+	return a.gitManager.AbortRevert()
+}
+
+// GetRebaseTodo lists the commits an interactive rebase onto ontoRef would
+// replay, oldest first, each set to pick
+func (a *App) GetRebaseTodo(ontoRef string) ([]models.GitRebaseAction, error) {
+	if err := a.gitReady(); err != nil {
+		return nil, err
+	}
+	return a.gitManager.GetRebaseTodo(ontoRef)
+}
+
+// StartInteractiveRebase rebases the current branch onto ontoRef, replaying
+// the commits in the order given with each one's pick, reword, squash, fixup
+// or drop action. Conflicts are reported in the result like a cherry-pick's.
+func (a *App) StartInteractiveRebase(ontoRef string, actions []models.GitRebaseAction) (*models.GitMergeResult, error) {
+	if err := a.gitReady(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[AUDIT] StartInteractiveRebase: directory=%s, onto=%s, commits=%d", a.projectDir, ontoRef, len(actions))
+
+	return a.gitManager.StartInteractiveRebase(ontoRef, actions)
+}
+
+// ContinueRebase resumes a rebase once its conflicts are resolved
+func (a *App) ContinueRebase() (*models.GitMergeResult, error) {
+	if err := a.gitReady(); err != nil {
+		return nil, err
+	}
+	return a.gitManager.ContinueRebase()
+}
+
+// AbortRebase abandons a rebase that stopped on conflicts
+func (a *App) AbortRebase() error {
+	if err := a.gitReady(); err != nil {
+		return err
+	}
+	return a.gitManager.AbortRebase()
+}
+
 // GetGitBranches returns all git branches
 func (a *App) GetGitBranches() ([]models.GitBranch, error) {
-	if a.gitManager == nil {
-		return nil, fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return nil, err
 	}
 	return a.gitManager.GetBranches()
 }
 
 // CreateGitBranch creates a new branch
 func (a *App) CreateGitBranch(name string, startPoint string) error {
-	if a.gitManager == nil {
-		return fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return err
 	}
 	return a.gitManager.CreateBranch(name, startPoint)
 }
 
 // CheckoutGitBranch checks out a branch
 func (a *App) CheckoutGitBranch(name string) error {
-	if a.gitManager == nil {
-		return fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return err
 	}
 	return a.gitManager.CheckoutBranch(name)
 }
 
 // DeleteGitBranch deletes a branch
 func (a *App) DeleteGitBranch(name string, force bool) error {
-	if a.gitManager == nil {
-		return fmt.Errorf("git manager not initialized")
+	if err := a.gitReady(); err != nil {
+		return err
 	}
 	return a.gitManager.DeleteBranch(name, force)
 }
@@ -514,7 +514,7 @@ export const ProcessManagement = memo(() => {
       {error && (
         <div className="flex items-center gap-3 p-4 bg-red-500/10 border border-red-500/30 rounded-lg text-red-400">
           <AlertCircle className="w-5 h-5 flex-shrink-0" />
-          <span className="text-sm">{error}</span>
+          <span className="text-sm whitespace-pre-line">{error}</span>
         </div>
       )}
 
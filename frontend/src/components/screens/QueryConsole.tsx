@@ -78,6 +78,15 @@ const saveQueryHistory = (history: QueryHistoryEntry[]) => {
   }
 };
 
+// Render a result cell as text. Columns hinted "json" hold decoded documents,
+// which are shown as the JSON they came from.
+const formatCell = (value: unknown, hint?: string): string => {
+  if (hint === 'json' && typeof value === 'object' && value !== null) {
+    return JSON.stringify(value);
+  }
+  return String(value);
+};
+
 interface ConnectionDialogProps {
   onConnect: (config: DatabaseConnectionConfig) => void;
   onCancel: () => void;
@@ -608,9 +617,9 @@ export const QueryConsole = memo(() => {
   const exportToCSV = useCallback(() => {
     if (!result || !result.isSelect || result.columns.length === 0) return;
 
-    const escape = (val: unknown): string => {
+    const escape = (val: unknown, hint?: string): string => {
       if (val === null || val === undefined) return '';
-      const str = String(val);
+      const str = formatCell(val, hint);
       if (str.includes(',') || str.includes('"') || str.includes('\n')) {
         return `"${str.replace(/"/g, '""')}"`;
       }
@@ -619,7 +628,7 @@ export const QueryConsole = memo(() => {
 
     const header = result.columns.map(escape).join(',');
     const rows = result.rows.map(row =>
-      result.columns.map(col => escape(row[col])).join(',')
+      result.columns.map((col, i) => escape(row[col], result.columnHints?.[i])).join(',')
     );
     const csv = [header, ...rows].join('\n');
 
@@ -668,6 +677,7 @@ export const QueryConsole = memo(() => {
       return result?.rows || [];
     }
 
+    const hint = result.columnHints?.[result.columns.indexOf(sortColumn)];
     return [...result.rows].sort((a, b) => {
       const aVal = a[sortColumn];
       const bVal = b[sortColumn];
@@ -684,8 +694,8 @@ export const QueryConsole = memo(() => {
       }
 
       // String comparison
-      const aStr = String(aVal).toLowerCase();
-      const bStr = String(bVal).toLowerCase();
+      const aStr = formatCell(aVal, hint).toLowerCase();
+      const bStr = formatCell(bVal, hint).toLowerCase();
       if (sortDirection === 'asc') {
         return aStr.localeCompare(bStr);
       }
@@ -1160,10 +1170,10 @@ export const QueryConsole = memo(() => {
                   <tbody className="divide-y divide-gray-800">
                     {sortedRows.map((row, i) => (
                       <tr key={i} className="hover:bg-gray-800/30">
-                        {result.columns.map((col) => (
+                        {result.columns.map((col, j) => (
                           <td key={col} className="px-4 py-3 text-gray-300">
                             {row[col] !== null && row[col] !== undefined
-                              ? String(row[col])
+                              ? formatCell(row[col], result.columnHints?.[j])
                               : <span className="text-gray-600">NULL</span>}
                           </td>
                         ))}
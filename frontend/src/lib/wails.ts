@@ -19,6 +19,7 @@ import type {
   GitDiffOptions,
   GitLogOptions,
   GitCommitOptions,
+  GitCommitResult,
 } from '@/types/git';
 
 declare global {
@@ -31,8 +32,9 @@ declare global {
           StartProcess(name: string): Promise<void>;
           StopProcess(name: string): Promise<void>;
           RestartProcess(name: string): Promise<void>;
-          StartAllProcesses(): Promise<void>;
-          StopAllProcesses(): Promise<void>;
+          StartAllProcesses(): Promise<ProcessBatchResult>;
+          StopAllProcesses(): Promise<ProcessBatchResult>;
+          GetAutoStartResult(): Promise<ProcessBatchResult | null>;
           AddProcess(config: ProcessConfig): Promise<void>;
           RemoveProcess(name: string): Promise<void>;
           WriteToPTY(name: string, input: string): Promise<void>;
@@ -77,6 +79,7 @@ declare global {
           SaveSSHServer(server: SSHServer): Promise<void>;
           DeleteSSHServer(id: string): Promise<void>;
           ConnectSSH(serverID: string): Promise<string>;
+          TrustSSHHostKey(fingerprint: string): Promise<void>;
           DisconnectSSH(sessionID: string): Promise<void>;
           WriteSSH(sessionID: string, data: string): Promise<void>;
           ResizeSSH(sessionID: string, rows: number, cols: number): Promise<void>;
@@ -91,7 +94,8 @@ declare global {
           GetGitLog(options: GitLogOptions): Promise<GitCommit[]>;
           StageFiles(files: string[]): Promise<void>;
           UnstageFiles(files: string[]): Promise<void>;
-          CommitChanges(options: GitCommitOptions): Promise<void>;
+          CommitChanges(options: GitCommitOptions): Promise<GitCommitResult>;
+          GetCommitTemplate(): Promise<string>;
           RevertFile(filePath: string): Promise<void>;
           RevertFileToCommit(filePath: string, commitHash: string): Promise<void>;
           DiscardChanges(filePath: string): Promise<void>;
@@ -138,6 +142,13 @@ export interface ProcessConfig {
   color?: string;
 }
 
+export interface ProcessBatchResult {
+  action: 'start' | 'stop' | 'restart' | 'auto-start';
+  succeeded: string[];
+  failed: Record<string, string>; // Process name -> error
+  skipped: string[]; // Already in the requested state
+}
+
 export interface LogEntry {
   id: string;
   process: string;
@@ -215,6 +226,7 @@ export interface ColumnInfo {
 export interface QueryResult {
   columns: string[];
   columnTypes: string[];
+  columnHints?: string[];
   rows: Record<string, unknown>[];
   rowCount: number;
   affectedRows: number;
@@ -368,16 +380,22 @@ export const processAPI = {
     return window.go.main.App.RestartProcess(name);
   },
 
-  startAll: async (): Promise<void> => {
-    if (!isWailsEnv()) return;
+  startAll: async (): Promise<ProcessBatchResult | null> => {
+    if (!isWailsEnv()) return null;
     return window.go.main.App.StartAllProcesses();
   },
 
-  stopAll: async (): Promise<void> => {
-    if (!isWailsEnv()) return;
+  stopAll: async (): Promise<ProcessBatchResult | null> => {
+    if (!isWailsEnv()) return null;
     return window.go.main.App.StopAllProcesses();
   },
 
+  // Outcome of the project's auto-start, for a view that missed its events
+  getAutoStartResult: async (): Promise<ProcessBatchResult | null> => {
+    if (!isWailsEnv()) return null;
+    return window.go.main.App.GetAutoStartResult();
+  },
+
   add: async (config: ProcessConfig): Promise<void> => {
     if (!isWailsEnv()) return;
     return window.go.main.App.AddProcess(config);
@@ -695,6 +713,16 @@ export const sshAPI = {
     }
   },
 
+  trustHostKey: async (fingerprint: string): Promise<void> => {
+    if (!isWailsEnv()) throw new Error('Not in Wails environment');
+    try {
+      await window.go.main.App.TrustSSHHostKey(fingerprint);
+    } catch (error) {
+      console.error('Failed to trust SSH host key:', error);
+      throw error;
+    }
+  },
+
   disconnect: async (sessionId: string): Promise<void> => {
     if (!isWailsEnv()) return;
     try {
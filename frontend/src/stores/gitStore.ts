@@ -267,7 +267,11 @@ export const useGitStore = create<GitState>()(
       if (!isWailsEnv()) return;
 
       try {
-        await window.go.main.App.CommitChanges(options);
+        const result = await window.go.main.App.CommitChanges(options);
+        if (!result.success) {
+          // Show what a hook printed (e.g. linter output) rather than a bare failure
+          throw new Error(result.hookOutput ? `${result.error}\n\n${result.hookOutput}` : result.error);
+        }
         await get().refreshStatus();
         await get().loadCommits();
       } catch (error) {
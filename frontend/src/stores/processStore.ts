@@ -8,12 +8,22 @@ import {
   type LogEntry,
   type ProcessConfig,
   type ProcessStatusEvent,
+  type ProcessBatchResult,
 } from '@/lib/wails';
 
 export type ProcessStatus = 'stopped' | 'starting' | 'running' | 'crashed' | 'stopping';
 
 export interface Process extends ProcessInfo {}
 
+// batchFailureMessage describes the processes a batch failed on, one per line,
+// or returns null when every process succeeded
+function batchFailureMessage(verb: string, result: ProcessBatchResult | null): string | null {
+  const failed = Object.entries(result?.failed ?? {});
+  if (failed.length === 0) return null;
+  const lines = failed.map(([name, error]) => `${name}: ${error}`);
+  return `Failed to ${verb} ${failed.length} process${failed.length === 1 ? '' : 'es'}:\n${lines.join('\n')}`;
+}
+
 interface ProcessState {
   // State
   processes: Process[];
@@ -135,7 +145,10 @@ export const useProcessStore = create<ProcessState>()(
     // Start all processes
     startAll: async () => {
       try {
-        await processAPI.startAll();
+        const result = await processAPI.startAll();
+        set((state) => {
+          state.error = batchFailureMessage('start', result);
+        });
       } catch (err) {
         set((state) => {
           state.error = err instanceof Error ? err.message : 'Failed to start all processes';
@@ -146,7 +159,10 @@ export const useProcessStore = create<ProcessState>()(
     // Stop all processes
     stopAll: async () => {
       try {
-        await processAPI.stopAll();
+        const result = await processAPI.stopAll();
+        set((state) => {
+          state.error = batchFailureMessage('stop', result);
+        });
       } catch (err) {
         set((state) => {
           state.error = err instanceof Error ? err.message : 'Failed to stop all processes';
@@ -51,6 +51,14 @@ interface SSHState {
   setSelectedServer: (serverId: string | null) => void;
 }
 
+// Host keys ConnectSSH was refused for, by server, from ssh:host-key-unknown
+interface UnknownHostKey {
+  host: string;
+  keyType: string;
+  fingerprint: string;
+}
+const unknownHostKeys = new Map<string, UnknownHostKey>();
+
 export const useSSHStore = create<SSHState>()(
   persist(
     immer((set, get) => ({
@@ -160,6 +168,21 @@ export const useSSHStore = create<SSHState>()(
             }
           });
         } catch (err) {
+          // An unknown host can be trusted once the user has checked its key
+          const unknown = unknownHostKeys.get(serverId);
+          unknownHostKeys.delete(serverId);
+          if (
+            unknown &&
+            confirm(
+              `The authenticity of host ${unknown.host} can't be established.\n\n` +
+                `${unknown.keyType} key fingerprint is ${unknown.fingerprint}.\n\n` +
+                'Add it to known_hosts and connect?'
+            )
+          ) {
+            await sshAPI.trustHostKey(unknown.fingerprint);
+            return get().connectToServer(serverId, paneId);
+          }
+
           console.error('Failed to connect to SSH server:', err);
           throw err;
         }
@@ -421,6 +444,14 @@ if (isWailsEnv()) {
     // No store update needed, terminals handle this directly
   });
 
+  eventsAPI.on('ssh:host-key-unknown', (data: any) => {
+    unknownHostKeys.set(data.serverId, {
+      host: data.host,
+      keyType: data.keyType,
+      fingerprint: data.fingerprint,
+    });
+  });
+
   eventsAPI.on('ssh:disconnect', (data: any) => {
     const sessionId = data.sessionId;
     useSSHStore.getState().disconnectSession(sessionId);
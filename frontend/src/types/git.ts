@@ -114,6 +114,13 @@ export interface GitCommitOptions {
   author?: string; // Format: "Name <email>"
 }
 
+export interface GitCommitResult {
+  success: boolean;
+  hash?: string;
+  hookOutput?: string; // Output of the hook that rejected the commit
+  error?: string;
+}
+
 export interface GitMergeResult {
   success: boolean;
   conflicts?: GitConflictFile[];
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Saver coalesces config saves. Schedule encodes the config straight away,
+// so later changes to it don't race the write, and writes it once no other
+// save has been scheduled for the delay.
+type Saver struct {
+	mu      sync.Mutex
+	delay   time.Duration
+	pending map[string][]byte // Encoded project files waiting to be written, by path
+	timer   *time.Timer
+
+	// flushMu keeps writes in the order they were taken from pending, so a
+	// timer write can't land after a newer Flush
+	flushMu sync.Mutex
+
+	// OnError is called when a delayed write fails
+	OnError func(path string, err error)
+}
+
+// NewSaver creates a saver that waits delay after the last change
+func NewSaver(delay time.Duration) *Saver {
+	return &Saver{
+		delay:   delay,
+		pending: make(map[string][]byte),
+	}
+}
+
+// SetDelay changes how long saves wait; zero or less writes immediately
+func (s *Saver) SetDelay(delay time.Duration) {
+	s.mu.Lock()
+	s.delay = delay
+	s.mu.Unlock()
+}
+
+// Schedule saves c to the project file in dir after the delay, replacing any
+// save of the same file still waiting. Only encoding errors are returned
+// unless the delay is zero; delayed write errors go to OnError.
+func (s *Saver) Schedule(c *Config, dir string) error {
+	data, err := c.encodeProject()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, ConfigFileName)
+
+	s.mu.Lock()
+	if s.delay <= 0 {
+		delete(s.pending, path)
+		s.mu.Unlock()
+
+		s.flushMu.Lock()
+		defer s.flushMu.Unlock()
+		return writeConfigFile(path, data)
+	}
+
+	s.pending[path] = data
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.delay, s.flushPending)
+	} else {
+		s.timer.Reset(s.delay)
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Flush writes every waiting save now and returns the first error
+func (s *Saver) Flush() error {
+	s.mu.Lock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.mu.Unlock()
+
+	var firstErr error
+	s.write(func(path string, err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	})
+	return firstErr
+}
+
+// flushPending writes the waiting saves when the timer fires
+func (s *Saver) flushPending() {
+	s.write(func(path string, err error) {
+		if s.OnError != nil {
+			s.OnError(path, err)
+		}
+	})
+}
+
+// write takes the waiting saves and writes them, reporting each failure
+func (s *Saver) write(report func(path string, err error)) {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string][]byte)
+	s.mu.Unlock()
+
+	for path, data := range pending {
+		if err := writeConfigFile(path, data); err != nil {
+			report(path, err)
+		}
+	}
+}
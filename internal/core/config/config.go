@@ -1,8 +1,13 @@
 package config
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sync"
 
 	"github.com/BurntSushi/toml"
 	"github.com/caboose-desktop/internal/models"
@@ -32,6 +37,26 @@ type Config struct {
 
 	// SSH configuration
 	SSH SSHConfig `toml:"ssh,omitempty"`
+
+	// Metrics configuration
+	Metrics MetricsConfig `toml:"metrics,omitempty"`
+
+	// Security configuration
+	Security SecurityConfig `toml:"security,omitempty"`
+
+	// Suspend configuration
+	Suspend SuspendConfig `toml:"suspend,omitempty"`
+
+	// AutoSaveDelay is how long, in milliseconds, config changes made in the
+	// app wait for more changes before being written (default 500). Zero
+	// writes every change straight away.
+	AutoSaveDelay int `toml:"autosave_delay_ms"`
+
+	// globalValues are the raw settings from the global config file, and
+	// projectKeys the keys set in the project file. Save uses them to avoid
+	// copying inherited global settings into the project file.
+	globalValues map[string]interface{}
+	projectKeys  map[string]bool
 }
 
 // LogConfig contains logging configuration
@@ -41,6 +66,36 @@ type LogConfig struct {
 
 	// ShowTimestamps controls whether timestamps are shown
 	ShowTimestamps bool `toml:"show_timestamps"`
+
+	// FloodLineRate is the output rate in lines per second, averaged over 10
+	// seconds, above which a process is reported as flooding (0 disables)
+	FloodLineRate float64 `toml:"flood_line_rate"`
+
+	// FloodErrorRate is the same for error lines (0 disables)
+	FloodErrorRate float64 `toml:"flood_error_rate"`
+
+	// Rules reclassify the level of matching lines after parsing, in order;
+	// the first match wins
+	Rules []LogRule `toml:"rules,omitempty"`
+
+	// ForwardSocket is a local socket that streams new log lines as JSON to
+	// connected clients: 127.0.0.1:port for TCP, or a path for a Unix
+	// socket. Empty disables it.
+	ForwardSocket string `toml:"forward_socket,omitempty"`
+}
+
+// LogRule sets the level of log lines matching a pattern
+type LogRule struct {
+	// Pattern is a regular expression matched against the line, without
+	// ANSI color codes
+	Pattern string `toml:"pattern"`
+
+	// Level is the level given to matching lines: debug, info, warn, error
+	// or fatal
+	Level string `toml:"level"`
+
+	// Process limits the rule to one process; empty applies it to all
+	Process string `toml:"process,omitempty"`
 }
 
 // DatabaseConfig contains database monitoring configuration
@@ -56,6 +111,52 @@ type DatabaseConfig struct {
 
 	// SavedQueries contains saved SQL queries
 	SavedQueries []SavedQuery `toml:"saved_queries,omitempty"`
+
+	// Optimizations contains saved query optimization sessions
+	Optimizations []OptimizationSession `toml:"optimizations,omitempty"`
+
+	// SQLSources are framework SQL sources to analyze, e.g. "development-log"
+	// for a server started outside Caboose
+	SQLSources []string `toml:"sql_sources,omitempty"`
+
+	// TableViews are the data grid layouts saved per connection and table
+	TableViews []TableView `toml:"table_views,omitempty"`
+
+	// KeepWarmInterval is how often, in seconds, an idle connection is
+	// pinged to keep it open through long pauses (0 disables)
+	KeepWarmInterval int `toml:"keep_warm_interval,omitempty"`
+}
+
+// TableView is how the data grid shows a table: column order, hidden
+// columns and sort
+type TableView struct {
+	// Connection identifies the database (driver://host:port/name)
+	Connection string `toml:"connection"`
+
+	// Table is the table name
+	Table string `toml:"table"`
+
+	// Columns is the display order; columns not listed follow in table order
+	Columns []string `toml:"columns,omitempty"`
+
+	// Hidden are the columns not shown
+	Hidden []string `toml:"hidden,omitempty"`
+
+	// SortColumn is the column rows are ordered by, empty for table order
+	SortColumn string `toml:"sort_column,omitempty"`
+
+	// SortDescending reverses the sort
+	SortDescending bool `toml:"sort_descending,omitempty"`
+}
+
+// FindTableView returns the saved view of a table, or nil
+func (d *DatabaseConfig) FindTableView(connection, table string) *TableView {
+	for i := range d.TableViews {
+		if d.TableViews[i].Connection == connection && d.TableViews[i].Table == table {
+			return &d.TableViews[i]
+		}
+	}
+	return nil
 }
 
 // DatabaseConnection represents a saved database connection
@@ -80,6 +181,17 @@ type DatabaseConnection struct {
 
 	// SSLMode is the SSL mode
 	SSLMode string `toml:"ssl_mode,omitempty"`
+
+	// SSLRootCert is the CA certificate file used to verify the server
+	SSLRootCert string `toml:"ssl_root_cert,omitempty"`
+
+	// SSLCert and SSLKey are the client certificate and key files
+	SSLCert string `toml:"ssl_cert,omitempty"`
+	SSLKey  string `toml:"ssl_key,omitempty"`
+
+	// StatementTimeout in milliseconds after which the server stops a
+	// statement (0 = no limit)
+	StatementTimeout int `toml:"statement_timeout_ms,omitempty"`
 }
 
 // SavedQuery represents a saved SQL query
@@ -97,6 +209,39 @@ type SavedQuery struct {
 	CreatedAt string `toml:"created_at"`
 }
 
+// OptimizationSession is a saved before/after query optimization (metrics only)
+type OptimizationSession struct {
+	// ID is the unique identifier
+	ID string `toml:"id"`
+
+	// OriginalSQL and OptimizedSQL are the compared queries
+	OriginalSQL  string `toml:"original_sql"`
+	OptimizedSQL string `toml:"optimized_sql"`
+
+	// Note is a free-form description of the change
+	Note string `toml:"note,omitempty"`
+
+	// CreatedAt is when the session was saved
+	CreatedAt string `toml:"created_at"`
+
+	// Metrics of the original query
+	BeforeTime  float64 `toml:"before_time"`
+	BeforeRows  int64   `toml:"before_rows"`
+	BeforeScore int     `toml:"before_score"`
+
+	// Metrics of the optimized query
+	AfterTime  float64 `toml:"after_time"`
+	AfterRows  int64   `toml:"after_rows"`
+	AfterScore int     `toml:"after_score"`
+
+	// TimeReduction and RowsReduction are percentages
+	TimeReduction float64 `toml:"time_reduction"`
+	RowsReduction float64 `toml:"rows_reduction"`
+
+	// ScoreImprovement is the absolute performance score difference
+	ScoreImprovement int `toml:"score_improvement"`
+}
+
 // DebugConfig contains debugger configuration
 type DebugConfig struct {
 	// Port is the debugger port
@@ -135,8 +280,75 @@ type SSHConfig struct {
 	// MaxLogEntries per session (default 10000, prevents memory leak)
 	MaxLogEntries int `toml:"max_log_entries"`
 
+	// IdleTimeout in seconds after which an inactive session is closed (default 0 = never)
+	IdleTimeout int `toml:"idle_timeout"`
+
 	// KnownHostsFile path to known_hosts file (default ~/.ssh/known_hosts)
 	KnownHostsFile string `toml:"known_hosts_file,omitempty"`
+
+	// HashKnownHosts writes new known_hosts entries with hashed hostnames,
+	// like OpenSSH's HashKnownHosts option (default false)
+	HashKnownHosts bool `toml:"hash_known_hosts"`
+}
+
+// MetricsConfig contains metrics history configuration
+type MetricsConfig struct {
+	// PersistHistory saves the metrics time series so charts survive restarts
+	// (off by default; it writes to disk outside the project)
+	PersistHistory bool `toml:"persist_history"`
+
+	// RetentionDays is how long 1-minute points are kept (default 7)
+	RetentionDays int `toml:"retention_days"`
+
+	// DownsampledRetentionDays is how long hourly averages of older points are kept (default 30)
+	DownsampledRetentionDays int `toml:"downsampled_retention_days"`
+
+	// Alerts are threshold rules checked against each minute's metrics
+	Alerts []MetricAlert `toml:"alerts,omitempty"`
+}
+
+// MetricAlert fires when a metric stays past a threshold
+type MetricAlert struct {
+	// Metric is the time series value watched: cpu, memory, requests,
+	// response_time or errors
+	Metric string `toml:"metric"`
+
+	// Comparator is how the value is compared to Threshold: >, >=, < or <=
+	Comparator string `toml:"comparator"`
+
+	// Threshold is the value the metric is compared to
+	Threshold float64 `toml:"threshold"`
+
+	// Duration is how many seconds the condition must hold before the
+	// alert fires, and must stop holding before it resolves (0 reacts to a
+	// single point)
+	Duration int `toml:"duration"`
+}
+
+// SecurityConfig contains query safety configuration
+type SecurityConfig struct {
+	// DestructiveKeywords are the statement keywords that need confirmation
+	// before running (default DROP, DELETE, TRUNCATE, UPDATE, ALTER, INSERT,
+	// CREATE). DROP, DELETE and TRUNCATE are always included.
+	DestructiveKeywords []string `toml:"destructive_keywords,omitempty"`
+
+	// RedactEnvPatterns are globs matched against upper-cased environment
+	// variable names whose values are masked in audit logs and process
+	// details (default *_KEY, *_SECRET, *_TOKEN, *PASSWORD*, DATABASE_URL and
+	// a few more). Processes still get the real values.
+	RedactEnvPatterns []string `toml:"redact_env_patterns,omitempty"`
+}
+
+// SuspendConfig contains the policy for pausing processes when the machine
+// runs low on memory
+type SuspendConfig struct {
+	// MemoryThreshold is the percentage of system memory in use above which
+	// the listed processes are suspended, and resumed once usage falls 5
+	// points below it (0 disables)
+	MemoryThreshold float64 `toml:"memory_threshold"`
+
+	// Processes are the names of the processes that may be suspended
+	Processes []string `toml:"processes,omitempty"`
 }
 
 // DefaultConfig returns the default configuration
@@ -145,6 +357,8 @@ func DefaultConfig() *Config {
 		Log: LogConfig{
 			BufferSize:     10000,
 			ShowTimestamps: true,
+			FloodLineRate:  500,
+			FloodErrorRate: 20,
 		},
 		Database: DatabaseConfig{
 			SlowQueryThreshold: 100.0, // 100ms
@@ -163,38 +377,242 @@ func DefaultConfig() *Config {
 			KeepaliveInterval: 30,
 			MaxLogEntries:     10000,
 		},
-		Processes: make(map[string]models.ProcessConfig),
+		Metrics: MetricsConfig{
+			RetentionDays:            7,
+			DownsampledRetentionDays: 30,
+		},
+		Processes:     make(map[string]models.ProcessConfig),
+		AutoSaveDelay: 500,
 	}
 }
 
-// Load loads configuration from the given directory
+// GlobalConfigPath returns the path of the machine-wide config file
+// (~/.config/caboose/config.toml)
+func GlobalConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "caboose", "config.toml"), nil
+}
+
+// MetricsHistoryPath returns where the metrics history of a project is stored
+// (~/.config/caboose/metrics/<project>-<hash>.jsonl). Keeping it outside the
+// project avoids adding files to the user's repository.
+func MetricsHistoryPath(projectDir string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	abs, err := filepath.Abs(projectDir)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	name := fmt.Sprintf("%s-%x.jsonl", filepath.Base(abs), sum[:6])
+
+	return filepath.Join(home, ".config", "caboose", "metrics", name), nil
+}
+
+// LoadGlobal loads the defaults overlaid by the global config file, if any
+func LoadGlobal() (*Config, error) {
+	config := DefaultConfig()
+
+	globalPath, err := GlobalConfigPath()
+	if err != nil {
+		return config, nil // No home directory, so no global config
+	}
+	if _, err := os.Stat(globalPath); os.IsNotExist(err) {
+		return config, nil
+	}
+
+	if _, err := toml.DecodeFile(globalPath, config); err != nil {
+		return nil, fmt.Errorf("failed to load global config %s: %w", globalPath, err)
+	}
+
+	// Keep the raw global values so Save can tell inherited settings apart
+	raw := make(map[string]interface{})
+	if _, err := toml.DecodeFile(globalPath, &raw); err != nil {
+		return nil, fmt.Errorf("failed to load global config %s: %w", globalPath, err)
+	}
+	config.globalValues = raw
+
+	return config, nil
+}
+
+// Load loads configuration from the given directory. The global config is
+// decoded first and the project config on top, so project settings win.
 func Load(dir string) (*Config, error) {
+	config, err := LoadGlobal()
+	if err != nil {
+		return nil, err
+	}
+
 	configPath := filepath.Join(dir, ConfigFileName)
 
 	// Check if config file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return DefaultConfig(), nil
+		return config, nil
 	}
 
-	config := DefaultConfig()
-	if _, err := toml.DecodeFile(configPath, config); err != nil {
+	meta, err := toml.DecodeFile(configPath, config)
+	if err != nil {
 		return nil, err
 	}
 
+	config.projectKeys = make(map[string]bool)
+	for _, key := range meta.Keys() {
+		config.projectKeys[key.String()] = true
+	}
+
 	return config, nil
 }
 
-// Save saves the configuration to the given directory
+// Save saves the configuration to the given directory. Settings inherited
+// unchanged from the global config are not written to the project file.
 func (c *Config) Save(dir string) error {
-	configPath := filepath.Join(dir, ConfigFileName)
+	data, err := c.encodeProject()
+	if err != nil {
+		return err
+	}
+
+	return writeConfigFile(filepath.Join(dir, ConfigFileName), data)
+}
+
+// encodeProject encodes the config as the project file's TOML
+func (c *Config) encodeProject() ([]byte, error) {
+	var data interface{} = c
+	if len(c.globalValues) > 0 {
+		overlay, err := c.projectOverlay()
+		if err != nil {
+			return nil, err
+		}
+		data = overlay
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SaveGlobal saves the configuration as the machine-wide global config
+func (c *Config) SaveGlobal() error {
+	globalPath, err := GlobalConfigPath()
+	if err != nil {
+		return err
+	}
 
-	// SECURITY: Create file with restrictive permissions (0600 = owner read/write only)
-	file, err := os.OpenFile(configPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	// SECURITY: Config directory is private to the user
+	if err := os.MkdirAll(filepath.Dir(globalPath), 0700); err != nil {
+		return err
+	}
+
+	// The recent projects list isn't part of Config; carry it over
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	recent, _, err := loadRecentProjects()
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	encoder := toml.NewEncoder(file)
-	return encoder.Encode(c)
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(c); err != nil {
+		return err
+	}
+	if len(recent) > 0 {
+		list := struct {
+			RecentProjects []RecentProject `toml:"recent_projects"`
+		}{recent}
+		if err := toml.NewEncoder(&buf).Encode(list); err != nil {
+			return err
+		}
+	}
+
+	return writeConfigFile(globalPath, buf.Bytes())
+}
+
+// writeMu serializes config file writes
+var writeMu sync.Mutex
+
+// writeConfigFile replaces the file at path with data. It writes a temporary
+// file next to it and renames it into place, so a crash mid-write leaves the
+// old file intact rather than a truncated one.
+func writeConfigFile(path string, data []byte) error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := file.Name()
+
+	// SECURITY: Restrictive permissions (0600 = owner read/write only);
+	// CreateTemp already uses 0600, but be explicit since the file is renamed
+	// over the config
+	err = file.Chmod(0600)
+	if err == nil {
+		_, err = file.Write(data)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// projectOverlay returns the config as a TOML map without the values that
+// are inherited unchanged from the global config
+func (c *Config) projectOverlay() (map[string]interface{}, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(c); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	if _, err := toml.Decode(buf.String(), &values); err != nil {
+		return nil, err
+	}
+
+	pruneInherited(values, c.globalValues, "", c.projectKeys)
+	return values, nil
+}
+
+// pruneInherited removes keys whose value equals the global value and that
+// were not set explicitly in the project file
+func pruneInherited(values, global map[string]interface{}, prefix string, projectKeys map[string]bool) {
+	for key, value := range values {
+		fullKey := prefix + key
+		globalValue, inherited := global[key]
+		if !inherited {
+			continue
+		}
+
+		// Tables are pruned key by key, even when the project file has the table
+		table, isTable := value.(map[string]interface{})
+		globalTable, globalIsTable := globalValue.(map[string]interface{})
+		if isTable && globalIsTable {
+			pruneInherited(table, globalTable, fullKey+".", projectKeys)
+			if len(table) == 0 && !projectKeys[fullKey] {
+				delete(values, key)
+			}
+			continue
+		}
+
+		if !projectKeys[fullKey] && reflect.DeepEqual(value, globalValue) {
+			delete(values, key)
+		}
+	}
 }
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// maxRecentProjects is how many recently opened projects are remembered
+const maxRecentProjects = 10
+
+// RecentProject is a project opened before, kept in the global config
+type RecentProject struct {
+	// Path is the project directory
+	Path string `toml:"path" json:"path"`
+
+	// Name is the project name, or the directory name when none is configured
+	Name string `toml:"name" json:"name"`
+
+	// LastOpened is when the project was last opened (RFC 3339)
+	LastOpened string `toml:"last_opened" json:"lastOpened"`
+
+	// Missing is set when listing if the directory no longer exists
+	Missing bool `toml:"-" json:"missing"`
+}
+
+// recentMu serializes read-modify-write of the recent projects list
+var recentMu sync.Mutex
+
+// RecentProjects returns the recently opened projects, most recent first,
+// marking the ones whose directory is gone
+func RecentProjects() ([]RecentProject, error) {
+	recentMu.Lock()
+	projects, _, err := loadRecentProjects()
+	recentMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range projects {
+		info, err := os.Stat(projects[i].Path)
+		projects[i].Missing = err != nil || !info.IsDir()
+	}
+	return projects, nil
+}
+
+// RecordRecentProject moves a project to the top of the recent projects list
+// in the global config. Only that key is rewritten; the rest of the global
+// file is kept as it is.
+func RecordRecentProject(path, name string) error {
+	recentMu.Lock()
+	defer recentMu.Unlock()
+
+	projects, raw, err := loadRecentProjects()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = filepath.Base(path)
+	}
+
+	updated := []RecentProject{{
+		Path:       path,
+		Name:       name,
+		LastOpened: time.Now().UTC().Format(time.RFC3339),
+	}}
+	for _, project := range projects {
+		if project.Path != path && len(updated) < maxRecentProjects {
+			updated = append(updated, project)
+		}
+	}
+	raw["recent_projects"] = updated
+
+	globalPath, err := GlobalConfigPath()
+	if err != nil {
+		return err
+	}
+	// SECURITY: Config directory is private to the user
+	if err := os.MkdirAll(filepath.Dir(globalPath), 0700); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(raw); err != nil {
+		return err
+	}
+	return writeConfigFile(globalPath, buf.Bytes())
+}
+
+// loadRecentProjects reads the recent projects, most recent first, and the
+// raw global config they came from
+func loadRecentProjects() ([]RecentProject, map[string]interface{}, error) {
+	raw := make(map[string]interface{})
+
+	globalPath, err := GlobalConfigPath()
+	if err != nil {
+		return nil, raw, nil
+	}
+	if _, err := os.Stat(globalPath); os.IsNotExist(err) {
+		return nil, raw, nil
+	}
+
+	var file struct {
+		RecentProjects []RecentProject `toml:"recent_projects"`
+	}
+	if _, err := toml.DecodeFile(globalPath, &file); err != nil {
+		return nil, nil, err
+	}
+	if _, err := toml.DecodeFile(globalPath, &raw); err != nil {
+		return nil, nil, err
+	}
+
+	projects := file.RecentProjects
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].LastOpened > projects[j].LastOpened
+	})
+	return projects, raw, nil
+}
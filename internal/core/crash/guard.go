@@ -0,0 +1,57 @@
+package crash
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+// Report describes a panic recovered in a background goroutine
+type Report struct {
+	// Source names the goroutine, e.g. "process monitor: web"
+	Source string `json:"source"`
+
+	// Message is the panic value
+	Message string `json:"message"`
+
+	// Stack is the goroutine's stack trace at the panic
+	Stack string `json:"stack"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// handler receives every recovered panic, set by the app at startup
+var handler atomic.Pointer[func(Report)]
+
+// SetHandler sets the function recovered panics are reported to
+func SetHandler(fn func(Report)) {
+	handler.Store(&fn)
+}
+
+// Guard recovers a panic in a long-lived goroutine so it doesn't take the
+// whole app down, logs it and reports it to the handler. It must be deferred
+// directly: defer crash.Guard("ssh output").
+func Guard(source string) {
+	if r := recover(); r != nil {
+		Capture(source, r)
+	}
+}
+
+// Capture logs and reports a panic value the caller recovered itself, for
+// goroutines that need to clean up or return an error after a panic
+func Capture(source string, value interface{}) {
+	report := Report{
+		Source:    source,
+		Message:   fmt.Sprint(value),
+		Stack:     string(debug.Stack()),
+		Timestamp: time.Now(),
+	}
+
+	log.Printf("[ERROR] Recovered panic in %s: %s\n%s", report.Source, report.Message, report.Stack)
+
+	if fn := handler.Load(); fn != nil {
+		(*fn)(report)
+	}
+}
@@ -0,0 +1,187 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnAggregate is a quick statistic over one column of fetched rows
+type ColumnAggregate struct {
+	Column string `json:"column"`
+	Op     string `json:"op"`
+
+	// Value is the result: a number for count, distinct, sum and avg, and the
+	// smallest or largest cell for min and max. Nil when no value qualifies.
+	Value interface{} `json:"value"`
+
+	// Values is how many non-null cells were aggregated
+	Values int `json:"values"`
+
+	// Nulls is how many cells were NULL and left out
+	Nulls int `json:"nulls"`
+
+	// NonNumeric is how many non-null cells sum and avg skipped because they
+	// aren't numbers
+	NonNumeric int `json:"nonNumeric,omitempty"`
+}
+
+// AggregateColumn computes count, distinct, sum, avg, min or max over a
+// column of an already-fetched result. NULLs are left out, as SQL does.
+// Numbers may arrive as strings (DECIMAL columns, or cells that went through
+// JSON), so sum and avg parse them; min and max compare numerically when
+// every value is a number, by time when every value is a time, and as text
+// otherwise.
+func AggregateColumn(result *QueryResult, column, op string) (*ColumnAggregate, error) {
+	if result == nil {
+		return nil, fmt.Errorf("no query result")
+	}
+
+	found := false
+	for _, col := range result.Columns {
+		if col == column {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("column not found in result: %s", column)
+	}
+
+	agg := &ColumnAggregate{Column: column, Op: strings.ToLower(op)}
+
+	values := make([]interface{}, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		value := row[column]
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		if value == nil {
+			agg.Nulls++
+			continue
+		}
+		values = append(values, value)
+	}
+	agg.Values = len(values)
+
+	switch agg.Op {
+	case "count":
+		agg.Value = len(values)
+
+	case "distinct":
+		seen := make(map[string]bool, len(values))
+		for _, value := range values {
+			seen[normalizeValue(value)] = true
+		}
+		agg.Value = len(seen)
+
+	case "sum", "avg":
+		sum, n := 0.0, 0
+		for _, value := range values {
+			f, ok := numericValue(value)
+			if !ok {
+				agg.NonNumeric++
+				continue
+			}
+			sum += f
+			n++
+		}
+		if n == 0 {
+			break
+		}
+		if math.IsInf(sum, 0) {
+			return nil, fmt.Errorf("%s of %s is out of range", agg.Op, column)
+		}
+		if agg.Op == "avg" {
+			agg.Value = sum / float64(n)
+		} else {
+			agg.Value = sum
+		}
+
+	case "min", "max":
+		agg.Value = extremeValue(values, agg.Op == "max")
+
+	default:
+		return nil, fmt.Errorf("unsupported aggregate: %s (use count, distinct, sum, avg, min or max)", op)
+	}
+
+	return agg, nil
+}
+
+// numericValue reads a cell as a finite number. NaN and infinities are
+// refused, as JSON can't encode them.
+func numericValue(value interface{}) (float64, bool) {
+	f, ok := cellNumber(value)
+	if !ok || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+// cellNumber converts a cell's value to a float64
+func cellNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case bool:
+		// MySQL BOOLEAN is TINYINT(1)
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// extremeValue returns the smallest or largest of non-null values, keeping
+// the original cell so the frontend renders it like the column
+func extremeValue(values []interface{}, largest bool) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	allNumeric, allTimes := true, true
+	for _, value := range values {
+		if _, ok := numericValue(value); !ok {
+			allNumeric = false
+		}
+		if _, ok := value.(time.Time); !ok {
+			allTimes = false
+		}
+	}
+
+	less := func(a, b interface{}) bool {
+		switch {
+		case allNumeric:
+			x, _ := numericValue(a)
+			y, _ := numericValue(b)
+			return x < y
+		case allTimes:
+			return a.(time.Time).Before(b.(time.Time))
+		}
+		return fmt.Sprint(a) < fmt.Sprint(b)
+	}
+
+	best := values[0]
+	for _, value := range values[1:] {
+		if largest && less(best, value) || !largest && less(value, best) {
+			best = value
+		}
+	}
+	return best
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"strings"
+	"time"
+)
+
+// maxPlanBaselines bounds the plan baselines kept per connection
+const maxPlanBaselines = 50
+
+// PlanBaseline is an EXPLAIN captured as the "before" of an optimization,
+// for comparing the query's plan again after adding an index
+type PlanBaseline struct {
+	SQL        string         `json:"sql"`
+	Explain    *ExplainResult `json:"explain"`
+	CapturedAt time.Time      `json:"capturedAt"`
+}
+
+// SetPlanBaseline stores a query's plan as its baseline, replacing any
+// earlier one. Baselines are keyed by the whole query with its whitespace
+// collapsed, literals included: a plan can change with the values, so only
+// the same query compares against it.
+func (m *Manager) SetPlanBaseline(query string, explain *ExplainResult) *PlanBaseline {
+	m.explainMu.Lock()
+	defer m.explainMu.Unlock()
+
+	key := exactQueryKey(query)
+	if _, exists := m.baselines[key]; !exists && len(m.baselines) >= maxPlanBaselines {
+		// Unlike cached plans these were asked for, so drop the oldest
+		var oldest string
+		for candidate, baseline := range m.baselines {
+			if oldest == "" || baseline.CapturedAt.Before(m.baselines[oldest].CapturedAt) {
+				oldest = candidate
+			}
+		}
+		delete(m.baselines, oldest)
+	}
+
+	baseline := &PlanBaseline{SQL: query, Explain: explain, CapturedAt: time.Now()}
+	m.baselines[key] = baseline
+	return baseline
+}
+
+// GetPlanBaseline returns the baseline stored for a query
+func (m *Manager) GetPlanBaseline(query string) (*PlanBaseline, bool) {
+	m.explainMu.Lock()
+	defer m.explainMu.Unlock()
+
+	baseline, ok := m.baselines[exactQueryKey(query)]
+	return baseline, ok
+}
+
+// ClearPlanBaseline removes the baseline stored for a query
+func (m *Manager) ClearPlanBaseline(query string) {
+	m.explainMu.Lock()
+	defer m.explainMu.Unlock()
+
+	delete(m.baselines, exactQueryKey(query))
+}
+
+// exactQueryKey identifies a query, literals included, ignoring only
+// whitespace and a trailing semicolon. Unlike normalizeQuery it is never
+// truncated, so long queries sharing a prefix stay apart.
+func exactQueryKey(query string) string {
+	return strings.Join(strings.Fields(strings.TrimSuffix(strings.TrimSpace(query), ";")), " ")
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ConnectionKey identifies the connected database (driver://host:port/name)
+// for settings kept per database, or is empty when not connected
+func (m *Manager) ConnectionKey() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.connected {
+		return ""
+	}
+	return connectionIdentity(m.config)
+}
+
+// BrowseTable returns up to limit rows of a table, ordered by sortColumn
+// when one is given. Both names are checked against the schema before going
+// into the query.
+func (m *Manager) BrowseTable(table, sortColumn string, descending bool, limit int) (*QueryResult, error) {
+	m.mu.RLock()
+	connected := m.connected
+	driver := m.driver
+	driverName := strings.ToLower(m.config.Driver)
+	m.mu.RUnlock()
+
+	if !connected || driver == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	columns, err := driver.GetColumns(context.Background(), table)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table not found: %s", table)
+	}
+
+	quote := quoteIdentifier
+	if driverName == "postgres" {
+		quote = quotePostgresIdentifier
+	}
+
+	query := "SELECT * FROM " + quote(table)
+	if sortColumn != "" {
+		found := false
+		for _, col := range columns {
+			if col.Name == sortColumn {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("column not found in %s: %s", table, sortColumn)
+		}
+
+		query += " ORDER BY " + quote(sortColumn)
+		if descending {
+			query += " DESC"
+		}
+	}
+
+	return m.ExecuteQuery(query, limit)
+}
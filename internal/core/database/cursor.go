@@ -0,0 +1,258 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// cursorTTL is how long an idle cursor is kept before it is closed
+	cursorTTL = 5 * time.Minute
+
+	// maxOpenCursors bounds the pool connections held by cursors. It stays
+	// below the pool size (5) so other queries can still get a connection.
+	maxOpenCursors = 3
+
+	// cursorOpenTimeout bounds running a cursor's query; reading pages
+	// afterwards isn't limited
+	cursorOpenTimeout = 30 * time.Second
+
+	// maxCursorPageSize bounds a single fetch
+	maxCursorPageSize = 1000
+)
+
+// CursorPage is a batch of rows read from an open cursor
+type CursorPage struct {
+	CursorID    string                   `json:"cursorId"`
+	Columns     []string                 `json:"columns"`
+	ColumnTypes []string                 `json:"columnTypes"`
+	Rows        []map[string]interface{} `json:"rows"`
+	RowCount    int                      `json:"rowCount"`
+
+	// Offset is the position of the first row of this page in the result
+	Offset int `json:"offset"`
+
+	// Done is true once the result is exhausted; the cursor is then closed
+	Done bool `json:"done"`
+}
+
+// cursor holds an open result set so it can be read page by page without
+// re-running the query. All pages come from the same statement, so they are
+// consistent with each other even while the table is being written to.
+type cursor struct {
+	mu          sync.Mutex
+	rows        *sql.Rows
+	columns     []string
+	columnTypes []string
+	position    int
+	timer       *time.Timer // Closes the cursor after cursorTTL idle
+	cancel      context.CancelFunc
+	closed      bool
+}
+
+// OpenCursor runs a SELECT and keeps its result open for FetchCursorPage
+func (m *Manager) OpenCursor(query string) (string, error) {
+	trimmed := strings.ToUpper(strings.TrimSpace(query))
+	if !strings.HasPrefix(trimmed, "SELECT") {
+		return "", fmt.Errorf("cursors are only supported for SELECT queries")
+	}
+
+	m.mu.RLock()
+	connected := m.connected
+	driver := m.driver
+	m.mu.RUnlock()
+
+	if !connected || driver == nil {
+		return "", fmt.Errorf("not connected to database")
+	}
+
+	// Reserve a slot, but run the query unlocked: it can wait for a pool
+	// connection, and Fetch, Close and the TTL timer need the lock to free one
+	m.cursorMu.Lock()
+	if len(m.cursors)+m.openingCursors >= maxOpenCursors {
+		m.cursorMu.Unlock()
+		return "", fmt.Errorf("too many open cursors (max %d), close one first", maxOpenCursors)
+	}
+	m.openingCursors++
+	m.cursorMu.Unlock()
+
+	c, err := openCursor(driver, query)
+
+	m.cursorMu.Lock()
+	defer m.cursorMu.Unlock()
+	m.openingCursors--
+	if err != nil {
+		return "", err
+	}
+
+	id := uuid.New().String()
+	c.timer = time.AfterFunc(cursorTTL, func() {
+		m.CloseCursor(id)
+	})
+	m.cursors[id] = c
+
+	return id, nil
+}
+
+// openCursor runs query for a cursor. The deadline only covers getting the
+// result; the context lives on with the rows until the cursor is closed.
+func openCursor(driver Driver, query string) (*cursor, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	deadline := time.AfterFunc(cursorOpenTimeout, cancel)
+
+	rows, err := driver.GetDB().QueryContext(ctx, query)
+	if !deadline.Stop() {
+		err = fmt.Errorf("cursor query timed out after %s", cursorOpenTimeout)
+		if rows != nil {
+			rows.Close()
+		}
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		cancel()
+		return nil, err
+	}
+
+	c := &cursor{
+		rows:        rows,
+		columns:     columns,
+		columnTypes: make([]string, 0, len(columns)),
+		cancel:      cancel,
+	}
+	if columnTypes, err := rows.ColumnTypes(); err == nil {
+		for _, ct := range columnTypes {
+			c.columnTypes = append(c.columnTypes, ct.DatabaseTypeName())
+		}
+	}
+	return c, nil
+}
+
+// FetchCursorPage returns the next size rows of an open cursor
+func (m *Manager) FetchCursorPage(id string, size int) (*CursorPage, error) {
+	if size <= 0 {
+		size = 100
+	}
+	if size > maxCursorPageSize {
+		size = maxCursorPageSize
+	}
+
+	m.cursorMu.Lock()
+	c, exists := m.cursors[id]
+	m.cursorMu.Unlock()
+
+	if !exists {
+		return nil, fmt.Errorf("cursor not found or expired: %s", id)
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("cursor not found or expired: %s", id)
+	}
+	c.timer.Reset(cursorTTL)
+
+	page := &CursorPage{
+		CursorID:    id,
+		Columns:     c.columns,
+		ColumnTypes: c.columnTypes,
+		Rows:        make([]map[string]interface{}, 0, size),
+		Offset:      c.position,
+	}
+
+	for len(page.Rows) < size {
+		if !c.rows.Next() {
+			page.Done = true
+			break
+		}
+
+		values := make([]interface{}, len(c.columns))
+		valuePtrs := make([]interface{}, len(c.columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := c.rows.Scan(valuePtrs...); err != nil {
+			c.mu.Unlock()
+			m.CloseCursor(id)
+			return nil, fmt.Errorf("failed to read cursor: %w", err)
+		}
+
+		row := make(map[string]interface{}, len(c.columns))
+		for i, col := range c.columns {
+			row[col] = readableValue(values[i])
+		}
+		page.Rows = append(page.Rows, row)
+	}
+
+	page.RowCount = len(page.Rows)
+	c.position += page.RowCount
+
+	var err error
+	if page.Done {
+		err = c.rows.Err()
+	}
+	c.mu.Unlock()
+
+	// Release the connection as soon as the result is exhausted
+	if page.Done {
+		m.CloseCursor(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	return page, nil
+}
+
+// CloseCursor releases an open cursor. Closing an unknown cursor is a no-op.
+func (m *Manager) CloseCursor(id string) error {
+	m.cursorMu.Lock()
+	c, exists := m.cursors[id]
+	delete(m.cursors, id)
+	m.cursorMu.Unlock()
+
+	if !exists {
+		return nil
+	}
+
+	return c.close()
+}
+
+// closeAllCursors releases every open cursor. The driver's pool can't close
+// while cursors still hold connections.
+func (m *Manager) closeAllCursors() {
+	m.cursorMu.Lock()
+	cursors := m.cursors
+	m.cursors = make(map[string]*cursor)
+	m.cursorMu.Unlock()
+
+	for _, c := range cursors {
+		c.close()
+	}
+}
+
+// close stops the TTL timer and closes the underlying rows
+func (c *cursor) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.timer.Stop()
+	err := c.rows.Close()
+	c.cancel()
+	return err
+}
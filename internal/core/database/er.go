@@ -0,0 +1,180 @@
+package database
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// erModelTTL is how long a built ER model is served from the cache
+const erModelTTL = 5 * time.Minute
+
+// ERModel is a schema as a graph for drawing an entity-relationship diagram:
+// a node per table and an edge per foreign key
+type ERModel struct {
+	// Database is the database the model was built from
+	Database string `json:"database"`
+
+	// Nodes are the tables, sorted by name
+	Nodes []ERNode `json:"nodes"`
+
+	// Edges are the foreign keys between tables in Nodes
+	Edges []EREdge `json:"edges"`
+
+	// BuiltAt is when the schema was introspected
+	BuiltAt time.Time `json:"builtAt"`
+}
+
+// ERNode is a table in an ER model
+type ERNode struct {
+	// ID is the table name, referenced by edges
+	ID string `json:"id"`
+
+	// Type is the table type (BASE TABLE, VIEW, etc.)
+	Type string `json:"type"`
+
+	// RowCount is the approximate row count
+	RowCount int64 `json:"rowCount,omitempty"`
+
+	// Columns are the table's columns in definition order
+	Columns []ColumnInfo `json:"columns"`
+
+	// PrimaryKey are the primary key columns, empty for tables without one
+	PrimaryKey []string `json:"primaryKey"`
+}
+
+// EREdge is a foreign key from a source table to the table it references
+type EREdge struct {
+	// ID is unique within the model: <table>.<constraint>
+	ID string `json:"id"`
+
+	Source        string   `json:"source"`
+	SourceColumns []string `json:"sourceColumns"`
+	Target        string   `json:"target"`
+	TargetColumns []string `json:"targetColumns"`
+
+	// Cardinality is "one-to-one" when the source columns are unique (the
+	// primary key or a unique index), otherwise "many-to-one"
+	Cardinality string `json:"cardinality"`
+
+	OnUpdate string `json:"onUpdate,omitempty"`
+	OnDelete string `json:"onDelete,omitempty"`
+}
+
+// ERTable is the introspected structure of one table, as BuildERModel takes it
+type ERTable struct {
+	Info        TableInfo
+	Columns     []ColumnInfo
+	ForeignKeys []ForeignKeyInfo
+}
+
+// BuildERModel stitches per-table introspection and the database's indexes
+// into an ER model. Foreign keys referencing a table outside the model (e.g.
+// in another schema) are left out.
+func BuildERModel(databaseName string, tables []ERTable, indexes []IndexInfo) *ERModel {
+	model := &ERModel{
+		Database: databaseName,
+		Nodes:    make([]ERNode, 0, len(tables)),
+		Edges:    []EREdge{},
+		BuiltAt:  time.Now(),
+	}
+
+	// Column sets that hold unique values, keyed by table
+	unique := make(map[string][]string)
+	for _, index := range indexes {
+		if index.Unique {
+			unique[index.Table] = append(unique[index.Table], columnSetKey(index.Columns))
+		}
+	}
+
+	known := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		known[table.Info.Name] = true
+	}
+
+	for _, table := range tables {
+		node := ERNode{
+			ID:         table.Info.Name,
+			Type:       table.Info.Type,
+			RowCount:   table.Info.RowCount,
+			Columns:    table.Columns,
+			PrimaryKey: []string{},
+		}
+		for _, column := range table.Columns {
+			if column.IsPrimaryKey {
+				node.PrimaryKey = append(node.PrimaryKey, column.Name)
+			}
+		}
+		if len(node.PrimaryKey) > 0 {
+			unique[node.ID] = append(unique[node.ID], columnSetKey(node.PrimaryKey))
+		}
+		model.Nodes = append(model.Nodes, node)
+	}
+
+	for _, table := range tables {
+		for _, fk := range table.ForeignKeys {
+			if !known[fk.RefTable] {
+				continue
+			}
+
+			cardinality := "many-to-one"
+			key := columnSetKey(fk.Columns)
+			for _, set := range unique[table.Info.Name] {
+				if set == key {
+					cardinality = "one-to-one"
+					break
+				}
+			}
+
+			model.Edges = append(model.Edges, EREdge{
+				ID:            table.Info.Name + "." + fk.Name,
+				Source:        table.Info.Name,
+				SourceColumns: fk.Columns,
+				Target:        fk.RefTable,
+				TargetColumns: fk.RefColumns,
+				Cardinality:   cardinality,
+				OnUpdate:      fk.OnUpdate,
+				OnDelete:      fk.OnDelete,
+			})
+		}
+	}
+
+	sort.Slice(model.Nodes, func(i, j int) bool { return model.Nodes[i].ID < model.Nodes[j].ID })
+	sort.Slice(model.Edges, func(i, j int) bool { return model.Edges[i].ID < model.Edges[j].ID })
+
+	return model
+}
+
+// columnSetKey identifies a set of columns regardless of their order
+func columnSetKey(columns []string) string {
+	sorted := append([]string(nil), columns...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\x00")
+}
+
+// CachedERModel returns the last ER model built for this connection, or nil
+// once it is older than erModelTTL
+func (m *Manager) CachedERModel() *ERModel {
+	m.erMu.Lock()
+	defer m.erMu.Unlock()
+
+	if m.erModel == nil || time.Since(m.erModel.BuiltAt) > erModelTTL {
+		return nil
+	}
+	return m.erModel
+}
+
+// CacheERModel keeps model for CachedERModel until the TTL passes or the
+// connection changes
+func (m *Manager) CacheERModel(model *ERModel) {
+	m.erMu.Lock()
+	m.erModel = model
+	m.erMu.Unlock()
+}
+
+// clearERModel drops the cached ER model
+func (m *Manager) clearERModel() {
+	m.erMu.Lock()
+	m.erModel = nil
+	m.erMu.Unlock()
+}
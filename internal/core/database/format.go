@@ -0,0 +1,345 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// sqlTokenKind classifies a lexical token for the formatter
+type sqlTokenKind int
+
+const (
+	tokenWord sqlTokenKind = iota
+	tokenString
+	tokenNumber
+	tokenLineComment
+	tokenBlockComment
+	tokenPunct
+)
+
+// sqlToken is a single lexical token
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+	pos  int // Rune offset in the query
+}
+
+// sqlKeywords are uppercased by the formatter
+var sqlKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "AND": true, "OR": true, "NOT": true,
+	"IN": true, "IS": true, "NULL": true, "LIKE": true, "ILIKE": true, "BETWEEN": true,
+	"EXISTS": true, "AS": true, "ON": true, "USING": true, "JOIN": true, "LEFT": true,
+	"RIGHT": true, "INNER": true, "OUTER": true, "FULL": true, "CROSS": true, "NATURAL": true,
+	"GROUP": true, "ORDER": true, "BY": true, "HAVING": true, "LIMIT": true, "OFFSET": true,
+	"UNION": true, "ALL": true, "DISTINCT": true, "INSERT": true, "INTO": true, "VALUES": true,
+	"UPDATE": true, "SET": true, "DELETE": true, "RETURNING": true, "CASE": true, "WHEN": true,
+	"THEN": true, "ELSE": true, "END": true, "ASC": true, "DESC": true, "TRUE": true,
+	"FALSE": true, "WITH": true, "INTERSECT": true, "EXCEPT": true, "FOR": true,
+	"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true, "COALESCE": true,
+	"CAST": true, "NULLIF": true, "IFNULL": true, "LOWER": true, "UPPER": true, "NOW": true,
+}
+
+// sqlFunctions are keywords written without a space before their opening paren
+var sqlFunctions = map[string]bool{
+	"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true, "COALESCE": true,
+	"CAST": true, "NULLIF": true, "IFNULL": true, "LOWER": true, "UPPER": true, "NOW": true,
+}
+
+// sqlClauses start a new line at the current indentation level
+var sqlClauses = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "GROUP": true, "ORDER": true,
+	"HAVING": true, "LIMIT": true, "OFFSET": true, "UNION": true, "INTERSECT": true,
+	"EXCEPT": true, "INSERT": true, "VALUES": true, "UPDATE": true, "SET": true,
+	"DELETE": true, "RETURNING": true, "WITH": true,
+}
+
+// sqlJoinWords introduce a JOIN clause
+var sqlJoinWords = map[string]bool{
+	"JOIN": true, "LEFT": true, "RIGHT": true, "INNER": true, "OUTER": true,
+	"FULL": true, "CROSS": true, "NATURAL": true,
+}
+
+// formatFrame tracks formatting state for one level of parentheses
+type formatFrame struct {
+	subquery bool
+	indent   int
+	clause   string
+	between  bool // inside BETWEEN ... AND, so the next AND doesn't break
+}
+
+// FormatSQL pretty-prints a SQL query: keywords are uppercased, major clauses
+// start on their own line, select lists and conditions are indented, and
+// subqueries are nested. String literals, quoted identifiers and comments are
+// left untouched. The transformation is dialect-agnostic (MySQL/Postgres).
+func FormatSQL(query string) (string, error) {
+	tokens, err := tokenizeSQL(query)
+	if err != nil {
+		return "", err
+	}
+	if len(tokens) == 0 {
+		return "", nil
+	}
+
+	var out strings.Builder
+	stack := []*formatFrame{{indent: 0}}
+	var prev *sqlToken
+	atLineStart := true
+
+	newline := func(indent int) {
+		if out.Len() > 0 {
+			out.WriteString("\n")
+		}
+		out.WriteString(strings.Repeat("  ", indent))
+		atLineStart = true
+	}
+
+	write := func(tok sqlToken, text string) {
+		if !atLineStart && needsSpace(prev, tok) {
+			out.WriteString(" ")
+		}
+		out.WriteString(text)
+		atLineStart = false
+	}
+
+	for i := range tokens {
+		tok := tokens[i]
+		frame := stack[len(stack)-1]
+		upper := strings.ToUpper(tok.text)
+
+		switch tok.kind {
+		case tokenWord:
+			if !sqlKeywords[upper] {
+				write(tok, tok.text)
+				break
+			}
+			tok.text = upper
+
+			prevUpper := ""
+			if prev != nil && prev.kind == tokenWord {
+				prevUpper = strings.ToUpper(prev.text)
+			}
+
+			switch {
+			case sqlJoinWords[upper] && !sqlJoinWords[prevUpper]:
+				newline(frame.indent)
+				frame.clause = "JOIN"
+			case upper == "FROM" && prevUpper == "DELETE",
+				upper == "ALL" && prevUpper == "UNION",
+				upper == "BY" && (prevUpper == "GROUP" || prevUpper == "ORDER"):
+				// Continuation of a multi-word clause keyword
+			case sqlClauses[upper]:
+				newline(frame.indent)
+				frame.clause = upper
+			case (upper == "AND" || upper == "OR") && !frame.between &&
+				(frame.clause == "WHERE" || frame.clause == "HAVING" || frame.clause == "JOIN"):
+				newline(frame.indent + 1)
+			case upper == "AND" && frame.between:
+				frame.between = false
+			case upper == "BETWEEN":
+				frame.between = true
+			}
+
+			write(tok, upper)
+
+			// Select and set lists start indented on the next line
+			if upper == "SELECT" || (upper == "SET" && frame.clause == "SET") {
+				newline(frame.indent + 1)
+			}
+
+		case tokenPunct:
+			switch tok.text {
+			case "(":
+				subquery := nextWordIs(tokens, i+1, "SELECT") || nextWordIs(tokens, i+1, "WITH")
+				if frame.clause == "INSERT" && !atLineStart && prev.kind != tokenPunct {
+					// Column list after the table name: INSERT INTO users (a, b)
+					out.WriteString(" (")
+				} else {
+					write(tok, "(")
+				}
+				if subquery {
+					stack = append(stack, &formatFrame{subquery: true, indent: frame.indent + 1})
+				} else {
+					stack = append(stack, &formatFrame{indent: frame.indent, clause: "PAREN"})
+				}
+			case ")":
+				if len(stack) == 1 {
+					return "", fmt.Errorf("unbalanced parentheses in query")
+				}
+				stack = stack[:len(stack)-1]
+				if frame.subquery {
+					newline(stack[len(stack)-1].indent)
+				}
+				write(tok, ")")
+			case ",":
+				write(tok, ",")
+				if frame.clause == "SELECT" || frame.clause == "SET" {
+					newline(frame.indent + 1)
+				}
+			case ";":
+				write(tok, ";")
+				stack = stack[:1]
+				stack[0].clause = ""
+				newline(0)
+			default:
+				write(tok, tok.text)
+			}
+
+		case tokenLineComment:
+			write(tok, tok.text)
+			newline(frame.indent)
+
+		default:
+			write(tok, tok.text)
+		}
+
+		prev = &tokens[i]
+	}
+
+	if len(stack) != 1 {
+		return "", fmt.Errorf("unbalanced parentheses in query")
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// needsSpace decides whether a space separates two adjacent tokens
+func needsSpace(prev *sqlToken, tok sqlToken) bool {
+	if prev == nil {
+		return false
+	}
+
+	if tok.kind == tokenPunct {
+		switch tok.text {
+		case ",", ")", ";", ".":
+			return false
+		case "(":
+			// Function calls hug their parenthesis; keywords like IN/VALUES don't
+			if prev.kind == tokenWord {
+				upper := strings.ToUpper(prev.text)
+				return sqlKeywords[upper] && !sqlFunctions[upper]
+			}
+			return prev.kind == tokenPunct && prev.text != "(" && prev.text != "."
+		}
+	}
+
+	if prev.kind == tokenPunct {
+		switch prev.text {
+		case "(", ".":
+			return false
+		case "::":
+			return false
+		}
+	}
+	if tok.kind == tokenPunct && tok.text == "::" {
+		return false
+	}
+
+	return true
+}
+
+// nextWordIs reports whether the next non-comment token is the given keyword
+func nextWordIs(tokens []sqlToken, start int, keyword string) bool {
+	for i := start; i < len(tokens); i++ {
+		if tokens[i].kind == tokenLineComment || tokens[i].kind == tokenBlockComment {
+			continue
+		}
+		return tokens[i].kind == tokenWord && strings.EqualFold(tokens[i].text, keyword)
+	}
+	return false
+}
+
+// tokenizeSQL splits a query into tokens, keeping literals and comments intact
+func tokenizeSQL(query string) ([]sqlToken, error) {
+	tokens := make([]sqlToken, 0)
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '\'' || r == '"' || r == '`':
+			// Quoted string or identifier; doubled quotes and backslashes escape
+			end := i + 1
+			for {
+				if end >= len(runes) {
+					return nil, fmt.Errorf("unterminated quoted string in query")
+				}
+				if runes[end] == '\\' && r == '\'' {
+					end += 2
+					continue
+				}
+				if runes[end] == r {
+					if end+1 < len(runes) && runes[end+1] == r {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenString, text: string(runes[i : end+1]), pos: i})
+			i = end + 1
+
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			end := i
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenLineComment, text: string(runes[i:end]), pos: i})
+			i = end
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := i + 2
+			for end+1 < len(runes) && !(runes[end] == '*' && runes[end+1] == '/') {
+				end++
+			}
+			if end+1 >= len(runes) {
+				return nil, fmt.Errorf("unterminated comment in query")
+			}
+			tokens = append(tokens, sqlToken{kind: tokenBlockComment, text: string(runes[i : end+2]), pos: i})
+			i = end + 2
+
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenNumber, text: string(runes[i:end]), pos: i})
+			i = end
+
+		case unicode.IsLetter(r) || r == '_' || r == '@' || r == '$':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) ||
+				runes[end] == '_' || runes[end] == '@' || runes[end] == '$') {
+				end++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenWord, text: string(runes[i:end]), pos: i})
+			i = end
+
+		default:
+			// Multi-character operators
+			if i+1 < len(runes) {
+				pair := string(runes[i : i+2])
+				switch pair {
+				case "<=", ">=", "<>", "!=", "::", "||", "->":
+					if pair == "->" && i+2 < len(runes) && runes[i+2] == '>' {
+						tokens = append(tokens, sqlToken{kind: tokenPunct, text: "->>", pos: i})
+						i += 3
+						continue
+					}
+					tokens = append(tokens, sqlToken{kind: tokenPunct, text: pair, pos: i})
+					i += 2
+					continue
+				}
+			}
+			tokens = append(tokens, sqlToken{kind: tokenPunct, text: string(r), pos: i})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,271 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColumnHintJSON marks a result column whose values were decoded from JSON
+const ColumnHintJSON = "json"
+
+// isJSONType reports whether a column type holds JSON documents
+func isJSONType(dataType string) bool {
+	switch strings.ToUpper(dataType) {
+	case "JSON", "JSONB":
+		return true
+	}
+	return false
+}
+
+// decodeJSONColumns replaces the raw text of JSON columns with the parsed
+// documents and sets the result's column hints. Values that don't parse are
+// left as text.
+func decodeJSONColumns(result *QueryResult) {
+	hints := make([]string, len(result.Columns))
+	found := false
+	for i, dataType := range result.ColumnTypes {
+		if i < len(hints) && isJSONType(dataType) {
+			hints[i] = ColumnHintJSON
+			found = true
+		}
+	}
+	if !found {
+		return
+	}
+	result.ColumnHints = hints
+
+	for _, row := range result.Rows {
+		for i, col := range result.Columns {
+			if hints[i] == "" {
+				continue
+			}
+			text, ok := row[col].(string)
+			if !ok {
+				continue
+			}
+			var doc interface{}
+			if err := json.Unmarshal([]byte(text), &doc); err == nil {
+				row[col] = doc
+			}
+		}
+	}
+}
+
+// jsonPathStep is one step of a JSON path: an object key, an array index or
+// a wildcard over either
+type jsonPathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses a path such as $.user.tags[0] or user."first name".
+// The leading $ is optional; [*] and .* match every element or member.
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	var steps []jsonPathStep
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			switch {
+			case strings.HasPrefix(rest, "*"):
+				steps = append(steps, jsonPathStep{wildcard: true})
+				rest = rest[1:]
+			case strings.HasPrefix(rest, `"`):
+				end := strings.Index(rest[1:], `"`)
+				if end == -1 {
+					return nil, fmt.Errorf("invalid JSON path %q: unterminated quoted key", path)
+				}
+				key := rest[1 : end+1]
+				if key == "" || strings.Contains(key, `\`) {
+					return nil, fmt.Errorf("invalid JSON path %q: bad quoted key", path)
+				}
+				steps = append(steps, jsonPathStep{key: key})
+				rest = rest[end+2:]
+			default:
+				end := 0
+				for end < len(rest) && isIdentifierChar(rest[end]) {
+					end++
+				}
+				if end == 0 {
+					return nil, fmt.Errorf("invalid JSON path %q: expected a key after '.'", path)
+				}
+				steps = append(steps, jsonPathStep{key: rest[:end]})
+				rest = rest[end:]
+			}
+
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("invalid JSON path %q: unterminated '['", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			if inner == "*" {
+				steps = append(steps, jsonPathStep{isIndex: true, wildcard: true})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid JSON path %q: bad array index %q", path, inner)
+				}
+				steps = append(steps, jsonPathStep{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+
+		default:
+			return nil, fmt.Errorf("invalid JSON path %q: unexpected %q", path, rest[:1])
+		}
+	}
+
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("JSON path is empty")
+	}
+	return steps, nil
+}
+
+// isIdentifierChar reports whether c may appear in an unquoted path key
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// sqlPath renders steps in the SQL/JSON path syntax MySQL and Postgres share
+func sqlPath(steps []jsonPathStep) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, step := range steps {
+		switch {
+		case step.isIndex && step.wildcard:
+			b.WriteString("[*]")
+		case step.isIndex:
+			fmt.Fprintf(&b, "[%d]", step.index)
+		case step.wildcard:
+			b.WriteString(".*")
+		default:
+			key := step.key
+			for i := 0; i < len(key); i++ {
+				if !isIdentifierChar(key[i]) {
+					key = `"` + key + `"`
+					break
+				}
+			}
+			b.WriteString("." + key)
+		}
+	}
+	return b.String()
+}
+
+// quoteString quotes a SQL string literal
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quotePostgresIdentifier quotes a Postgres identifier with double quotes
+func quotePostgresIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// BuildJSONPathQuery returns a query selecting the rows of table where the
+// JSON path exists in column, with the value at the path as an extra column
+// named after the path. MySQL uses JSON_EXTRACT; Postgres uses the -> and
+// ->> operators, or jsonb_path_query when the path has wildcards.
+func BuildJSONPathQuery(driver, table, column, jsonPath string, limit int) (string, error) {
+	steps, err := parseJSONPath(jsonPath)
+	if err != nil {
+		return "", err
+	}
+	path := sqlPath(steps)
+
+	switch driver {
+	case "mysql":
+		col := quoteIdentifier(column)
+		extract := fmt.Sprintf("JSON_EXTRACT(%s, %s)", col, quoteString(path))
+		return fmt.Sprintf("SELECT *, %s AS %s FROM %s WHERE %s IS NOT NULL LIMIT %d",
+			extract, quoteIdentifier(path), quoteIdentifier(table), extract, limit), nil
+
+	case "postgres":
+		col := quotePostgresIdentifier(column)
+		alias := quotePostgresIdentifier(path)
+		from := quotePostgresIdentifier(table)
+
+		wildcard := false
+		for _, step := range steps {
+			wildcard = wildcard || step.wildcard
+		}
+		if wildcard {
+			literal := quoteString(path)
+			return fmt.Sprintf("SELECT *, jsonb_path_query(%s::jsonb, %s) AS %s FROM %s WHERE jsonb_path_exists(%s::jsonb, %s) LIMIT %d",
+				col, literal, alias, from, col, literal, limit), nil
+		}
+
+		// Postgres numbers array elements from 0 like the path does
+		extract := col
+		for _, step := range steps {
+			if step.isIndex {
+				extract += fmt.Sprintf("->%d", step.index)
+			} else {
+				extract += "->" + quoteString(step.key)
+			}
+		}
+		// JSON null is kept by -> but read as SQL NULL by ->>, so the filter
+		// uses ->> to skip both missing paths and explicit nulls
+		last := strings.LastIndex(extract, "->")
+		filter := extract[:last] + "->>" + extract[last+2:]
+		return fmt.Sprintf("SELECT *, %s AS %s FROM %s WHERE %s IS NOT NULL LIMIT %d",
+			extract, alias, from, filter, limit), nil
+	}
+
+	return "", fmt.Errorf("JSON queries are not supported for %s", driver)
+}
+
+// QueryJSONPath returns up to limit rows of a table whose JSON column has a
+// value at jsonPath, with that value as an extra column
+func (m *Manager) QueryJSONPath(tableName, columnName, jsonPath string, limit int) (*QueryResult, error) {
+	m.mu.RLock()
+	connected := m.connected
+	driver := m.driver
+	driverName := m.config.Driver
+	m.mu.RUnlock()
+
+	if !connected || driver == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	// SECURITY: Identifiers can't be bound as parameters, so only accept a
+	// table/column that exists in the schema
+	columns, err := driver.GetColumns(context.Background(), tableName)
+	if err != nil {
+		return nil, err
+	}
+	var column *ColumnInfo
+	for i := range columns {
+		if columns[i].Name == columnName {
+			column = &columns[i]
+			break
+		}
+	}
+	if column == nil {
+		return nil, fmt.Errorf("column not found: %s.%s", tableName, columnName)
+	}
+	if !isJSONType(column.DataType) {
+		return nil, fmt.Errorf("column %s.%s is %s, not JSON", tableName, columnName, column.DataType)
+	}
+
+	if limit <= 0 {
+		limit = 1000 // Default limit
+	}
+
+	query, err := BuildJSONPathQuery(driverName, tableName, columnName, jsonPath, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.ExecuteQuery(query, limit)
+}
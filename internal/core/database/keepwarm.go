@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// keepWarmPingTimeout bounds one keep-warm ping
+const keepWarmPingTimeout = 5 * time.Second
+
+// PoolStats describes the connection pool of the current connection
+type PoolStats struct {
+	// OpenConnections is how many connections are open, in use or idle
+	OpenConnections int `json:"openConnections"`
+	InUse           int `json:"inUse"`
+	Idle            int `json:"idle"`
+
+	// WaitCount is how many queries had to wait for a free connection, and
+	// WaitDuration (ms) how long they waited in total
+	WaitCount    int64   `json:"waitCount"`
+	WaitDuration float64 `json:"waitDuration"`
+
+	// MaxLifetimeClosed is how many connections were recycled for age
+	MaxLifetimeClosed int64 `json:"maxLifetimeClosed"`
+
+	// StaleReconnects is how many queries failed on a dropped connection
+	// and were retried on a fresh one, since the manager was created
+	StaleReconnects int64 `json:"staleReconnects"`
+
+	// KeepWarmInterval (seconds) is how often an idle connection is pinged;
+	// 0 when keep-warm is off
+	KeepWarmInterval int `json:"keepWarmInterval"`
+}
+
+// PoolStats returns the connection pool statistics
+func (m *Manager) PoolStats() (*PoolStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.connected || m.driver == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	stats := &PoolStats{
+		StaleReconnects:  m.staleReconnects.Load(),
+		KeepWarmInterval: int(m.keepWarm / time.Second),
+	}
+	if db := m.driver.GetDB(); db != nil {
+		s := db.Stats()
+		stats.OpenConnections = s.OpenConnections
+		stats.InUse = s.InUse
+		stats.Idle = s.Idle
+		stats.WaitCount = s.WaitCount
+		stats.WaitDuration = float64(s.WaitDuration.Microseconds()) / 1000
+		stats.MaxLifetimeClosed = s.MaxLifetimeClosed
+	}
+	return stats, nil
+}
+
+// SetKeepWarmInterval sets how often a pooled connection is pinged so that
+// one stays open and validated through long idle periods, where the first
+// query would otherwise pay for a reconnect. Zero or less turns it off.
+func (m *Manager) SetKeepWarmInterval(interval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.keepWarm = max(interval, 0)
+	m.stopKeepWarm()
+	if m.connected && m.driver != nil {
+		m.startKeepWarm(m.driver)
+	}
+}
+
+// startKeepWarm starts the keep-warm loop for driver if it is enabled.
+// Caller must hold m.mu.
+func (m *Manager) startKeepWarm(driver Driver) {
+	if m.keepWarm <= 0 {
+		return
+	}
+	m.stopWarm = make(chan struct{})
+	go m.keepWarmLoop(driver, m.keepWarm, m.stopWarm)
+}
+
+// stopKeepWarm stops the keep-warm loop. Caller must hold m.mu.
+func (m *Manager) stopKeepWarm() {
+	if m.stopWarm != nil {
+		close(m.stopWarm)
+		m.stopWarm = nil
+	}
+}
+
+// keepWarmLoop checks a connection out of the pool and pings it on every
+// tick. Unlike the health loop's pool Ping, which may find no idle
+// connection after the pool recycled them, this opens one when needed. A
+// failed ping is left to the health loop to report.
+func (m *Manager) keepWarmLoop(driver Driver, interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		m.mu.RLock()
+		if m.driver != driver {
+			m.mu.RUnlock()
+			return
+		}
+		if db := driver.GetDB(); db != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), keepWarmPingTimeout)
+			if conn, err := db.Conn(ctx); err == nil {
+				conn.PingContext(ctx)
+				conn.Close()
+			}
+			cancel()
+		}
+		m.mu.RUnlock()
+	}
+}
+
+// isConnectionError reports whether a query failed because its connection
+// was dropped (server restart, wait_timeout, a NAT closing the idle link)
+// rather than because of the query itself
+func isConnectionError(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
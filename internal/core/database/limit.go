@@ -0,0 +1,172 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// LimitStyle is how a SQL dialect caps the rows a query returns
+type LimitStyle int
+
+const (
+	// LimitClause appends LIMIT n (MySQL, Postgres, SQLite)
+	LimitClause LimitStyle = iota
+
+	// LimitTop inserts TOP n after SELECT (SQL Server)
+	LimitTop
+
+	// LimitFetchFirst appends FETCH FIRST n ROWS ONLY (Oracle, DB2)
+	LimitFetchFirst
+)
+
+// LimitStyleFor returns the limit style of a driver name
+func LimitStyleFor(driver string) LimitStyle {
+	switch strings.ToLower(driver) {
+	case "sqlserver", "mssql":
+		return LimitTop
+	case "oracle", "db2":
+		return LimitFetchFirst
+	}
+	return LimitClause
+}
+
+// limitsRows reports whether code[i] starts a clause capping rows (a limited
+// subquery counts too). TOP, FETCH and ROWNUM are only taken as such in
+// clause position, since unquoted they can also be column names.
+func limitsRows(code []*sqlToken, i int) bool {
+	tok := code[i]
+	if tok.kind != tokenWord || (i > 0 && code[i-1].text == ".") {
+		return false
+	}
+	word := func(j int) string {
+		if j < 0 || j >= len(code) || code[j].kind != tokenWord {
+			return ""
+		}
+		return strings.ToUpper(code[j].text)
+	}
+
+	switch strings.ToUpper(tok.text) {
+	case "LIMIT":
+		return true
+	case "TOP":
+		// SELECT [DISTINCT|ALL] TOP n or TOP (n)
+		prev := word(i - 1)
+		if prev != "SELECT" && prev != "DISTINCT" && prev != "ALL" {
+			return false
+		}
+		return i+1 < len(code) && (code[i+1].kind == tokenNumber || code[i+1].text == "(")
+	case "FETCH":
+		// FETCH FIRST|NEXT n ROWS ONLY
+		next := word(i + 1)
+		return next == "FIRST" || next == "NEXT"
+	case "ROWNUM":
+		// WHERE ROWNUM <= n
+		if i+1 < len(code) && code[i+1].kind == tokenPunct {
+			switch code[i+1].text {
+			case "<", "<=", "=":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ApplyRowLimit caps a SELECT (or WITH ... SELECT) at limit rows in the given
+// style. Anything else, several statements, a query that already limits its
+// rows, or one that doesn't tokenize is returned unchanged.
+func ApplyRowLimit(query string, limit int, style LimitStyle) string {
+	if limit <= 0 {
+		return query
+	}
+
+	tokens, err := tokenizeSQL(query)
+	if err != nil {
+		return query
+	}
+	statements := splitStatements(tokens)
+	if len(statements) != 1 {
+		return query
+	}
+	stmt := statements[0]
+
+	first := strings.ToUpper(firstCode(stmt).text)
+	if first != "SELECT" && first != "WITH" {
+		return query
+	}
+
+	code := make([]*sqlToken, 0, len(stmt))
+	for i := range stmt {
+		if stmt[i].kind != tokenLineComment && stmt[i].kind != tokenBlockComment {
+			code = append(code, &stmt[i])
+		}
+	}
+
+	// The main SELECT is the first one outside parentheses; a WITH's CTE
+	// bodies are all parenthesized. Row-locking clauses must stay last.
+	depth := 0
+	var mainSelect, lastCode, locking *sqlToken
+	for i, tok := range code {
+		switch tok.kind {
+		case tokenWord:
+			if limitsRows(code, i) {
+				return query
+			}
+			word := strings.ToUpper(tok.text)
+			if depth == 0 {
+				switch {
+				case word == "SELECT" && mainSelect == nil:
+					mainSelect = tok
+				case (word == "INSERT" || word == "UPDATE" || word == "DELETE") && first == "WITH":
+					// WITH ... UPDATE/DELETE writes rather than reads
+					return query
+				case (word == "FOR" || word == "LOCK") && locking == nil:
+					locking = tok
+				}
+			}
+		case tokenPunct:
+			switch tok.text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+		}
+		lastCode = tok
+	}
+	if mainSelect == nil {
+		return query
+	}
+
+	runes := []rune(query)
+	insertAt := func(pos int, text string) string {
+		return string(runes[:pos]) + text + string(runes[pos:])
+	}
+
+	if style == LimitTop {
+		// TOP follows DISTINCT/ALL
+		pos := mainSelect.pos + utf8.RuneCountInString(mainSelect.text)
+		for i := range stmt {
+			tok := &stmt[i]
+			if tok.pos > mainSelect.pos && tok.kind == tokenWord {
+				if word := strings.ToUpper(tok.text); word == "DISTINCT" || word == "ALL" {
+					pos = tok.pos + utf8.RuneCountInString(tok.text)
+				}
+				break
+			}
+		}
+		return insertAt(pos, fmt.Sprintf(" TOP %d", limit))
+	}
+
+	clause := fmt.Sprintf(" LIMIT %d", limit)
+	if style == LimitFetchFirst {
+		clause = fmt.Sprintf(" FETCH FIRST %d ROWS ONLY", limit)
+	}
+
+	// Insert after the last code token so a trailing comment or semicolon
+	// can't swallow the clause
+	if locking != nil {
+		return insertAt(locking.pos, strings.TrimPrefix(clause, " ")+" ")
+	}
+	return insertAt(lastCode.pos+utf8.RuneCountInString(lastCode.text), clause)
+}
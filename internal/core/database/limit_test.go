@@ -0,0 +1,122 @@
+package database
+
+import "testing"
+
+func TestApplyRowLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		style LimitStyle
+		want  string
+	}{
+		{
+			name:  "plain select",
+			query: "SELECT * FROM users",
+			want:  "SELECT * FROM users LIMIT 100",
+		},
+		{
+			name:  "existing limit",
+			query: "SELECT * FROM users LIMIT 10",
+			want:  "SELECT * FROM users LIMIT 10",
+		},
+		{
+			name:  "existing limit and offset",
+			query: "SELECT * FROM users LIMIT 10 OFFSET 20",
+			want:  "SELECT * FROM users LIMIT 10 OFFSET 20",
+		},
+		{
+			name:  "limited subquery",
+			query: "SELECT * FROM (SELECT * FROM users LIMIT 5) AS u",
+			want:  "SELECT * FROM (SELECT * FROM users LIMIT 5) AS u",
+		},
+		{
+			name:  "with select",
+			query: "WITH recent AS (SELECT * FROM orders WHERE created_at > NOW() - INTERVAL 1 DAY) SELECT * FROM recent",
+			want:  "WITH recent AS (SELECT * FROM orders WHERE created_at > NOW() - INTERVAL 1 DAY) SELECT * FROM recent LIMIT 100",
+		},
+		{
+			name:  "with update",
+			query: "WITH stale AS (SELECT id FROM sessions) UPDATE sessions SET expired = 1 WHERE id IN (SELECT id FROM stale)",
+			want:  "WITH stale AS (SELECT id FROM sessions) UPDATE sessions SET expired = 1 WHERE id IN (SELECT id FROM stale)",
+		},
+		{
+			name:  "for update",
+			query: "SELECT * FROM accounts WHERE id = 1 FOR UPDATE",
+			want:  "SELECT * FROM accounts WHERE id = 1 LIMIT 100 FOR UPDATE",
+		},
+		{
+			name:  "trailing semicolon",
+			query: "SELECT * FROM users;",
+			want:  "SELECT * FROM users LIMIT 100;",
+		},
+		{
+			name:  "trailing comment",
+			query: "SELECT * FROM users -- everyone",
+			want:  "SELECT * FROM users LIMIT 100 -- everyone",
+		},
+		{
+			name:  "not a select",
+			query: "DELETE FROM users",
+			want:  "DELETE FROM users",
+		},
+		{
+			name:  "several statements",
+			query: "SELECT 1; SELECT 2",
+			want:  "SELECT 1; SELECT 2",
+		},
+		{
+			name:  "column named top",
+			query: "SELECT top, bottom FROM bounds",
+			want:  "SELECT top, bottom FROM bounds LIMIT 100",
+		},
+		{
+			name:  "columns named fetch and rownum",
+			query: "SELECT fetch, rownum FROM jobs WHERE rownum > 3",
+			want:  "SELECT fetch, rownum FROM jobs WHERE rownum > 3 LIMIT 100",
+		},
+		{
+			name:  "top style",
+			query: "SELECT name FROM users",
+			style: LimitTop,
+			want:  "SELECT TOP 100 name FROM users",
+		},
+		{
+			name:  "top style after distinct",
+			query: "SELECT DISTINCT name FROM users",
+			style: LimitTop,
+			want:  "SELECT DISTINCT TOP 100 name FROM users",
+		},
+		{
+			name:  "existing top",
+			query: "SELECT TOP 5 name FROM users",
+			style: LimitTop,
+			want:  "SELECT TOP 5 name FROM users",
+		},
+		{
+			name:  "fetch first style",
+			query: "SELECT name FROM users",
+			style: LimitFetchFirst,
+			want:  "SELECT name FROM users FETCH FIRST 100 ROWS ONLY",
+		},
+		{
+			name:  "existing fetch first",
+			query: "SELECT name FROM users FETCH FIRST 5 ROWS ONLY",
+			style: LimitFetchFirst,
+			want:  "SELECT name FROM users FETCH FIRST 5 ROWS ONLY",
+		},
+		{
+			name:  "existing rownum",
+			query: "SELECT name FROM users WHERE ROWNUM <= 5",
+			style: LimitFetchFirst,
+			want:  "SELECT name FROM users WHERE ROWNUM <= 5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplyRowLimit(tt.query, 100, tt.style); got != tt.want {
+				t.Errorf("ApplyRowLimit(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
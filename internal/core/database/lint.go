@@ -0,0 +1,227 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LintWarning is a suspicious pattern found in a query by LintQuery
+type LintWarning struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"` // high, medium, low
+	Message  string `json:"message"`
+
+	// Line and Column locate the offending token (1-based)
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// placeholderPattern matches template placeholders left in a query built by
+// string interpolation (Ruby #{...}, JS ${...}, Mustache {{...}})
+var placeholderPattern = regexp.MustCompile(`#\{|\$\{|\{\{`)
+
+// printfPattern matches printf verbs (%s, %d, %v). Only checked outside
+// literals, where '%Y-%m-%d' date formats and LIKE '%s%' patterns are valid.
+var printfPattern = regexp.MustCompile(`%[sdv]\b`)
+
+// LintQuery looks for patterns that suggest untrusted input was interpolated
+// into a query or that the query does more than intended: tautologies like
+// OR 1=1, stacked statements, commented-out WHERE clauses, UPDATE/DELETE
+// without WHERE and leftover template placeholders. It only analyzes the text
+// and never blocks execution.
+func LintQuery(query string) []LintWarning {
+	l := &linter{runes: []rune(query), warnings: make([]LintWarning, 0)}
+
+	tokens, err := tokenizeSQL(query)
+	if err != nil {
+		l.add(0, "unbalanced-quote", "high",
+			fmt.Sprintf("%s; an unmatched quote often means input was interpolated without escaping", err))
+		return l.warnings
+	}
+
+	statements := splitStatements(tokens)
+	if len(statements) > 1 && !isTransactionBlock(statements) {
+		l.add(firstCode(statements[1]).pos, "stacked-statements", "medium",
+			"Query contains more than one statement; if that's unintended, part of it may come from interpolated input")
+	}
+
+	for _, stmt := range statements {
+		l.lintStatement(stmt)
+	}
+
+	return l.warnings
+}
+
+// linter accumulates warnings for a query
+type linter struct {
+	runes    []rune
+	warnings []LintWarning
+}
+
+// add records a warning at the given rune offset
+func (l *linter) add(pos int, rule, severity, message string) {
+	line, column := 1, 1
+	for i := 0; i < pos && i < len(l.runes); i++ {
+		if l.runes[i] == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	l.warnings = append(l.warnings, LintWarning{
+		Rule:     rule,
+		Severity: severity,
+		Message:  message,
+		Line:     line,
+		Column:   column,
+	})
+}
+
+// lintStatement applies the per-statement rules
+func (l *linter) lintStatement(stmt []sqlToken) {
+	var code, comments []sqlToken
+	for _, tok := range stmt {
+		if tok.kind == tokenLineComment || tok.kind == tokenBlockComment {
+			comments = append(comments, tok)
+		} else {
+			code = append(code, tok)
+		}
+	}
+
+	verb := strings.ToUpper(code[0].text)
+	hasWhere := false
+	for _, tok := range code {
+		if tok.kind == tokenWord && strings.EqualFold(tok.text, "WHERE") {
+			hasWhere = true
+			break
+		}
+	}
+
+	var commentedWhere *sqlToken
+	for i, tok := range comments {
+		if strings.Contains(strings.ToUpper(tok.text), "WHERE") {
+			commentedWhere = &comments[i]
+			break
+		}
+	}
+
+	switch {
+	case (verb == "UPDATE" || verb == "DELETE") && !hasWhere && commentedWhere != nil:
+		l.add(commentedWhere.pos, "commented-where", "high",
+			fmt.Sprintf("The WHERE clause is commented out, so this %s affects every row", verb))
+	case (verb == "UPDATE" || verb == "DELETE") && !hasWhere:
+		l.add(code[0].pos, "missing-where", "high",
+			fmt.Sprintf("%s without a WHERE clause affects every row", verb))
+	case commentedWhere != nil:
+		l.add(commentedWhere.pos, "commented-where", "medium",
+			"A comment contains a WHERE condition; check it wasn't commented out by accident")
+	}
+
+	l.lintTautologies(code)
+	l.lintPlaceholders(code)
+}
+
+// lintTautologies flags OR conditions that are always true (OR 1=1, OR 'a'='a', OR TRUE)
+func (l *linter) lintTautologies(code []sqlToken) {
+	for i, tok := range code {
+		if tok.kind != tokenWord || !strings.EqualFold(tok.text, "OR") {
+			continue
+		}
+
+		// Skip opening parentheses: OR (1=1)
+		j := i + 1
+		for j < len(code) && code[j].text == "(" {
+			j++
+		}
+		if j >= len(code) {
+			continue
+		}
+
+		condition := ""
+		switch {
+		case code[j].kind == tokenWord && strings.EqualFold(code[j].text, "TRUE"):
+			condition = code[j].text
+		case j+2 < len(code) && code[j+1].text == "=" &&
+			isLiteral(code[j]) && isLiteral(code[j+2]) && code[j].text == code[j+2].text:
+			condition = code[j].text + "=" + code[j+2].text
+		default:
+			continue
+		}
+
+		l.add(tok.pos, "tautology", "high",
+			fmt.Sprintf("OR %s is always true, so the condition matches every row; this is a common injection pattern", condition))
+	}
+}
+
+// lintPlaceholders flags template placeholders left from building the query as a string
+func (l *linter) lintPlaceholders(code []sqlToken) {
+	for i, tok := range code {
+		text := tok.text
+		if tok.kind != tokenString && i+1 < len(code) && code[i+1].kind != tokenString {
+			// The tokenizer splits placeholders outside literals: "#" "{", "%" "s"
+			text += code[i+1].text
+		}
+
+		match := placeholderPattern.FindString(text)
+		if match == "" && tok.kind != tokenString {
+			match = printfPattern.FindString(text)
+		}
+		if match != "" {
+			l.add(tok.pos, "placeholder", "medium",
+				fmt.Sprintf("%q looks like an unexpanded template placeholder; pass values as bound parameters instead of building the query as a string", match))
+			return
+		}
+	}
+}
+
+// splitStatements splits tokens on semicolons, dropping statements that have
+// no code (e.g. only a trailing comment)
+func splitStatements(tokens []sqlToken) [][]sqlToken {
+	var statements [][]sqlToken
+	var current []sqlToken
+
+	flush := func() {
+		if firstCode(current) != nil {
+			statements = append(statements, current)
+		}
+		current = nil
+	}
+
+	for _, tok := range tokens {
+		if tok.kind == tokenPunct && tok.text == ";" {
+			flush()
+			continue
+		}
+		current = append(current, tok)
+	}
+	flush()
+
+	return statements
+}
+
+// firstCode returns the first non-comment token of a statement, or nil
+func firstCode(stmt []sqlToken) *sqlToken {
+	for i, tok := range stmt {
+		if tok.kind != tokenLineComment && tok.kind != tokenBlockComment {
+			return &stmt[i]
+		}
+	}
+	return nil
+}
+
+// isTransactionBlock reports whether the statements are an explicit
+// transaction (BEGIN / START TRANSACTION ... COMMIT / ROLLBACK)
+func isTransactionBlock(statements [][]sqlToken) bool {
+	first := strings.ToUpper(firstCode(statements[0]).text)
+	last := strings.ToUpper(firstCode(statements[len(statements)-1]).text)
+
+	return (first == "BEGIN" || first == "START") && (last == "COMMIT" || last == "ROLLBACK")
+}
+
+// isLiteral reports whether a token is a number or quoted string
+func isLiteral(tok sqlToken) bool {
+	return tok.kind == tokenNumber || (tok.kind == tokenString && strings.HasPrefix(tok.text, "'"))
+}
@@ -0,0 +1,191 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Maintenance statements by driver and operation
+var maintenanceOperations = map[string]map[string]string{
+	"mysql": {
+		"optimize": "OPTIMIZE TABLE %s",
+		"analyze":  "ANALYZE TABLE %s",
+	},
+}
+
+// Queries for a table's size in bytes, data plus indexes
+var tableSizeQueries = map[string]string{
+	"mysql": `SELECT IFNULL(DATA_LENGTH, 0) + IFNULL(INDEX_LENGTH, 0)
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?`,
+}
+
+// MaintenanceResult is the outcome of a table maintenance operation
+type MaintenanceResult struct {
+	Table     string `json:"table"`
+	Operation string `json:"operation"`
+	Statement string `json:"statement"`
+
+	// Messages are the rows the statement returned, e.g. MySQL's
+	// "status: OK" or "note: Table does not support optimize, ..."
+	Messages []string `json:"messages"`
+
+	// SizeBefore and SizeAfter are the table's size in bytes, data plus
+	// indexes, or 0 when the server didn't report it
+	SizeBefore int64 `json:"sizeBefore,omitempty"`
+	SizeAfter  int64 `json:"sizeAfter,omitempty"`
+
+	// ExecutionTime is how long the statement took in milliseconds
+	ExecutionTime float64 `json:"executionTime"`
+}
+
+// MaintenanceOperations returns the maintenance operations a driver supports
+func MaintenanceOperations(driver string) []string {
+	ops := make([]string, 0, len(maintenanceOperations[driver]))
+	for op := range maintenanceOperations[driver] {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// RunMaintenance runs a maintenance operation (OPTIMIZE or ANALYZE TABLE on
+// MySQL) on one table. These lock or rewrite the table, so callers should confirm first and
+// allow a long timeout through ctx.
+func (m *Manager) RunMaintenance(ctx context.Context, table, operation string) (*MaintenanceResult, error) {
+	m.mu.RLock()
+	connected := m.connected
+	driver := m.driver
+	config := m.config
+	m.mu.RUnlock()
+
+	if !connected || driver == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if config.ReadOnly {
+		return nil, ErrReadOnly
+	}
+
+	driverName := strings.ToLower(config.Driver)
+	operation = strings.ToLower(strings.TrimSpace(operation))
+	statement, ok := maintenanceOperations[driverName][operation]
+	if !ok {
+		return nil, fmt.Errorf("unsupported maintenance operation for %s: %s (use %s)",
+			driverName, operation, strings.Join(MaintenanceOperations(driverName), ", "))
+	}
+
+	// The table must exist, which also rules out anything but a table name
+	tables, err := driver.GetTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, t := range tables {
+		if t.Name == table && t.Type != "VIEW" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("table not found: %s", table)
+	}
+
+	result := &MaintenanceResult{
+		Table:     table,
+		Operation: operation,
+		Statement: fmt.Sprintf(statement, quoteIdentifier(table)),
+		Messages:  []string{},
+	}
+
+	// One connection for everything, so the session setting below applies
+	conn, err := driver.GetDB().Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if driverName == "mysql" {
+		// MySQL 8 caches table sizes for a day; read them fresh. Older
+		// servers and MariaDB don't have the setting and don't cache.
+		if _, err := conn.ExecContext(ctx, "SET SESSION information_schema_stats_expiry = 0"); err == nil {
+			defer conn.ExecContext(context.Background(), "SET SESSION information_schema_stats_expiry = DEFAULT")
+		}
+	}
+
+	result.SizeBefore = tableSize(ctx, conn, driverName, table)
+
+	start := time.Now()
+	rows, err := conn.QueryContext(ctx, result.Statement)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", operation, err)
+	}
+	result.Messages, err = maintenanceMessages(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	result.ExecutionTime = float64(time.Since(start).Microseconds()) / 1000
+
+	result.SizeAfter = tableSize(ctx, conn, driverName, table)
+
+	return result, nil
+}
+
+// tableSize returns a table's size in bytes, or 0 if it can't be read
+func tableSize(ctx context.Context, conn *sql.Conn, driver, table string) int64 {
+	query, ok := tableSizeQueries[driver]
+	if !ok {
+		return 0
+	}
+	var size sql.NullInt64
+	if err := conn.QueryRowContext(ctx, query, table).Scan(&size); err != nil {
+		return 0
+	}
+	return size.Int64
+}
+
+// maintenanceMessages reads a maintenance statement's result rows. MySQL
+// reports Table, Op, Msg_type and Msg_text; other results are joined as is.
+func maintenanceMessages(rows *sql.Rows) ([]string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	msgType, msgText := -1, -1
+	for i, col := range columns {
+		switch strings.ToLower(col) {
+		case "msg_type":
+			msgType = i
+		case "msg_text":
+			msgText = i
+		}
+	}
+
+	messages := []string{}
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if msgType >= 0 && msgText >= 0 {
+			messages = append(messages, values[msgType].String+": "+values[msgText].String)
+			continue
+		}
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = v.String
+		}
+		messages = append(messages, strings.Join(parts, " "))
+	}
+	return messages, rows.Err()
+}
@@ -1,10 +1,12 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,17 +22,38 @@ type Driver interface {
 	Ping() error
 
 	// GetTables returns all tables in the database
-	GetTables() ([]TableInfo, error)
+	GetTables(ctx context.Context) ([]TableInfo, error)
 
 	// GetColumns returns columns for a specific table
-	GetColumns(tableName string) ([]ColumnInfo, error)
+	GetColumns(ctx context.Context, tableName string) ([]ColumnInfo, error)
+
+	// GetIndexes returns all indexes in the database
+	GetIndexes(ctx context.Context) ([]IndexInfo, error)
+
+	// GetForeignKeys returns the foreign keys defined on a table
+	GetForeignKeys(ctx context.Context, tableName string) ([]ForeignKeyInfo, error)
+
+	// GetColumnStats profiles a column, scanning at most scanCap rows
+	GetColumnStats(tableName, columnName string, topN, scanCap int) (*ColumnStats, error)
 
 	// ExecuteQuery executes a SQL query and returns results
 	ExecuteQuery(query string, limit int) (*QueryResult, error)
 
+	// LimitQuery caps a SELECT at limit rows in the driver's SQL dialect
+	LimitQuery(query string, limit int) string
+
 	// ExplainQuery returns the execution plan for a query
 	ExplainQuery(query string) (*ExplainResult, error)
 
+	// UpdateRow updates the single row with the given primary key
+	UpdateRow(tableName string, pk, changes map[string]interface{}) (int64, error)
+
+	// InsertRow inserts a row and returns it as stored
+	InsertRow(tableName string, values map[string]interface{}) (map[string]interface{}, error)
+
+	// DeleteRow deletes the single row with the given primary key
+	DeleteRow(tableName string, pk map[string]interface{}) (int64, error)
+
 	// GetVersion returns the database version
 	GetVersion() (string, error)
 
@@ -48,6 +71,48 @@ type Manager struct {
 	maxHistory         int
 	queryStats         map[string]*QueryStatistic
 	slowQueryThreshold float64 // in milliseconds
+
+	// Health check
+	healthInterval time.Duration
+	stopHealth     chan struct{}
+
+	// Keep-warm ping, off when keepWarm is zero
+	keepWarm time.Duration
+	stopWarm chan struct{}
+
+	// Queries retried after failing on a dropped connection
+	staleReconnects atomic.Int64
+
+	// Open result cursors by ID, and how many are still running their query
+	cursorMu       sync.Mutex
+	cursors        map[string]*cursor
+	openingCursors int
+
+	// Latest EXPLAIN per query fingerprint
+	explainMu    sync.Mutex
+	explainCache map[string]*ExplainResult
+
+	// Fingerprint each RunnableQuery example came from, by exactQueryKey, so
+	// explaining the example caches the plan for its fingerprint too. Guarded
+	// by explainMu.
+	runnableOf map[string]string
+
+	// Plan baselines by exactQueryKey, guarded by explainMu
+	baselines map[string]*PlanBaseline
+
+	// Query statistics of every database connected to, by identity;
+	// queryStats is the entry for statsKey
+	statsKey    string
+	statsByConn map[string]map[string]*QueryStatistic
+	statsNames  map[string]string // Connection name -> identity
+
+	// Last ER model built for the connection, see CachedERModel
+	erMu    sync.Mutex
+	erModel *ERModel
+
+	// Callbacks for connection events
+	OnConnectionLost func(err error)
+	OnReconnected    func()
 }
 
 // NewManager creates a new database manager
@@ -56,17 +121,64 @@ func NewManager() *Manager {
 		queryHistory:       make([]SavedQuery, 0),
 		maxHistory:         100,
 		queryStats:         make(map[string]*QueryStatistic),
+		statsByConn:        make(map[string]map[string]*QueryStatistic),
+		statsNames:         make(map[string]string),
 		slowQueryThreshold: 100.0, // 100ms default
+		healthInterval:     15 * time.Second,
+		cursors:            make(map[string]*cursor),
+		explainCache:       make(map[string]*ExplainResult),
+		runnableOf:         make(map[string]string),
+		baselines:          make(map[string]*PlanBaseline),
 	}
 }
 
+// SetSlowQueryThreshold sets the slow query threshold in milliseconds and
+// re-flags collected statistics against it
+func (m *Manager) SetSlowQueryThreshold(threshold float64) {
+	if threshold <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.slowQueryThreshold = threshold
+	reflag := func(stats map[string]*QueryStatistic) {
+		for _, stat := range stats {
+			switch {
+			case stat.Issue == "" && stat.AvgTime > threshold:
+				stat.Issue = "slow"
+			case stat.Issue == "slow" && stat.AvgTime <= threshold:
+				stat.Issue = ""
+			}
+		}
+	}
+	reflag(m.queryStats)
+	for key, stats := range m.statsByConn {
+		if key != m.statsKey {
+			reflag(stats)
+		}
+	}
+}
+
+// SlowQueryThreshold returns the slow query threshold in milliseconds
+func (m *Manager) SlowQueryThreshold() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.slowQueryThreshold
+}
+
 // Connect connects to a database
 func (m *Manager) Connect(config ConnectionConfig) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Disconnect existing connection if any
+	m.stopHealthCheck()
+	m.stopKeepWarm()
 	if m.driver != nil {
+		m.closeAllCursors()
 		m.driver.Disconnect()
 	}
 
@@ -85,10 +197,20 @@ func (m *Manager) Connect(config ConnectionConfig) error {
 		return err
 	}
 
+	// The driver's pool keeps what it needs to reconnect; don't hold the password
+	config.Password = ""
+
+	m.clearERModel()
+	m.switchQueryStats(config)
+
 	m.driver = driver
 	m.config = config
 	m.connected = true
 
+	m.stopHealth = make(chan struct{})
+	go m.healthLoop(driver, m.stopHealth)
+	m.startKeepWarm(driver)
+
 	return nil
 }
 
@@ -97,7 +219,11 @@ func (m *Manager) Disconnect() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	m.stopHealthCheck()
+	m.stopKeepWarm()
+	m.clearERModel()
 	if m.driver != nil {
+		m.closeAllCursors()
 		err := m.driver.Disconnect()
 		m.driver = nil
 		m.connected = false
@@ -107,6 +233,104 @@ func (m *Manager) Disconnect() error {
 	return nil
 }
 
+// stopHealthCheck stops the health loop. Caller must hold m.mu.
+func (m *Manager) stopHealthCheck() {
+	if m.stopHealth != nil {
+		close(m.stopHealth)
+		m.stopHealth = nil
+	}
+}
+
+// healthLoop pings the database periodically. When a ping fails the manager
+// is marked disconnected and pinged with backoff until the server is back;
+// the driver's pool re-establishes connections from its stored DSN.
+func (m *Manager) healthLoop(driver Driver, stop chan struct{}) {
+	ticker := time.NewTicker(m.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		current, err := m.pingDriver(driver)
+		if !current {
+			return
+		}
+		if err == nil {
+			continue
+		}
+
+		m.mu.Lock()
+		if m.driver != driver {
+			m.mu.Unlock()
+			return
+		}
+		m.connected = false
+		m.mu.Unlock()
+
+		if m.OnConnectionLost != nil {
+			m.OnConnectionLost(err)
+		}
+
+		if !m.waitForReconnect(driver, stop) {
+			return
+		}
+
+		m.mu.Lock()
+		if m.driver != driver {
+			m.mu.Unlock()
+			return
+		}
+		m.connected = true
+		m.mu.Unlock()
+
+		if m.OnReconnected != nil {
+			m.OnReconnected()
+		}
+	}
+}
+
+// waitForReconnect pings with exponential backoff (1s up to 30s) until the
+// database answers. Returns false if the loop was stopped first.
+func (m *Manager) waitForReconnect(driver Driver, stop chan struct{}) bool {
+	backoff := time.Second
+	for {
+		select {
+		case <-stop:
+			return false
+		case <-time.After(backoff):
+		}
+
+		current, err := m.pingDriver(driver)
+		if !current {
+			return false
+		}
+		if err == nil {
+			return true
+		}
+
+		backoff *= 2
+		if backoff > 30*time.Second {
+			backoff = 30 * time.Second
+		}
+	}
+}
+
+// pingDriver pings driver if it is still the manager's driver. The read lock
+// keeps Disconnect from closing the driver mid-ping.
+func (m *Manager) pingDriver(driver Driver) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.driver != driver {
+		return false, nil
+	}
+	return true, driver.Ping()
+}
+
 // GetStatus returns the current connection status
 func (m *Manager) GetStatus() DatabaseStatus {
 	m.mu.RLock()
@@ -120,6 +344,8 @@ func (m *Manager) GetStatus() DatabaseStatus {
 		status.Driver = m.config.Driver
 		status.Database = m.config.Database
 		status.Host = m.config.Host
+		status.Name = m.config.Name
+		status.ReadOnly = m.config.ReadOnly
 
 		if version, err := m.driver.GetVersion(); err == nil {
 			status.Version = version
@@ -130,7 +356,7 @@ func (m *Manager) GetStatus() DatabaseStatus {
 }
 
 // GetTables returns all tables
-func (m *Manager) GetTables() ([]TableInfo, error) {
+func (m *Manager) GetTables(ctx context.Context) ([]TableInfo, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -138,11 +364,23 @@ func (m *Manager) GetTables() ([]TableInfo, error) {
 		return nil, fmt.Errorf("not connected to database")
 	}
 
-	return m.driver.GetTables()
+	return m.driver.GetTables(ctx)
 }
 
 // GetColumns returns columns for a table
-func (m *Manager) GetColumns(tableName string) ([]ColumnInfo, error) {
+func (m *Manager) GetColumns(ctx context.Context, tableName string) ([]ColumnInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.connected || m.driver == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	return m.driver.GetColumns(ctx, tableName)
+}
+
+// GetIndexes returns all indexes in the database
+func (m *Manager) GetIndexes(ctx context.Context) ([]IndexInfo, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -150,7 +388,31 @@ func (m *Manager) GetColumns(tableName string) ([]ColumnInfo, error) {
 		return nil, fmt.Errorf("not connected to database")
 	}
 
-	return m.driver.GetColumns(tableName)
+	return m.driver.GetIndexes(ctx)
+}
+
+// GetForeignKeys returns the foreign keys defined on a table
+func (m *Manager) GetForeignKeys(ctx context.Context, tableName string) ([]ForeignKeyInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.connected || m.driver == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	return m.driver.GetForeignKeys(ctx, tableName)
+}
+
+// GetColumnStats returns value statistics for a table column
+func (m *Manager) GetColumnStats(tableName, columnName string, topN, scanCap int) (*ColumnStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.connected || m.driver == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	return m.driver.GetColumnStats(tableName, columnName, topN, scanCap)
 }
 
 // ExecuteQuery executes a SQL query
@@ -164,6 +426,10 @@ func (m *Manager) ExecuteQuery(query string, limit int) (*QueryResult, error) {
 		return nil, fmt.Errorf("not connected to database")
 	}
 
+	if m.IsReadOnly() && !IsReadOnlyQuery(query) {
+		return nil, ErrReadOnly
+	}
+
 	if limit <= 0 {
 		limit = 1000 // Default limit
 	}
@@ -173,12 +439,70 @@ func (m *Manager) ExecuteQuery(query string, limit int) (*QueryResult, error) {
 		return result, err
 	}
 
+	// A connection the server dropped while idle fails the first query on
+	// it. Reads are retried once on a fresh connection; a write may already
+	// have been applied, so it is left failed.
+	if result.Error != "" && isConnectionError(result.err) && IsReadOnlyQuery(query) {
+		if current, pingErr := m.pingDriver(driver); current && pingErr == nil {
+			m.staleReconnects.Add(1)
+			result, err = driver.ExecuteQuery(query, limit)
+			if err != nil {
+				return result, err
+			}
+		}
+	}
+
 	// Record query statistics
 	m.RecordQueryExecution(query, result.ExecutionTime)
 
 	return result, nil
 }
 
+// UpdateRow updates one row of a table, identified by its primary key
+func (m *Manager) UpdateRow(tableName string, pk, changes map[string]interface{}) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.connected || m.driver == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+	if m.config.ReadOnly {
+		return 0, ErrReadOnly
+	}
+
+	return m.driver.UpdateRow(tableName, pk, changes)
+}
+
+// InsertRow inserts a row into a table and returns it as stored
+func (m *Manager) InsertRow(tableName string, values map[string]interface{}) (map[string]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.connected || m.driver == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if m.config.ReadOnly {
+		return nil, ErrReadOnly
+	}
+
+	return m.driver.InsertRow(tableName, values)
+}
+
+// DeleteRow deletes one row of a table, identified by its primary key
+func (m *Manager) DeleteRow(tableName string, pk map[string]interface{}) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.connected || m.driver == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+	if m.config.ReadOnly {
+		return 0, ErrReadOnly
+	}
+
+	return m.driver.DeleteRow(tableName, pk)
+}
+
 // ExplainQuery returns the execution plan
 func (m *Manager) ExplainQuery(query string) (*ExplainResult, error) {
 	m.mu.RLock()
@@ -188,7 +512,13 @@ func (m *Manager) ExplainQuery(query string) (*ExplainResult, error) {
 		return nil, fmt.Errorf("not connected to database")
 	}
 
-	return m.driver.ExplainQuery(query)
+	result, err := m.driver.ExplainQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	m.cacheExplain(query, result)
+
+	return result, nil
 }
 
 // SaveQuery saves a query to history
@@ -270,6 +600,10 @@ func (m *Manager) RecordQueryExecution(sql string, executionTime float64) {
 		stat.AvgTime = stat.TotalTime / float64(stat.Count)
 		stat.LastExecuted = time.Now().Format(time.RFC3339)
 		stat.SQL = sql // Keep most recent query
+		if executionTime > stat.MaxTime {
+			stat.SlowestSQL = sql
+			stat.MaxTime = executionTime
+		}
 
 		// Check for slow query
 		if stat.AvgTime > m.slowQueryThreshold {
@@ -286,6 +620,8 @@ func (m *Manager) RecordQueryExecution(sql string, executionTime float64) {
 			ID:           fmt.Sprintf("%d", time.Now().UnixNano()),
 			Fingerprint:  fingerprint,
 			SQL:          sql,
+			SlowestSQL:   sql,
+			MaxTime:      executionTime,
 			Count:        1,
 			AvgTime:      executionTime,
 			TotalTime:    executionTime,
@@ -295,6 +631,66 @@ func (m *Manager) RecordQueryExecution(sql string, executionTime float64) {
 	}
 }
 
+// connectionIdentity identifies the database a config points at, so stats
+// follow the database rather than the connection's name
+func connectionIdentity(config ConnectionConfig) string {
+	return fmt.Sprintf("%s://%s:%d/%s", strings.ToLower(config.Driver), config.Host, config.Port, config.Database)
+}
+
+// switchQueryStats makes queryStats the statistics of the database config
+// points at. Stats recorded before the first connection (e.g. from framework
+// logs) belong to that connection. Caller must hold m.mu.
+func (m *Manager) switchQueryStats(config ConnectionConfig) {
+	key := connectionIdentity(config)
+	if config.Name != "" {
+		m.statsNames[config.Name] = key
+	}
+	if key == m.statsKey {
+		return
+	}
+
+	if m.statsKey != "" {
+		m.statsByConn[m.statsKey] = m.queryStats
+		if stats, ok := m.statsByConn[key]; ok {
+			m.queryStats = stats
+		} else {
+			m.queryStats = make(map[string]*QueryStatistic)
+		}
+
+		// Plans from another database don't describe this one
+		m.explainMu.Lock()
+		m.explainCache = make(map[string]*ExplainResult)
+		m.runnableOf = make(map[string]string)
+		m.baselines = make(map[string]*PlanBaseline)
+		m.explainMu.Unlock()
+	}
+	m.statsKey = key
+	m.statsByConn[key] = m.queryStats
+}
+
+// GetQueryStatisticsFor returns the statistics collected while connected to
+// another database this manager has used, looked up by connection name or
+// by driver://host:port/database
+func (m *Manager) GetQueryStatisticsFor(connection string) ([]QueryStatistic, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key := connection
+	if named, ok := m.statsNames[connection]; ok {
+		key = named
+	}
+	stats, ok := m.statsByConn[key]
+	if !ok {
+		return nil, fmt.Errorf("no query statistics for connection: %s", connection)
+	}
+
+	result := make([]QueryStatistic, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, *stat)
+	}
+	return result, nil
+}
+
 // GetQueryStatistics returns all collected query statistics
 func (m *Manager) GetQueryStatistics() []QueryStatistic {
 	m.mu.RLock()
@@ -307,12 +703,20 @@ func (m *Manager) GetQueryStatistics() []QueryStatistic {
 	return result
 }
 
-// ClearQueryStatistics clears all query statistics
+// ClearQueryStatistics clears the current connection's query statistics
 func (m *Manager) ClearQueryStatistics() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	m.queryStats = make(map[string]*QueryStatistic)
+	if m.statsKey != "" {
+		m.statsByConn[m.statsKey] = m.queryStats
+	}
+
+	m.explainMu.Lock()
+	m.explainCache = make(map[string]*ExplainResult)
+	m.runnableOf = make(map[string]string)
+	m.explainMu.Unlock()
 }
 
 // GetDatabaseHealth returns database health metrics
@@ -393,8 +797,9 @@ func calculateAvgQueryTime(stats map[string]*QueryStatistic) float64 {
 // normalizeQuery creates a fingerprint from a SQL query by removing literals
 func normalizeQuery(sql string) string {
 	// Simple normalization: trim whitespace and convert to uppercase for grouping
-	// A more sophisticated approach would replace literals with placeholders
-	normalized := strings.TrimSpace(sql)
+	// A more sophisticated approach would replace literals with placeholders.
+	// Rails' logged bind values vary per call, so they're left out.
+	normalized := strings.TrimSpace(railsBinds.ReplaceAllString(sql, ""))
 	normalized = strings.ToUpper(normalized)
 
 	// Remove multiple spaces
@@ -1,8 +1,10 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +16,7 @@ type MySQLDriver struct {
 	db       *sql.DB
 	config   ConnectionConfig
 	database string
+	tlsName  string // Registered custom TLS config, if any
 }
 
 // NewMySQLDriver creates a new MySQL driver
@@ -33,13 +36,72 @@ func (d *MySQLDriver) Connect(config ConnectionConfig) error {
 	)
 
 	// Add SSL mode if specified
-	if config.SSLMode != "" && config.SSLMode != "disable" {
-		dsn += "&tls=" + config.SSLMode
+	tlsParam, tlsName, err := mysqlTLSParam(config)
+	if err != nil {
+		return err
+	}
+	if tlsParam != "" {
+		dsn += "&tls=" + tlsParam
+	}
+
+	// Read-only sessions and the statement timeout are set up per pooled
+	// connection through the DSN, so every query is bounded, not just the
+	// ones run through the worker pool
+	if config.ReadOnly {
+		dsn += "&transaction_read_only=1"
+	}
+	if config.StatementTimeout > 0 {
+		dsn += fmt.Sprintf("&max_execution_time=%d", config.StatementTimeout)
+	}
+
+	db, err := openMySQL(dsn)
+	for i := 0; i < 2 && err != nil; i++ {
+		fallback, ok := mysqlVariableFallback(dsn, err, config)
+		if !ok {
+			break
+		}
+		dsn = fallback
+		db, err = openMySQL(dsn)
+	}
+	if err != nil {
+		deregisterTLS(tlsName)
+		return err
+	}
+
+	d.db = db
+	d.tlsName = tlsName
+	d.config = config
+	d.config.Password = "" // Kept in the pool's DSN only
+	d.database = config.Database
+
+	return nil
+}
+
+// mysqlVariableFallback rewrites a session variable the server rejected to
+// its older name. MySQL before 8.0 and MariaDB before 11.1 only know
+// tx_read_only, and MariaDB bounds statements with max_statement_time, in
+// seconds, instead of max_execution_time.
+func mysqlVariableFallback(dsn string, err error, config ConnectionConfig) (string, bool) {
+	msg := err.Error()
+	if !strings.Contains(msg, "Unknown system variable") {
+		return "", false
+	}
+
+	switch {
+	case config.ReadOnly && strings.Contains(msg, "transaction_read_only"):
+		return strings.Replace(dsn, "transaction_read_only", "tx_read_only", 1), true
+	case config.StatementTimeout > 0 && strings.Contains(msg, "max_execution_time"):
+		seconds := strconv.FormatFloat(float64(config.StatementTimeout)/1000, 'f', -1, 64)
+		return strings.Replace(dsn, fmt.Sprintf("max_execution_time=%d", config.StatementTimeout), "max_statement_time="+seconds, 1), true
 	}
+	return "", false
+}
 
+// openMySQL opens a connection pool and checks it can connect
+func openMySQL(dsn string) (*sql.DB, error) {
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
-		return fmt.Errorf("failed to open connection: %w", err)
+		return nil, fmt.Errorf("failed to open connection: %w", err)
 	}
 
 	// Set connection pool settings
@@ -50,14 +112,10 @@ func (d *MySQLDriver) Connect(config ConnectionConfig) error {
 	// Test connection
 	if err := db.Ping(); err != nil {
 		db.Close()
-		return fmt.Errorf("failed to connect: %w", err)
+		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
 
-	d.db = db
-	d.config = config
-	d.database = config.Database
-
-	return nil
+	return db, nil
 }
 
 // Disconnect closes the MySQL connection
@@ -65,6 +123,8 @@ func (d *MySQLDriver) Disconnect() error {
 	if d.db != nil {
 		err := d.db.Close()
 		d.db = nil
+		deregisterTLS(d.tlsName)
+		d.tlsName = ""
 		return err
 	}
 	return nil
@@ -79,7 +139,7 @@ func (d *MySQLDriver) Ping() error {
 }
 
 // GetTables returns all tables in the database
-func (d *MySQLDriver) GetTables() ([]TableInfo, error) {
+func (d *MySQLDriver) GetTables(ctx context.Context) ([]TableInfo, error) {
 	if d.db == nil {
 		return nil, fmt.Errorf("not connected")
 	}
@@ -96,7 +156,7 @@ func (d *MySQLDriver) GetTables() ([]TableInfo, error) {
 		ORDER BY TABLE_NAME
 	`
 
-	rows, err := d.db.Query(query, d.database)
+	rows, err := d.db.QueryContext(ctx, query, d.database)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tables: %w", err)
 	}
@@ -115,7 +175,7 @@ func (d *MySQLDriver) GetTables() ([]TableInfo, error) {
 }
 
 // GetColumns returns columns for a specific table
-func (d *MySQLDriver) GetColumns(tableName string) ([]ColumnInfo, error) {
+func (d *MySQLDriver) GetColumns(ctx context.Context, tableName string) ([]ColumnInfo, error) {
 	if d.db == nil {
 		return nil, fmt.Errorf("not connected")
 	}
@@ -134,7 +194,7 @@ func (d *MySQLDriver) GetColumns(tableName string) ([]ColumnInfo, error) {
 		ORDER BY c.ORDINAL_POSITION
 	`
 
-	rows, err := d.db.Query(query, d.database, tableName)
+	rows, err := d.db.QueryContext(ctx, query, d.database, tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get columns: %w", err)
 	}
@@ -152,6 +212,238 @@ func (d *MySQLDriver) GetColumns(tableName string) ([]ColumnInfo, error) {
 	return columns, nil
 }
 
+// functionalKeyPart stands in for an index key part on an expression
+const functionalKeyPart = "(expression)"
+
+// GetIndexes returns all indexes in the database, grouped by table
+func (d *MySQLDriver) GetIndexes(ctx context.Context) ([]IndexInfo, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	query := `
+		SELECT
+			TABLE_NAME,
+			INDEX_NAME,
+			NON_UNIQUE,
+			COLUMN_NAME
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = ?
+		ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX
+	`
+
+	rows, err := d.db.QueryContext(ctx, query, d.database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var table, name string
+		var nonUnique int
+		var columnName sql.NullString
+		if err := rows.Scan(&table, &name, &nonUnique, &columnName); err != nil {
+			return nil, fmt.Errorf("failed to scan index: %w", err)
+		}
+
+		// Functional key parts (MySQL 8) have no column. The placeholder keeps
+		// the index from matching a plain index on the other columns.
+		column := columnName.String
+		if !columnName.Valid {
+			column = functionalKeyPart
+		}
+
+		// Rows are ordered by index, so consecutive rows belong to the same index
+		if n := len(indexes); n > 0 && indexes[n-1].Table == table && indexes[n-1].Name == name {
+			indexes[n-1].Columns = append(indexes[n-1].Columns, column)
+			continue
+		}
+
+		indexes = append(indexes, IndexInfo{
+			Name:    name,
+			Table:   table,
+			Columns: []string{column},
+			Unique:  nonUnique == 0,
+		})
+	}
+
+	return indexes, nil
+}
+
+// GetForeignKeys returns the foreign keys defined on a table
+func (d *MySQLDriver) GetForeignKeys(ctx context.Context, tableName string) ([]ForeignKeyInfo, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	query := `
+		SELECT
+			k.CONSTRAINT_NAME,
+			k.COLUMN_NAME,
+			k.REFERENCED_TABLE_NAME,
+			k.REFERENCED_COLUMN_NAME,
+			r.UPDATE_RULE,
+			r.DELETE_RULE
+		FROM information_schema.KEY_COLUMN_USAGE k
+		JOIN information_schema.REFERENTIAL_CONSTRAINTS r
+			ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA
+			AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME
+			AND r.TABLE_NAME = k.TABLE_NAME
+		WHERE k.TABLE_SCHEMA = ? AND k.TABLE_NAME = ?
+			AND k.REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY k.CONSTRAINT_NAME, k.ORDINAL_POSITION
+	`
+
+	rows, err := d.db.QueryContext(ctx, query, d.database, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []ForeignKeyInfo
+	for rows.Next() {
+		var name, column, refTable, refColumn, onUpdate, onDelete string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			return nil, fmt.Errorf("failed to scan foreign key: %w", err)
+		}
+
+		// Rows are ordered by constraint, so composite keys arrive together
+		if n := len(keys); n > 0 && keys[n-1].Name == name {
+			keys[n-1].Columns = append(keys[n-1].Columns, column)
+			keys[n-1].RefColumns = append(keys[n-1].RefColumns, refColumn)
+			continue
+		}
+
+		keys = append(keys, ForeignKeyInfo{
+			Name:       name,
+			Table:      tableName,
+			Columns:    []string{column},
+			RefTable:   refTable,
+			RefColumns: []string{refColumn},
+			OnUpdate:   onUpdate,
+			OnDelete:   onDelete,
+		})
+	}
+
+	return keys, nil
+}
+
+// Column types profiled with min/max rather than value counts
+var rangeTypes = map[string]bool{
+	"tinyint": true, "smallint": true, "mediumint": true, "int": true, "bigint": true,
+	"decimal": true, "float": true, "double": true,
+	"date": true, "datetime": true, "timestamp": true, "time": true, "year": true,
+}
+
+// Column types that are never grouped by value; grouping long values sorts
+// them on disk
+var opaqueTypes = map[string]bool{
+	"blob": true, "tinyblob": true, "mediumblob": true, "longblob": true,
+	"text": true, "tinytext": true, "mediumtext": true, "longtext": true,
+	"binary": true, "varbinary": true, "geometry": true, "json": true,
+}
+
+// lowCardinalityLimit is the distinct count up to which a column gets top values
+const lowCardinalityLimit = 50
+
+// GetColumnStats profiles a column: null/distinct counts, min/max for numeric
+// and date columns, and top-N value counts for low-cardinality columns. Only
+// the first scanCap rows are examined so huge tables stay bounded.
+func (d *MySQLDriver) GetColumnStats(tableName, columnName string, topN, scanCap int) (*ColumnStats, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	// SECURITY: Identifiers can't be bound as parameters, so only accept a
+	// table/column that exists in the schema
+	columns, err := d.GetColumns(context.Background(), tableName)
+	if err != nil {
+		return nil, err
+	}
+	var column *ColumnInfo
+	for i := range columns {
+		if columns[i].Name == columnName {
+			column = &columns[i]
+			break
+		}
+	}
+	if column == nil {
+		return nil, fmt.Errorf("column not found: %s.%s", tableName, columnName)
+	}
+
+	dataType := strings.ToLower(column.DataType)
+	stats := &ColumnStats{
+		Table:    tableName,
+		Column:   columnName,
+		DataType: column.DataType,
+		Strategy: "values",
+	}
+	if rangeTypes[dataType] {
+		stats.Strategy = "range"
+	}
+
+	col := quoteIdentifier(columnName)
+	sample := fmt.Sprintf("(SELECT %s AS v FROM %s LIMIT %d) AS sample", col, quoteIdentifier(tableName), scanCap)
+
+	summary := fmt.Sprintf("SELECT COUNT(*), IFNULL(SUM(v IS NULL), 0), COUNT(DISTINCT v) FROM %s", sample)
+	if err := d.db.QueryRow(summary).Scan(&stats.RowsScanned, &stats.NullCount, &stats.DistinctCount); err != nil {
+		return nil, fmt.Errorf("failed to get column stats: %w", err)
+	}
+	stats.Sampled = stats.RowsScanned >= int64(scanCap)
+
+	if stats.Strategy == "range" {
+		var minValue, maxValue interface{}
+		if err := d.db.QueryRow(fmt.Sprintf("SELECT MIN(v), MAX(v) FROM %s", sample)).Scan(&minValue, &maxValue); err != nil {
+			return nil, fmt.Errorf("failed to get column range: %w", err)
+		}
+		stats.Min = readableValue(minValue)
+		stats.Max = readableValue(maxValue)
+	}
+
+	// Value counts are only meaningful for low-cardinality columns
+	if opaqueTypes[dataType] || stats.DistinctCount > lowCardinalityLimit {
+		return stats, nil
+	}
+
+	query := fmt.Sprintf("SELECT v, COUNT(*) AS c FROM %s GROUP BY v ORDER BY c DESC LIMIT %d", sample, topN)
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top values: %w", err)
+	}
+	defer rows.Close()
+
+	stats.TopValues = make([]ValueCount, 0, topN)
+	for rows.Next() {
+		var value interface{}
+		var count int64
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan top value: %w", err)
+		}
+		stats.TopValues = append(stats.TopValues, ValueCount{Value: readableValue(value), Count: count})
+	}
+
+	return stats, nil
+}
+
+// quoteIdentifier quotes a MySQL identifier with backticks
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// readableValue converts driver []byte values to strings
+func readableValue(value interface{}) interface{} {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return value
+}
+
+// LimitQuery appends LIMIT to a SELECT that doesn't already limit its rows
+func (d *MySQLDriver) LimitQuery(query string, limit int) string {
+	return ApplyRowLimit(query, limit, LimitClause)
+}
+
 // ExecuteQuery executes a SQL query and returns results
 func (d *MySQLDriver) ExecuteQuery(query string, limit int) (*QueryResult, error) {
 	if d.db == nil {
@@ -166,24 +458,20 @@ func (d *MySQLDriver) ExecuteQuery(query string, limit int) (*QueryResult, error
 
 	start := time.Now()
 
-	// Detect if this is a SELECT query
+	// Detect if this query returns rows. EXPLAIN does even for a write, and
+	// WITH only does when a SELECT follows its CTEs.
 	trimmedQuery := strings.TrimSpace(strings.ToUpper(query))
-	isSelect := strings.HasPrefix(trimmedQuery, "SELECT") ||
-		strings.HasPrefix(trimmedQuery, "SHOW") ||
-		strings.HasPrefix(trimmedQuery, "DESCRIBE") ||
-		strings.HasPrefix(trimmedQuery, "EXPLAIN")
+	isSelect := strings.HasPrefix(trimmedQuery, "EXPLAIN") || IsReadOnlyQuery(query)
 
 	if isSelect {
 		result.IsSelect = true
 
-		// Add LIMIT if not present and this is a SELECT
-		if strings.HasPrefix(trimmedQuery, "SELECT") && !strings.Contains(trimmedQuery, "LIMIT") {
-			query = query + fmt.Sprintf(" LIMIT %d", limit)
-		}
+		query = d.LimitQuery(query, limit)
 
 		rows, err := d.db.Query(query)
 		if err != nil {
 			result.Error = err.Error()
+			result.err = err
 			result.ExecutionTime = float64(time.Since(start).Microseconds()) / 1000
 			return result, nil
 		}
@@ -193,6 +481,7 @@ func (d *MySQLDriver) ExecuteQuery(query string, limit int) (*QueryResult, error
 		columns, err := rows.Columns()
 		if err != nil {
 			result.Error = err.Error()
+			result.err = err
 			result.ExecutionTime = float64(time.Since(start).Microseconds()) / 1000
 			return result, nil
 		}
@@ -233,12 +522,14 @@ func (d *MySQLDriver) ExecuteQuery(query string, limit int) (*QueryResult, error
 			result.Rows = append(result.Rows, row)
 		}
 
+		decodeJSONColumns(result)
 		result.RowCount = len(result.Rows)
 	} else {
 		// Execute non-SELECT query
 		res, err := d.db.Exec(query)
 		if err != nil {
 			result.Error = err.Error()
+			result.err = err
 			result.ExecutionTime = float64(time.Since(start).Microseconds()) / 1000
 			return result, nil
 		}
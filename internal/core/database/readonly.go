@@ -0,0 +1,129 @@
+package database
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrReadOnly is returned for writes on a read-only connection
+var ErrReadOnly = errors.New("connection is read-only: only SELECT and EXPLAIN queries are allowed")
+
+// Statements that only read
+var readStatements = map[string]bool{
+	"SELECT":   true,
+	"WITH":     true,
+	"SHOW":     true,
+	"DESCRIBE": true,
+	"DESC":     true,
+	"TABLE":    true,
+	"VALUES":   true,
+}
+
+// Statements EXPLAIN may wrap that change data; EXPLAIN ANALYZE runs them
+var writeStatements = map[string]bool{
+	"INSERT":  true,
+	"UPDATE":  true,
+	"DELETE":  true,
+	"REPLACE": true,
+	"MERGE":   true,
+}
+
+// IsReadOnlyQuery reports whether a query is a SELECT (or similar read) or
+// an EXPLAIN of one, as allowed on read-only connections. A WITH query is
+// classified by the statement after its CTEs, since MySQL allows WITH ...
+// UPDATE and WITH ... DELETE.
+func IsReadOnlyQuery(query string) bool {
+	tokens, err := tokenizeSQL(query)
+	if err != nil {
+		return false
+	}
+	code := make([]sqlToken, 0, len(tokens))
+	for _, tok := range tokens {
+		if tok.kind != tokenLineComment && tok.kind != tokenBlockComment {
+			code = append(code, tok)
+		}
+	}
+	if len(code) == 0 {
+		return false
+	}
+
+	i := 0
+	if keywordAt(code, 0) == "EXPLAIN" {
+		// Skip EXPLAIN's options to the statement it explains
+		for i = 1; i < len(code); i++ {
+			if word := keywordAt(code, i); readStatements[word] || writeStatements[word] {
+				break
+			}
+		}
+	}
+	// A parenthesized query, e.g. (SELECT ...) UNION (SELECT ...)
+	for i < len(code) && code[i].text == "(" {
+		i++
+	}
+	if keywordAt(code, i) == "WITH" {
+		i = skipCTEs(code, i)
+	}
+
+	word := keywordAt(code, i)
+	return readStatements[word] && word != "WITH"
+}
+
+// keywordAt returns the uppercased word at code[i], or "" if it isn't a word
+func keywordAt(code []sqlToken, i int) string {
+	if i >= len(code) || code[i].kind != tokenWord {
+		return ""
+	}
+	return strings.ToUpper(code[i].text)
+}
+
+// skipCTEs returns the index of the statement following the WITH clause at
+// code[i], or len(code) if the clause is malformed
+func skipCTEs(code []sqlToken, i int) int {
+	i++
+	if keywordAt(code, i) == "RECURSIVE" {
+		i++
+	}
+	for i < len(code) {
+		i++ // The CTE's name
+		if i < len(code) && code[i].text == "(" {
+			i = skipParens(code, i) // Its column list
+		}
+		if keywordAt(code, i) != "AS" || i+1 >= len(code) || code[i+1].text != "(" {
+			return len(code)
+		}
+		i = skipParens(code, i+1)
+		if i >= len(code) || code[i].text != "," {
+			return i
+		}
+		i++
+	}
+	return len(code)
+}
+
+// skipParens returns the index after the parenthesis matching code[i]
+func skipParens(code []sqlToken, i int) int {
+	depth := 0
+	for ; i < len(code); i++ {
+		if code[i].kind != tokenPunct {
+			continue
+		}
+		switch code[i].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(code)
+}
+
+// IsReadOnly reports whether the current connection is read-only
+func (m *Manager) IsReadOnly() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.config.ReadOnly
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// maxReportSlowQueries bounds the slow queries listed in a health report
+const maxReportSlowQueries = 20
+
+// healthReport is the data rendered into a health report
+type healthReport struct {
+	Health      *DatabaseHealth
+	Status      DatabaseStatus
+	Subtitle    string // Database, server and generation time
+	SlowQueries []SlowQuery
+}
+
+// reportFuncs are shared by the HTML and Markdown templates
+var reportFuncs = map[string]interface{}{
+	"ms":  func(v float64) string { return fmt.Sprintf("%.1f ms", v) },
+	"pct": func(v float64) string { return fmt.Sprintf("%.1f%%", v) },
+	"scoreClass": func(score int) string {
+		switch {
+		case score >= 80:
+			return "good"
+		case score >= 50:
+			return "fair"
+		default:
+			return "poor"
+		}
+	},
+	// mdcell makes a value safe inside a Markdown table cell
+	"mdcell": func(s string) string {
+		s = strings.ReplaceAll(s, "|", `\|`)
+		return strings.Join(strings.Fields(s), " ")
+	},
+}
+
+var htmlReportTemplate = htmltemplate.Must(htmltemplate.New("health").Funcs(reportFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Database Health Report{{with .Status.Database}} - {{.}}{{end}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; color: #1f2328; margin: 2rem auto; max-width: 960px; padding: 0 1rem; }
+h1 { margin-bottom: 0.25rem; }
+h2 { border-bottom: 1px solid #d0d7de; padding-bottom: 0.3rem; margin-top: 2rem; }
+.meta { color: #656d76; }
+.score { font-size: 3rem; font-weight: 600; }
+.good { color: #1a7f37; } .fair { color: #9a6700; } .poor { color: #cf222e; }
+.critical { color: #cf222e; font-weight: 600; } .warning { color: #9a6700; font-weight: 600; } .info { color: #0969da; }
+table { border-collapse: collapse; width: 100%; font-size: 0.9rem; }
+th, td { border: 1px solid #d0d7de; padding: 0.4rem 0.6rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+td.num { text-align: right; white-space: nowrap; }
+code { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 0.85rem; word-break: break-all; }
+</style>
+</head>
+<body>
+<h1>Database Health Report</h1>
+<p class="meta">{{.Subtitle}}</p>
+
+<div class="score {{scoreClass .Health.Score}}">{{.Health.Score}}/100</div>
+
+<h2>Overview</h2>
+<table>
+<tr><th>Connections</th><td class="num">{{.Health.Connections.Active}} active / {{.Health.Connections.Max}} max ({{pct .Health.Connections.Utilization}})</td></tr>
+<tr><th>Cache hit rate</th><td class="num">{{pct .Health.Performance.CacheHitRate}}</td></tr>
+<tr><th>Average query time</th><td class="num">{{ms .Health.Performance.AvgQueryTime}}</td></tr>
+<tr><th>Slow queries</th><td class="num">{{.Health.Performance.SlowQueryCount}}</td></tr>
+</table>
+
+<h2>Issues ({{len .Health.Issues}})</h2>
+{{if .Health.Issues}}<table>
+<tr><th>Severity</th><th>Issue</th><th>Table</th><th>Impact</th><th>Recommendation</th></tr>
+{{range .Health.Issues}}<tr><td class="{{.Severity}}">{{.Severity}}</td><td><strong>{{.Title}}</strong><br>{{.Description}}</td><td>{{.Table}}</td><td>{{.Impact}}</td><td>{{.Recommendation}}</td></tr>
+{{end}}</table>{{else}}<p>No issues detected.</p>{{end}}
+
+<h2>Slow Queries</h2>
+{{if .SlowQueries}}<table>
+<tr><th>Query</th><th>Time</th><th>Count</th></tr>
+{{range .SlowQueries}}<tr><td><code>{{.Query}}</code></td><td class="num">{{ms .Time}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>{{else}}<p>No slow queries recorded.</p>{{end}}
+
+<h2>Tables</h2>
+{{if .Health.TableStats}}<table>
+<tr><th>Table</th><th>Rows</th><th>Size</th><th>Indexes</th></tr>
+{{range .Health.TableStats}}<tr><td>{{.Name}}</td><td class="num">{{.Rows}}</td><td class="num">{{.SizeFormatted}}</td><td class="num">{{.IndexCount}}</td></tr>
+{{end}}</table>{{else}}<p>No table statistics available.</p>{{end}}
+</body>
+</html>
+`))
+
+var markdownReportTemplate = texttemplate.Must(texttemplate.New("health").Funcs(reportFuncs).Parse(`# Database Health Report
+
+{{.Subtitle}}
+
+**Health score: {{.Health.Score}}/100**
+
+## Overview
+
+| Metric | Value |
+|---|---|
+| Connections | {{.Health.Connections.Active}} active / {{.Health.Connections.Max}} max ({{pct .Health.Connections.Utilization}}) |
+| Cache hit rate | {{pct .Health.Performance.CacheHitRate}} |
+| Average query time | {{ms .Health.Performance.AvgQueryTime}} |
+| Slow queries | {{.Health.Performance.SlowQueryCount}} |
+
+## Issues ({{len .Health.Issues}})
+{{if .Health.Issues}}
+| Severity | Issue | Table | Impact | Recommendation |
+|---|---|---|---|---|
+{{range .Health.Issues}}| {{.Severity}} | **{{mdcell .Title}}** {{mdcell .Description}} | {{mdcell .Table}} | {{mdcell .Impact}} | {{mdcell .Recommendation}} |
+{{end}}{{else}}
+No issues detected.
+{{end}}
+## Slow Queries
+{{if .SlowQueries}}
+| Query | Time | Count |
+|---|---:|---:|
+{{range .SlowQueries}}| ` + "`{{mdcell .Query}}`" + ` | {{ms .Time}} | {{.Count}} |
+{{end}}{{else}}
+No slow queries recorded.
+{{end}}
+## Tables
+{{if .Health.TableStats}}
+| Table | Rows | Size | Indexes |
+|---|---:|---:|---:|
+{{range .Health.TableStats}}| {{mdcell .Name}} | {{.Rows}} | {{.SizeFormatted}} | {{.IndexCount}} |
+{{end}}{{else}}
+No table statistics available.
+{{end}}`))
+
+// RenderHealthReport renders a self-contained health report as "html" (inline
+// CSS, no external assets) or "markdown"
+func RenderHealthReport(health *DatabaseHealth, status DatabaseStatus, format string, generated time.Time) (string, error) {
+	if health == nil {
+		return "", fmt.Errorf("no health data to report")
+	}
+
+	parts := make([]string, 0, 3)
+	if status.Database != "" {
+		parts = append(parts, status.Database)
+	}
+	if server := strings.TrimSpace(status.Driver + " " + status.Version); server != "" {
+		parts = append(parts, server)
+	}
+	parts = append(parts, "Generated "+generated.Format("2006-01-02 15:04 MST"))
+
+	data := healthReport{
+		Health:      health,
+		Status:      status,
+		Subtitle:    strings.Join(parts, " · "),
+		SlowQueries: health.SlowQueries,
+	}
+	if len(data.SlowQueries) > maxReportSlowQueries {
+		data.SlowQueries = data.SlowQueries[:maxReportSlowQueries]
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "html":
+		err = htmlReportTemplate.Execute(&buf, data)
+	case "markdown", "md":
+		err = markdownReportTemplate.Execute(&buf, data)
+	default:
+		return "", fmt.Errorf("unsupported report format: %s (use html or markdown)", format)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to render health report: %w", err)
+	}
+
+	return buf.String(), nil
+}
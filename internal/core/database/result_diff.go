@@ -0,0 +1,158 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResultDiff is the row-level difference between two query results
+type ResultDiff struct {
+	// Columns are the columns compared (present in both results, sorted)
+	Columns []string `json:"columns"`
+
+	// ColumnsOnlyInA and ColumnsOnlyInB are columns returned by only one query
+	ColumnsOnlyInA []string `json:"columnsOnlyInA"`
+	ColumnsOnlyInB []string `json:"columnsOnlyInB"`
+
+	// OnlyInA and OnlyInB are rows returned by only one query (duplicates count)
+	OnlyInA []map[string]interface{} `json:"onlyInA"`
+	OnlyInB []map[string]interface{} `json:"onlyInB"`
+
+	// MatchCount is the number of rows present in both results
+	MatchCount int `json:"matchCount"`
+
+	RowCountA int `json:"rowCountA"`
+	RowCountB int `json:"rowCountB"`
+
+	// ExecutionTimeA and ExecutionTimeB are in milliseconds
+	ExecutionTimeA float64 `json:"executionTimeA"`
+	ExecutionTimeB float64 `json:"executionTimeB"`
+
+	// Identical is true when both queries returned the same columns and rows
+	Identical bool `json:"identical"`
+}
+
+// DiffQueryResults compares two results as multisets of rows, ignoring row
+// order and column order. NULLs only match NULLs, and numbers match their
+// string form ("1.50" equals 1.5) so driver type coercions don't show as diffs.
+func DiffQueryResults(a, b *QueryResult) *ResultDiff {
+	diff := &ResultDiff{
+		Columns:        make([]string, 0),
+		ColumnsOnlyInA: make([]string, 0),
+		ColumnsOnlyInB: make([]string, 0),
+		OnlyInA:        make([]map[string]interface{}, 0),
+		OnlyInB:        make([]map[string]interface{}, 0),
+		RowCountA:      len(a.Rows),
+		RowCountB:      len(b.Rows),
+		ExecutionTimeA: a.ExecutionTime,
+		ExecutionTimeB: b.ExecutionTime,
+	}
+
+	inB := make(map[string]bool, len(b.Columns))
+	for _, col := range b.Columns {
+		inB[col] = true
+	}
+	inA := make(map[string]bool, len(a.Columns))
+	for _, col := range a.Columns {
+		inA[col] = true
+		if inB[col] {
+			diff.Columns = append(diff.Columns, col)
+		} else {
+			diff.ColumnsOnlyInA = append(diff.ColumnsOnlyInA, col)
+		}
+	}
+	for _, col := range b.Columns {
+		if !inA[col] {
+			diff.ColumnsOnlyInB = append(diff.ColumnsOnlyInB, col)
+		}
+	}
+	sort.Strings(diff.Columns)
+
+	// Count rows of B by key, then consume them while walking A
+	remaining := make(map[string][]map[string]interface{}, len(b.Rows))
+	for _, row := range b.Rows {
+		key := rowKey(row, diff.Columns)
+		remaining[key] = append(remaining[key], row)
+	}
+
+	for _, row := range a.Rows {
+		key := rowKey(row, diff.Columns)
+		if matches := remaining[key]; len(matches) > 0 {
+			remaining[key] = matches[1:]
+			diff.MatchCount++
+			continue
+		}
+		diff.OnlyInA = append(diff.OnlyInA, row)
+	}
+
+	// Keep B's leftovers in their original order
+	for _, row := range b.Rows {
+		key := rowKey(row, diff.Columns)
+		if matches := remaining[key]; len(matches) > 0 {
+			remaining[key] = matches[1:]
+			diff.OnlyInB = append(diff.OnlyInB, row)
+		}
+	}
+
+	diff.Identical = len(diff.ColumnsOnlyInA) == 0 && len(diff.ColumnsOnlyInB) == 0 &&
+		len(diff.OnlyInA) == 0 && len(diff.OnlyInB) == 0
+	return diff
+}
+
+// rowKey builds a comparable key from a row's normalized column values
+func rowKey(row map[string]interface{}, columns []string) string {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = normalizeValue(row[col])
+	}
+	return strings.Join(parts, "\x1f")
+}
+
+// normalizeValue renders a value so equivalent driver representations compare equal
+func normalizeValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "\x00NULL" // Distinct from the string "NULL"
+	case []byte:
+		return normalizeValue(string(v))
+	case string:
+		if i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+			return strconv.FormatInt(i, 10)
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return normalizeFloat(f)
+		}
+		return v
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return normalizeFloat(v)
+	case float32:
+		return normalizeFloat(float64(v))
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// normalizeFloat renders integral floats like integers so 1.0 matches 1
+func normalizeFloat(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,435 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rowUpdateTimeout bounds a single-row change, including waiting for the row lock
+const rowUpdateTimeout = 30 * time.Second
+
+// Integer column types, whose values must be whole numbers
+var integerTypes = map[string]bool{
+	"tinyint": true, "smallint": true, "mediumint": true, "int": true, "bigint": true, "year": true,
+}
+
+// UpdateRow sets columns of the single row identified by its full primary key.
+// Values are coerced to the column types and bound as parameters. The row is
+// locked and counted in a transaction first, so the update is refused unless
+// exactly one row matches. It returns the number of rows changed, which is 0
+// when the new values equal the old ones.
+func (d *MySQLDriver) UpdateRow(tableName string, pk, changes map[string]interface{}) (int64, error) {
+	if d.db == nil {
+		return 0, fmt.Errorf("not connected")
+	}
+	if len(changes) == 0 {
+		return 0, fmt.Errorf("no changes to apply")
+	}
+
+	schema, err := d.tableSchema(tableName)
+	if err != nil {
+		return 0, err
+	}
+	condition, whereArgs, err := schema.keyCondition(pk)
+	if err != nil {
+		return 0, err
+	}
+	names, args, err := schema.columnValues(changes)
+	if err != nil {
+		return 0, err
+	}
+
+	set := make([]string, len(names))
+	for i, name := range names {
+		set[i] = quoteIdentifier(name) + " = ?"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rowUpdateTimeout)
+	defer cancel()
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := schema.lockSingleRow(ctx, tx, condition, whereArgs); err != nil {
+		return 0, err
+	}
+
+	update := fmt.Sprintf("UPDATE %s SET %s WHERE %s", quoteIdentifier(tableName), strings.Join(set, ", "), condition)
+	result, err := tx.ExecContext(ctx, update, append(args, whereArgs...)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update row: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to update row: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit update: %w", err)
+	}
+
+	return affected, nil
+}
+
+// InsertRow inserts a row and returns it as stored, including defaults and a
+// generated auto-increment key. Columns left out of values get their default;
+// a nil value inserts NULL. Tables without a primary key return the values
+// as given, since the new row can't be read back reliably.
+func (d *MySQLDriver) InsertRow(tableName string, values map[string]interface{}) (map[string]interface{}, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	schema, err := d.tableSchema(tableName)
+	if err != nil {
+		return nil, err
+	}
+	names, args, err := schema.columnValues(values)
+	if err != nil {
+		return nil, err
+	}
+
+	quoted := make([]string, len(names))
+	placeholders := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdentifier(name)
+		placeholders[i] = "?"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rowUpdateTimeout)
+	defer cancel()
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		quoteIdentifier(tableName), strings.Join(quoted, ", "), strings.Join(placeholders, ", "))
+	result, err := tx.ExecContext(ctx, insert, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert row: %w", err)
+	}
+
+	row := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		row[name] = value
+	}
+
+	if len(schema.keyColumns) > 0 {
+		pk := make(map[string]interface{}, len(schema.keyColumns))
+		for _, name := range schema.keyColumns {
+			pk[name] = values[name]
+		}
+		// A single key column left out was generated by AUTO_INCREMENT
+		if len(schema.keyColumns) == 1 && pk[schema.keyColumns[0]] == nil {
+			id, err := result.LastInsertId()
+			if err != nil || id == 0 {
+				return nil, fmt.Errorf("primary key %s was not provided or generated", schema.keyColumns[0])
+			}
+			pk[schema.keyColumns[0]] = strconv.FormatInt(id, 10)
+		}
+
+		condition, whereArgs, err := schema.keyCondition(pk)
+		if err != nil {
+			return nil, err
+		}
+		if row, err = readRow(ctx, tx, tableName, condition, whereArgs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit insert: %w", err)
+	}
+
+	return row, nil
+}
+
+// DeleteRow deletes the single row identified by its full primary key,
+// refusing unless exactly one row matches
+func (d *MySQLDriver) DeleteRow(tableName string, pk map[string]interface{}) (int64, error) {
+	if d.db == nil {
+		return 0, fmt.Errorf("not connected")
+	}
+
+	schema, err := d.tableSchema(tableName)
+	if err != nil {
+		return 0, err
+	}
+	condition, whereArgs, err := schema.keyCondition(pk)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rowUpdateTimeout)
+	defer cancel()
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := schema.lockSingleRow(ctx, tx, condition, whereArgs); err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s", quoteIdentifier(tableName), condition), whereArgs...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete row: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete row: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit delete: %w", err)
+	}
+
+	return affected, nil
+}
+
+// tableSchema is the column metadata row operations check their input against
+type tableSchema struct {
+	name       string
+	columns    map[string]ColumnInfo
+	keyColumns []string // Primary key columns, in table order
+}
+
+// tableSchema loads a table's columns. SECURITY: Identifiers can't be bound
+// as parameters, so row operations only accept columns listed here.
+func (d *MySQLDriver) tableSchema(tableName string) (*tableSchema, error) {
+	columns, err := d.GetColumns(context.Background(), tableName)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table not found: %s", tableName)
+	}
+
+	schema := &tableSchema{
+		name:    tableName,
+		columns: make(map[string]ColumnInfo, len(columns)),
+	}
+	for _, c := range columns {
+		schema.columns[c.Name] = c
+		if c.IsPrimaryKey {
+			schema.keyColumns = append(schema.keyColumns, c.Name)
+		}
+	}
+
+	return schema, nil
+}
+
+// keyCondition builds a WHERE clause matching the full primary key
+func (s *tableSchema) keyCondition(pk map[string]interface{}) (string, []interface{}, error) {
+	if len(s.keyColumns) == 0 {
+		return "", nil, fmt.Errorf("table %s has no primary key; rows can't be changed safely", s.name)
+	}
+	if len(pk) != len(s.keyColumns) {
+		return "", nil, fmt.Errorf("primary key of %s is (%s)", s.name, strings.Join(s.keyColumns, ", "))
+	}
+
+	where := make([]string, 0, len(s.keyColumns))
+	args := make([]interface{}, 0, len(s.keyColumns))
+	for _, name := range s.keyColumns {
+		raw, ok := pk[name]
+		if !ok {
+			return "", nil, fmt.Errorf("primary key of %s is (%s)", s.name, strings.Join(s.keyColumns, ", "))
+		}
+		value, err := coerceColumnValue(s.columns[name], raw)
+		if err != nil {
+			return "", nil, err
+		}
+		if value == nil {
+			return "", nil, fmt.Errorf("primary key column %s can't be NULL", name)
+		}
+		where = append(where, quoteIdentifier(name)+" = ?")
+		args = append(args, value)
+	}
+
+	return strings.Join(where, " AND "), args, nil
+}
+
+// columnValues checks and coerces column values, returning the column names
+// sorted so statements are stable for the same input
+func (s *tableSchema) columnValues(values map[string]interface{}) ([]string, []interface{}, error) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if _, ok := s.columns[name]; !ok {
+			return nil, nil, fmt.Errorf("column not found: %s.%s", s.name, name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		value, err := coerceColumnValue(s.columns[name], values[name])
+		if err != nil {
+			return nil, nil, err
+		}
+		args[i] = value
+	}
+
+	return names, args, nil
+}
+
+// lockSingleRow locks the rows matching condition and fails unless there is
+// exactly one
+func (s *tableSchema) lockSingleRow(ctx context.Context, tx *sql.Tx, condition string, args []interface{}) error {
+	var matched int64
+	count := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s FOR UPDATE", quoteIdentifier(s.name), condition)
+	if err := tx.QueryRowContext(ctx, count, args...).Scan(&matched); err != nil {
+		return fmt.Errorf("failed to find row: %w", err)
+	}
+
+	switch {
+	case matched == 0:
+		return fmt.Errorf("row not found in %s", s.name)
+	case matched > 1:
+		return fmt.Errorf("operation would change %d rows in %s; refusing", matched, s.name)
+	}
+	return nil
+}
+
+// readRow reads the row matching condition as a column-to-value map
+func readRow(ctx context.Context, tx *sql.Tx, tableName, condition string, args []interface{}) (map[string]interface{}, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s WHERE %s", quoteIdentifier(tableName), condition), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read row: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read row: %w", err)
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read row: %w", err)
+		}
+		return nil, fmt.Errorf("row not found in %s", tableName)
+	}
+
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+	if err := rows.Scan(valuePtrs...); err != nil {
+		return nil, fmt.Errorf("failed to read row: %w", err)
+	}
+
+	row := make(map[string]interface{}, len(columns))
+	for i, col := range columns {
+		row[col] = readableValue(values[i])
+	}
+	return row, nil
+}
+
+// coerceColumnValue converts a value decoded from JSON (string, float64,
+// bool, nil, or a map/slice for JSON columns) to one suitable for binding to
+// the column, rejecting values the column can't hold
+func coerceColumnValue(column ColumnInfo, value interface{}) (interface{}, error) {
+	if value == nil {
+		if !column.IsNullable {
+			return nil, fmt.Errorf("column %s can't be NULL", column.Name)
+		}
+		return nil, nil
+	}
+
+	dataType := strings.ToLower(column.DataType)
+	invalid := func() (interface{}, error) {
+		return nil, fmt.Errorf("invalid value for %s column %s: %v", column.DataType, column.Name, value)
+	}
+
+	switch {
+	case integerTypes[dataType]:
+		switch v := value.(type) {
+		case float64:
+			if v != math.Trunc(v) || math.IsInf(v, 0) {
+				return invalid()
+			}
+			return int64(v), nil
+		case bool:
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case string:
+			n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				// Unsigned BIGINT values can exceed int64
+				u, uerr := strconv.ParseUint(strings.TrimSpace(v), 10, 64)
+				if uerr != nil {
+					return invalid()
+				}
+				return u, nil
+			}
+			return n, nil
+		}
+
+	case dataType == "decimal" || dataType == "float" || dataType == "double":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			// Kept as text so DECIMAL values don't lose precision
+			if _, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+				return invalid()
+			}
+			return strings.TrimSpace(v), nil
+		}
+
+	case dataType == "json":
+		switch v := value.(type) {
+		case string:
+			if !json.Valid([]byte(v)) {
+				return invalid()
+			}
+			return v, nil
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return invalid()
+			}
+			return string(encoded), nil
+		}
+
+	case opaqueTypes[dataType]:
+		if v, ok := value.(string); ok {
+			return []byte(v), nil
+		}
+
+	default:
+		// Text, enum/set and date/time columns take their string form;
+		// MySQL validates dates and enum members itself
+		switch v := value.(type) {
+		case string:
+			if column.MaxLength > 0 && int64(len([]rune(v))) > column.MaxLength {
+				return nil, fmt.Errorf("value for %s is longer than %d characters", column.Name, column.MaxLength)
+			}
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+	}
+
+	return invalid()
+}
@@ -0,0 +1,262 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxCachedExplains bounds the EXPLAIN results kept for GetRunnableQuery
+const maxCachedExplains = 200
+
+// railsBinds matches a bind list of [name, value] pairs at the end of a
+// logged query, after whitespace, e.g. [["id", 1], ["LIMIT", 1]]. Group 1 is
+// the list; it must open with "[[", so a query ending in a plain bracket
+// isn't taken for one.
+var railsBinds = regexp.MustCompile(`\s+(\[\[.*\]\])\s*$`)
+
+// RunnableQuery is a concrete example of a query fingerprint, with logged
+// bind values filled in so it can be pasted into the editor and run
+type RunnableQuery struct {
+	Fingerprint string `json:"fingerprint"`
+	SQL         string `json:"sql"`
+
+	// Source is which sample was used: "slowest" or "latest"
+	Source string `json:"source"`
+
+	// Time is the sample's execution time in milliseconds, when known
+	Time float64 `json:"time,omitempty"`
+
+	// Binds are the values substituted for placeholders
+	Binds []interface{} `json:"binds,omitempty"`
+
+	// Runnable is false when placeholders remain that no bind was logged for
+	Runnable bool `json:"runnable"`
+
+	// Explain is the last EXPLAIN of this fingerprint, if one was run
+	Explain *ExplainResult `json:"explain,omitempty"`
+}
+
+// RunnableQuery returns the best concrete example of a fingerprint from the
+// statistics: the slowest sample, which is the one worth explaining
+func (m *Manager) RunnableQuery(fingerprint string) (*RunnableQuery, error) {
+	m.mu.RLock()
+	stat, exists := m.queryStats[fingerprint]
+	var sample, source string
+	var sampleTime float64
+	if exists {
+		sample, source, sampleTime = stat.SlowestSQL, "slowest", stat.MaxTime
+		if sample == "" {
+			sample, source, sampleTime = stat.SQL, "latest", 0
+		}
+	}
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("no statistics for query: %s", fingerprint)
+	}
+
+	sql, binds := InlineBinds(sample)
+	result := &RunnableQuery{
+		Fingerprint: fingerprint,
+		SQL:         sql,
+		Source:      source,
+		Time:        sampleTime,
+		Binds:       binds,
+		Runnable:    !hasPlaceholders(sql),
+	}
+
+	m.explainMu.Lock()
+	result.Explain = m.explainCache[fingerprint]
+	if len(m.runnableOf) >= maxCachedExplains {
+		m.runnableOf = make(map[string]string)
+	}
+	m.runnableOf[exactQueryKey(sql)] = fingerprint
+	m.explainMu.Unlock()
+
+	return result, nil
+}
+
+// cacheExplain keeps an EXPLAIN result under the query's fingerprint and,
+// when the query is an example from RunnableQuery, under the fingerprint of
+// the statistic it came from: with binds inlined it fingerprints differently.
+func (m *Manager) cacheExplain(query string, result *ExplainResult) {
+	m.explainMu.Lock()
+	defer m.explainMu.Unlock()
+
+	m.storeExplain(normalizeQuery(query), result)
+	if fingerprint, ok := m.runnableOf[exactQueryKey(query)]; ok {
+		m.storeExplain(fingerprint, result)
+	}
+}
+
+// CachedExplains returns the latest EXPLAIN of each query fingerprint that
+// has been explained
+func (m *Manager) CachedExplains() map[string]*ExplainResult {
+	m.explainMu.Lock()
+	defer m.explainMu.Unlock()
+
+	explains := make(map[string]*ExplainResult, len(m.explainCache))
+	for fingerprint, result := range m.explainCache {
+		explains[fingerprint] = result
+	}
+	return explains
+}
+
+// storeExplain caches result under fingerprint; explainMu must be held
+func (m *Manager) storeExplain(fingerprint string, result *ExplainResult) {
+	if _, exists := m.explainCache[fingerprint]; !exists && len(m.explainCache) >= maxCachedExplains {
+		// Drop an arbitrary entry; the cache is a convenience, not a record
+		for key := range m.explainCache {
+			delete(m.explainCache, key)
+			break
+		}
+	}
+	m.explainCache[fingerprint] = result
+}
+
+// InlineBinds strips the bind list Rails logs after a query and substitutes
+// its values for the $1 or ? placeholders. A query without a readable bind
+// list is returned unchanged.
+func InlineBinds(sql string) (string, []interface{}) {
+	match := railsBinds.FindStringSubmatchIndex(sql)
+	if match == nil {
+		return sql, nil
+	}
+
+	binds, ok := parseRailsBinds(sql[match[2]:match[3]])
+	if !ok {
+		return sql, nil
+	}
+	query := strings.TrimSpace(sql[:match[0]])
+
+	var b strings.Builder
+	next := 0 // For positional ? placeholders
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			end := i + 1
+			for end < len(query) && isDigit(query[end]) {
+				end++
+			}
+			n, _ := strconv.Atoi(query[i+1 : end])
+			if n >= 1 && n <= len(binds) {
+				b.WriteString(sqlLiteral(binds[n-1]))
+				i = end - 1
+				continue
+			}
+		case c == '?' && next < len(binds):
+			b.WriteString(sqlLiteral(binds[next]))
+			next++
+			continue
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String(), binds
+}
+
+// parseRailsBinds reads the values of a logged bind list. It is Ruby inspect
+// output, which is JSON once nil is spelled null; each bind is a [name,
+// value] pair, or a lone value in newer Rails.
+func parseRailsBinds(list string) ([]interface{}, bool) {
+	var raw []interface{}
+	if err := json.Unmarshal([]byte(rubyNilToNull(list)), &raw); err != nil {
+		return nil, false
+	}
+
+	binds := make([]interface{}, 0, len(raw))
+	for _, bind := range raw {
+		if pair, ok := bind.([]interface{}); ok && len(pair) == 2 {
+			binds = append(binds, pair[1])
+		} else {
+			binds = append(binds, bind)
+		}
+	}
+	return binds, true
+}
+
+// rubyNilToNull replaces nil outside string literals with null
+func rubyNilToNull(s string) string {
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inString && c == '\\' && i+1 < len(s):
+			b.WriteByte(c)
+			i++
+			c = s[i]
+		case c == '"':
+			inString = !inString
+		case !inString && strings.HasPrefix(s[i:], "nil"):
+			b.WriteString("null")
+			i += 2
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// sqlLiteral renders a bind value as a MySQL literal
+func sqlLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return quoteMySQLString(v)
+	}
+	// Arrays and hashes (e.g. IN lists bound as one value) as their JSON text
+	data, _ := json.Marshal(value)
+	return quoteMySQLString(string(data))
+}
+
+// quoteMySQLString quotes a MySQL string literal. Backslash is an escape
+// character under the default sql_mode, so it's doubled too.
+func quoteMySQLString(s string) string {
+	return quoteString(strings.ReplaceAll(s, `\`, `\\`))
+}
+
+// hasPlaceholders reports whether a query has $1 or ? placeholders outside
+// string literals
+func hasPlaceholders(query string) bool {
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			return true
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			return true
+		}
+	}
+	return false
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,243 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaSnapshot is the structure of a database at a point in time
+type SchemaSnapshot struct {
+	// Database is the database name the snapshot was taken from
+	Database string `json:"database"`
+
+	// Tables maps table name to its columns
+	Tables map[string][]ColumnInfo `json:"tables"`
+
+	// Indexes maps table name to its indexes
+	Indexes map[string][]IndexInfo `json:"indexes"`
+}
+
+// SchemaDiff is the structural difference between two schemas (A -> B)
+type SchemaDiff struct {
+	// SourceA and SourceB identify the compared connections
+	SourceA string `json:"sourceA"`
+	SourceB string `json:"sourceB"`
+
+	// TablesOnlyInA are tables present in A but missing from B
+	TablesOnlyInA []string `json:"tablesOnlyInA"`
+
+	// TablesOnlyInB are tables present in B but missing from A
+	TablesOnlyInB []string `json:"tablesOnlyInB"`
+
+	// Tables holds column/index differences for tables present in both
+	Tables []TableDiff `json:"tables"`
+
+	// Identical is true when no differences were found
+	Identical bool `json:"identical"`
+}
+
+// TableDiff describes the differences for a table present in both schemas
+type TableDiff struct {
+	Table string `json:"table"`
+
+	// ColumnsAdded exist in B but not A; ColumnsRemoved exist in A but not B
+	ColumnsAdded   []ColumnInfo   `json:"columnsAdded,omitempty"`
+	ColumnsRemoved []ColumnInfo   `json:"columnsRemoved,omitempty"`
+	ColumnsChanged []ColumnChange `json:"columnsChanged,omitempty"`
+
+	// IndexesAdded exist in B but not A; IndexesRemoved exist in A but not B
+	IndexesAdded   []IndexInfo   `json:"indexesAdded,omitempty"`
+	IndexesRemoved []IndexInfo   `json:"indexesRemoved,omitempty"`
+	IndexesChanged []IndexChange `json:"indexesChanged,omitempty"`
+}
+
+// ColumnChange is a column whose definition differs between schemas
+type ColumnChange struct {
+	Name    string     `json:"name"`
+	A       ColumnInfo `json:"a"`
+	B       ColumnInfo `json:"b"`
+	Changes []string   `json:"changes"` // e.g. "type: varchar(255) -> text"
+}
+
+// IndexChange is an index with the same name but a different definition
+type IndexChange struct {
+	Name string    `json:"name"`
+	A    IndexInfo `json:"a"`
+	B    IndexInfo `json:"b"`
+}
+
+// GetSchemaSnapshot reads all tables, columns and indexes of the connected
+// database. Cancelling ctx abandons the queries in flight.
+func (m *Manager) GetSchemaSnapshot(ctx context.Context) (*SchemaSnapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.connected || m.driver == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	tables, err := m.driver.GetTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &SchemaSnapshot{
+		Database: m.config.Database,
+		Tables:   make(map[string][]ColumnInfo, len(tables)),
+		Indexes:  make(map[string][]IndexInfo),
+	}
+
+	for _, table := range tables {
+		columns, err := m.driver.GetColumns(ctx, table.Name)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Tables[table.Name] = columns
+	}
+
+	indexes, err := m.driver.GetIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		snapshot.Indexes[index.Table] = append(snapshot.Indexes[index.Table], index)
+	}
+
+	return snapshot, nil
+}
+
+// DiffSchemas compares two schema snapshots and returns what differs from A to B
+func DiffSchemas(a, b *SchemaSnapshot) *SchemaDiff {
+	diff := &SchemaDiff{
+		TablesOnlyInA: make([]string, 0),
+		TablesOnlyInB: make([]string, 0),
+		Tables:        make([]TableDiff, 0),
+	}
+
+	for _, table := range sortedKeys(a.Tables) {
+		if _, exists := b.Tables[table]; !exists {
+			diff.TablesOnlyInA = append(diff.TablesOnlyInA, table)
+			continue
+		}
+
+		tableDiff := TableDiff{Table: table}
+		diffColumns(&tableDiff, a.Tables[table], b.Tables[table])
+		diffIndexes(&tableDiff, a.Indexes[table], b.Indexes[table])
+
+		if len(tableDiff.ColumnsAdded)+len(tableDiff.ColumnsRemoved)+len(tableDiff.ColumnsChanged)+
+			len(tableDiff.IndexesAdded)+len(tableDiff.IndexesRemoved)+len(tableDiff.IndexesChanged) > 0 {
+			diff.Tables = append(diff.Tables, tableDiff)
+		}
+	}
+
+	for _, table := range sortedKeys(b.Tables) {
+		if _, exists := a.Tables[table]; !exists {
+			diff.TablesOnlyInB = append(diff.TablesOnlyInB, table)
+		}
+	}
+
+	diff.Identical = len(diff.TablesOnlyInA) == 0 && len(diff.TablesOnlyInB) == 0 && len(diff.Tables) == 0
+	return diff
+}
+
+// diffColumns records added, removed and changed columns of a table
+func diffColumns(tableDiff *TableDiff, a, b []ColumnInfo) {
+	bByName := make(map[string]ColumnInfo, len(b))
+	for _, col := range b {
+		bByName[col.Name] = col
+	}
+	aByName := make(map[string]ColumnInfo, len(a))
+	for _, col := range a {
+		aByName[col.Name] = col
+	}
+
+	for _, colA := range a {
+		colB, exists := bByName[colA.Name]
+		if !exists {
+			tableDiff.ColumnsRemoved = append(tableDiff.ColumnsRemoved, colA)
+			continue
+		}
+
+		changes := make([]string, 0)
+		if !strings.EqualFold(colA.DataType, colB.DataType) || colA.MaxLength != colB.MaxLength {
+			changes = append(changes, fmt.Sprintf("type: %s -> %s", columnType(colA), columnType(colB)))
+		}
+		if colA.IsNullable != colB.IsNullable {
+			changes = append(changes, fmt.Sprintf("nullable: %t -> %t", colA.IsNullable, colB.IsNullable))
+		}
+		if colA.Default != colB.Default {
+			changes = append(changes, fmt.Sprintf("default: %q -> %q", colA.Default, colB.Default))
+		}
+		if colA.IsPrimaryKey != colB.IsPrimaryKey {
+			changes = append(changes, fmt.Sprintf("primary key: %t -> %t", colA.IsPrimaryKey, colB.IsPrimaryKey))
+		}
+
+		if len(changes) > 0 {
+			tableDiff.ColumnsChanged = append(tableDiff.ColumnsChanged, ColumnChange{
+				Name:    colA.Name,
+				A:       colA,
+				B:       colB,
+				Changes: changes,
+			})
+		}
+	}
+
+	for _, colB := range b {
+		if _, exists := aByName[colB.Name]; !exists {
+			tableDiff.ColumnsAdded = append(tableDiff.ColumnsAdded, colB)
+		}
+	}
+}
+
+// diffIndexes records added, removed and redefined indexes of a table
+func diffIndexes(tableDiff *TableDiff, a, b []IndexInfo) {
+	bByName := make(map[string]IndexInfo, len(b))
+	for _, idx := range b {
+		bByName[idx.Name] = idx
+	}
+	aByName := make(map[string]IndexInfo, len(a))
+	for _, idx := range a {
+		aByName[idx.Name] = idx
+	}
+
+	for _, idxA := range a {
+		idxB, exists := bByName[idxA.Name]
+		if !exists {
+			tableDiff.IndexesRemoved = append(tableDiff.IndexesRemoved, idxA)
+			continue
+		}
+		if idxA.Unique != idxB.Unique || strings.Join(idxA.Columns, ",") != strings.Join(idxB.Columns, ",") {
+			tableDiff.IndexesChanged = append(tableDiff.IndexesChanged, IndexChange{
+				Name: idxA.Name,
+				A:    idxA,
+				B:    idxB,
+			})
+		}
+	}
+
+	for _, idxB := range b {
+		if _, exists := aByName[idxB.Name]; !exists {
+			tableDiff.IndexesAdded = append(tableDiff.IndexesAdded, idxB)
+		}
+	}
+}
+
+// columnType renders a column type with its length (varchar(255))
+func columnType(col ColumnInfo) string {
+	if col.MaxLength > 0 {
+		return fmt.Sprintf("%s(%d)", col.DataType, col.MaxLength)
+	}
+	return col.DataType
+}
+
+// sortedKeys returns the table names of a snapshot in stable order
+func sortedKeys(tables map[string][]ColumnInfo) []string {
+	keys := make([]string, 0, len(tables))
+	for key := range tables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
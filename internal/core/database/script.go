@@ -0,0 +1,336 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ScriptStatement is one statement of a SQL script
+type ScriptStatement struct {
+	SQL  string `json:"sql"`
+	Line int    `json:"line"` // 1-based line the statement starts on
+}
+
+// ScriptStatementResult is the outcome of one script statement
+type ScriptStatementResult struct {
+	ScriptStatement
+
+	AffectedRows  int64   `json:"affectedRows"`
+	RowCount      int     `json:"rowCount"`      // Rows returned by a read
+	ExecutionTime float64 `json:"executionTime"` // Milliseconds
+	Error         string  `json:"error,omitempty"`
+}
+
+// ScriptResult is the outcome of running a SQL script
+type ScriptResult struct {
+	// Statements are the results of the statements that ran, in order
+	Statements []ScriptStatementResult `json:"statements"`
+
+	// Total is how many statements the script has; with stopOnError the
+	// ones after a failure didn't run
+	Total  int `json:"total"`
+	Failed int `json:"failed"`
+
+	// Committed is set when the transaction was committed, and false when it
+	// was rolled back after a failure
+	Committed bool `json:"committed"`
+
+	ExecutionTime float64 `json:"executionTime"` // Milliseconds
+}
+
+// SplitScript splits a SQL script into statements. Semicolons inside
+// quoted strings, quoted identifiers and comments don't end a statement, and
+// MySQL client DELIMITER lines change the terminator, so stored procedure
+// bodies stay whole. Statements with only comments are dropped.
+func SplitScript(script string) ([]ScriptStatement, error) {
+	runes := []rune(script)
+	delimiter := []rune(";")
+
+	var statements []ScriptStatement
+	var current strings.Builder
+	line, startLine := 1, 1
+	hasCode := false
+
+	flush := func() {
+		if hasCode {
+			statements = append(statements, ScriptStatement{
+				SQL:  strings.TrimSpace(current.String()),
+				Line: startLine,
+			})
+		}
+		current.Reset()
+		hasCode = false
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		// DELIMITER is a client command, only recognized at the start of a
+		// statement
+		if !hasCode && atLineStart(runes, i) && hasWordPrefix(runes[i:], "DELIMITER") {
+			end := i
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+			fields := strings.Fields(string(runes[i:end]))
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: DELIMITER needs exactly one terminator", line)
+			}
+			delimiter = []rune(fields[1])
+			current.Reset()
+			i = end
+			continue
+		}
+
+		switch {
+		case r == '\n':
+			line++
+			current.WriteRune(r)
+			i++
+
+		case unicode.IsSpace(r):
+			current.WriteRune(r)
+			i++
+
+		case r == '\'' || r == '"' || r == '`':
+			quoteLine := line
+			end := i + 1
+			for {
+				if end >= len(runes) {
+					return nil, fmt.Errorf("line %d: unterminated quoted string", quoteLine)
+				}
+				if runes[end] == '\\' && r != '`' {
+					end += 2
+					continue
+				}
+				if runes[end] == '\n' {
+					line++
+				}
+				if runes[end] == r {
+					break
+				}
+				end++
+			}
+			if !hasCode {
+				hasCode, startLine = true, quoteLine
+			}
+			current.WriteString(string(runes[i : end+1]))
+			i = end + 1
+
+		case r == '#' || (r == '-' && i+1 < len(runes) && runes[i+1] == '-'):
+			end := i
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+			current.WriteString(string(runes[i:end]))
+			i = end
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			commentLine := line
+			end := i + 2
+			for end+1 < len(runes) && !(runes[end] == '*' && runes[end+1] == '/') {
+				if runes[end] == '\n' {
+					line++
+				}
+				end++
+			}
+			if end+1 >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated comment", commentLine)
+			}
+			current.WriteString(string(runes[i : end+2]))
+			i = end + 2
+
+		case hasRunePrefix(runes[i:], delimiter):
+			flush()
+			i += len(delimiter)
+
+		default:
+			if !hasCode {
+				hasCode, startLine = true, line
+			}
+			current.WriteRune(r)
+			i++
+		}
+	}
+	flush()
+
+	return statements, nil
+}
+
+// atLineStart reports whether only blanks precede position i on its line
+func atLineStart(runes []rune, i int) bool {
+	for j := i - 1; j >= 0 && runes[j] != '\n'; j-- {
+		if runes[j] != ' ' && runes[j] != '\t' && runes[j] != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// hasWordPrefix reports whether runes start with word, case-insensitively,
+// followed by a blank
+func hasWordPrefix(runes []rune, word string) bool {
+	w := []rune(word)
+	if len(runes) <= len(w) || !unicode.IsSpace(runes[len(w)]) {
+		return false
+	}
+	return strings.EqualFold(string(runes[:len(w)]), word)
+}
+
+// hasRunePrefix reports whether runes start with prefix
+func hasRunePrefix(runes, prefix []rune) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+	for i, r := range prefix {
+		if runes[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// RunScript runs statements in order in one transaction. With stopOnError
+// the first failure rolls the transaction back and the rest are skipped;
+// otherwise failures are recorded and the statements that succeeded are
+// committed. MySQL commits implicitly on DDL, so a script that creates or
+// alters tables can't be fully rolled back. Scripts that manage their own
+// transaction (BEGIN, START TRANSACTION, COMMIT, ROLLBACK) are refused,
+// since a COMMIT partway through would defeat the rollback.
+func (m *Manager) RunScript(ctx context.Context, statements []ScriptStatement, stopOnError bool) (*ScriptResult, error) {
+	m.mu.RLock()
+	connected := m.connected
+	driver := m.driver
+	m.mu.RUnlock()
+
+	if !connected || driver == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if m.IsReadOnly() {
+		for _, stmt := range statements {
+			if !IsReadOnlyQuery(stmt.SQL) {
+				return nil, ErrReadOnly
+			}
+		}
+	}
+
+	for _, stmt := range statements {
+		if word := transactionControl(stmt.SQL); word != "" {
+			return nil, fmt.Errorf("line %d: %s is not allowed, the script already runs in a transaction", stmt.Line, word)
+		}
+	}
+
+	db := driver.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	start := time.Now()
+	result := &ScriptResult{
+		Statements: make([]ScriptStatementResult, 0, len(statements)),
+		Total:      len(statements),
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	for _, stmt := range statements {
+		stmtStart := time.Now()
+		res := ScriptStatementResult{ScriptStatement: stmt}
+
+		if IsReadOnlyQuery(stmt.SQL) {
+			res.RowCount, err = countRows(ctx, tx, stmt.SQL)
+		} else {
+			var execResult sql.Result
+			execResult, err = tx.ExecContext(ctx, stmt.SQL)
+			if err == nil {
+				res.AffectedRows, _ = execResult.RowsAffected()
+			}
+		}
+		res.ExecutionTime = float64(time.Since(stmtStart).Microseconds()) / 1000
+
+		if err != nil {
+			res.Error = err.Error()
+			result.Failed++
+			result.Statements = append(result.Statements, res)
+			if stopOnError || ctx.Err() != nil {
+				tx.Rollback()
+				result.ExecutionTime = float64(time.Since(start).Microseconds()) / 1000
+				return result, nil
+			}
+			continue
+		}
+
+		m.RecordQueryExecution(stmt.SQL, res.ExecutionTime)
+		result.Statements = append(result.Statements, res)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	result.Committed = true
+	result.ExecutionTime = float64(time.Since(start).Microseconds()) / 1000
+
+	return result, nil
+}
+
+// transactionControl returns the statement's keyword if it starts, commits
+// or rolls back a transaction, or "" otherwise. ROLLBACK TO SAVEPOINT stays
+// inside the transaction, so it's allowed.
+func transactionControl(query string) string {
+	tokens, err := tokenizeSQL(query)
+	if err != nil {
+		return ""
+	}
+	var words []string
+	for _, tok := range tokens {
+		if tok.kind == tokenWord {
+			words = append(words, strings.ToUpper(tok.text))
+		} else if tok.kind != tokenLineComment && tok.kind != tokenBlockComment {
+			break
+		}
+		if len(words) == 3 {
+			break
+		}
+	}
+	if len(words) == 0 {
+		return ""
+	}
+
+	switch words[0] {
+	case "BEGIN", "COMMIT":
+		return words[0]
+	case "START":
+		if len(words) > 1 && words[1] == "TRANSACTION" {
+			return "START TRANSACTION"
+		}
+	case "ROLLBACK":
+		if slices.Contains(words[1:], "TO") {
+			return ""
+		}
+		return words[0]
+	}
+	return ""
+}
+
+// countRows runs a read in tx and counts the rows it returns
+func countRows(ctx context.Context, tx *sql.Tx, query string) (int, error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		n++
+	}
+	return n, rows.Err()
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// tlsConfigCounter makes the names of registered MySQL TLS configs unique
+var tlsConfigCounter uint64
+
+// hasTLSFiles reports whether any certificate file is configured
+func (c ConnectionConfig) hasTLSFiles() bool {
+	return c.SSLRootCert != "" || c.SSLCert != "" || c.SSLKey != ""
+}
+
+// buildTLSConfig builds a tls.Config for the connection's SSL mode and
+// certificate files:
+//   - require: encrypt; the server certificate is only checked when a root CA is given
+//   - verify-ca: the server certificate must chain to the root CA (or system roots)
+//   - verify-full: as verify-ca, and the certificate must match the host name
+func buildTLSConfig(config ConnectionConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: config.Host,
+	}
+
+	var roots *x509.CertPool
+	if config.SSLRootCert != "" {
+		pem, err := os.ReadFile(config.SSLRootCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SSL root certificate: %w", err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in SSL root certificate %s", config.SSLRootCert)
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	if (config.SSLCert == "") != (config.SSLKey == "") {
+		return nil, fmt.Errorf("SSL client certificate and key must be set together")
+	}
+	if config.SSLCert != "" {
+		cert, err := tls.LoadX509KeyPair(config.SSLCert, config.SSLKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SSL client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	switch config.SSLMode {
+	case "verify-full":
+		// Default verification: chain and host name
+	case "verify-ca":
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyChain(roots)
+	default: // preferred, require
+		tlsConfig.InsecureSkipVerify = true
+		if roots != nil {
+			tlsConfig.VerifyPeerCertificate = verifyChain(roots)
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// verifyChain checks the server certificate chains to roots (system roots if
+// nil) without checking the host name. Used with InsecureSkipVerify, which
+// disables Go's built-in verification entirely.
+func verifyChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("server sent no certificate")
+		}
+
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return fmt.Errorf("invalid server certificate: %w", err)
+			}
+			certs[i] = cert
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		return err
+	}
+}
+
+// mysqlTLSParam returns the DSN tls value for a connection. When a custom
+// config is needed it is registered with the driver and its name returned so
+// it can be deregistered on disconnect.
+func mysqlTLSParam(config ConnectionConfig) (param, registered string, err error) {
+	switch config.SSLMode {
+	case "", "disable":
+		return "", "", nil
+	case "preferred":
+		if !config.hasTLSFiles() {
+			return "preferred", "", nil
+		}
+	case "require":
+		if !config.hasTLSFiles() {
+			return "skip-verify", "", nil
+		}
+	}
+
+	tlsConfig, err := buildTLSConfig(config)
+	if err != nil {
+		return "", "", err
+	}
+
+	name := fmt.Sprintf("caboose-%d", atomic.AddUint64(&tlsConfigCounter, 1))
+	if err := mysql.RegisterTLSConfig(name, tlsConfig); err != nil {
+		return "", "", fmt.Errorf("failed to register TLS config: %w", err)
+	}
+
+	return name, name, nil
+}
+
+// deregisterTLS removes a custom TLS config registered by mysqlTLSParam
+func deregisterTLS(name string) {
+	if name != "" {
+		mysql.DeregisterTLSConfig(name)
+	}
+}
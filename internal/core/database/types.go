@@ -23,8 +23,25 @@ type ConnectionConfig struct {
 	// SSLMode is the SSL mode (disable, require, verify-ca, verify-full)
 	SSLMode string `json:"sslMode,omitempty" toml:"ssl_mode,omitempty"`
 
+	// SSLRootCert is a PEM file of CA certificates to verify the server against
+	SSLRootCert string `json:"sslRootCert,omitempty" toml:"ssl_root_cert,omitempty"`
+
+	// SSLCert and SSLKey are the PEM client certificate and key, for servers
+	// that require client authentication
+	SSLCert string `json:"sslCert,omitempty" toml:"ssl_cert,omitempty"`
+	SSLKey  string `json:"sslKey,omitempty" toml:"ssl_key,omitempty"`
+
 	// Name is a friendly name for this connection
 	Name string `json:"name,omitempty" toml:"name,omitempty"`
+
+	// ReadOnly opens a read-only session and rejects anything but SELECT and
+	// EXPLAIN queries, for safely investigating production
+	ReadOnly bool `json:"readOnly,omitempty" toml:"read_only,omitempty"`
+
+	// StatementTimeout in milliseconds stops any statement still running on
+	// the server after that long, whatever the app-side timeout (0 = none).
+	// MySQL only bounds SELECT statements this way.
+	StatementTimeout int `json:"statementTimeout,omitempty" toml:"statement_timeout_ms,omitempty"`
 }
 
 // TableInfo represents information about a database table
@@ -69,6 +86,79 @@ type ColumnInfo struct {
 	MaxLength int64 `json:"maxLength,omitempty"`
 }
 
+// IndexInfo represents an index on a table
+type IndexInfo struct {
+	// Name is the index name
+	Name string `json:"name"`
+
+	// Table is the indexed table
+	Table string `json:"table"`
+
+	// Columns are the indexed columns, in index order; a key part on an
+	// expression is "(expression)"
+	Columns []string `json:"columns"`
+
+	// Unique indicates a unique index
+	Unique bool `json:"unique"`
+}
+
+// ForeignKeyInfo is a foreign-key constraint from one table to another
+type ForeignKeyInfo struct {
+	// Name is the constraint name
+	Name string `json:"name"`
+
+	// Table and Columns are the referencing side, in key order
+	Table   string   `json:"table"`
+	Columns []string `json:"columns"`
+
+	// RefTable and RefColumns are the referenced side, matching Columns
+	RefTable   string   `json:"refTable"`
+	RefColumns []string `json:"refColumns"`
+
+	// OnUpdate and OnDelete are the referential actions (CASCADE, RESTRICT, ...)
+	OnUpdate string `json:"onUpdate,omitempty"`
+	OnDelete string `json:"onDelete,omitempty"`
+}
+
+// ColumnStats summarizes the values of a single column
+type ColumnStats struct {
+	// Table and Column identify the column
+	Table  string `json:"table"`
+	Column string `json:"column"`
+
+	// DataType is the column data type
+	DataType string `json:"dataType"`
+
+	// Strategy is how the column was profiled: "range" (numeric/date) or "values"
+	Strategy string `json:"strategy"`
+
+	// RowsScanned is the number of rows examined
+	RowsScanned int64 `json:"rowsScanned"`
+
+	// Sampled is true when the scan cap was hit and stats cover only a sample
+	Sampled bool `json:"sampled"`
+
+	// NullCount is the number of NULL values
+	NullCount int64 `json:"nullCount"`
+
+	// DistinctCount is the number of distinct non-NULL values
+	DistinctCount int64 `json:"distinctCount"`
+
+	// Min and Max are set for range columns
+	Min interface{} `json:"min,omitempty"`
+	Max interface{} `json:"max,omitempty"`
+
+	// TopValues are the most frequent values, for columns with at most 50
+	// distinct values that aren't TEXT, BLOB or JSON
+	TopValues []ValueCount `json:"topValues,omitempty"`
+}
+
+// ValueCount is a column value and how often it occurs
+type ValueCount struct {
+	Value interface{} `json:"value"`
+	Count int64       `json:"count"`
+}
+
 // QueryResult represents the result of a SQL query
 type QueryResult struct {
 	// Columns are the column names
@@ -77,6 +167,11 @@ type QueryResult struct {
 	// ColumnTypes are the column data types
 	ColumnTypes []string `json:"columnTypes"`
 
+	// ColumnHints say how to render each column: "json" for columns whose
+	// values were decoded into nested objects and arrays, empty otherwise.
+	// Omitted when no column needs a hint.
+	ColumnHints []string `json:"columnHints,omitempty"`
+
 	// Rows are the result rows (each row is a map of column name to value)
 	Rows []map[string]interface{} `json:"rows"`
 
@@ -94,6 +189,9 @@ type QueryResult struct {
 
 	// IsSelect indicates if this was a SELECT query
 	IsSelect bool `json:"isSelect"`
+
+	// err is the driver error behind Error
+	err error
 }
 
 // ExplainResult represents the result of an EXPLAIN query
@@ -194,9 +292,15 @@ type DatabaseStatus struct {
 	// Host is the connected host
 	Host string `json:"host,omitempty"`
 
+	// Name is the friendly connection name
+	Name string `json:"name,omitempty"`
+
 	// Version is the database server version
 	Version string `json:"version,omitempty"`
 
+	// ReadOnly indicates the connection only allows reads
+	ReadOnly bool `json:"readOnly"`
+
 	// Error is any connection error
 	Error string `json:"error,omitempty"`
 }
@@ -212,6 +316,11 @@ type QueryStatistic struct {
 	// SQL is a sample of the actual SQL query
 	SQL string `json:"sql"`
 
+	// SlowestSQL is the sample that took longest, and MaxTime its time in
+	// milliseconds
+	SlowestSQL string  `json:"slowestSql,omitempty"`
+	MaxTime    float64 `json:"maxTime"`
+
 	// Count is the number of times this query was executed
 	Count int `json:"count"`
 
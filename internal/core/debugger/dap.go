@@ -20,8 +20,9 @@ type Client struct {
 	mu     sync.Mutex
 
 	seq         int64
-	pending     map[int]chan *dap.Response
+	pending     map[int]chan dap.ResponseMessage
 	pendingMu   sync.Mutex
+	closed      bool // Reader exited; guarded by pendingMu
 	initialized bool
 
 	// Event callbacks
@@ -29,12 +30,15 @@ type Client struct {
 	OnOutput     func(event *dap.OutputEvent)
 	OnTerminated func(event *dap.TerminatedEvent)
 	OnBreakpoint func(event *dap.BreakpointEvent)
+
+	onClose func() // Set with SetOnClose; guarded by closeMu
+	closeMu sync.Mutex
 }
 
 // NewClient creates a new DAP client
 func NewClient() *Client {
 	return &Client{
-		pending: make(map[int]chan *dap.Response),
+		pending: make(map[int]chan dap.ResponseMessage),
 	}
 }
 
@@ -63,6 +67,15 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// SetOnClose sets the callback run, at most once, when the connection to the
+// adapter is lost or closed. Set it to nil before closing the client to
+// close it quietly; that is safe while the reader is running.
+func (c *Client) SetOnClose(fn func()) {
+	c.closeMu.Lock()
+	c.onClose = fn
+	c.closeMu.Unlock()
+}
+
 // Initialize sends the initialize request to the debug adapter
 func (c *Client) Initialize() (*dap.InitializeResponse, error) {
 	req := &dap.InitializeRequest{
@@ -121,6 +134,17 @@ func (c *Client) Attach(args map[string]interface{}) error {
 	return err
 }
 
+// ConfigurationDone tells the adapter that breakpoints are set and the
+// debuggee may run
+func (c *Client) ConfigurationDone() error {
+	req := &dap.ConfigurationDoneRequest{
+		Request: c.newRequest("configurationDone"),
+	}
+
+	_, err := c.sendRequest(req)
+	return err
+}
+
 // SetBreakpoints sets breakpoints in a source file
 func (c *Client) SetBreakpoints(source string, lines []int) (*dap.SetBreakpointsResponse, error) {
 	breakpoints := make([]dap.SourceBreakpoint, len(lines))
@@ -319,67 +343,37 @@ func (c *Client) newRequest(command string) dap.Request {
 }
 
 // sendRequest sends a request and waits for the response
-func (c *Client) sendRequest(req dap.Message) (dap.Message, error) {
-	c.mu.Lock()
+func (c *Client) sendRequest(req dap.RequestMessage) (dap.ResponseMessage, error) {
+	seqNum := req.GetRequest().Seq
+
+	// Register before sending so a fast response can't arrive unclaimed
+	respChan := make(chan dap.ResponseMessage, 1)
+	c.pendingMu.Lock()
+	if c.closed {
+		c.pendingMu.Unlock()
+		return nil, fmt.Errorf("debug adapter connection closed")
+	}
+	c.pending[seqNum] = respChan
+	c.pendingMu.Unlock()
+
+	defer func() {
+		c.pendingMu.Lock()
+		delete(c.pending, seqNum)
+		c.pendingMu.Unlock()
+	}()
 
 	// Encode and send
+	c.mu.Lock()
 	err := dap.WriteProtocolMessage(c.writer, req)
+	c.mu.Unlock()
 	if err != nil {
-		c.mu.Unlock()
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 
-	c.mu.Unlock()
-
 	// Wait for response
-	respChan := make(chan *dap.Response, 1)
-
-	seqNum := 0
-	switch r := req.(type) {
-	case *dap.InitializeRequest:
-		seqNum = r.Seq
-	case *dap.LaunchRequest:
-		seqNum = r.Seq
-	case *dap.AttachRequest:
-		seqNum = r.Seq
-	case *dap.SetBreakpointsRequest:
-		seqNum = r.Seq
-	case *dap.ContinueRequest:
-		seqNum = r.Seq
-	case *dap.NextRequest:
-		seqNum = r.Seq
-	case *dap.StepInRequest:
-		seqNum = r.Seq
-	case *dap.StepOutRequest:
-		seqNum = r.Seq
-	case *dap.PauseRequest:
-		seqNum = r.Seq
-	case *dap.ThreadsRequest:
-		seqNum = r.Seq
-	case *dap.StackTraceRequest:
-		seqNum = r.Seq
-	case *dap.ScopesRequest:
-		seqNum = r.Seq
-	case *dap.VariablesRequest:
-		seqNum = r.Seq
-	case *dap.EvaluateRequest:
-		seqNum = r.Seq
-	case *dap.DisconnectRequest:
-		seqNum = r.Seq
-	}
-
-	c.pendingMu.Lock()
-	c.pending[seqNum] = respChan
-	c.pendingMu.Unlock()
-
 	resp := <-respChan
-
-	c.pendingMu.Lock()
-	delete(c.pending, seqNum)
-	c.pendingMu.Unlock()
-
-	if !resp.Success {
-		return nil, fmt.Errorf("request failed: %s", resp.Message)
+	if !resp.GetResponse().Success {
+		return nil, fmt.Errorf("request failed: %s", resp.GetResponse().Message)
 	}
 
 	return resp, nil
@@ -393,6 +387,15 @@ func (c *Client) readMessages() {
 			if err != io.EOF {
 				// Log error
 			}
+			c.failPending("debug adapter connection closed")
+
+			c.closeMu.Lock()
+			onClose := c.onClose
+			c.onClose = nil
+			c.closeMu.Unlock()
+			if onClose != nil {
+				onClose()
+			}
 			return
 		}
 
@@ -400,12 +403,27 @@ func (c *Client) readMessages() {
 	}
 }
 
+// failPending unblocks requests still waiting for a response
+func (c *Client) failPending(message string) {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	c.closed = true
+	for _, ch := range c.pending {
+		select {
+		case ch <- &dap.Response{Success: false, Message: message}:
+		default:
+		}
+	}
+}
+
 // handleMessage dispatches a received message
 func (c *Client) handleMessage(msg dap.Message) {
 	switch m := msg.(type) {
-	case *dap.Response:
+	case dap.ResponseMessage:
+		// Responses are decoded into typed structs (InitializeResponse, ...)
 		c.pendingMu.Lock()
-		if ch, ok := c.pending[m.RequestSeq]; ok {
+		if ch, ok := c.pending[m.GetResponse().RequestSeq]; ok {
 			ch <- m
 		}
 		c.pendingMu.Unlock()
@@ -0,0 +1,375 @@
+package debugger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/go-dap"
+	"github.com/google/uuid"
+)
+
+// Session is one debug adapter connection and the state seen through it
+type Session struct {
+	ID        string
+	Name      string
+	Address   string
+	Process   string // Managed process the session was launched in, if any
+	StartedAt time.Time
+	Client    *Client
+
+	mu    sync.Mutex
+	state DebugState
+}
+
+// SessionInfo describes a session for listing
+type SessionInfo struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	Process   string    `json:"process,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	Running   bool      `json:"running"`
+	ThreadId  int       `json:"threadId"`
+}
+
+// SessionManager holds the open debug sessions and forwards each client's
+// adapter events, tagged with the session's ID
+type SessionManager struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+
+	// OnEvent receives adapter events; data always has a "sessionId" key
+	OnEvent func(event string, data map[string]interface{})
+}
+
+// NewSessionManager creates an empty session manager
+func NewSessionManager() *SessionManager {
+	return &SessionManager{
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Attach connects to the debug adapter at address, attaches to the running
+// program (initialize, attach, configurationDone) and registers the session
+func (m *SessionManager) Attach(name, address, process string) (*Session, error) {
+	s := &Session{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Address:   address,
+		Process:   process,
+		StartedAt: time.Now(),
+		Client:    NewClient(),
+		state: DebugState{
+			Connected:   true,
+			Running:     true,
+			Variables:   make(map[int][]dap.Variable),
+			Breakpoints: make(map[string][]int),
+		},
+	}
+	m.wire(s)
+
+	client := s.Client
+	if err := client.Connect(address); err != nil {
+		return nil, err
+	}
+
+	// Nothing is registered yet, so a failed handshake mustn't report a
+	// disconnect for a session the frontend never saw
+	detach := func() {
+		client.SetOnClose(nil)
+		client.Close()
+	}
+	if _, err := client.Initialize(); err != nil {
+		detach()
+		return nil, fmt.Errorf("debugger initialize failed: %w", err)
+	}
+	if err := client.Attach(map[string]interface{}{"request": "attach"}); err != nil {
+		detach()
+		return nil, fmt.Errorf("debugger attach failed: %w", err)
+	}
+	if err := client.ConfigurationDone(); err != nil {
+		detach()
+		return nil, fmt.Errorf("debugger configuration failed: %w", err)
+	}
+
+	m.mu.Lock()
+	m.sessions[s.ID] = s
+	m.mu.Unlock()
+
+	m.send("debug:connected", s, map[string]interface{}{
+		"name":    s.Name,
+		"address": s.Address,
+	})
+
+	return s, nil
+}
+
+// wire routes the client's adapter events to the session's state and OnEvent
+func (m *SessionManager) wire(s *Session) {
+	s.Client.OnStopped = func(event *dap.StoppedEvent) {
+		s.mu.Lock()
+		s.state.Running = false
+		s.state.ThreadId = event.Body.ThreadId
+		s.mu.Unlock()
+
+		m.send("debug:stopped", s, map[string]interface{}{
+			"reason":      event.Body.Reason,
+			"threadId":    event.Body.ThreadId,
+			"description": event.Body.Description,
+		})
+	}
+	s.Client.OnOutput = func(event *dap.OutputEvent) {
+		m.send("debug:output", s, map[string]interface{}{
+			"category": event.Body.Category,
+			"output":   event.Body.Output,
+		})
+	}
+	s.Client.OnBreakpoint = func(event *dap.BreakpointEvent) {
+		m.send("debug:breakpoint", s, map[string]interface{}{
+			"reason":     event.Body.Reason,
+			"breakpoint": event.Body.Breakpoint,
+		})
+	}
+	s.Client.OnTerminated = func(event *dap.TerminatedEvent) {
+		m.send("debug:terminated", s, nil)
+	}
+	s.Client.SetOnClose(func() {
+		s.mu.Lock()
+		s.state.Connected = false
+		s.state.Running = false
+		s.mu.Unlock()
+
+		m.mu.Lock()
+		delete(m.sessions, s.ID)
+		m.mu.Unlock()
+
+		m.send("debug:disconnected", s, nil)
+	})
+}
+
+// send emits an event with the session's ID added to data
+func (m *SessionManager) send(event string, s *Session, data map[string]interface{}) {
+	if m.OnEvent == nil {
+		return
+	}
+	if data == nil {
+		data = make(map[string]interface{}, 1)
+	}
+	data["sessionId"] = s.ID
+	m.OnEvent(event, data)
+}
+
+// Get returns the session with the given ID
+func (m *SessionManager) Get(id string) (*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("debug session not found: %s", id)
+	}
+	return s, nil
+}
+
+// FindByProcess returns the session launched in the named process, if any
+func (m *SessionManager) FindByProcess(process string) *Session {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, s := range m.sessions {
+		if s.Process == process {
+			return s
+		}
+	}
+	return nil
+}
+
+// List returns the open sessions, oldest first
+func (m *SessionManager) List() []SessionInfo {
+	m.mu.RLock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+	})
+
+	result := make([]SessionInfo, 0, len(sessions))
+	for _, s := range sessions {
+		s.mu.Lock()
+		result = append(result, SessionInfo{
+			ID:        s.ID,
+			Name:      s.Name,
+			Address:   s.Address,
+			Process:   s.Process,
+			StartedAt: s.StartedAt,
+			Running:   s.state.Running,
+			ThreadId:  s.state.ThreadId,
+		})
+		s.mu.Unlock()
+	}
+	return result
+}
+
+// Detach disconnects a session, leaving its program running
+func (m *SessionManager) Detach(id string) error {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	delete(m.sessions, id)
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("debug session not found: %s", id)
+	}
+	return m.close(s)
+}
+
+// CloseAll disconnects every session
+func (m *SessionManager) CloseAll() {
+	m.mu.Lock()
+	sessions := m.sessions
+	m.sessions = make(map[string]*Session)
+	m.mu.Unlock()
+
+	for _, s := range sessions {
+		m.close(s)
+	}
+}
+
+func (m *SessionManager) close(s *Session) error {
+	s.Client.SetOnClose(nil)
+	s.Client.Disconnect(false)
+	err := s.Client.Close()
+
+	s.mu.Lock()
+	s.state.Connected = false
+	s.state.Running = false
+	s.mu.Unlock()
+
+	m.send("debug:disconnected", s, nil)
+	return err
+}
+
+// State returns a copy of the session's debug state
+func (s *Session) State() DebugState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.state
+	state.StackFrames = append([]dap.StackFrame(nil), s.state.StackFrames...)
+	state.Variables = make(map[int][]dap.Variable, len(s.state.Variables))
+	for ref, vars := range s.state.Variables {
+		state.Variables[ref] = vars
+	}
+	state.Breakpoints = make(map[string][]int, len(s.state.Breakpoints))
+	for file, lines := range s.state.Breakpoints {
+		state.Breakpoints[file] = lines
+	}
+	return state
+}
+
+// resumed marks the program running before a resume request is sent, so a
+// stopped event arriving ahead of the response isn't overwritten. Frames
+// and variables are stale until it stops again.
+func (s *Session) resumed() {
+	s.mu.Lock()
+	s.state.Running = true
+	s.state.StackFrames = nil
+	s.state.Variables = make(map[int][]dap.Variable)
+	s.mu.Unlock()
+}
+
+// Continue resumes the thread
+func (s *Session) Continue(threadId int) error {
+	s.resumed()
+	return s.Client.Continue(threadId)
+}
+
+// Next steps the thread over to the next line
+func (s *Session) Next(threadId int) error {
+	s.resumed()
+	return s.Client.Next(threadId)
+}
+
+// StepIn steps the thread into the next call
+func (s *Session) StepIn(threadId int) error {
+	s.resumed()
+	return s.Client.StepIn(threadId)
+}
+
+// StepOut steps the thread out of the current frame
+func (s *Session) StepOut(threadId int) error {
+	s.resumed()
+	return s.Client.StepOut(threadId)
+}
+
+// Pause pauses the thread; the stopped event updates the state
+func (s *Session) Pause(threadId int) error {
+	return s.Client.Pause(threadId)
+}
+
+// StackTrace fetches the thread's stack frames and keeps them in the state
+func (s *Session) StackTrace(threadId int) ([]dap.StackFrame, error) {
+	resp, err := s.Client.GetStackTrace(threadId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.state.ThreadId = threadId
+	s.state.StackFrames = resp.Body.StackFrames
+	s.mu.Unlock()
+
+	return resp.Body.StackFrames, nil
+}
+
+// Variables fetches the variables under a reference and keeps them in the state
+func (s *Session) Variables(variablesRef int) ([]dap.Variable, error) {
+	resp, err := s.Client.GetVariables(variablesRef)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.state.Variables[variablesRef] = resp.Body.Variables
+	s.mu.Unlock()
+
+	return resp.Body.Variables, nil
+}
+
+// SetBreakpoints replaces the breakpoints in a source file
+func (s *Session) SetBreakpoints(source string, lines []int) (*dap.SetBreakpointsResponse, error) {
+	resp, err := s.Client.SetBreakpoints(source, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if len(lines) == 0 {
+		delete(s.state.Breakpoints, source)
+	} else {
+		s.state.Breakpoints[source] = append([]int(nil), lines...)
+	}
+	s.mu.Unlock()
+
+	return resp, nil
+}
+
+// Evaluate evaluates an expression in a stack frame
+func (s *Session) Evaluate(expression string, frameId int) (*dap.EvaluateResponse, error) {
+	return s.Client.Evaluate(expression, frameId)
+}
+
+// Scopes fetches the variable scopes of a stack frame
+func (s *Session) Scopes(frameId int) ([]dap.Scope, error) {
+	resp, err := s.Client.GetScopes(frameId)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body.Scopes, nil
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is a frontend event recorded in the journal
+type Event struct {
+	Seq       int64       `json:"seq"` // Increases by one per recorded event
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// Journal keeps the most recent events in a ring buffer so a frontend that
+// mounts or reconnects late can replay state changes it missed
+type Journal struct {
+	mu       sync.RWMutex
+	buffer   []Event
+	capacity int
+	head     int
+	count    int
+	seq      int64
+}
+
+// NewJournal creates a journal holding up to capacity events
+func NewJournal(capacity int) *Journal {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+
+	return &Journal{
+		buffer:   make([]Event, capacity),
+		capacity: capacity,
+	}
+}
+
+// Record adds an event, evicting the oldest once the journal is full
+func (j *Journal) Record(eventType string, data interface{}) Event {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.seq++
+	event := Event{
+		Seq:       j.seq,
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+
+	j.buffer[j.head] = event
+	j.head = (j.head + 1) % j.capacity
+	if j.count < j.capacity {
+		j.count++
+	}
+
+	return event
+}
+
+// Since returns events recorded after since, oldest first. A zero since
+// returns everything still held, and a non-empty types limits the result to
+// those event types.
+func (j *Journal) Since(since time.Time, types []string) []Event {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	var wanted map[string]bool
+	if len(types) > 0 {
+		wanted = make(map[string]bool, len(types))
+		for _, t := range types {
+			wanted[t] = true
+		}
+	}
+
+	result := []Event{}
+	start := (j.head - j.count + j.capacity) % j.capacity
+	for i := 0; i < j.count; i++ {
+		event := j.buffer[(start+i)%j.capacity]
+		if !event.Timestamp.After(since) {
+			continue
+		}
+		if wanted != nil && !wanted[event.Type] {
+			continue
+		}
+		result = append(result, event)
+	}
+
+	return result
+}
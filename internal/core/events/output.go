@@ -0,0 +1,197 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// throttleWindow is how long a Throttle's per-key budget lasts
+const throttleWindow = time.Second
+
+// Throttle caps how many units (events, lines) are let through per key each
+// second. What's over the cap is dropped and counted, and once the second is
+// over onSuppressed is called with the count so it can be summarized.
+type Throttle struct {
+	limit        int
+	onSuppressed func(key string, count int)
+
+	mu      sync.Mutex
+	windows map[string]*window
+}
+
+type window struct {
+	start      time.Time
+	count      int
+	suppressed int
+	reporting  bool // A report of suppressed is scheduled
+}
+
+// NewThrottle creates a throttle letting through limit units per key per second
+func NewThrottle(limit int, onSuppressed func(key string, count int)) *Throttle {
+	return &Throttle{
+		limit:        limit,
+		onSuppressed: onSuppressed,
+		windows:      make(map[string]*window),
+	}
+}
+
+// Allow reports whether one more unit for key fits in this second's budget
+func (t *Throttle) Allow(key string) bool {
+	return t.AllowN(key, 1)
+}
+
+// AllowN reports whether n units for key fit in this second's budget. The
+// first units of a second always pass, so a single large batch isn't lost.
+func (t *Throttle) AllowN(key string, n int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	w := t.windows[key]
+	if w == nil || now.Sub(w.start) >= throttleWindow {
+		w = &window{start: now}
+		t.windows[key] = w
+	}
+
+	if w.count == 0 || w.count+n <= t.limit {
+		w.count += n
+		return true
+	}
+
+	w.suppressed += n
+	if !w.reporting {
+		w.reporting = true
+		time.AfterFunc(w.start.Add(throttleWindow).Sub(now), func() { t.report(key, w) })
+	}
+	return false
+}
+
+func (t *Throttle) report(key string, w *window) {
+	t.mu.Lock()
+	count := w.suppressed
+	w.suppressed = 0
+	w.reporting = false
+	t.mu.Unlock()
+
+	if count > 0 && t.onSuppressed != nil {
+		t.onSuppressed(key, count)
+	}
+}
+
+// OutputBatcher coalesces raw output written in small chunks into batches
+// emitted at most once per interval per key, or sooner once a batch holds
+// many lines or bytes. Batches over the per-second line budget are dropped
+// and replaced by a "lines suppressed" note.
+type OutputBatcher struct {
+	interval time.Duration
+	emit     func(key, data string)
+	throttle *Throttle
+
+	mu      sync.Mutex
+	pending map[string]*batch
+}
+
+type batch struct {
+	buf   strings.Builder
+	lines int
+	timer *time.Timer
+}
+
+const (
+	// maxBatchLines flushes a batch early once it holds this many lines
+	maxBatchLines = 500
+
+	// maxBatchBytes flushes a batch early once it holds this many bytes
+	maxBatchBytes = 64 * 1024
+)
+
+// NewOutputBatcher creates a batcher flushing every interval and letting
+// through up to maxLinesPerSecond lines per key
+func NewOutputBatcher(interval time.Duration, maxLinesPerSecond int, emit func(key, data string)) *OutputBatcher {
+	b := &OutputBatcher{
+		interval: interval,
+		emit:     emit,
+		pending:  make(map[string]*batch),
+	}
+	b.throttle = NewThrottle(maxLinesPerSecond, func(key string, count int) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		// Send any held-back partial line (usually a prompt) after the note
+		note := "\r\n" + SuppressedNote(count) + "\r\n"
+		if p := b.pending[key]; p != nil && p.timer == nil {
+			note += p.buf.String()
+			p.buf.Reset()
+			p.lines = 0
+		}
+		b.emit(key, note)
+	})
+	return b
+}
+
+// Write adds output for key to its pending batch
+func (b *OutputBatcher) Write(key, data string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := b.pending[key]
+	if p == nil {
+		p = &batch{}
+		b.pending[key] = p
+	}
+	p.buf.WriteString(data)
+	p.lines += strings.Count(data, "\n")
+
+	if p.lines >= maxBatchLines || p.buf.Len() >= maxBatchBytes {
+		b.flushLocked(key, p)
+		return
+	}
+	if p.timer == nil {
+		p.timer = time.AfterFunc(b.interval, func() { b.Flush(key) })
+	}
+}
+
+// Flush emits the pending batch for key now
+func (b *OutputBatcher) Flush(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if p := b.pending[key]; p != nil {
+		b.flushLocked(key, p)
+	}
+}
+
+func (b *OutputBatcher) flushLocked(key string, p *batch) {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	if p.buf.Len() == 0 {
+		return
+	}
+
+	data, lines := p.buf.String(), p.lines
+	p.buf.Reset()
+	p.lines = 0
+
+	// Emitting under the lock keeps batches in order
+	if b.throttle.AllowN(key, lines) {
+		b.emit(key, data)
+		return
+	}
+
+	// Hold back the trailing partial line so a prompt isn't lost with the flood
+	if i := strings.LastIndex(data, "\n"); i < len(data)-1 {
+		p.buf.WriteString(data[i+1:])
+	}
+}
+
+// SuppressedNote is the text standing in for count dropped lines
+func SuppressedNote(count int) string {
+	if count == 1 {
+		return "… 1 line suppressed"
+	}
+	return fmt.Sprintf("… %d lines suppressed", count)
+}
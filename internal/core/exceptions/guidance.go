@@ -0,0 +1,192 @@
+package exceptions
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// GuidanceRule maps exceptions to a canned explanation. Type and Message are
+// regular expressions; an empty pattern matches anything, but a rule must set
+// at least one of them. Type also matches driver errors wrapped by the
+// framework, whose class leads the message ("Mysql2::Error: Deadlock found...").
+type GuidanceRule struct {
+	Type            string `json:"type,omitempty"`
+	Message         string `json:"message,omitempty"`
+	Title           string `json:"title"`
+	LikelyCause     string `json:"likelyCause"`
+	SuggestedAction string `json:"suggestedAction"`
+}
+
+// Guidance is the explanation for a tracked exception
+type Guidance struct {
+	ExceptionID     string `json:"exceptionId"`
+	Title           string `json:"title"`
+	LikelyCause     string `json:"likelyCause"`
+	SuggestedAction string `json:"suggestedAction"`
+	Source          string `json:"source"` // "builtin" or the framework that supplied the rule
+}
+
+// compiledRule is a GuidanceRule with its patterns compiled
+type compiledRule struct {
+	rule   GuidanceRule
+	typeRe *regexp.Regexp
+	msgRe  *regexp.Regexp
+	source string
+}
+
+// builtinGuidance covers database driver errors seen regardless of framework
+var builtinGuidance = []GuidanceRule{
+	{
+		Type:            `^PG::UndefinedTable$`,
+		Title:           "Table does not exist",
+		LikelyCause:     "A query references a table that hasn't been created in this database, usually because migrations haven't run or ran against another database.",
+		SuggestedAction: "Run pending migrations (db:migrate) and check the database the app is connected to.",
+	},
+	{
+		Type:            `^PG::UndefinedColumn$`,
+		Title:           "Column does not exist",
+		LikelyCause:     "The code expects a column that the schema doesn't have, often after a migration was added but not run, or a column was renamed.",
+		SuggestedAction: "Run pending migrations, then check the column name against the schema.",
+	},
+	{
+		Type:            `^PG::UniqueViolation$`,
+		Title:           "Unique constraint violated",
+		LikelyCause:     "An insert or update produced a duplicate value for a uniquely indexed column, often from a race between two requests.",
+		SuggestedAction: "Add a matching uniqueness validation and handle the conflict (retry, or find-or-create inside a transaction).",
+	},
+	{
+		Type:            `^PG::ConnectionBad$`,
+		Title:           "Cannot connect to PostgreSQL",
+		LikelyCause:     "The database server isn't running or the host, port or credentials are wrong.",
+		SuggestedAction: "Check that PostgreSQL is running and that the database configuration matches it.",
+	},
+	{
+		Type:            `^Mysql2::Error$`,
+		Message:         `(?i)deadlock found`,
+		Title:           "Database deadlock",
+		LikelyCause:     "Two transactions locked the same rows in opposite order and MySQL rolled one back.",
+		SuggestedAction: "Retry the transaction, keep transactions short, and touch rows in a consistent order.",
+	},
+	{
+		Type:            `^Mysql2::Error$`,
+		Message:         `(?i)lock wait timeout exceeded`,
+		Title:           "Lock wait timeout",
+		LikelyCause:     "A transaction waited too long for a row lock held by another, long-running transaction.",
+		SuggestedAction: "Find the blocking transaction (SHOW ENGINE INNODB STATUS) and shorten it or move slow work out of it.",
+	},
+	{
+		Type:            `^Mysql2::Error$`,
+		Message:         `(?i)(table .* doesn't exist|unknown column)`,
+		Title:           "Schema out of date",
+		LikelyCause:     "A query references a table or column missing from this database, usually because migrations haven't run.",
+		SuggestedAction: "Run pending migrations (db:migrate) and check the database the app is connected to.",
+	},
+	{
+		Type:            `^Mysql2::Error::ConnectionError$`,
+		Title:           "Cannot connect to MySQL",
+		LikelyCause:     "The database server isn't running or the host, port or credentials are wrong.",
+		SuggestedAction: "Check that MySQL is running and that the database configuration matches it.",
+	},
+	{
+		Message:         `(?i)too many connections`,
+		Title:           "Connection limit reached",
+		LikelyCause:     "More clients are connected than the database allows, often from a pool size larger than the server limit across all processes.",
+		SuggestedAction: "Lower the connection pool size or raise the server's connection limit.",
+	},
+}
+
+// builtinRules are the compiled built-in rules, shared by all trackers
+var builtinRules = mustCompileRules(builtinGuidance, "builtin")
+
+// mustCompileRules compiles rules that are part of the program and must be valid
+func mustCompileRules(rules []GuidanceRule, source string) []compiledRule {
+	compiled, err := compileRules(rules, source)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
+
+// compileRules validates and compiles guidance rules
+func compileRules(rules []GuidanceRule, source string) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Type == "" && rule.Message == "" {
+			return nil, fmt.Errorf("guidance rule %d (%s) has no type or message pattern", i, rule.Title)
+		}
+
+		cr := compiledRule{rule: rule, source: source}
+		var err error
+		if rule.Type != "" {
+			if cr.typeRe, err = regexp.Compile(rule.Type); err != nil {
+				return nil, fmt.Errorf("guidance rule %d (%s): invalid type pattern: %w", i, rule.Title, err)
+			}
+		}
+		if rule.Message != "" {
+			if cr.msgRe, err = regexp.Compile(rule.Message); err != nil {
+				return nil, fmt.Errorf("guidance rule %d (%s): invalid message pattern: %w", i, rule.Title, err)
+			}
+		}
+		compiled = append(compiled, cr)
+	}
+	return compiled, nil
+}
+
+// wrappedType extracts an exception class that leads a message
+var wrappedType = regexp.MustCompile(`^([A-Z]\w*(?:::[A-Z]\w*)+):`)
+
+// matches reports whether the rule applies to an exception
+func (cr compiledRule) matches(exc *Exception) bool {
+	if cr.typeRe != nil && !cr.typeRe.MatchString(exc.Type) {
+		wrapped := wrappedType.FindStringSubmatch(exc.Message)
+		if wrapped == nil || !cr.typeRe.MatchString(wrapped[1]) {
+			return false
+		}
+	}
+	if cr.msgRe != nil && !cr.msgRe.MatchString(exc.Message) {
+		return false
+	}
+	return true
+}
+
+// SetFrameworkGuidance replaces the guidance rules supplied by a framework.
+// Framework rules are checked before the built-in ones.
+func (t *Tracker) SetFrameworkGuidance(framework string, rules []GuidanceRule) error {
+	compiled, err := compileRules(rules, framework)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.frameworkRules = compiled
+	return nil
+}
+
+// GetGuidance returns the explanation for an exception, or nil if no rule matches
+func (t *Tracker) GetGuidance(id string) (*Guidance, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	exc, exists := t.exceptions[id]
+	if !exists {
+		return nil, fmt.Errorf("exception not found: %s", id)
+	}
+
+	for _, rules := range [][]compiledRule{t.frameworkRules, builtinRules} {
+		for _, cr := range rules {
+			if cr.matches(exc) {
+				return &Guidance{
+					ExceptionID:     id,
+					Title:           cr.rule.Title,
+					LikelyCause:     cr.rule.LikelyCause,
+					SuggestedAction: cr.rule.SuggestedAction,
+					Source:          cr.source,
+				}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,86 @@
+package exceptions
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// exportVersion is the format version written by Export
+const exportVersion = 1
+
+// importedIDPrefix keeps imported snapshots apart from live exceptions with
+// the same fingerprint
+const importedIDPrefix = "imported-"
+
+// Export is a self-contained snapshot of an exception for sharing
+type Export struct {
+	Version    int          `json:"version"`
+	ExportedAt string       `json:"exportedAt"`
+	Exception  *Exception   `json:"exception"`
+	Trend      []TrendPoint `json:"trend"`
+}
+
+// Export returns the exception with its context and last hour's trend as
+// indented JSON
+func (t *Tracker) Export(id string) (string, error) {
+	trend, err := t.GetTrend(id)
+	if err != nil {
+		return "", err
+	}
+
+	// Marshal under the lock so the copy isn't torn by a live occurrence
+	t.mu.RLock()
+	exc, exists := t.exceptions[id]
+	var data []byte
+	if exists {
+		data, err = json.MarshalIndent(Export{
+			Version:    exportVersion,
+			ExportedAt: time.Now().Format(time.RFC3339),
+			Exception:  exc,
+			Trend:      trend,
+		}, "", "  ")
+	}
+	t.mu.RUnlock()
+
+	if !exists {
+		return "", fmt.Errorf("exception not found: %s", id)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode exception: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// Import loads an exported exception as a read-only snapshot. Importing the
+// same exception again replaces the earlier snapshot.
+func (t *Tracker) Import(data string) (*Exception, error) {
+	var export Export
+	if err := json.Unmarshal([]byte(data), &export); err != nil {
+		return nil, fmt.Errorf("invalid exception export: %w", err)
+	}
+	if export.Version != exportVersion {
+		return nil, fmt.Errorf("unsupported exception export version: %d", export.Version)
+	}
+
+	exc := export.Exception
+	if exc == nil || exc.Type == "" {
+		return nil, fmt.Errorf("exception export has no exception")
+	}
+
+	if exc.Fingerprint == "" {
+		exc.Fingerprint = generateFingerprint(exc.Type, exc.Message)
+	}
+	exc.ID = importedIDPrefix + exc.Fingerprint
+	exc.Imported = true
+	exc.Resolved = false
+	exc.Ignored = false
+	exc.importedTrend = export.Trend
+
+	t.mu.Lock()
+	t.exceptions[exc.ID] = exc
+	t.mu.Unlock()
+
+	return exc, nil
+}
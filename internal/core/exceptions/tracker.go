@@ -25,13 +25,49 @@ type Exception struct {
 	Resolved    bool                 `json:"resolved"`
 	Ignored     bool                 `json:"ignored"`
 	Fingerprint string               `json:"fingerprint"`
+
+	// RatePerMinute is the number of occurrences in the current minute
+	RatePerMinute int `json:"ratePerMinute"`
+	// Spiking is true when the current rate is well above the recent baseline
+	Spiking bool `json:"spiking"`
+
+	// Imported marks a snapshot loaded with ImportException. Its counts and
+	// trend are as exported; live occurrences are tracked separately.
+	Imported bool `json:"imported,omitempty"`
+
+	// Per-minute occurrence counts for the last hour, indexed by minute % trendWindow
+	buckets    [trendWindow]int
+	lastBucket int64 // Unix minute of the most recent bucket written
+
+	// Trend at export time, used instead of the buckets when Imported
+	importedTrend []TrendPoint
+}
+
+// TrendPoint is the occurrence count of an exception for one minute
+type TrendPoint struct {
+	Time  string `json:"time"`
+	Count int    `json:"count"`
 }
 
+const (
+	// trendWindow is the number of per-minute buckets kept per exception
+	trendWindow = 60
+
+	// spikeMinCount is the minimum per-minute count considered a spike
+	spikeMinCount = 5
+
+	// spikeFactor is how far above the baseline per-minute average a spike must be
+	spikeFactor = 3
+)
+
 // Tracker tracks and aggregates exceptions
 type Tracker struct {
 	mu         sync.RWMutex
 	exceptions map[string]*Exception
 	maxCount   int
+
+	// Guidance rules supplied by the active framework plugin
+	frameworkRules []compiledRule
 }
 
 // NewTracker creates a new exception tracker
@@ -42,10 +78,11 @@ func NewTracker() *Tracker {
 	}
 }
 
-// TrackException records an exception from a log entry
-func (t *Tracker) TrackException(logEntry *models.LogEntry) {
+// TrackException records an exception from a log entry. It returns the
+// exception ID and whether this is the first occurrence of its fingerprint.
+func (t *Tracker) TrackException(logEntry *models.LogEntry) (string, bool) {
 	if logEntry == nil || logEntry.Exception == nil {
-		return
+		return "", false
 	}
 
 	exc := logEntry.Exception
@@ -57,12 +94,15 @@ func (t *Tracker) TrackException(logEntry *models.LogEntry) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	now := time.Now()
+
 	existing, exists := t.exceptions[fingerprint]
 	if exists {
 		// Update existing exception
 		existing.Count++
-		existing.LastSeen = time.Now().Format(time.RFC3339)
-		return
+		existing.LastSeen = now.Format(time.RFC3339)
+		existing.record(now)
+		return existing.ID, false
 	}
 
 	// Create new exception
@@ -112,8 +152,8 @@ func (t *Tracker) TrackException(logEntry *models.LogEntry) {
 		Message:     exc.Message,
 		Severity:    severity,
 		Count:       1,
-		FirstSeen:   time.Now().Format(time.RFC3339),
-		LastSeen:    time.Now().Format(time.RFC3339),
+		FirstSeen:   now.Format(time.RFC3339),
+		LastSeen:    now.Format(time.RFC3339),
 		File:        file,
 		Line:        line,
 		StackTrace:  stackTrace,
@@ -122,6 +162,7 @@ func (t *Tracker) TrackException(logEntry *models.LogEntry) {
 		Ignored:     false,
 		Fingerprint: fingerprint,
 	}
+	newException.record(now)
 
 	t.exceptions[fingerprint] = newException
 
@@ -129,16 +170,23 @@ func (t *Tracker) TrackException(logEntry *models.LogEntry) {
 	if len(t.exceptions) > t.maxCount {
 		t.pruneOldest()
 	}
+
+	return fingerprint, true
 }
 
 // GetExceptions returns all tracked exceptions
 func (t *Tracker) GetExceptions() []*Exception {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
+	now := time.Now()
 	result := make([]*Exception, 0, len(t.exceptions))
 	for _, exc := range t.exceptions {
 		if !exc.Resolved && !exc.Ignored {
+			// Decay rate/spike state for exceptions that have gone quiet
+			if !exc.Imported {
+				exc.advance(now)
+			}
 			result = append(result, exc)
 		}
 	}
@@ -148,10 +196,43 @@ func (t *Tracker) GetExceptions() []*Exception {
 
 // GetException returns a specific exception by ID
 func (t *Tracker) GetException(id string) *Exception {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	exc := t.exceptions[id]
+	if exc != nil && !exc.Imported {
+		exc.advance(time.Now())
+	}
+	return exc
+}
+
+// GetTrend returns the per-minute occurrence counts of an exception for the
+// last hour, oldest first
+func (t *Tracker) GetTrend(id string) ([]TrendPoint, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	exc, exists := t.exceptions[id]
+	if !exists {
+		return nil, fmt.Errorf("exception not found: %s", id)
+	}
+	if exc.Imported {
+		return append([]TrendPoint(nil), exc.importedTrend...), nil
+	}
 
-	return t.exceptions[id]
+	now := time.Now()
+	exc.advance(now)
+
+	current := now.Unix() / 60
+	points := make([]TrendPoint, 0, trendWindow)
+	for minute := current - trendWindow + 1; minute <= current; minute++ {
+		points = append(points, TrendPoint{
+			Time:  time.Unix(minute*60, 0).Format(time.RFC3339),
+			Count: exc.buckets[minute%trendWindow],
+		})
+	}
+
+	return points, nil
 }
 
 // ResolveException marks an exception as resolved
@@ -203,6 +284,52 @@ func (t *Tracker) pruneOldest() {
 	}
 }
 
+// IsSpiking reports whether the exception is occurring well above its recent baseline
+func (e *Exception) IsSpiking() bool {
+	return e.Spiking
+}
+
+// record counts one occurrence in the bucket for the given time
+func (e *Exception) record(now time.Time) {
+	e.advance(now)
+	e.buckets[e.lastBucket%trendWindow]++
+	e.updateRate()
+}
+
+// advance moves the bucket window forward to now, zeroing any minutes that
+// passed without an occurrence
+func (e *Exception) advance(now time.Time) {
+	current := now.Unix() / 60
+	if e.lastBucket == 0 || current-e.lastBucket >= trendWindow {
+		e.buckets = [trendWindow]int{}
+	} else {
+		for minute := e.lastBucket + 1; minute <= current; minute++ {
+			e.buckets[minute%trendWindow] = 0
+		}
+	}
+	if current > e.lastBucket {
+		e.lastBucket = current
+	}
+	e.updateRate()
+}
+
+// updateRate recomputes the current rate and spike flag from the buckets.
+// A spike is a current-minute count of at least spikeMinCount that is also
+// spikeFactor times the average of the preceding minutes.
+func (e *Exception) updateRate() {
+	current := e.buckets[e.lastBucket%trendWindow]
+	baseline := 0
+	for i, count := range e.buckets {
+		if int64(i) != e.lastBucket%trendWindow {
+			baseline += count
+		}
+	}
+
+	e.RatePerMinute = current
+	average := float64(baseline) / float64(trendWindow-1)
+	e.Spiking = current >= spikeMinCount && float64(current) >= average*spikeFactor
+}
+
 // generateFingerprint creates a unique fingerprint for an exception
 func generateFingerprint(excType, message string) string {
 	data := []byte(excType + ":" + message)
@@ -0,0 +1,49 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// ErrNotInstalled is returned in place of every git operation's exec error
+// when the git binary can't be found or run
+var ErrNotInstalled = errors.New("git is not installed or not on PATH")
+
+var (
+	availableMu sync.Mutex
+	gitVersion  string // Set once git has been found
+)
+
+// CheckGitAvailable verifies the git binary runs and returns its version
+// (e.g. "2.43.0"). Once git has been found the version is cached; a failed
+// check is retried on the next call, so installing git or fixing PATH takes
+// effect without a restart.
+func CheckGitAvailable() (string, error) {
+	availableMu.Lock()
+	defer availableMu.Unlock()
+
+	if gitVersion != "" {
+		return gitVersion, nil
+	}
+
+	path, err := exec.LookPath("git")
+	if err != nil {
+		return "", ErrNotInstalled
+	}
+
+	output, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s --version failed: %v", ErrNotInstalled, path, err)
+	}
+
+	// "git version 2.43.0" or "git version 2.39.3 (Apple Git-145)"
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(string(output)), "git version"))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%w: unexpected version output %q", ErrNotInstalled, output)
+	}
+	gitVersion = fields[0]
+	return gitVersion, nil
+}
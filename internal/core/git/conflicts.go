@@ -0,0 +1,112 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+// conflictPath returns the absolute path of a file in the working tree,
+// refusing paths that escape the repository
+func (m *Manager) conflictPath(filePath string) (string, error) {
+	absPath := filepath.Join(m.workingDir, filePath)
+	rel, err := filepath.Rel(m.workingDir, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("path is outside the repository: %s", filePath)
+	}
+	return absPath, nil
+}
+
+// PreviewConflictResolution returns a conflicted file's content with each
+// chosen region replaced by ours, theirs or both, without writing anything.
+// Regions without a choice keep their conflict markers. Regions are numbered
+// in file order, as returned by GetConflictFile.
+func (m *Manager) PreviewConflictResolution(filePath string, choices []models.GitRegionChoice) (*models.GitConflictPreview, error) {
+	absPath, err := m.conflictPath(filePath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	resolutions := make(map[int]string, len(choices))
+	for _, choice := range choices {
+		switch choice.Resolution {
+		case "ours", "theirs", "both":
+			resolutions[choice.Region] = choice.Resolution
+		default:
+			return nil, fmt.Errorf("invalid resolution: %s (must be 'ours', 'theirs' or 'both')", choice.Resolution)
+		}
+	}
+
+	// The same parser as GetConflictFile, so region numbers agree
+	lines := strings.Split(string(data), "\n")
+	regions := m.parseConflictMarkers(string(data))
+	preview := &models.GitConflictPreview{Path: filePath}
+	var out []string
+	next := 0 // First line not yet copied
+
+	for index, region := range regions {
+		out = append(out, lines[next:region.StartLine-1]...)
+		switch resolutions[index] {
+		case "ours":
+			out = append(out, region.OursLines...)
+		case "theirs":
+			out = append(out, region.TheirsLines...)
+		case "both":
+			out = append(append(out, region.OursLines...), region.TheirsLines...)
+		default:
+			out = append(out, lines[region.StartLine-1:region.EndLine]...)
+			preview.Unresolved++
+		}
+		delete(resolutions, index)
+		next = region.EndLine
+	}
+	// Anything after the last region, including an unterminated one, is kept
+	out = append(out, lines[next:]...)
+
+	for index := range resolutions {
+		return nil, fmt.Errorf("conflict region %d not found in %s (it has %d)", index, filePath, len(regions))
+	}
+
+	preview.Content = strings.Join(out, "\n")
+	return preview, nil
+}
+
+// ApplyConflictResolution writes merged content to a conflicted file and
+// stages it, marking the conflict resolved. Content that still has conflict
+// markers is refused, and so is a file git no longer lists as conflicted.
+func (m *Manager) ApplyConflictResolution(filePath, mergedContent string) error {
+	absPath, err := m.conflictPath(filePath)
+	if err != nil {
+		return err
+	}
+
+	if regions := m.parseConflictMarkers(mergedContent); len(regions) > 0 {
+		return fmt.Errorf("%s still has %d unresolved conflict region(s)", filePath, len(regions))
+	}
+
+	// Don't overwrite a file whose conflict was resolved meanwhile
+	unmerged, err := m.execGit("diff", "--name-only", "--diff-filter=U", "--", filePath)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(unmerged) == "" {
+		return fmt.Errorf("%s is not conflicted", filePath)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	if err := os.WriteFile(absPath, []byte(mergedContent), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filePath, err)
+	}
+
+	return m.Stage([]string{filePath})
+}
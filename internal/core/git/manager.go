@@ -3,10 +3,13 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -33,19 +36,33 @@ func (m *Manager) SetWorkingDir(dir string) {
 
 // execGit executes a git command and returns the output
 func (m *Manager) execGit(args ...string) (string, error) {
+	stdout, stderr, err := m.execGitCapture(args...)
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w\n%s", strings.Join(args, " "), err, stderr)
+	}
+
+	return stdout, nil
+}
+
+// execGitCapture runs a git command and returns its stdout and stderr as is
+func (m *Manager) execGitCapture(args ...string) (string, string, error) {
+	return m.execGitCaptureEnv(nil, args...)
+}
+
+// execGitCaptureEnv is execGitCapture with extra environment variables
+func (m *Manager) execGitCaptureEnv(env []string, args ...string) (string, string, error) {
 	cmd := exec.Command("git", args...)
 	cmd.Dir = m.workingDir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
-	if err != nil {
-		return "", fmt.Errorf("git %s failed: %w\n%s", strings.Join(args, " "), err, stderr.String())
-	}
-
-	return stdout.String(), nil
+	return stdout.String(), stderr.String(), err
 }
 
 // IsGitRepository checks if the working directory is a git repository
@@ -108,9 +125,44 @@ func (m *Manager) GetStatus() (*models.GitStatus, error) {
 		}
 	}
 
+	status.RepositoryState = m.repositoryState(status.CurrentBranch)
+
 	return status, nil
 }
 
+// stateMarkers maps files git leaves in the git directory during an
+// operation to the state they indicate, checked in order
+var stateMarkers = []struct {
+	path  string
+	state string
+}{
+	{"rebase-merge", "rebasing"},
+	{filepath.Join("rebase-apply", "rebasing"), "rebasing"},
+	{filepath.Join("rebase-apply", "applying"), "applying-patches"},
+	{"MERGE_HEAD", "merging"},
+	{"CHERRY_PICK_HEAD", "cherry-picking"},
+	{"REVERT_HEAD", "reverting"},
+	{"BISECT_LOG", "bisecting"},
+}
+
+// repositoryState reports the operation in progress in the repository, or
+// "detached" / "clean" when there is none. Rebasing and bisecting detach HEAD
+// themselves, so the operation takes precedence.
+func (m *Manager) repositoryState(branch string) string {
+	if gitDir, err := m.absoluteGitDir(); err == nil {
+		for _, marker := range stateMarkers {
+			if _, err := os.Stat(filepath.Join(gitDir, marker.path)); err == nil {
+				return marker.state
+			}
+		}
+	}
+
+	if branch == "(detached)" {
+		return "detached"
+	}
+	return "clean"
+}
+
 // parseStatusLine parses a porcelain v2 status line
 func (m *Manager) parseStatusLine(line string) *models.GitFileStatus {
 	parts := strings.Fields(line)
@@ -195,7 +247,28 @@ func (m *Manager) GetDiff(options models.GitDiffOptions) ([]models.GitDiff, erro
 		return nil, err
 	}
 
-	return m.parseDiff(output)
+	diffs, err := m.parseDiff(output)
+	if err != nil {
+		return nil, err
+	}
+
+	// git diff ignores untracked files, so show a path's new files as added
+	workingTree := !options.Staged && !options.Cached && options.Ref == "" && options.RefA == ""
+	if workingTree && options.FilePath != "" {
+		untracked, err := m.untrackedFiles(options.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range untracked {
+			diff, err := m.untrackedDiff(file)
+			if err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return diffs, nil
 }
 
 // parseDiff parses git diff output
@@ -303,6 +376,76 @@ func (m *Manager) parseDiff(output string) ([]models.GitDiff, error) {
 	return diffs, nil
 }
 
+// GetDiffContext returns a range of lines from a file at the given revision.
+// An empty ref reads the working tree. If the file does not exist on that side
+// (added or deleted files), Exists is false and no lines are returned.
+func (m *Manager) GetDiffContext(filePath string, startLine, endLine int, ref string) (*models.GitDiffContext, error) {
+	if startLine < 1 {
+		startLine = 1
+	}
+	if endLine < startLine {
+		return nil, fmt.Errorf("invalid line range: %d-%d", startLine, endLine)
+	}
+
+	result := &models.GitDiffContext{
+		FilePath:  filePath,
+		Ref:       ref,
+		StartLine: startLine,
+		EndLine:   endLine,
+		Lines:     []string{},
+	}
+
+	var content string
+	if ref == "" {
+		absPath := filepath.Join(m.workingDir, filePath)
+		rel, err := filepath.Rel(m.workingDir, absPath)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("path is outside the repository: %s", filePath)
+		}
+
+		data, err := os.ReadFile(absPath)
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		content = string(data)
+	} else {
+		// Check the object exists first so a missing side isn't reported as an error
+		if _, err := m.execGit("cat-file", "-e", ref+":"+filePath); err != nil {
+			return result, nil
+		}
+
+		output, err := m.execGit("show", ref+":"+filePath)
+		if err != nil {
+			return nil, err
+		}
+		content = output
+	}
+
+	result.Exists = true
+
+	lines := strings.Split(content, "\n")
+	// Drop the empty element produced by a trailing newline
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	result.TotalLines = len(lines)
+
+	if startLine > len(lines) {
+		result.EndLine = len(lines)
+		return result, nil
+	}
+	if endLine > len(lines) {
+		endLine = len(lines)
+	}
+	result.EndLine = endLine
+	result.Lines = lines[startLine-1 : endLine]
+
+	return result, nil
+}
+
 // GetBlame returns blame information for a file
 func (m *Manager) GetBlame(filePath string) (*models.GitBlameFile, error) {
 	// Use porcelain format for easier parsing
@@ -311,6 +454,26 @@ func (m *Manager) GetBlame(filePath string) (*models.GitBlameFile, error) {
 		return nil, err
 	}
 
+	return m.parseBlame(filePath, output), nil
+}
+
+// GetBlameRange returns blame information for lines startLine..endLine (1-based, inclusive)
+func (m *Manager) GetBlameRange(filePath string, startLine, endLine int) (*models.GitBlameFile, error) {
+	if startLine < 1 || endLine < startLine {
+		return nil, fmt.Errorf("invalid line range: %d-%d", startLine, endLine)
+	}
+
+	lineRange := fmt.Sprintf("%d,%d", startLine, endLine)
+	output, err := m.execGit("blame", "--porcelain", "-L", lineRange, "--", filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.parseBlame(filePath, output), nil
+}
+
+// parseBlame parses git blame --porcelain output
+func (m *Manager) parseBlame(filePath, output string) *models.GitBlameFile {
 	blameFile := &models.GitBlameFile{
 		FilePath: filePath,
 		Lines:    []models.GitBlameLine{},
@@ -381,12 +544,15 @@ func (m *Manager) GetBlame(filePath string) (*models.GitBlameFile, error) {
 		}
 	}
 
-	return blameFile, nil
+	return blameFile
 }
 
+// logFormat is the git log format understood by parseLog
+const logFormat = "--pretty=format:%H%n%h%n%an%n%ae%n%at%n%cn%n%ce%n%ct%n%s%n%b%n--END--"
+
 // GetLog returns commit history
 func (m *Manager) GetLog(options models.GitLogOptions) ([]models.GitCommit, error) {
-	args := []string{"log", "--pretty=format:%H%n%h%n%an%n%ae%n%at%n%cn%n%ce%n%ct%n%s%n%b%n--END--"}
+	args := []string{"log", logFormat}
 
 	if options.MaxCount > 0 {
 		args = append(args, fmt.Sprintf("--max-count=%d", options.MaxCount))
@@ -420,6 +586,47 @@ func (m *Manager) GetLog(options models.GitLogOptions) ([]models.GitCommit, erro
 	return m.parseLog(output)
 }
 
+// GetCommitDetail returns a single commit with its parents and changed files
+func (m *Manager) GetCommitDetail(hash string) (*models.GitCommit, error) {
+	if hash == "" || strings.HasPrefix(hash, "-") {
+		return nil, fmt.Errorf("invalid commit: %q", hash)
+	}
+
+	output, err := m.execGit("log", "-1", logFormat, hash, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := m.parseLog(output + "\n")
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("commit not found: %s", hash)
+	}
+	commit := &commits[0]
+
+	parents, err := m.execGit("show", "-s", "--format=%P", commit.Hash)
+	if err != nil {
+		return nil, err
+	}
+	commit.Parents = strings.Fields(parents)
+
+	// --root lists the files of the initial commit too
+	files, err := m.execGit("diff-tree", "--no-commit-id", "--name-only", "-r", "--root", commit.Hash)
+	if err != nil {
+		return nil, err
+	}
+	commit.Files = []string{}
+	for _, file := range strings.Split(strings.TrimSpace(files), "\n") {
+		if file != "" {
+			commit.Files = append(commit.Files, file)
+		}
+	}
+
+	return commit, nil
+}
+
 // parseLog parses git log output
 func (m *Manager) parseLog(output string) ([]models.GitCommit, error) {
 	commits := []models.GitCommit{}
@@ -489,12 +696,92 @@ func (m *Manager) Unstage(files []string) error {
 	return err
 }
 
+// StageAll stages all changes, including untracked and deleted files
+func (m *Manager) StageAll() error {
+	_, err := m.execGit("add", "-A")
+	return err
+}
+
+// UnstageAll unstages everything in the index
+func (m *Manager) UnstageAll() error {
+	_, err := m.execGit("reset", "HEAD")
+	return err
+}
+
+// DiscardAllChanges discards all unstaged changes and removes untracked files.
+// Ignored files are never touched, and any paths in preserve are excluded from
+// the clean so they survive (e.g. untracked local config).
+func (m *Manager) DiscardAllChanges(preserve []string) error {
+	if _, err := m.execGit("checkout", "--", "."); err != nil {
+		return err
+	}
+
+	args := []string{"clean", "-fd"}
+	for _, path := range preserve {
+		args = append(args, "-e", path)
+	}
+
+	_, err := m.execGit(args...)
+	return err
+}
+
+// ListUntracked returns the untracked files and directories that a clean
+// would remove (git clean -nd). Directories end with a slash.
+func (m *Manager) ListUntracked(includeIgnored bool) ([]string, error) {
+	args := []string{"clean", "-nd"}
+	if includeIgnored {
+		args = append(args, "-x")
+	}
+
+	output, err := m.execGit(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		path, ok := strings.CutPrefix(scanner.Text(), "Would remove ")
+		if !ok {
+			continue
+		}
+
+		// Paths with unusual characters are C-quoted
+		if strings.HasPrefix(path, "\"") {
+			if unquoted, err := strconv.Unquote(path); err == nil {
+				path = unquoted
+			}
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// CleanUntracked removes the given untracked files and directories
+// (git clean -fd). Ignored files are only removed when includeIgnored is set.
+func (m *Manager) CleanUntracked(paths []string, includeIgnored bool) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	args := []string{"clean", "-fd"}
+	if includeIgnored {
+		args = append(args, "-x")
+	}
+	args = append(args, "--")
+	args = append(args, paths...)
+
+	_, err := m.execGit(args...)
+	return err
+}
+
 // Commit creates a commit
-func (m *Manager) Commit(options models.GitCommitOptions) error {
+func (m *Manager) Commit(options models.GitCommitOptions) (*models.GitCommitResult, error) {
 	// Stage files if specified
 	if len(options.Files) > 0 {
 		if err := m.Stage(options.Files); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -507,8 +794,96 @@ func (m *Manager) Commit(options models.GitCommitOptions) error {
 		args = append(args, "--author", options.Author)
 	}
 
-	_, err := m.execGit(args...)
-	return err
+	// The trace shows which hooks ran, so a hook is only blamed for failures
+	// that are its own
+	stdout, stderr, err := m.execGitCaptureEnv([]string{"GIT_TRACE=2"}, args...)
+	if err == nil {
+		hash, _ := m.execGit("rev-parse", "HEAD")
+		return &models.GitCommitResult{Success: true, Hash: strings.TrimSpace(hash)}, nil
+	}
+
+	gitOutput, hook := splitCommitTrace(stderr)
+	output := strings.TrimSpace(strings.TrimSpace(gitOutput) + "\n" + strings.TrimSpace(stdout))
+	result := &models.GitCommitResult{Error: output}
+
+	// Git checks for an empty commit after pre-commit runs, so rule that out
+	// before blaming a hook
+	if strings.Contains(output, "nothing to commit") || strings.Contains(output, "no changes added to commit") {
+		result.Error = "nothing to commit"
+		return result, nil
+	}
+	// A failing hook makes git exit 1; its own fatal errors exit 128
+	var exitErr *exec.ExitError
+	if hook != "" && errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		result.HookOutput = output
+		result.Error = fmt.Sprintf("commit rejected by hook (%s)", hook)
+	}
+
+	return result, nil
+}
+
+// commitHookNames are the hooks that can reject a commit
+var commitHookNames = []string{"pre-commit", "prepare-commit-msg", "commit-msg"}
+
+// traceLine matches a line GIT_TRACE adds to stderr, e.g.
+// "13:40:42.411349 run-command.c:655       trace: run_command: ..."
+var traceLine = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}\.\d+ +\S+:\d+ +`)
+
+// splitCommitTrace removes the GIT_TRACE lines from a commit's stderr. It
+// also returns the commit hook that ran last, unless git printed something
+// of its own once that hook had finished, which means git failed after it.
+func splitCommitTrace(stderr string) (string, string) {
+	var output strings.Builder
+	hook, hookRunning := "", false
+	for _, line := range strings.SplitAfter(stderr, "\n") {
+		prefix := traceLine.FindString(line)
+		if prefix == "" {
+			output.WriteString(line)
+			if hook != "" && !hookRunning && strings.TrimSpace(line) != "" {
+				hook = ""
+			}
+			continue
+		}
+
+		trace := line[len(prefix):]
+		switch {
+		case strings.HasPrefix(trace, "trace: run_command:"):
+			// The hook's path follows any environment assignments
+			for _, field := range strings.Fields(trace) {
+				if name := filepath.Base(field); slices.Contains(commitHookNames, name) {
+					hook, hookRunning = name, true
+					break
+				}
+			}
+		case strings.HasPrefix(trace, "run_processes_parallel: done"):
+			hookRunning = false
+		}
+	}
+	return output.String(), hook
+}
+
+// GetCommitTemplate returns the commit message template configured with
+// git's commit.template, or "" if there is none
+func (m *Manager) GetCommitTemplate() (string, error) {
+	// --path expands a leading ~
+	path, err := m.execGit("config", "--path", "--get", "commit.template")
+	if err != nil {
+		return "", nil // Not set
+	}
+	path = strings.TrimSpace(path)
+	if !filepath.IsAbs(path) {
+		root, err := m.execGit("rev-parse", "--show-toplevel")
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(strings.TrimSpace(root), path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit template: %w", err)
+	}
+	return string(data), nil
 }
 
 // Revert reverts a file to HEAD
@@ -529,6 +904,75 @@ func (m *Manager) DiscardChanges(filePath string) error {
 	return err
 }
 
+// CherryPick applies the changes of a commit onto the current branch
+func (m *Manager) CherryPick(hash string) (*models.GitMergeResult, error) {
+	return m.applyCommit("cherry-pick", hash, "cherry-pick")
+}
+
+// RevertCommit creates a new commit that undoes the changes of a commit
+func (m *Manager) RevertCommit(hash string) (*models.GitMergeResult, error) {
+	return m.applyCommit("revert", hash, "revert", "--no-edit")
+}
+
+// AbortCherryPick abandons an in-progress cherry-pick
+func (m *Manager) AbortCherryPick() error {
+	_, err := m.execGit("cherry-pick", "--abort")
+	return err
+}
+
+// AbortRevert abandons an in-progress revert
+func (m *Manager) AbortRevert() error {
+	_, err := m.execGit("revert", "--abort")
+	return err
+}
+
+// applyCommit runs a cherry-pick or revert. A run that stops on conflicts is
+// not an error: the result lists the conflicted files so they can be resolved
+// or the operation aborted.
+func (m *Manager) applyCommit(operation, hash string, args ...string) (*models.GitMergeResult, error) {
+	if hash == "" || strings.HasPrefix(hash, "-") {
+		return nil, fmt.Errorf("invalid commit: %q", hash)
+	}
+
+	_, runErr := m.execGit(append(args, hash)...)
+	if runErr == nil {
+		return &models.GitMergeResult{
+			Success: true,
+			Message: fmt.Sprintf("%s of %s succeeded", operation, hash),
+		}, nil
+	}
+
+	return m.stoppedResult(runErr, fmt.Sprintf("%s of %s", operation, hash))
+}
+
+// stoppedResult turns the failure of an operation that can stop on conflicts
+// into a result listing them, or returns runErr if it failed for another
+// reason
+func (m *Manager) stoppedResult(runErr error, operation string) (*models.GitMergeResult, error) {
+	status, err := m.GetStatus()
+	if err != nil || !status.HasConflicts {
+		return nil, runErr
+	}
+
+	result := &models.GitMergeResult{
+		Success:   false,
+		Conflicts: []models.GitConflictFile{},
+	}
+	for _, file := range status.Files {
+		if file.Status != "conflict" {
+			continue
+		}
+		conflict, err := m.GetConflictFile(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		result.Conflicts = append(result.Conflicts, *conflict)
+	}
+	result.Message = fmt.Sprintf("%s stopped with %d conflicting file(s)", operation, len(result.Conflicts))
+
+	return result, nil
+}
+
 // GetConflictFile returns conflict information for a file
 func (m *Manager) GetConflictFile(filePath string) (*models.GitConflictFile, error) {
 	// Get "ours" version (current branch)
@@ -581,6 +1025,7 @@ func (m *Manager) parseConflictMarkers(content string) []models.GitConflictRegio
 	inConflict := false
 	var currentRegion *models.GitConflictRegion
 	inOurs := false
+	inBase := false
 	inTheirs := false
 
 	for i, line := range lines {
@@ -594,9 +1039,14 @@ func (m *Manager) parseConflictMarkers(content string) []models.GitConflictRegio
 				TheirsLines: []string{},
 				BaseLines:   []string{},
 			}
+		} else if strings.HasPrefix(line, "|||||||") && inOurs {
+			// diff3 style: the common ancestor's lines come next
+			inOurs = false
+			inBase = true
 		} else if strings.HasPrefix(line, "=======") && inConflict {
 			// Switch from ours to theirs
 			inOurs = false
+			inBase = false
 			inTheirs = true
 		} else if strings.HasPrefix(line, ">>>>>>>") && inConflict {
 			// End of conflict
@@ -608,6 +1058,8 @@ func (m *Manager) parseConflictMarkers(content string) []models.GitConflictRegio
 		} else if inConflict {
 			if inOurs {
 				currentRegion.OursLines = append(currentRegion.OursLines, line)
+			} else if inBase {
+				currentRegion.BaseLines = append(currentRegion.BaseLines, line)
 			} else if inTheirs {
 				currentRegion.TheirsLines = append(currentRegion.TheirsLines, line)
 			}
@@ -667,13 +1119,25 @@ func (m *Manager) GetBranches() ([]models.GitBranch, error) {
 		// Parse branch info
 		parts := strings.Fields(line)
 		if len(parts) >= 2 {
+			// remotes/origin/HEAD -> origin/main is an alias, not a branch
+			if parts[1] == "->" {
+				continue
+			}
+
 			branch.Name = parts[0]
 			branch.CommitHash = parts[1]
 
-			// Parse upstream if present
-			if len(parts) >= 3 && strings.HasPrefix(parts[2], "[") {
-				upstream := strings.Trim(parts[2], "[]")
-				branch.Upstream = upstream
+			if strings.HasPrefix(branch.Name, "remotes/") {
+				branch.IsRemote = true
+				branch.Remote = strings.SplitN(strings.TrimPrefix(branch.Name, "remotes/"), "/", 2)[0]
+			}
+
+			// Parse upstream if present: [origin/main: ahead 2, behind 1]
+			rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(line, parts[0])), parts[1]))
+			if strings.HasPrefix(rest, "[") {
+				if end := strings.Index(rest, "]"); end != -1 {
+					parseBranchTracking(&branch, rest[1:end])
+				}
 			}
 		}
 
@@ -683,6 +1147,30 @@ func (m *Manager) GetBranches() ([]models.GitBranch, error) {
 	return branches, nil
 }
 
+// parseBranchTracking reads the upstream and ahead/behind counts from the
+// bracketed part of a `git branch -vv` line, e.g. "origin/main: ahead 2, behind 1"
+func parseBranchTracking(branch *models.GitBranch, tracking string) {
+	upstream, counts, _ := strings.Cut(tracking, ":")
+	branch.Upstream = upstream
+
+	for _, part := range strings.Split(counts, ",") {
+		fields := strings.Fields(part)
+		if len(fields) != 2 {
+			continue
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "ahead":
+			branch.Ahead = n
+		case "behind":
+			branch.Behind = n
+		}
+	}
+}
+
 // CreateBranch creates a new branch
 func (m *Manager) CreateBranch(name string, startPoint string) error {
 	args := []string{"branch", name}
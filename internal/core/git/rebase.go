@@ -0,0 +1,227 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+// rebaseMessageDir holds reword messages in the git directory while a
+// rebase started by StartInteractiveRebase is in progress
+const rebaseMessageDir = "caboose-rebase"
+
+// rebaseActions are the todo commands StartInteractiveRebase accepts
+var rebaseActions = map[string]bool{
+	"pick":   true,
+	"reword": true,
+	"squash": true,
+	"fixup":  true,
+	"drop":   true,
+}
+
+// nonInteractiveEditor keeps the message git proposes, so squashes and
+// continued picks never wait on an editor
+var nonInteractiveEditor = []string{"GIT_EDITOR=true"}
+
+// GetRebaseTodo returns the commits an interactive rebase onto ontoRef would
+// replay, oldest first, each with the pick action. Merge commits are left
+// out, as git rebase does without --rebase-merges, and so are commits whose
+// change is already upstream, which git drops from its own todo.
+func (m *Manager) GetRebaseTodo(ontoRef string) ([]models.GitRebaseAction, error) {
+	if ontoRef == "" || strings.HasPrefix(ontoRef, "-") {
+		return nil, fmt.Errorf("invalid ref: %q", ontoRef)
+	}
+
+	// The same selection as git's todo: HEAD's side of the symmetric
+	// difference, minus patch-equivalent commits
+	output, err := m.execGit("log", "--reverse", "--topo-order", "--no-merges", "--cherry-pick", "--right-only",
+		"--format=%H%x00%h%x00%s", ontoRef+"...HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	todo := []models.GitRebaseAction{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		parts := strings.SplitN(line, "\x00", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		todo = append(todo, models.GitRebaseAction{
+			Hash:      parts[0],
+			ShortHash: parts[1],
+			Summary:   parts[2],
+			Action:    "pick",
+		})
+	}
+	return todo, nil
+}
+
+// StartInteractiveRebase rebases the current branch onto ontoRef with the
+// given actions, in the given order. Every commit from GetRebaseTodo must
+// appear exactly once, so a list gone stale since it was fetched is refused
+// instead of silently dropping commits. The todo is handed to git through
+// GIT_SEQUENCE_EDITOR and reword messages are applied by an exec line, so no
+// editor opens. A rebase that stops on conflicts is not an error: resolve
+// them, then ContinueRebase or AbortRebase.
+func (m *Manager) StartInteractiveRebase(ontoRef string, actions []models.GitRebaseAction) (*models.GitMergeResult, error) {
+	current, err := m.GetRebaseTodo(ontoRef)
+	if err != nil {
+		return nil, err
+	}
+	if len(current) == 0 {
+		return nil, fmt.Errorf("no commits to rebase onto %s", ontoRef)
+	}
+	if err := validateRebaseActions(current, actions); err != nil {
+		return nil, err
+	}
+
+	gitDir, err := m.absoluteGitDir()
+	if err != nil {
+		return nil, err
+	}
+	messageDir := filepath.Join(gitDir, rebaseMessageDir)
+	os.RemoveAll(messageDir)
+	if err := os.MkdirAll(messageDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to prepare rebase: %w", err)
+	}
+
+	var todo strings.Builder
+	for _, action := range actions {
+		if action.Action != "reword" {
+			fmt.Fprintf(&todo, "%s %s\n", action.Action, action.Hash)
+			continue
+		}
+		messageFile := filepath.Join(messageDir, action.Hash)
+		if err := os.WriteFile(messageFile, []byte(action.Message), 0600); err != nil {
+			os.RemoveAll(messageDir)
+			return nil, fmt.Errorf("failed to prepare rebase: %w", err)
+		}
+		fmt.Fprintf(&todo, "pick %s\nexec git commit --amend --only --allow-empty --quiet -F %s\n", action.Hash, shellQuote(messageFile))
+	}
+
+	todoFile, err := os.CreateTemp("", "caboose-rebase-todo-*")
+	if err != nil {
+		os.RemoveAll(messageDir)
+		return nil, fmt.Errorf("failed to prepare rebase: %w", err)
+	}
+	defer os.Remove(todoFile.Name())
+	_, err = todoFile.WriteString(todo.String())
+	if closeErr := todoFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.RemoveAll(messageDir)
+		return nil, fmt.Errorf("failed to prepare rebase: %w", err)
+	}
+
+	// git runs the sequence editor through the shell with the todo path as
+	// its argument; copying over it replaces the proposed todo with ours
+	env := append([]string{"GIT_SEQUENCE_EDITOR=cp " + shellQuote(todoFile.Name())}, nonInteractiveEditor...)
+	return m.runRebase(env, fmt.Sprintf("rebase onto %s", ontoRef), "rebase", "-i", ontoRef)
+}
+
+// ContinueRebase continues a rebase after its conflicts were resolved and
+// staged
+func (m *Manager) ContinueRebase() (*models.GitMergeResult, error) {
+	return m.runRebase(nonInteractiveEditor, "rebase", "rebase", "--continue")
+}
+
+// AbortRebase abandons a rebase in progress and restores the branch
+func (m *Manager) AbortRebase() error {
+	_, err := m.execGit("rebase", "--abort")
+	m.removeRebaseMessages()
+	return err
+}
+
+// runRebase runs a rebase command and reports where it stopped, cleaning up
+// reword messages once the rebase is over
+func (m *Manager) runRebase(env []string, operation string, args ...string) (*models.GitMergeResult, error) {
+	_, stderr, err := m.execGitCaptureEnv(env, args...)
+	if err == nil {
+		m.removeRebaseMessages()
+		return &models.GitMergeResult{
+			Success: true,
+			Message: fmt.Sprintf("%s succeeded", operation),
+		}, nil
+	}
+
+	runErr := fmt.Errorf("git %s failed: %w\n%s", strings.Join(args, " "), err, stderr)
+	if m.repositoryState("") != "rebasing" {
+		m.removeRebaseMessages()
+	}
+	return m.stoppedResult(runErr, operation)
+}
+
+// removeRebaseMessages deletes the reword messages of a finished rebase
+func (m *Manager) removeRebaseMessages() {
+	if gitDir, err := m.absoluteGitDir(); err == nil {
+		os.RemoveAll(filepath.Join(gitDir, rebaseMessageDir))
+	}
+}
+
+// absoluteGitDir returns the repository's git directory
+func (m *Manager) absoluteGitDir() (string, error) {
+	output, err := m.execGit("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// validateRebaseActions checks that actions name each commit to rebase
+// exactly once with a known action, and that the result is a valid todo
+func validateRebaseActions(current, actions []models.GitRebaseAction) error {
+	pending := make(map[string]bool, len(current))
+	for _, commit := range current {
+		pending[commit.Hash] = true
+	}
+
+	picked := false
+	for _, action := range actions {
+		if !pending[action.Hash] {
+			return fmt.Errorf("commit %q is not being rebased or is listed twice; reload the rebase list", action.Hash)
+		}
+		delete(pending, action.Hash)
+
+		if !rebaseActions[action.Action] {
+			return fmt.Errorf("invalid rebase action: %s (must be pick, reword, squash, fixup or drop)", action.Action)
+		}
+		switch action.Action {
+		case "squash", "fixup":
+			if !picked {
+				return fmt.Errorf("cannot %s %s: there is no earlier commit to combine it with", action.Action, shortHash(action.Hash))
+			}
+		case "reword":
+			if strings.TrimSpace(action.Message) == "" {
+				return fmt.Errorf("commit %s needs a new message to reword", shortHash(action.Hash))
+			}
+		}
+		if action.Action != "drop" {
+			picked = true
+		}
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("%d commit(s) missing from the rebase actions; reload the rebase list", len(pending))
+	}
+	if !picked {
+		return fmt.Errorf("every commit would be dropped")
+	}
+	return nil
+}
+
+// shortHash abbreviates a full commit hash for messages
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// shellQuote quotes s for the POSIX shell git runs editors and exec lines in
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
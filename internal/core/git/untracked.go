@@ -0,0 +1,113 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+const (
+	// maxUntrackedDiffSize is the largest untracked file shown as a diff
+	maxUntrackedDiffSize = 1 << 20 // 1MB
+
+	// binarySniffLen is how much of a file is checked for NUL bytes, as git does
+	binarySniffLen = 8000
+)
+
+// untrackedFiles returns the untracked, non-ignored files at or under path
+func (m *Manager) untrackedFiles(path string) ([]string, error) {
+	output, err := m.execGit("ls-files", "-z", "--others", "--exclude-standard", "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, file := range strings.Split(output, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// untrackedDiff builds the diff git would show for an untracked file once
+// added: a single hunk adding every line. Binary files and files over
+// maxUntrackedDiffSize get no hunks.
+func (m *Manager) untrackedDiff(filePath string) (models.GitDiff, error) {
+	diff := models.GitDiff{
+		FilePath: filePath,
+		Status:   "added",
+		Hunks:    []models.GitDiffHunk{},
+	}
+
+	absPath := filepath.Join(m.workingDir, filePath)
+	rel, err := filepath.Rel(m.workingDir, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return diff, fmt.Errorf("path is outside the repository: %s", filePath)
+	}
+
+	info, err := os.Lstat(absPath)
+	if err != nil {
+		return diff, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var data []byte
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		// Git stores a symlink as its target
+		diff.NewMode = "120000"
+		target, err := os.Readlink(absPath)
+		if err != nil {
+			return diff, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		data = []byte(target)
+	case info.Size() > maxUntrackedDiffSize:
+		diff.NewMode = fileMode(info)
+		diff.TooLarge = true
+		return diff, nil
+	default:
+		diff.NewMode = fileMode(info)
+		if data, err = os.ReadFile(absPath); err != nil {
+			return diff, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+	}
+
+	if bytes.IndexByte(data[:min(len(data), binarySniffLen)], 0) != -1 {
+		diff.IsBinary = true
+		return diff, nil
+	}
+	if len(data) == 0 {
+		return diff, nil
+	}
+
+	content := string(data)
+	noNewline := !strings.HasSuffix(content, "\n")
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	hunk := models.GitDiffHunk{
+		NewStart: 1,
+		NewLines: len(lines),
+		Header:   fmt.Sprintf("@@ -0,0 +1,%d @@", len(lines)),
+		Lines:    make([]string, 0, len(lines)+1),
+	}
+	for _, line := range lines {
+		hunk.Lines = append(hunk.Lines, "+"+line)
+	}
+	if noNewline {
+		hunk.Lines = append(hunk.Lines, `\ No newline at end of file`)
+	}
+	diff.Hunks = append(diff.Hunks, hunk)
+
+	return diff, nil
+}
+
+func fileMode(info os.FileInfo) string {
+	if info.Mode()&0o111 != 0 {
+		return "100755"
+	}
+	return "100644"
+}
@@ -0,0 +1,259 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/caboose-desktop/internal/core/crash"
+	"github.com/caboose-desktop/internal/models"
+)
+
+const (
+	// forwardBuffer is how many lines a client may fall behind before new
+	// lines are dropped for it, as with Streamer subscriptions
+	forwardBuffer = 1000
+
+	// maxForwardClients bounds the connected clients
+	maxForwardClients = 16
+
+	// forwardWriteTimeout is how long a write to a client may block before
+	// the client is disconnected
+	forwardWriteTimeout = 5 * time.Second
+)
+
+// ForwardStatus describes a running log forwarder
+type ForwardStatus struct {
+	Network string `json:"network"` // "tcp" or "unix"
+	Address string `json:"address"`
+	Clients int    `json:"clients"`
+
+	// Dropped is how many lines were dropped for clients too slow to keep up
+	Dropped int64 `json:"dropped"`
+}
+
+// forwardClient is a connected client and its Streamer subscription
+type forwardClient struct {
+	conn    net.Conn
+	id      string
+	entries <-chan *models.LogEntry
+	once    sync.Once
+}
+
+// Forwarder serves log entries as JSON lines on a local socket, for external
+// tools to follow the log stream. Each client is a Streamer subscriber with
+// a bounded queue; when a client falls behind, lines are dropped for it
+// rather than holding up logging.
+type Forwarder struct {
+	listener net.Listener
+	network  string
+	address  string
+	stream   *Streamer // Only its subscriptions are used
+
+	mu      sync.Mutex
+	clients map[*forwardClient]bool
+	closed  bool
+
+	count atomic.Int32
+}
+
+// ParseForwardAddress reads a forward_socket setting: tcp://host:port or
+// host:port for TCP, and unix:///path or a path for a Unix socket. TCP must
+// listen on a loopback address, since log lines can carry secrets.
+func ParseForwardAddress(spec string) (network, address string, err error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "":
+		return "", "", fmt.Errorf("forward socket address is empty")
+	case strings.HasPrefix(spec, "unix://"):
+		network, address = "unix", strings.TrimPrefix(spec, "unix://")
+	case strings.HasPrefix(spec, "tcp://"):
+		network, address = "tcp", strings.TrimPrefix(spec, "tcp://")
+	case strings.ContainsAny(spec, `/\`):
+		network, address = "unix", spec
+	default:
+		network, address = "tcp", spec
+	}
+
+	if network == "unix" {
+		if address == "" {
+			return "", "", fmt.Errorf("unix socket path is empty")
+		}
+		return network, address, nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid forward socket address %q: %w", spec, err)
+	}
+	// SECURITY: Never expose the log stream beyond this machine
+	if host != "localhost" {
+		ip := net.ParseIP(host)
+		if ip == nil || !ip.IsLoopback() {
+			return "", "", fmt.Errorf("forward socket must listen on a loopback address, not %q", host)
+		}
+	}
+	return network, address, nil
+}
+
+// ListenForwarder starts serving forwarded log lines on spec (see
+// ParseForwardAddress). A stale Unix socket file left by a previous run is
+// replaced, but not one another process is still listening on.
+func ListenForwarder(spec string) (*Forwarder, error) {
+	network, address, err := ParseForwardAddress(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if network == "unix" {
+		if info, err := os.Lstat(address); err == nil {
+			if info.Mode()&os.ModeSocket == 0 {
+				return nil, fmt.Errorf("%s exists and is not a socket", address)
+			}
+			conn, err := net.Dial("unix", address)
+			if err == nil {
+				conn.Close()
+				return nil, fmt.Errorf("%s is in use by another process", address)
+			}
+			if !isConnRefused(err) {
+				return nil, fmt.Errorf("failed to check socket %s: %w", address, err)
+			}
+			os.Remove(address)
+		}
+	}
+
+	var listener net.Listener
+	if network == "unix" {
+		// SECURITY: Only the user may connect, from the moment it exists
+		listener, err = listenUnix(address)
+	} else {
+		listener, err = net.Listen(network, address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", spec, err)
+	}
+
+	f := &Forwarder{
+		listener: listener,
+		network:  network,
+		address:  listener.Addr().String(),
+		stream:   NewStreamer(1),
+		clients:  make(map[*forwardClient]bool),
+	}
+	if network == "unix" {
+		f.address = address // The socket may have been bound elsewhere and moved
+	}
+	go f.accept()
+	return f, nil
+}
+
+// accept serves connecting clients until the listener is closed
+func (f *Forwarder) accept() {
+	defer crash.Guard("log forwarder")
+
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		f.mu.Lock()
+		if f.closed || len(f.clients) >= maxForwardClients {
+			f.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		client := &forwardClient{conn: conn}
+		client.id, client.entries = f.stream.SubscribeBuffered(forwardBuffer)
+		f.clients[client] = true
+		f.count.Store(int32(len(f.clients)))
+		f.mu.Unlock()
+
+		go f.write(client)
+		go f.watch(client)
+	}
+}
+
+// write sends a client its queued lines until it disconnects or is removed
+func (f *Forwarder) write(client *forwardClient) {
+	defer crash.Guard("log forwarder client")
+	defer f.remove(client)
+
+	for entry := range client.entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			continue
+		}
+		client.conn.SetWriteDeadline(time.Now().Add(forwardWriteTimeout))
+		if _, err := client.conn.Write(append(line, '\n')); err != nil {
+			return
+		}
+	}
+}
+
+// watch notices a client hanging up; clients aren't expected to send
+// anything, so whatever they do is discarded
+func (f *Forwarder) watch(client *forwardClient) {
+	defer crash.Guard("log forwarder client")
+
+	io.Copy(io.Discard, client.conn)
+	f.remove(client)
+}
+
+// remove disconnects a client
+func (f *Forwarder) remove(client *forwardClient) {
+	client.once.Do(func() {
+		f.mu.Lock()
+		delete(f.clients, client)
+		f.count.Store(int32(len(f.clients)))
+		f.mu.Unlock()
+
+		f.stream.Unsubscribe(client.id)
+		client.conn.Close()
+	})
+}
+
+// Publish queues an entry, sent as one JSON line, for every connected
+// client. It never blocks: a client whose queue is full misses the line.
+func (f *Forwarder) Publish(entry *models.LogEntry) {
+	if f == nil || f.count.Load() == 0 {
+		return
+	}
+	f.stream.Broadcast(entry)
+}
+
+// Status reports where the forwarder listens and how it is keeping up
+func (f *Forwarder) Status() ForwardStatus {
+	return ForwardStatus{
+		Network: f.network,
+		Address: f.address,
+		Clients: int(f.count.Load()),
+		Dropped: f.stream.Dropped(),
+	}
+}
+
+// Close stops listening and disconnects every client
+func (f *Forwarder) Close() error {
+	f.mu.Lock()
+	f.closed = true
+	clients := make([]*forwardClient, 0, len(f.clients))
+	for client := range f.clients {
+		clients = append(clients, client)
+	}
+	f.mu.Unlock()
+
+	err := f.listener.Close()
+	for _, client := range clients {
+		f.remove(client)
+	}
+	if f.network == "unix" {
+		os.Remove(f.address) // listenUnix leaves unlinking to us
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+//go:build !windows
+
+package log
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// listenUnix creates the socket at address readable and writable by the user
+// only. It is bound in a new 0700 directory, where no one else can reach it,
+// made private there, then moved into place. The listener doesn't unlink
+// the socket on Close; the caller removes it.
+func listenUnix(address string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(address), ".caboose-socket-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	staging := filepath.Join(dir, "s")
+	listener, err := net.Listen("unix", staging)
+	if err != nil {
+		return nil, err
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+
+	if err = os.Chmod(staging, 0600); err == nil {
+		err = os.Rename(staging, address)
+	}
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// isConnRefused reports whether a dial failed because nothing is listening
+func isConnRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
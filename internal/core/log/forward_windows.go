@@ -0,0 +1,23 @@
+//go:build windows
+
+package log
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// wsaeConnRefused is WSAECONNREFUSED, which syscall doesn't define
+const wsaeConnRefused = syscall.Errno(10061)
+
+// listenUnix creates the socket at address. Windows has no file modes; the
+// socket inherits the directory's ACL.
+func listenUnix(address string) (net.Listener, error) {
+	return net.Listen("unix", address)
+}
+
+// isConnRefused reports whether a dial failed because nothing is listening
+func isConnRefused(err error) bool {
+	return errors.Is(err, wsaeConnRefused)
+}
@@ -0,0 +1,127 @@
+package log
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxLinksPerLine bounds the links extracted from one line
+const maxLinksPerLine = 50
+
+// Link is a URL or file reference found in a log line
+type Link struct {
+	Kind string `json:"kind"` // "url" or "file"
+	Text string `json:"text"` // As it appears in the line
+
+	// Start and End delimit Text in UTF-16 code units, the way JavaScript
+	// indexes strings, so the frontend can slice the line directly
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	URL    string `json:"url,omitempty"`
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// urlPattern matches web URLs up to whitespace, quotes or a control code
+var urlPattern = regexp.MustCompile("https?://[^\\s<>\"'`\\x00-\\x1f]+")
+
+// fileRefPattern matches a path with an extension followed by :line and an
+// optional :column, as in Ruby backtraces ("app/models/user.rb:12:in ...")
+// and Node stack frames ("at handler (/app/src/server.js:40:7)")
+var fileRefPattern = regexp.MustCompile(`((?:\.{0,2}/)?(?:[\w@+.-]+/)*[\w@+-][\w@+.-]*\.[A-Za-z]\w*):(\d+)(?::(\d+))?`)
+
+// ExtractLinks finds the URLs and file:line references in a log line, in
+// the order they appear. File references are returned as written; whether
+// they name a real file is for the caller to check.
+func ExtractLinks(content string) []Link {
+	links := []Link{}
+	var urlSpans [][2]int
+
+	for _, loc := range urlPattern.FindAllStringIndex(content, maxLinksPerLine) {
+		start, end := loc[0], trimURLEnd(content, loc[0], loc[1])
+		urlSpans = append(urlSpans, [2]int{start, end})
+		links = append(links, Link{
+			Kind:  "url",
+			Text:  content[start:end],
+			Start: start,
+			End:   end,
+			URL:   content[start:end],
+		})
+	}
+
+	for _, loc := range fileRefPattern.FindAllStringSubmatchIndex(content, -1) {
+		if len(links) >= maxLinksPerLine {
+			break
+		}
+		// Paths and ports inside a URL are part of the URL link
+		if insideSpan(urlSpans, loc[0]) {
+			continue
+		}
+		line, err := strconv.Atoi(content[loc[4]:loc[5]])
+		if err != nil || line <= 0 {
+			continue
+		}
+		link := Link{
+			Kind:  "file",
+			Text:  content[loc[0]:loc[1]],
+			Start: loc[0],
+			End:   loc[1],
+			File:  content[loc[2]:loc[3]],
+			Line:  line,
+		}
+		if loc[6] >= 0 {
+			link.Column, _ = strconv.Atoi(content[loc[6]:loc[7]])
+		}
+		links = append(links, link)
+	}
+
+	sort.Slice(links, func(i, j int) bool { return links[i].Start < links[j].Start })
+	for i := range links {
+		links[i].Start = utf16Offset(content, links[i].Start)
+		links[i].End = utf16Offset(content, links[i].End)
+	}
+	return links
+}
+
+// trimURLEnd drops punctuation that ends the sentence around a URL rather
+// than the URL itself, and closing brackets the URL didn't open
+func trimURLEnd(content string, start, end int) int {
+	for end > start {
+		last := content[end-1]
+		switch {
+		case strings.IndexByte(".,;:!?", last) >= 0:
+			end--
+		case last == ')' && strings.Count(content[start:end], "(") < strings.Count(content[start:end], ")"):
+			end--
+		case last == ']' && strings.Count(content[start:end], "[") < strings.Count(content[start:end], "]"):
+			end--
+		default:
+			return end
+		}
+	}
+	return end
+}
+
+// insideSpan reports whether offset falls in one of spans
+func insideSpan(spans [][2]int, offset int) bool {
+	for _, span := range spans {
+		if offset >= span[0] && offset < span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// utf16Offset converts a byte offset into s to UTF-16 code units
+func utf16Offset(s string, offset int) int {
+	n := 0
+	for _, r := range s[:offset] {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
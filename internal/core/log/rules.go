@@ -0,0 +1,89 @@
+package log
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/caboose-desktop/internal/core/config"
+	"github.com/caboose-desktop/internal/models"
+)
+
+// ansiEscape matches the color codes rule patterns are not matched against
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// levelRule is a LogRule with its pattern compiled
+type levelRule struct {
+	pattern *regexp.Regexp
+	level   models.LogLevel
+	process string
+}
+
+// LevelRules reclassifies parsed log lines by the configured rules, so lines
+// that merely mention "error" or framework noise can be given the level they
+// deserve. It is read-only once built and safe for concurrent use.
+type LevelRules struct {
+	rules []levelRule
+}
+
+// ParseLevel checks a rule level name
+func ParseLevel(level string) (models.LogLevel, error) {
+	switch l := models.LogLevel(strings.ToLower(strings.TrimSpace(level))); l {
+	case models.LogLevelDebug, models.LogLevelInfo, models.LogLevelWarning, models.LogLevelError, models.LogLevelFatal:
+		return l, nil
+	case "warning":
+		return models.LogLevelWarning, nil
+	}
+	return "", fmt.Errorf("invalid log level %q (use debug, info, warn, error or fatal)", level)
+}
+
+// ValidateRule checks that a rule's pattern compiles and its level is known
+func ValidateRule(rule config.LogRule) error {
+	if rule.Pattern == "" {
+		return fmt.Errorf("pattern is required")
+	}
+	if _, err := regexp.Compile(rule.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	_, err := ParseLevel(rule.Level)
+	return err
+}
+
+// NewLevelRules compiles the rules, skipping any that are invalid
+func NewLevelRules(rules []config.LogRule) *LevelRules {
+	r := &LevelRules{}
+	for _, rule := range rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil || rule.Pattern == "" {
+			continue
+		}
+		level, err := ParseLevel(rule.Level)
+		if err != nil {
+			continue
+		}
+		r.rules = append(r.rules, levelRule{pattern: pattern, level: level, process: rule.Process})
+	}
+	return r
+}
+
+// Apply returns the level of the first rule matching the line from the
+// process, or level unchanged when none does
+func (r *LevelRules) Apply(process, line string, level models.LogLevel) models.LogLevel {
+	if r == nil || len(r.rules) == 0 {
+		return level
+	}
+
+	plain := line
+	if strings.IndexByte(line, 0x1b) >= 0 {
+		plain = ansiEscape.ReplaceAllString(line, "")
+	}
+	for _, rule := range r.rules {
+		if rule.process != "" && rule.process != process {
+			continue
+		}
+		if rule.pattern.MatchString(plain) {
+			return rule.level
+		}
+	}
+	return level
+}
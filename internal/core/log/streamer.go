@@ -2,6 +2,7 @@ package log
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/caboose-desktop/internal/models"
@@ -19,6 +20,7 @@ type Streamer struct {
 	// Subscribers for real-time updates
 	subscribers map[string]chan *models.LogEntry
 	subMu       sync.RWMutex
+	dropped     atomic.Int64 // Entries skipped for subscribers that were full
 
 	// Callback for new log entries
 	OnNewLog func(entry *models.LogEntry)
@@ -213,16 +215,33 @@ func (s *Streamer) Clear() {
 
 // Subscribe creates a subscription for real-time log updates
 func (s *Streamer) Subscribe() (string, <-chan *models.LogEntry) {
+	return s.SubscribeBuffered(100)
+}
+
+// SubscribeBuffered is Subscribe with room for size entries the subscriber
+// hasn't read yet; past that, entries are dropped for it
+func (s *Streamer) SubscribeBuffered(size int) (string, <-chan *models.LogEntry) {
 	s.subMu.Lock()
 	defer s.subMu.Unlock()
 
 	id := uuid.New().String()
-	ch := make(chan *models.LogEntry, 100)
+	ch := make(chan *models.LogEntry, size)
 	s.subscribers[id] = ch
 
 	return id, ch
 }
 
+// Broadcast sends an entry to the subscribers only, without keeping it in
+// the buffer or calling OnNewLog
+func (s *Streamer) Broadcast(entry *models.LogEntry) {
+	s.notifySubscribers(entry)
+}
+
+// Dropped returns how many entries subscribers missed because they were full
+func (s *Streamer) Dropped() int64 {
+	return s.dropped.Load()
+}
+
 // Unsubscribe removes a subscription
 func (s *Streamer) Unsubscribe(id string) {
 	s.subMu.Lock()
@@ -244,6 +263,7 @@ func (s *Streamer) notifySubscribers(entry *models.LogEntry) {
 		case ch <- entry:
 		default:
 			// Channel full, skip to avoid blocking
+			s.dropped.Add(1)
 		}
 	}
 }
@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/caboose-desktop/internal/core/config"
+)
+
+// Alert is a threshold rule that fired, or resolved
+type Alert struct {
+	// Rule is the rule's index in the configured alerts
+	Rule       int     `json:"rule"`
+	Metric     string  `json:"metric"`
+	Comparator string  `json:"comparator"`
+	Threshold  float64 `json:"threshold"`
+
+	// Value is the point that fired or resolved the alert
+	Value float64 `json:"value"`
+
+	// Since is when the condition started holding (RFC3339)
+	Since string `json:"since"`
+
+	// Timestamp is the time of the point that fired or resolved it (RFC3339)
+	Timestamp string `json:"timestamp"`
+}
+
+// alertState tracks one rule across points. Both edges are debounced: the
+// condition must hold for the rule's duration to fire, and stop holding for
+// as long to resolve.
+type alertState struct {
+	rule        config.MetricAlert
+	active      bool
+	breachSince time.Time // Zero while the condition doesn't hold
+	normalSince time.Time // Zero while it does
+	alert       Alert
+}
+
+// Alerter evaluates metric threshold rules against time series points
+type Alerter struct {
+	mu     sync.Mutex
+	states []*alertState
+}
+
+// NewAlerter creates an alerter with no rules
+func NewAlerter() *Alerter {
+	return &Alerter{}
+}
+
+// ValidateAlert checks an alert rule's metric and comparator
+func ValidateAlert(rule config.MetricAlert) error {
+	if _, ok := pointValue(TimeSeriesPoint{}, rule.Metric); !ok {
+		return fmt.Errorf("unknown metric: %s (use cpu, memory, requests, response_time or errors)", rule.Metric)
+	}
+	switch rule.Comparator {
+	case ">", ">=", "<", "<=":
+	default:
+		return fmt.Errorf("invalid comparator: %s (use >, >=, < or <=)", rule.Comparator)
+	}
+	if rule.Duration < 0 {
+		return fmt.Errorf("alert duration must not be negative")
+	}
+	return nil
+}
+
+// SetRules replaces the rules, skipping invalid ones. Rules that didn't
+// change keep their state, so reloading the config doesn't re-fire or
+// silently drop an active alert.
+func (a *Alerter) SetRules(rules []config.MetricAlert) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	previous := make(map[config.MetricAlert]*alertState, len(a.states))
+	for _, state := range a.states {
+		previous[state.rule] = state
+	}
+
+	a.states = a.states[:0:0]
+	for i, rule := range rules {
+		if ValidateAlert(rule) != nil {
+			continue
+		}
+		state, ok := previous[rule]
+		if ok {
+			delete(previous, rule)
+		} else {
+			state = &alertState{rule: rule}
+		}
+		state.alert.Rule = i
+		a.states = append(a.states, state)
+	}
+}
+
+// Evaluate checks a point against every rule and returns the alerts that
+// fired and those that resolved with it
+func (a *Alerter) Evaluate(point TimeSeriesPoint) (fired, resolved []Alert) {
+	at, err := time.Parse(time.RFC3339, point.Timestamp)
+	if err != nil {
+		at = time.Now()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, state := range a.states {
+		value, _ := pointValue(point, state.rule.Metric)
+		hold := time.Duration(state.rule.Duration) * time.Second
+
+		if breached(value, state.rule.Comparator, state.rule.Threshold) {
+			state.normalSince = time.Time{}
+			if state.breachSince.IsZero() {
+				state.breachSince = at
+			}
+			if !state.active && at.Sub(state.breachSince) >= hold {
+				state.active = true
+				state.alert.Metric = state.rule.Metric
+				state.alert.Comparator = state.rule.Comparator
+				state.alert.Threshold = state.rule.Threshold
+				state.alert.Since = state.breachSince.Format(time.RFC3339)
+				state.alert.Value = value
+				state.alert.Timestamp = point.Timestamp
+				fired = append(fired, state.alert)
+			}
+			continue
+		}
+
+		state.breachSince = time.Time{}
+		if !state.active {
+			continue
+		}
+		if state.normalSince.IsZero() {
+			state.normalSince = at
+		}
+		if at.Sub(state.normalSince) >= hold {
+			state.active = false
+			state.normalSince = time.Time{}
+			state.alert.Value = value
+			state.alert.Timestamp = point.Timestamp
+			resolved = append(resolved, state.alert)
+		}
+	}
+	return fired, resolved
+}
+
+// Active returns the alerts currently firing
+func (a *Alerter) Active() []Alert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	active := []Alert{}
+	for _, state := range a.states {
+		if state.active {
+			active = append(active, state.alert)
+		}
+	}
+	return active
+}
+
+// breached reports whether value crosses threshold
+func breached(value float64, comparator string, threshold float64) bool {
+	switch comparator {
+	case ">":
+		return value > threshold
+	case ">=":
+		return value >= threshold
+	case "<":
+		return value < threshold
+	case "<=":
+		return value <= threshold
+	}
+	return false
+}
+
+// pointValue reads a metric from a time series point
+func pointValue(point TimeSeriesPoint, metric string) (float64, bool) {
+	switch metric {
+	case "cpu":
+		return point.CPU, true
+	case "memory":
+		return point.Memory, true
+	case "requests":
+		return float64(point.Requests), true
+	case "response_time":
+		return point.ResponseTime, true
+	case "errors":
+		return float64(point.Errors), true
+	}
+	return 0, false
+}
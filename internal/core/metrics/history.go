@@ -0,0 +1,235 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// historyFlushEvery is how many new points are buffered before the history
+// is written, so a crash loses at most that many minutes
+const historyFlushEvery = 10
+
+// History persists time-series points to a JSONL file so charts survive
+// restarts. Points newer than the retention are kept at full (1-minute)
+// resolution; older ones are averaged into hourly points until they pass the
+// downsampled retention and are dropped.
+type History struct {
+	mu                   sync.Mutex
+	path                 string
+	retention            time.Duration
+	downsampledRetention time.Duration
+	points               []TimeSeriesPoint // Oldest first
+	pending              int               // Points added since the last flush
+}
+
+// OpenHistory loads the history stored at path, if any. Malformed lines are
+// skipped rather than failing the whole file.
+func OpenHistory(path string, retention, downsampledRetention time.Duration) (*History, error) {
+	if downsampledRetention < retention {
+		downsampledRetention = retention
+	}
+
+	h := &History{
+		path:                 path,
+		retention:            retention,
+		downsampledRetention: downsampledRetention,
+		points:               make([]TimeSeriesPoint, 0),
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metrics history: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var point TimeSeriesPoint
+		if err := json.Unmarshal(scanner.Bytes(), &point); err != nil {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, point.Timestamp); err != nil {
+			continue
+		}
+		h.points = append(h.points, point)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metrics history: %w", err)
+	}
+
+	sort.SliceStable(h.points, func(i, j int) bool {
+		return pointTime(h.points[i]).Before(pointTime(h.points[j]))
+	})
+	h.compact(time.Now())
+
+	return h, nil
+}
+
+// Add appends a point, flushing to disk every historyFlushEvery points
+func (h *History) Add(point TimeSeriesPoint) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.points = append(h.points, point)
+	h.pending++
+	if h.pending < historyFlushEvery {
+		return nil
+	}
+	return h.flushLocked()
+}
+
+// Since returns the points recorded at or after since, oldest first
+func (h *History) Since(since time.Time) []TimeSeriesPoint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	start := sort.Search(len(h.points), func(i int) bool {
+		return !pointTime(h.points[i]).Before(since)
+	})
+
+	result := make([]TimeSeriesPoint, len(h.points)-start)
+	copy(result, h.points[start:])
+	return result
+}
+
+// Recent returns the last n points, oldest first
+func (h *History) Recent(n int) []TimeSeriesPoint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	start := len(h.points) - n
+	if start < 0 {
+		start = 0
+	}
+
+	result := make([]TimeSeriesPoint, len(h.points)-start)
+	copy(result, h.points[start:])
+	return result
+}
+
+// Flush applies retention and writes the history to disk
+func (h *History) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.flushLocked()
+}
+
+// flushLocked rewrites the file through a temporary file so a crash mid-write
+// can't leave it truncated. Caller must hold h.mu.
+func (h *History) flushLocked() error {
+	h.compact(time.Now())
+
+	// SECURITY: Metrics history is private to the user
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return err
+	}
+
+	tmpPath := h.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, point := range h.points {
+		if err := encoder.Encode(point); err != nil {
+			file.Close()
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, h.path); err != nil {
+		return err
+	}
+
+	h.pending = 0
+	return nil
+}
+
+// compact drops points past the downsampled retention and averages points
+// past the retention into hourly points. Only whole hours are downsampled, so
+// an hour is never merged twice. Caller must hold h.mu.
+func (h *History) compact(now time.Time) {
+	dropBefore := now.Add(-h.downsampledRetention)
+	downsampleBefore := now.Add(-h.retention).Truncate(time.Hour)
+
+	compacted := make([]TimeSeriesPoint, 0, len(h.points))
+	var bucket []TimeSeriesPoint
+	var bucketHour time.Time
+
+	for _, point := range h.points {
+		ts := pointTime(point)
+		if ts.Before(dropBefore) {
+			continue
+		}
+		if !ts.Before(downsampleBefore) {
+			compacted = append(compacted, point)
+			continue
+		}
+
+		hour := ts.Truncate(time.Hour)
+		if len(bucket) > 0 && !hour.Equal(bucketHour) {
+			compacted = append(compacted, average(bucketHour, bucket))
+			bucket = bucket[:0]
+		}
+		bucketHour = hour
+		bucket = append(bucket, point)
+	}
+	if len(bucket) > 0 {
+		compacted = append(compacted, average(bucketHour, bucket))
+	}
+
+	// Downsampled points all precede the full-resolution ones
+	sort.SliceStable(compacted, func(i, j int) bool {
+		return pointTime(compacted[i]).Before(pointTime(compacted[j]))
+	})
+	h.points = compacted
+}
+
+// average merges the points of one hour: gauges are averaged and counts summed
+func average(hour time.Time, points []TimeSeriesPoint) TimeSeriesPoint {
+	merged := TimeSeriesPoint{
+		Time:      hour.Local().Format("15:04"),
+		Timestamp: hour.Format(time.RFC3339),
+	}
+	for _, p := range points {
+		merged.CPU += p.CPU
+		merged.Memory += p.Memory
+		merged.ResponseTime += p.ResponseTime
+		merged.Requests += p.Requests
+		merged.Errors += p.Errors
+	}
+
+	n := float64(len(points))
+	merged.CPU /= n
+	merged.Memory /= n
+	merged.ResponseTime /= n
+	return merged
+}
+
+// pointTime returns a point's timestamp; points are validated when loaded
+func pointTime(point TimeSeriesPoint) time.Time {
+	ts, _ := time.Parse(time.RFC3339, point.Timestamp)
+	return ts
+}
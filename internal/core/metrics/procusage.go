@@ -0,0 +1,7 @@
+package metrics
+
+// ProcessUsage is a process's CPU and resident memory use
+type ProcessUsage struct {
+	CPU    float64 // Percent of one core
+	Memory int64   // Resident set size in bytes
+}
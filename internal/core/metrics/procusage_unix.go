@@ -0,0 +1,46 @@
+//go:build !windows
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ProcessesUsage reads the CPU and memory use of the given processes with a
+// single ps call. Processes that have exited are missing from the result.
+func ProcessesUsage(pids []int) (map[int]ProcessUsage, error) {
+	usage := make(map[int]ProcessUsage, len(pids))
+	if len(pids) == 0 {
+		return usage, nil
+	}
+
+	list := make([]string, len(pids))
+	for i, pid := range pids {
+		list[i] = strconv.Itoa(pid)
+	}
+	// ps exits 1 when some of the pids are gone but still lists the others
+	out, err := exec.Command("ps", "-o", "pid=,%cpu=,rss=", "-p", strings.Join(list, ",")).Output()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("failed to run ps: %w", err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		cpu, _ := strconv.ParseFloat(fields[1], 64)
+		rssKB, _ := strconv.ParseInt(fields[2], 10, 64)
+		usage[pid] = ProcessUsage{CPU: cpu, Memory: rssKB * 1024}
+	}
+	return usage, nil
+}
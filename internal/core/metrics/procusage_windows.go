@@ -0,0 +1,10 @@
+//go:build windows
+
+package metrics
+
+import "errors"
+
+// ProcessesUsage isn't available on Windows
+func ProcessesUsage(pids []int) (map[int]ProcessUsage, error) {
+	return nil, errors.New("process usage is not supported on this platform")
+}
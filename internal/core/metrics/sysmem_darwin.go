@@ -0,0 +1,48 @@
+//go:build darwin
+
+package metrics
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var vmStatLine = regexp.MustCompile(`^Pages (free|inactive|speculative|purgeable):\s+(\d+)`)
+var vmStatPageSize = regexp.MustCompile(`page size of (\d+) bytes`)
+
+// systemMemoryUsage returns the percentage of physical memory in use, from
+// sysctl hw.memsize and the free, inactive and speculative pages of vm_stat
+func systemMemoryUsage() (float64, error) {
+	out, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read memory size: %w", err)
+	}
+	total, err := strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil || total == 0 {
+		return 0, fmt.Errorf("invalid memory size: %q", out)
+	}
+
+	out, err = exec.Command("vm_stat").Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read vm_stat: %w", err)
+	}
+	pageSize := uint64(4096)
+	if m := vmStatPageSize.FindSubmatch(out); m != nil {
+		pageSize, _ = strconv.ParseUint(string(m[1]), 10, 64)
+	}
+
+	var availablePages uint64
+	for _, line := range strings.Split(string(out), "\n") {
+		// Purgeable pages are already counted as inactive or free
+		if m := vmStatLine.FindStringSubmatch(line); m != nil && m[1] != "purgeable" {
+			pages, _ := strconv.ParseUint(m[2], 10, 64)
+			availablePages += pages
+		}
+	}
+
+	available := min(availablePages*pageSize, total)
+	return float64(total-available) / float64(total) * 100, nil
+}
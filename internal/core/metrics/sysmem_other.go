@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package metrics
+
+import "errors"
+
+// systemMemoryUsage isn't available on this platform
+func systemMemoryUsage() (float64, error) {
+	return 0, errors.New("system memory usage is not supported on this platform")
+}
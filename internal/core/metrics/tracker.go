@@ -15,6 +15,8 @@ type SystemMetrics struct {
 	MemoryAllocMB    float64 `json:"memoryAllocMB"`
 	MemorySysMB      float64 `json:"memorySysMB"`
 	NumGC            uint32  `json:"numGC"`
+	// SystemMemory is the percent of physical memory in use, 0 if unknown
+	SystemMemory     float64 `json:"systemMemory"`
 }
 
 // RequestMetrics represents HTTP request metrics
@@ -29,6 +31,7 @@ type RequestMetrics struct {
 // TimeSeriesPoint represents a single point in time series data
 type TimeSeriesPoint struct {
 	Time         string  `json:"time"`
+	Timestamp    string  `json:"timestamp"` // RFC3339, for history lookups
 	CPU          float64 `json:"cpu"`
 	Memory       float64 `json:"memory"`
 	Requests     int     `json:"requests"`
@@ -67,8 +70,18 @@ type Tracker struct {
 	endpointMetrics   map[string]*EndpointMetric
 	lastCPUTime       time.Time
 	lastNumRequests   int64
+	lastNumErrors     int64
+
+	// Last system memory reading; on macOS taking one forks two commands
+	sysMemMu  sync.Mutex
+	sysMem    float64
+	sysMemErr error
+	sysMemAt  time.Time
 }
 
+// systemMemoryTTL is how long a system memory reading is reused
+const systemMemoryTTL = 3 * time.Second
+
 // NewTracker creates a new metrics tracker
 func NewTracker() *Tracker {
 	return &Tracker{
@@ -145,6 +158,7 @@ func (t *Tracker) GetMetrics() *Metrics {
 		MemorySysMB:   float64(mem.Sys) / 1024 / 1024,
 		NumGC:         mem.NumGC,
 	}
+	system.SystemMemory, _ = t.SystemMemory()
 
 	// Calculate request metrics
 	var avgResponseTime float64
@@ -205,8 +219,8 @@ func (t *Tracker) GetMetrics() *Metrics {
 	}
 }
 
-// RecordTimeSeriesPoint adds a point to the time series
-func (t *Tracker) RecordTimeSeriesPoint() {
+// RecordTimeSeriesPoint adds a point to the time series and returns it
+func (t *Tracker) RecordTimeSeriesPoint() TimeSeriesPoint {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -217,6 +231,8 @@ func (t *Tracker) RecordTimeSeriesPoint() {
 	// Calculate requests in last minute
 	requestsLastMin := t.requestCount - t.lastNumRequests
 	t.lastNumRequests = t.requestCount
+	errorsLastMin := t.errorCount - t.lastNumErrors
+	t.lastNumErrors = t.errorCount
 
 	// Calculate avg response time for last minute
 	avgRT := 0.0
@@ -239,17 +255,45 @@ func (t *Tracker) RecordTimeSeriesPoint() {
 
 	point := TimeSeriesPoint{
 		Time:         now.Format("15:04"),
+		Timestamp:    now.Format(time.RFC3339),
 		CPU:          t.calculateCPU(),
 		Memory:       float64(mem.Alloc) / float64(mem.Sys) * 100,
 		Requests:     int(requestsLastMin),
 		ResponseTime: avgRT,
-		Errors:       0, // Would need to track errors per minute
+		Errors:       int(errorsLastMin),
 	}
 
 	t.timeSeries = append(t.timeSeries, point)
 	if len(t.timeSeries) > t.maxTimeSeriesSize {
 		t.timeSeries = t.timeSeries[1:]
 	}
+
+	return point
+}
+
+// SeedTimeSeries replaces the time series with previously recorded points,
+// e.g. from History, so the chart continues across restarts
+func (t *Tracker) SeedTimeSeries(points []TimeSeriesPoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(points) > t.maxTimeSeriesSize {
+		points = points[len(points)-t.maxTimeSeriesSize:]
+	}
+	t.timeSeries = append(make([]TimeSeriesPoint, 0, len(points)), points...)
+}
+
+// SystemMemory returns the percentage of the machine's physical memory in
+// use, as read at most systemMemoryTTL ago
+func (t *Tracker) SystemMemory() (float64, error) {
+	t.sysMemMu.Lock()
+	defer t.sysMemMu.Unlock()
+
+	if t.sysMemAt.IsZero() || time.Since(t.sysMemAt) > systemMemoryTTL {
+		t.sysMem, t.sysMemErr = systemMemoryUsage()
+		t.sysMemAt = time.Now()
+	}
+	return t.sysMem, t.sysMemErr
 }
 
 // calculateCPU estimates CPU usage (simplified)
@@ -277,6 +321,8 @@ func (t *Tracker) Reset() {
 	t.startTime = time.Now()
 	t.requestCount = 0
 	t.errorCount = 0
+	t.lastNumRequests = 0
+	t.lastNumErrors = 0
 	t.responseTimes = make([]float64, 0)
 	t.timeSeries = make([]TimeSeriesPoint, 0)
 	t.endpointMetrics = make(map[string]*EndpointMetric)
@@ -0,0 +1,212 @@
+package process
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// colorBackgrounds are the backgrounds process colors are drawn on: the
+// terminal (DARK_THEME in XTerminal.tsx) and the log viewer under each app
+// theme (--color-background in index.css). Keep them in sync with the
+// frontend.
+var colorBackgrounds = []struct{ name, color string }{
+	{"terminal", "#0a0a0a"},
+	{"default", "#030712"},
+	{"tokyo-night", "#1a1b26"},
+	{"dracula", "#282a36"},
+	{"nord", "#2e3440"},
+	{"solarized-dark", "#002b36"},
+	{"catppuccin", "#1e1e2e"},
+}
+
+// minColorContrast is the WCAG contrast ratio a process color needs against
+// every background, the level asked of non-text UI and large text
+const minColorContrast = 3.0
+
+// minHueDistance is how far apart, in degrees, a suggested color's hue should
+// be from the colors in use for the two to be told apart at a glance
+const minHueDistance = 30.0
+
+// ParseHexColor parses a #rgb or #rrggbb color
+func ParseHexColor(color string) (r, g, b uint8, err error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(color), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 || !strings.HasPrefix(strings.TrimSpace(color), "#") {
+		return 0, 0, 0, fmt.Errorf("invalid color %q: use #rrggbb", color)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid color %q: use #rrggbb", color)
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), nil
+}
+
+// ValidateColor checks a process color is a hex color readable on the
+// terminal and on every theme's background
+func ValidateColor(color string) error {
+	r, g, b, err := ParseHexColor(color)
+	if err != nil {
+		return err
+	}
+	if ratio, background := lowestContrast(r, g, b); ratio < minColorContrast {
+		return fmt.Errorf("color %s is too dark to read on the %s background (contrast %.1f, need %.1f)", color, background, ratio, minColorContrast)
+	}
+	return nil
+}
+
+// lowestContrast returns a color's contrast with the background it is
+// hardest to read on, and that background's name
+func lowestContrast(r, g, b uint8) (float64, string) {
+	lowest, name := math.Inf(1), ""
+	for _, background := range colorBackgrounds {
+		br, bg, bb, _ := ParseHexColor(background.color)
+		if ratio := contrastRatio(r, g, b, br, bg, bb); ratio < lowest {
+			lowest, name = ratio, background.name
+		}
+	}
+	return lowest, name
+}
+
+// SuggestColor picks a color for a new process that is readable and easy to
+// tell apart from the processes' current colors: the first palette color
+// whose hue is far enough from all of them, or else the hue furthest from
+// any in use
+func (m *Manager) SuggestColor() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return suggestColor(m.usedColors())
+}
+
+// usedColors returns the colors of the managed processes. Caller must hold m.mu.
+func (m *Manager) usedColors() []string {
+	used := make([]string, 0, len(m.processes))
+	for _, mp := range m.processes {
+		used = append(used, mp.Config.Color)
+	}
+	return used
+}
+
+// suggestColor picks a color distinct from the used ones
+func suggestColor(used []string) string {
+	var hues []float64
+	for _, color := range used {
+		if r, g, b, err := ParseHexColor(color); err == nil {
+			if hue, saturated := hueOf(r, g, b); saturated {
+				hues = append(hues, hue)
+			}
+		}
+	}
+	distance := func(hue float64) float64 {
+		nearest := 180.0
+		for _, h := range hues {
+			d := math.Abs(hue - h)
+			nearest = math.Min(nearest, math.Min(d, 360-d))
+		}
+		return nearest
+	}
+
+	for _, color := range templateColors {
+		r, g, b, _ := ParseHexColor(color)
+		if hue, _ := hueOf(r, g, b); distance(hue) >= minHueDistance {
+			return color
+		}
+	}
+
+	best, bestDistance := 0.0, -1.0
+	for hue := 0.0; hue < 360; hue += 5 {
+		if d := distance(hue); d > bestDistance {
+			best, bestDistance = hue, d
+		}
+	}
+	return readableColor(best)
+}
+
+// readableColor returns a saturated color of the given hue, lightened until
+// it reads on every background
+func readableColor(hue float64) string {
+	var r, g, b uint8
+	for lightness := 0.55; lightness <= 0.9; lightness += 0.05 {
+		r, g, b = hslToRGB(hue, 0.75, lightness)
+		if ratio, _ := lowestContrast(r, g, b); ratio >= minColorContrast {
+			break
+		}
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// hueOf returns a color's hue in degrees, and whether it is saturated
+// enough for its hue to be what tells it apart
+func hueOf(r, g, b uint8) (float64, bool) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	hi := math.Max(rf, math.Max(gf, bf))
+	lo := math.Min(rf, math.Min(gf, bf))
+	chroma := hi - lo
+	if chroma < 0.15 {
+		return 0, false
+	}
+
+	var hue float64
+	switch hi {
+	case rf:
+		hue = math.Mod((gf-bf)/chroma, 6)
+	case gf:
+		hue = (bf-rf)/chroma + 2
+	default:
+		hue = (rf-gf)/chroma + 4
+	}
+	hue *= 60
+	if hue < 0 {
+		hue += 360
+	}
+	return hue, true
+}
+
+// hslToRGB converts a hue in degrees and saturation and lightness in [0, 1]
+func hslToRGB(hue, saturation, lightness float64) (uint8, uint8, uint8) {
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	m := lightness - chroma/2
+
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g, b = chroma, x, 0
+	case hue < 120:
+		r, g, b = x, chroma, 0
+	case hue < 180:
+		r, g, b = 0, chroma, x
+	case hue < 240:
+		r, g, b = 0, x, chroma
+	case hue < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return uint8(math.Round((r + m) * 255)), uint8(math.Round((g + m) * 255)), uint8(math.Round((b + m) * 255))
+}
+
+// contrastRatio is the WCAG contrast ratio between two colors, from 1 to 21
+func contrastRatio(r1, g1, b1, r2, g2, b2 uint8) float64 {
+	l1, l2 := luminance(r1, g1, b1), luminance(r2, g2, b2)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// luminance is a color's WCAG relative luminance
+func luminance(r, g, b uint8) float64 {
+	channel := func(c uint8) float64 {
+		v := float64(c) / 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(r) + 0.7152*channel(g) + 0.0722*channel(b)
+}
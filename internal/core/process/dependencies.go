@@ -0,0 +1,65 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+// StartOrder sorts processes so each comes after the processes it depends on,
+// breaking ties by name. Processes that depend on an unknown process, are part
+// of a dependency cycle or depend on such a process can't be ordered and are
+// returned in blocked with the reason instead.
+func StartOrder(processes []*models.Process) (order []string, blocked map[string]string) {
+	byName := make(map[string]*models.Process, len(processes))
+	names := make([]string, 0, len(processes))
+	for _, p := range processes {
+		byName[p.Name] = p
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+
+	blocked = make(map[string]string)
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(names))
+
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		switch state[name] {
+		case visiting:
+			blocked[name] = "dependency cycle"
+			return false
+		case visited:
+			_, failed := blocked[name]
+			return !failed
+		}
+		state[name] = visiting
+		defer func() { state[name] = visited }()
+
+		for _, dep := range byName[name].DependsOn {
+			if _, ok := byName[dep]; !ok {
+				blocked[name] = fmt.Sprintf("depends on unknown process %s", dep)
+				return false
+			}
+			if !visit(dep) {
+				if _, set := blocked[name]; !set {
+					blocked[name] = fmt.Sprintf("dependency %s can't be started", dep)
+				}
+				return false
+			}
+		}
+
+		order = append(order, name)
+		return true
+	}
+
+	for _, name := range names {
+		visit(name)
+	}
+
+	return order, blocked
+}
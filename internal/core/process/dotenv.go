@@ -0,0 +1,200 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Valid dotenv variable names
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// environ builds the environment for a process: the app environment, then the
+// process's dotenv files, then its explicit Environment map, so explicit
+// values win over files and files win over the inherited environment.
+func (mp *ManagedProcess) environ() ([]string, error) {
+	fileEnv, err := loadEnvFiles(mp.workDir, mp.Config.EnvFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	env := os.Environ()
+	for k, v := range fileEnv {
+		if _, explicit := mp.Config.Environment[k]; !explicit {
+			env = append(env, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
+	for k, v := range mp.Config.Environment {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	return env, nil
+}
+
+// loadEnvFiles reads dotenv files relative to workingDir. Later files override
+// earlier ones, and missing files are skipped so optional files like
+// .env.local can be listed. Files must stay inside workingDir.
+func loadEnvFiles(workingDir string, files []string) (map[string]string, error) {
+	env := make(map[string]string)
+	if len(files) == 0 {
+		return env, nil
+	}
+
+	root, err := filepath.Abs(workingDir)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	for _, file := range files {
+		path, err := resolveEnvFile(root, file)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read env file %s: %w", file, err)
+		}
+
+		values, err := parseDotenv(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("env file %s: %w", file, err)
+		}
+		for k, v := range values {
+			env[k] = v
+		}
+	}
+
+	return env, nil
+}
+
+// resolveEnvFile resolves a dotenv path against root and rejects paths that
+// escape it, including through symlinks
+func resolveEnvFile(root, file string) (string, error) {
+	if filepath.IsAbs(file) {
+		return "", fmt.Errorf("env file must be relative to the working directory: %s", file)
+	}
+
+	path := filepath.Join(root, file)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("env file is outside the working directory: %s", file)
+	}
+
+	return path, nil
+}
+
+// parseDotenv parses KEY=value lines. It supports `export` prefixes, comments,
+// single quotes (literal), and double quotes (escapes, may span lines).
+// Variable references are not expanded.
+func parseDotenv(data string) (map[string]string, error) {
+	env := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		eq := strings.Index(line, "=")
+		if eq == -1 {
+			return nil, fmt.Errorf("line %d: expected KEY=value", i+1)
+		}
+
+		key := strings.TrimSpace(line[:eq])
+		if !envKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", i+1, key)
+		}
+
+		value := strings.TrimSpace(line[eq+1:])
+		switch {
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end == -1 {
+				return nil, fmt.Errorf("line %d: unterminated single quote", i+1)
+			}
+			value = value[1 : end+1]
+
+		case strings.HasPrefix(value, `"`):
+			// Keep reading lines until the closing quote
+			start := i
+			raw := value[1:]
+			for {
+				if end := closingQuote(raw); end != -1 {
+					raw = raw[:end]
+					break
+				}
+				i++
+				if i >= len(lines) {
+					return nil, fmt.Errorf("line %d: unterminated double quote", start+1)
+				}
+				raw += "\n" + lines[i]
+			}
+			value = unescapeDotenv(raw)
+
+		default:
+			// Unquoted: an inline comment starts at " #"
+			if idx := strings.Index(value, " #"); idx != -1 {
+				value = strings.TrimSpace(value[:idx])
+			}
+		}
+
+		env[key] = value
+	}
+
+	return env, nil
+}
+
+// closingQuote returns the index of the first unescaped double quote, or -1
+func closingQuote(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++ // Skip the escaped character
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDotenv expands the escapes allowed in double-quoted values
+func unescapeDotenv(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '"', '\\':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package process
+
+import (
+	"sort"
+	"time"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+// logRateWindow is how many seconds output rates are averaged over
+const logRateWindow = 10
+
+// lineRate counts a process's output lines in one-second buckets covering
+// the last logRateWindow seconds
+type lineRate struct {
+	buckets  [logRateWindow]rateBucket
+	flooding bool // Over a flood threshold as of the last line
+}
+
+type rateBucket struct {
+	second int64
+	lines  int
+	errors int
+}
+
+func (r *lineRate) add(now time.Time, lines, errors int) {
+	second := now.Unix()
+	b := &r.buckets[second%logRateWindow]
+	if b.second != second {
+		*b = rateBucket{second: second}
+	}
+	b.lines += lines
+	b.errors += errors
+}
+
+// rates returns lines and error lines per second over the window
+func (r *lineRate) rates(now time.Time) (lines, errors float64) {
+	second := now.Unix()
+	for _, b := range r.buckets {
+		if second-b.second < logRateWindow {
+			lines += float64(b.lines)
+			errors += float64(b.errors)
+		}
+	}
+	return lines / logRateWindow, errors / logRateWindow
+}
+
+// SetLogFloodThresholds sets the output rates, in lines per second, above
+// which OnLogFlood is called. Zero disables a threshold.
+func (m *Manager) SetLogFloodThresholds(lineRate, errorRate float64) {
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+
+	m.floodLineRate = lineRate
+	m.floodErrorRate = errorRate
+}
+
+// RecordLogError counts a line already seen by OnLog as an error line. The
+// manager doesn't classify lines itself; the caller's parser does.
+func (m *Manager) RecordLogError(name string) {
+	m.mu.RLock()
+	mp, exists := m.processes[name]
+	m.mu.RUnlock()
+
+	if exists {
+		m.recordLines(mp, 0, 1)
+	}
+}
+
+// recordLines adds to a process's output counts and reports the moment it
+// goes over a flood threshold
+func (m *Manager) recordLines(mp *ManagedProcess, lines, errors int) {
+	now := time.Now()
+
+	m.statsMu.Lock()
+	mp.logRate.add(now, lines, errors)
+	lineRate, errorRate := mp.logRate.rates(now)
+	flooding := m.overFloodThreshold(lineRate, errorRate)
+	started := flooding && !mp.logRate.flooding
+	mp.logRate.flooding = flooding
+	m.statsMu.Unlock()
+
+	if started && m.OnLogFlood != nil {
+		m.OnLogFlood(models.ProcessLogStats{
+			Name:            mp.Config.Name,
+			LinesPerSecond:  lineRate,
+			ErrorsPerSecond: errorRate,
+			Flooding:        true,
+		})
+	}
+}
+
+// overFloodThreshold must be called with statsMu held
+func (m *Manager) overFloodThreshold(lineRate, errorRate float64) bool {
+	return (m.floodLineRate > 0 && lineRate > m.floodLineRate) ||
+		(m.floodErrorRate > 0 && errorRate > m.floodErrorRate)
+}
+
+// GetLogStats returns the current output rates of every process, by name
+func (m *Manager) GetLogStats() []models.ProcessLogStats {
+	m.mu.RLock()
+	processes := make([]*ManagedProcess, 0, len(m.processes))
+	for _, mp := range m.processes {
+		processes = append(processes, mp)
+	}
+	m.mu.RUnlock()
+
+	now := time.Now()
+	m.statsMu.Lock()
+	stats := make([]models.ProcessLogStats, 0, len(processes))
+	for _, mp := range processes {
+		lineRate, errorRate := mp.logRate.rates(now)
+		stats = append(stats, models.ProcessLogStats{
+			Name:            mp.Config.Name,
+			LinesPerSecond:  lineRate,
+			ErrorsPerSecond: errorRate,
+			Flooding:        m.overFloodThreshold(lineRate, errorRate),
+		})
+	}
+	m.statsMu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
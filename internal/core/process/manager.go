@@ -1,13 +1,18 @@
 package process
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"regexp"
 	"sync"
 	"time"
 
+	"github.com/caboose-desktop/internal/core/crash"
 	"github.com/caboose-desktop/internal/models"
 )
 
@@ -18,21 +23,66 @@ type Manager struct {
 	ctx       context.Context
 	cancel    context.CancelFunc
 
+	// projectDir is the root templated working directories expand against
+	projectDir string
+
 	// Callbacks for events
 	OnStatusChange  func(name string, status models.ProcessStatus)
-	OnLog           func(name string, line string)
+	OnLog           func(name string, line string, stream models.LogStream)
 	OnConsoleOutput func(name string, data string) // For interactive console raw output
+	OnStartTimeout  func(name string, timeout time.Duration)
+	OnLogFlood      func(stats models.ProcessLogStats) // A process went over a flood threshold
+	OnPortDetected  func(name string, port int)        // A process reported the port it bound
+	OnStartError    func(name string, err error)       // An auto-restart failed to start the process
+
+	statsMu        sync.Mutex // Guards output rates and flood thresholds
+	floodLineRate  float64
+	floodErrorRate float64
 }
 
+// maxProcessHistory is the number of lifecycle events kept per process
+const maxProcessHistory = 100
+
+// maxCapturedOutput bounds the start of the output kept for a one-off run.
+// Past it only the last maxScrollback bytes are kept, since that's where
+// summaries such as a test run's totals are.
+const maxCapturedOutput = 1024 * 1024
+
 // ManagedProcess wraps a process with management capabilities
 type ManagedProcess struct {
 	Config       models.ProcessConfig
 	Process      *models.Process
 	cmd          *exec.Cmd
 	pty          *os.File
+	stdout       io.ReadCloser
+	stderr       io.ReadCloser
+	outputWg     sync.WaitGroup // Plain-mode pipe readers; must finish before cmd.Wait
 	mu           sync.Mutex
 	restartCount int
 	lastRestart  time.Time
+	history      []models.ProcessEvent // Bounded to maxProcessHistory, oldest first
+	done         chan struct{}         // Closed when the current run has exited
+	oneShot      bool                  // Exiting is expected, not a crash
+	captureMu    sync.Mutex
+	captured     *bytes.Buffer  // Combined output of a one-off run
+	capturedTail byteRing       // Its end, once captured is full
+	readyPattern *regexp.Regexp // Health check log pattern, if any
+	readyMu      sync.Mutex
+	readySignal  chan struct{} // Set while waiting for readyPattern
+	logRate      lineRate      // Guarded by Manager.statsMu
+	scrollback   byteRing      // Raw PTY output for repainting a terminal
+	workDir      string        // WorkingDir of the current run, expanded
+	portMu       sync.Mutex
+	portDetected bool // Process.Port came from this run's output
+}
+
+// RunResult is the outcome of a one-off process run
+type RunResult struct {
+	Output    string `json:"output"`
+	ExitCode  int    `json:"exitCode"`
+	Truncated bool   `json:"truncated"` // Output exceeded maxCapturedOutput; its middle is left out
+	TimedOut  bool   `json:"timedOut"`
+	Duration  int64  `json:"duration"` // in milliseconds
 }
 
 // NewManager creates a new process manager
@@ -54,18 +104,33 @@ func (m *Manager) AddProcess(config models.ProcessConfig) error {
 		return fmt.Errorf("process %s already exists", config.Name)
 	}
 
+	readyPattern, err := compileHealthCheck(config.HealthCheck)
+	if err != nil {
+		return err
+	}
+
+	if config.Color == "" {
+		config.Color = suggestColor(m.usedColors())
+	}
+
 	m.processes[config.Name] = &ManagedProcess{
-		Config: config,
+		Config:       config,
+		readyPattern: readyPattern,
 		Process: &models.Process{
 			Name:        config.Name,
 			Command:     config.Command,
 			Args:        config.Args,
 			WorkingDir:  config.WorkingDir,
 			Environment: config.Environment,
+			EnvFiles:    config.EnvFiles,
 			Status:      models.ProcessStatusStopped,
 			AutoRestart: config.AutoRestart,
+			AutoStart:   config.AutoStart,
 			UsePTY:      config.UsePTY,
 			Color:       config.Color,
+			HealthCheck: config.HealthCheck,
+			DependsOn:   config.DependsOn,
+			Port:        config.Port,
 		},
 	}
 
@@ -93,11 +158,35 @@ func (m *Manager) startProcess(mp *ManagedProcess) error {
 	if mp.Process.Status == models.ProcessStatusRunning {
 		return fmt.Errorf("process %s is already running", mp.Config.Name)
 	}
+	if mp.Process.Status == models.ProcessStatusStarting {
+		return fmt.Errorf("process %s is already starting", mp.Config.Name)
+	}
+	if mp.Process.Status == models.ProcessStatusSuspended {
+		return fmt.Errorf("process %s is suspended, resume it instead", mp.Config.Name)
+	}
+
+	dir, err := m.resolveWorkingDir(mp)
+	if err != nil {
+		return err
+	}
+	mp.workDir = dir
+
+	// Fail up front rather than with "Address already in use" in the logs
+	if mp.Config.Port > 0 {
+		if err := checkPortFree(mp.Config.Name, mp.Config.Port); err != nil {
+			return err
+		}
+	}
+
+	mp.portMu.Lock()
+	mp.Process.Port = mp.Config.Port
+	mp.portDetected = false
+	mp.portMu.Unlock()
 
 	mp.Process.Status = models.ProcessStatusStarting
+	mp.done = make(chan struct{})
 	m.emitStatusChange(mp.Config.Name, models.ProcessStatusStarting)
 
-	var err error
 	if mp.Config.UsePTY {
 		err = m.startWithPTY(mp)
 	} else {
@@ -105,16 +194,29 @@ func (m *Manager) startProcess(mp *ManagedProcess) error {
 	}
 
 	if err != nil {
+		close(mp.done)
 		mp.Process.Status = models.ProcessStatusCrashed
+		mp.recordEvent(models.ProcessEvent{Type: models.ProcessEventCrashed, Message: err.Error()})
 		m.emitStatusChange(mp.Config.Name, models.ProcessStatusCrashed)
 		return err
 	}
 
-	mp.Process.Status = models.ProcessStatusRunning
 	now := time.Now()
 	mp.Process.StartedAt = &now
 	mp.Process.PID = mp.cmd.Process.Pid
-	m.emitStatusChange(mp.Config.Name, models.ProcessStatusRunning)
+	mp.recordEvent(models.ProcessEvent{Type: models.ProcessEventStarted, PID: mp.Process.PID})
+
+	// A health-checked process stays starting until the watchdog sees it ready
+	if mp.Config.HealthCheck != nil {
+		signal := make(chan struct{}, 1)
+		mp.readyMu.Lock()
+		mp.readySignal = signal
+		mp.readyMu.Unlock()
+		go m.watchStartup(mp, mp.done, signal)
+	} else {
+		mp.Process.Status = models.ProcessStatusRunning
+		m.emitStatusChange(mp.Config.Name, models.ProcessStatusRunning)
+	}
 
 	// Start output reader goroutine
 	go m.readOutput(mp)
@@ -128,15 +230,34 @@ func (m *Manager) startProcess(mp *ManagedProcess) error {
 // startPlain starts a process without PTY
 func (m *Manager) startPlain(mp *ManagedProcess) error {
 	mp.cmd = exec.CommandContext(m.ctx, mp.Config.Command, mp.Config.Args...)
-	mp.cmd.Dir = mp.Config.WorkingDir
+	mp.cmd.Dir = mp.workDir
 
 	// Set environment
-	mp.cmd.Env = os.Environ()
-	for k, v := range mp.Config.Environment {
-		mp.cmd.Env = append(mp.cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	env, err := mp.environ()
+	if err != nil {
+		return err
+	}
+	mp.cmd.Env = env
+	setProcessGroup(mp.cmd)
+
+	// Keep stdout and stderr separate so log entries know their origin
+	stdout, err := mp.cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open stdout: %w", err)
+	}
+	stderr, err := mp.cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open stderr: %w", err)
 	}
 
-	return mp.cmd.Start()
+	if err := mp.cmd.Start(); err != nil {
+		return err
+	}
+
+	mp.stdout = stdout
+	mp.stderr = stderr
+	mp.outputWg.Add(2)
+	return nil
 }
 
 // Stop stops a process by name
@@ -157,9 +278,11 @@ func (m *Manager) stopProcess(mp *ManagedProcess) error {
 	mp.mu.Lock()
 	defer mp.mu.Unlock()
 
-	if mp.Process.Status != models.ProcessStatusRunning {
+	if mp.Process.Status != models.ProcessStatusRunning && mp.Process.Status != models.ProcessStatusStarting &&
+		mp.Process.Status != models.ProcessStatusSuspended {
 		return nil
 	}
+	suspended := mp.Process.Status == models.ProcessStatusSuspended
 
 	mp.Process.Status = models.ProcessStatusStopping
 	m.emitStatusChange(mp.Config.Name, models.ProcessStatusStopping)
@@ -167,6 +290,10 @@ func (m *Manager) stopProcess(mp *ManagedProcess) error {
 	// Try graceful shutdown first
 	if mp.cmd != nil && mp.cmd.Process != nil {
 		mp.cmd.Process.Signal(os.Interrupt)
+		// A stopped process only handles the interrupt once it's continued
+		if suspended {
+			resumeSignal(mp.cmd.Process)
+		}
 
 		// Wait for graceful shutdown with timeout
 		done := make(chan error, 1)
@@ -186,6 +313,7 @@ func (m *Manager) stopProcess(mp *ManagedProcess) error {
 	mp.Process.Status = models.ProcessStatusStopped
 	mp.Process.StartedAt = nil
 	mp.Process.PID = 0
+	mp.recordEvent(models.ProcessEvent{Type: models.ProcessEventStopped})
 	m.emitStatusChange(mp.Config.Name, models.ProcessStatusStopped)
 
 	return nil
@@ -234,7 +362,8 @@ func (m *Manager) RemoveProcess(name string) error {
 	}
 
 	// Make sure process is stopped before removing
-	if mp.Process.Status == models.ProcessStatusRunning {
+	if mp.Process.Status == models.ProcessStatusRunning || mp.Process.Status == models.ProcessStatusStarting ||
+		mp.Process.Status == models.ProcessStatusSuspended {
 		return fmt.Errorf("cannot remove running process %s, stop it first", name)
 	}
 
@@ -247,7 +376,11 @@ func (m *Manager) monitorProcess(mp *ManagedProcess) {
 	if mp.cmd == nil {
 		return
 	}
+	defer close(mp.done)
+	defer crash.Guard("process monitor: " + mp.Config.Name)
 
+	// Wait must not be called until the pipe readers have drained
+	mp.outputWg.Wait()
 	err := mp.cmd.Wait()
 
 	mp.mu.Lock()
@@ -261,11 +394,24 @@ func (m *Manager) monitorProcess(mp *ManagedProcess) {
 	}
 	mp.Process.ExitCode = &exitCode
 
-	if mp.Process.Status == models.ProcessStatusStopping {
+	// stopProcess holds the lock until the process is gone, so by the time we
+	// get here an expected stop may already be marked stopped
+	if mp.Process.Status == models.ProcessStatusStopping || mp.Process.Status == models.ProcessStatusStopped ||
+		mp.Process.Status == models.ProcessStatusStartTimeout {
 		return // Expected stop
 	}
 
+	if mp.oneShot {
+		mp.Process.Status = models.ProcessStatusStopped
+		mp.Process.StartedAt = nil
+		mp.Process.PID = 0
+		mp.recordEvent(models.ProcessEvent{Type: models.ProcessEventStopped, ExitCode: &exitCode})
+		m.emitStatusChange(mp.Config.Name, models.ProcessStatusStopped)
+		return
+	}
+
 	mp.Process.Status = models.ProcessStatusCrashed
+	mp.recordEvent(models.ProcessEvent{Type: models.ProcessEventCrashed, ExitCode: &exitCode})
 	m.emitStatusChange(mp.Config.Name, models.ProcessStatusCrashed)
 
 	// Handle auto-restart with exponential backoff
@@ -293,13 +439,164 @@ func (m *Manager) handleAutoRestart(mp *ManagedProcess) {
 	time.Sleep(backoff)
 	mp.lastRestart = time.Now()
 
-	m.startProcess(mp)
+	mp.mu.Lock()
+	mp.Process.RestartCount++
+	mp.recordEvent(models.ProcessEvent{
+		Type:    models.ProcessEventRestarted,
+		Message: fmt.Sprintf("auto-restart after %s backoff", backoff),
+	})
+	mp.mu.Unlock()
+
+	if err := m.startProcess(mp); err != nil {
+		mp.mu.Lock()
+		mp.recordEvent(models.ProcessEvent{
+			Type:    models.ProcessEventCrashed,
+			Message: "auto-restart failed: " + err.Error(),
+		})
+		mp.mu.Unlock()
+		if m.OnStartError != nil {
+			m.OnStartError(mp.Config.Name, err)
+		}
+	}
+}
+
+// RunOnce adds a process, runs it to completion and removes it, returning its
+// combined stdout/stderr and exit code. Lines are still emitted through OnLog
+// while it runs. The process is stopped if it outlives timeout.
+func (m *Manager) RunOnce(config models.ProcessConfig, timeout time.Duration) (*RunResult, error) {
+	config.AutoRestart = false
+	config.UsePTY = false // Plain pipes so output can be captured
+
+	if err := m.AddProcess(config); err != nil {
+		return nil, err
+	}
+	defer m.RemoveProcess(config.Name)
+
+	m.mu.RLock()
+	mp := m.processes[config.Name]
+	m.mu.RUnlock()
+
+	mp.oneShot = true
+	mp.captured = &bytes.Buffer{}
+
+	started := time.Now()
+	if err := m.startProcess(mp); err != nil {
+		return nil, err
+	}
+
+	result := &RunResult{}
+	select {
+	case <-mp.done:
+	case <-time.After(timeout):
+		result.TimedOut = true
+		m.stopProcess(mp)
+		<-mp.done
+	}
+	result.Duration = time.Since(started).Milliseconds()
+
+	mp.mu.Lock()
+	if result.TimedOut {
+		result.ExitCode = -1 // Killed; there is no meaningful exit status
+	} else if mp.Process.ExitCode != nil {
+		result.ExitCode = *mp.Process.ExitCode
+	}
+	mp.mu.Unlock()
+
+	mp.captureMu.Lock()
+	result.Output = mp.captured.String()
+	result.Truncated = mp.captured.Len() >= maxCapturedOutput
+	if tail := mp.capturedTail.Tail(0); len(tail) > 0 {
+		result.Output += "\n[... output truncated ...]\n" + string(tail)
+	}
+	mp.captureMu.Unlock()
+
+	return result, nil
+}
+
+// capture appends a line to the one-off output buffer, if capturing
+func (mp *ManagedProcess) capture(line string) {
+	mp.captureMu.Lock()
+	defer mp.captureMu.Unlock()
+
+	if mp.captured == nil {
+		return
+	}
+	if mp.captured.Len() >= maxCapturedOutput {
+		mp.capturedTail.Write([]byte(line + "\n"))
+		return
+	}
+	if remaining := maxCapturedOutput - mp.captured.Len(); len(line)+1 > remaining {
+		mp.captured.WriteString(line[:remaining])
+		return
+	}
+	mp.captured.WriteString(line)
+	mp.captured.WriteByte('\n')
 }
 
-// readOutput reads process output and emits log events
+// GetHistory returns the recorded lifecycle events for a process, oldest first
+func (m *Manager) GetHistory(name string) ([]models.ProcessEvent, error) {
+	m.mu.RLock()
+	mp, exists := m.processes[name]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("process %s not found", name)
+	}
+
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	history := make([]models.ProcessEvent, len(mp.history))
+	copy(history, mp.history)
+	return history, nil
+}
+
+// recordEvent appends a lifecycle event, dropping the oldest beyond
+// maxProcessHistory. Caller must hold mp.mu.
+func (mp *ManagedProcess) recordEvent(event models.ProcessEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	mp.history = append(mp.history, event)
+	if len(mp.history) > maxProcessHistory {
+		mp.history = mp.history[len(mp.history)-maxProcessHistory:]
+	}
+}
+
+// readOutput reads plain-mode stdout and stderr and emits log events.
+// PTY output is handled by readPTYOutput.
 func (m *Manager) readOutput(mp *ManagedProcess) {
-	// Implementation depends on PTY vs plain mode
-	// This is a placeholder for the output reading logic
+	if mp.pty != nil || mp.stdout == nil || mp.stderr == nil {
+		return
+	}
+
+	go m.readStream(mp, mp.stdout, models.LogStreamStdout)
+	go m.readStream(mp, mp.stderr, models.LogStreamStderr)
+}
+
+// readStream emits each line of a pipe as a log event until EOF
+func (m *Manager) readStream(mp *ManagedProcess, r io.Reader, stream models.LogStream) {
+	defer mp.outputWg.Done()
+	// Drain anything left after a scan error or a panic so the process
+	// doesn't block on a full pipe
+	defer io.Copy(io.Discard, r)
+	defer crash.Guard("process output: " + mp.Config.Name)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024) // Allow long lines (e.g. JSON logs)
+	for scanner.Scan() {
+		line := scanner.Text()
+		mp.capture(line)
+		mp.checkReady(line)
+		m.detectPort(mp, line)
+		if line != "" {
+			m.recordLines(mp, 1, 0)
+		}
+		if line != "" && m.OnLog != nil {
+			m.OnLog(mp.Config.Name, line, stream)
+		}
+	}
 }
 
 // emitStatusChange calls the status change callback if set
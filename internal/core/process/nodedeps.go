@@ -0,0 +1,60 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+// CheckNodeDependencies checks that the packages in package.json are in
+// node_modules, or returns nil when the project has no package.json. Only
+// direct dependencies are looked for, which is enough to tell a fresh
+// checkout or a newly added package.
+func CheckNodeDependencies(projectPath string) *models.DependencyStatus {
+	data, err := os.ReadFile(filepath.Join(projectPath, "package.json"))
+	if err != nil {
+		return nil
+	}
+
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(projectPath, name))
+		return err == nil
+	}
+	manager := packageManager(exists)
+	status := &models.DependencyStatus{
+		Manager:        manager,
+		Manifest:       "package.json",
+		InstallCommand: []string{manager, "install"},
+	}
+
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		status.Message = fmt.Sprintf("invalid package.json: %v", err)
+		return status
+	}
+	if _, err := exec.LookPath(manager); err != nil {
+		status.Message = fmt.Sprintf("%s is not installed", manager)
+		return status
+	}
+
+	for _, deps := range []map[string]string{pkg.Dependencies, pkg.DevDependencies} {
+		for name := range deps {
+			if !exists(filepath.Join("node_modules", filepath.FromSlash(name), "package.json")) {
+				status.Missing = append(status.Missing, name)
+			}
+		}
+	}
+	sort.Strings(status.Missing)
+
+	if len(status.Missing) == 0 {
+		status.Satisfied = true
+	} else if !exists("node_modules") {
+		status.Message = "node_modules is missing"
+	}
+	return status
+}
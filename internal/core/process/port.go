@@ -0,0 +1,104 @@
+package process
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PortInUseError is returned when a process's port is already taken before
+// it starts. PID and Command identify the listener when it could be found.
+type PortInUseError struct {
+	Process string `json:"process"`
+	Port    int    `json:"port"`
+	PID     int    `json:"pid,omitempty"`
+	Command string `json:"command,omitempty"`
+}
+
+func (e *PortInUseError) Error() string {
+	if e.PID == 0 {
+		return fmt.Sprintf("cannot start %s: port %d is already in use", e.Process, e.Port)
+	}
+	return fmt.Sprintf("cannot start %s: port %d is already in use by %s (pid %d)", e.Process, e.Port, e.Command, e.PID)
+}
+
+// checkPortFree fails with a PortInUseError if nothing could bind port.
+// Loopback is probed as well as the wildcard address: macOS lets a wildcard
+// bind succeed while a server holds the port on 127.0.0.1 only.
+func checkPortFree(name string, port int) error {
+	for _, host := range []string{"", "127.0.0.1"} {
+		listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err != nil {
+			conflict := &PortInUseError{Process: name, Port: port}
+			conflict.PID, conflict.Command, _ = FindPortOwner(port)
+			return conflict
+		}
+		listener.Close()
+	}
+	return nil
+}
+
+// FindPortOwner returns the PID and command name of the process listening on
+// a TCP port. It relies on lsof, so it fails where lsof isn't installed.
+func FindPortOwner(port int) (int, string, error) {
+	// -F pc prints "p<pid>" and "c<command>" lines
+	output, err := exec.Command("lsof", "-nP", "-iTCP:"+strconv.Itoa(port), "-sTCP:LISTEN", "-Fpc").Output()
+	if err != nil {
+		return 0, "", fmt.Errorf("no listener found on port %d", port)
+	}
+
+	pid, command := 0, ""
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "p") && pid == 0:
+			pid, _ = strconv.Atoi(line[1:])
+		case strings.HasPrefix(line, "c") && command == "":
+			command = line[1:]
+		}
+	}
+	if pid == 0 {
+		return 0, "", fmt.Errorf("no listener found on port %d", port)
+	}
+	return pid, command, nil
+}
+
+// KillPortOwner stops the process listening on a TCP port: an interrupt
+// first, then a kill if the port is still taken after 5 seconds. It returns
+// the PID it stopped.
+func KillPortOwner(port int) (int, error) {
+	pid, _, err := FindPortOwner(port)
+	if err != nil {
+		return 0, err
+	}
+	if pid == os.Getpid() {
+		return 0, fmt.Errorf("port %d is held by this app", port)
+	}
+
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.Signal(os.Interrupt); err != nil {
+		return 0, fmt.Errorf("failed to stop pid %d: %w", pid, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if owner, _, err := FindPortOwner(port); err != nil || owner != pid {
+			return pid, nil
+		}
+		time.Sleep(readinessPollInterval)
+	}
+
+	if err := p.Kill(); err != nil {
+		return 0, fmt.Errorf("failed to kill pid %d: %w", pid, err)
+	}
+	return pid, nil
+}
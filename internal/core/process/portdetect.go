@@ -0,0 +1,66 @@
+package process
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// outputEscape matches the color and cursor codes dev servers wrap their
+// banners in
+var outputEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// portPatterns recognize the lines common dev servers print once bound; the
+// first group is the port. Bare URLs elsewhere in the output (asset links,
+// proxied requests) don't count, only ones introduced as the listen address.
+var portPatterns = []*regexp.Regexp{
+	// Puma: "* Listening on http://127.0.0.1:3000", "Listening on tcp://0.0.0.0:3000"
+	// Node: "Listening at http://localhost:3000", "Server listening on 0.0.0.0:8080"
+	regexp.MustCompile(`(?i)\blistening (?:on|at)\s+(?:(?:https?|tcp)://)?(?:\[[0-9a-f:]*\]|[\w.-]+):(\d{1,5})\b`),
+	// Vite and Next.js: "➜  Local:   http://localhost:5173/"
+	// webpack-dev-server: "Loopback: http://localhost:8080/"
+	regexp.MustCompile(`(?i)\b(?:local|loopback):\s+https?://(?:\[[0-9a-f:]*\]|[\w.-]+):(\d{1,5})\b`),
+	// webpack-dev-server 3: "Project is running at http://localhost:8080/"
+	// Node: "Server running at http://127.0.0.1:3000/"
+	regexp.MustCompile(`(?i)\brunning (?:at|on):?\s+https?://(?:\[[0-9a-f:]*\]|[\w.-]+):(\d{1,5})\b`),
+	// Node: "Listening on port 3000", "Server started on port 4000"
+	regexp.MustCompile(`(?i)\b(?:listening|started|running|server)\b.*?\bon port:?\s+(\d{1,5})\b`),
+}
+
+// parseListenPort returns the port a dev server output line announces it is
+// listening on
+func parseListenPort(line string) (int, bool) {
+	plain := outputEscape.ReplaceAllString(line, "")
+	for _, pattern := range portPatterns {
+		match := pattern.FindStringSubmatch(plain)
+		if match == nil {
+			continue
+		}
+		port, err := strconv.Atoi(match[1])
+		if err == nil && port > 0 && port <= 65535 {
+			return port, true
+		}
+	}
+	return 0, false
+}
+
+// detectPort records the port the process reports binding to, from the
+// first matching output line of a run
+func (m *Manager) detectPort(mp *ManagedProcess, line string) {
+	mp.portMu.Lock()
+	if mp.portDetected {
+		mp.portMu.Unlock()
+		return
+	}
+	port, ok := parseListenPort(line)
+	if !ok {
+		mp.portMu.Unlock()
+		return
+	}
+	mp.portDetected = true
+	mp.Process.Port = port
+	mp.portMu.Unlock()
+
+	if m.OnPortDetected != nil {
+		m.OnPortDetected(mp.Config.Name, port)
+	}
+}
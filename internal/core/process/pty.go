@@ -3,22 +3,24 @@ package process
 import (
 	"fmt"
 	"io"
-	"os"
 	"os/exec"
 
+	"github.com/caboose-desktop/internal/core/crash"
+	"github.com/caboose-desktop/internal/models"
 	"github.com/creack/pty"
 )
 
 // startWithPTY starts a process with PTY support for interactive terminals
 func (m *Manager) startWithPTY(mp *ManagedProcess) error {
 	mp.cmd = exec.CommandContext(m.ctx, mp.Config.Command, mp.Config.Args...)
-	mp.cmd.Dir = mp.Config.WorkingDir
+	mp.cmd.Dir = mp.workDir
 
 	// Set environment
-	mp.cmd.Env = os.Environ()
-	for k, v := range mp.Config.Environment {
-		mp.cmd.Env = append(mp.cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	env, err := mp.environ()
+	if err != nil {
+		return err
 	}
+	mp.cmd.Env = env
 
 	// Start with PTY
 	ptmx, err := pty.Start(mp.cmd)
@@ -34,11 +36,35 @@ func (m *Manager) startWithPTY(mp *ManagedProcess) error {
 	return nil
 }
 
+// maxPartialLine bounds the unterminated line readPTYOutput holds back; a
+// longer one (e.g. a progress bar redrawn with \r) is emitted as it is
+const maxPartialLine = 64 * 1024
+
 // readPTYOutput reads output from the PTY and emits log events
 func (m *Manager) readPTYOutput(mp *ManagedProcess) {
 	if mp.pty == nil {
 		return
 	}
+	// Keep reading after a panic so the process doesn't block on a full PTY
+	defer io.Copy(io.Discard, mp.pty)
+	defer crash.Guard("process output: " + mp.Config.Name)
+
+	// A read can end mid-line; the rest of the line is held until its
+	// newline arrives, so log lines (and parsers of them) see it whole
+	var partial string
+	emitLines := func(lines []string) {
+		if m.OnLog == nil {
+			return
+		}
+		for _, line := range lines {
+			mp.checkReady(line)
+			m.detectPort(mp, line)
+			if line != "" {
+				m.recordLines(mp, 1, 0)
+				m.OnLog(mp.Config.Name, line, models.LogStreamPTY)
+			}
+		}
+	}
 
 	// Use a buffer for reading chunks (better for interactive console)
 	buf := make([]byte, 4096)
@@ -52,6 +78,7 @@ func (m *Manager) readPTYOutput(mp *ManagedProcess) {
 		}
 
 		if n > 0 {
+			mp.scrollback.Write(buf[:n])
 			data := string(buf[:n])
 
 			// Emit console output for interactive processes (like rails-console)
@@ -60,34 +87,30 @@ func (m *Manager) readPTYOutput(mp *ManagedProcess) {
 			}
 
 			// Also emit as log lines (split by newline for log viewer)
-			if m.OnLog != nil {
-				// For log compatibility, emit each line separately
-				lines := splitLines(data)
-				for _, line := range lines {
-					if line != "" {
-						m.OnLog(mp.Config.Name, line)
-					}
-				}
+			var lines []string
+			lines, partial = splitLines(partial + data)
+			if len(partial) > maxPartialLine {
+				lines, partial = append(lines, partial), ""
 			}
+			emitLines(lines)
 		}
 	}
+
+	if partial != "" {
+		emitLines([]string{partial})
+	}
 }
 
-// splitLines splits data into lines, handling partial lines
-func splitLines(data string) []string {
-	var lines []string
+// splitLines splits data into complete lines and the unterminated rest
+func splitLines(data string) (lines []string, rest string) {
 	start := 0
-	for i, c := range data {
-		if c == '\n' {
+	for i := 0; i < len(data); i++ {
+		if data[i] == '\n' {
 			lines = append(lines, data[start:i])
 			start = i + 1
 		}
 	}
-	// Include any remaining partial line
-	if start < len(data) {
-		lines = append(lines, data[start:])
-	}
-	return lines
+	return lines, data[start:]
 }
 
 // ResizePTY resizes the PTY window
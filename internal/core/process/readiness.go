@@ -0,0 +1,205 @@
+package process
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+const (
+	// defaultStartTimeout is how long a health-checked process has to become ready
+	defaultStartTimeout = 60 * time.Second
+
+	// readinessPollInterval is how often a health check port is probed
+	readinessPollInterval = 500 * time.Millisecond
+
+	// abortExitTimeout bounds waiting for a killed start's monitor to finish
+	abortExitTimeout = 5 * time.Second
+)
+
+// compileHealthCheck validates a health check and compiles its log pattern
+func compileHealthCheck(check *models.HealthCheck) (*regexp.Regexp, error) {
+	if check == nil {
+		return nil, nil
+	}
+	if check.Port == 0 && check.LogPattern == "" {
+		return nil, fmt.Errorf("health check needs a port or a log pattern")
+	}
+	if check.Port < 0 || check.Port > 65535 {
+		return nil, fmt.Errorf("invalid health check port: %d", check.Port)
+	}
+	if check.LogPattern == "" {
+		return nil, nil
+	}
+
+	pattern, err := regexp.Compile(check.LogPattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid health check log pattern: %w", err)
+	}
+	return pattern, nil
+}
+
+// startTimeout returns the configured readiness timeout
+func (mp *ManagedProcess) startTimeout() time.Duration {
+	if mp.Config.HealthCheck != nil && mp.Config.HealthCheck.Timeout > 0 {
+		return time.Duration(mp.Config.HealthCheck.Timeout) * time.Second
+	}
+	return defaultStartTimeout
+}
+
+// checkReady signals the startup watchdog when an output line matches the
+// health check pattern
+func (mp *ManagedProcess) checkReady(line string) {
+	if mp.readyPattern == nil {
+		return
+	}
+
+	mp.readyMu.Lock()
+	signal := mp.readySignal
+	mp.readyMu.Unlock()
+
+	if signal != nil && mp.readyPattern.MatchString(line) {
+		select {
+		case signal <- struct{}{}:
+		default: // Already signalled
+		}
+	}
+}
+
+// watchStartup waits for a health-checked process to become ready and marks
+// it running, or kills it if it doesn't within the timeout. It returns once
+// the process is ready, aborted or has exited.
+func (m *Manager) watchStartup(mp *ManagedProcess, done <-chan struct{}, signal <-chan struct{}) {
+	defer func() {
+		mp.readyMu.Lock()
+		mp.readySignal = nil
+		mp.readyMu.Unlock()
+	}()
+
+	timeout := mp.startTimeout()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	var poll <-chan time.Time
+	if mp.Config.HealthCheck.Port > 0 {
+		ticker := time.NewTicker(readinessPollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(mp.Config.HealthCheck.Port))
+
+	for {
+		select {
+		case <-done:
+			return // Exited while starting; monitorProcess reports it
+		case <-signal:
+			m.markReady(mp)
+			return
+		case <-poll:
+			if conn, err := net.DialTimeout("tcp", address, readinessPollInterval); err == nil {
+				conn.Close()
+				m.markReady(mp)
+				return
+			}
+		case <-deadline.C:
+			if m.abortStart(mp, models.ProcessStatusStartTimeout,
+				fmt.Sprintf("not ready after %s", timeout)) && m.OnStartTimeout != nil {
+				m.OnStartTimeout(mp.Config.Name, timeout)
+			}
+			return
+		}
+	}
+}
+
+// markReady moves a starting process to running
+func (m *Manager) markReady(mp *ManagedProcess) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	if mp.Process.Status != models.ProcessStatusStarting {
+		return
+	}
+	mp.Process.Status = models.ProcessStatusRunning
+	m.emitStatusChange(mp.Config.Name, models.ProcessStatusRunning)
+}
+
+// abortStart kills a process that is still starting and moves it to status.
+// It reports false if the process was no longer starting. Like Stop, it
+// returns once the process has exited and its monitor is done with it, so a
+// restart doesn't race the old run.
+func (m *Manager) abortStart(mp *ManagedProcess, status models.ProcessStatus, reason string) bool {
+	mp.mu.Lock()
+	if mp.Process.Status != models.ProcessStatusStarting {
+		mp.mu.Unlock()
+		return false
+	}
+
+	// Set the status first so monitorProcess treats the exit as expected
+	mp.Process.Status = status
+	if mp.cmd != nil && mp.cmd.Process != nil {
+		mp.cmd.Process.Kill()
+	}
+	mp.Process.StartedAt = nil
+	mp.Process.PID = 0
+	mp.recordEvent(models.ProcessEvent{Type: models.ProcessEventStopped, Message: reason})
+	m.emitStatusChange(mp.Config.Name, status)
+	done := mp.done
+	mp.mu.Unlock()
+
+	// monitorProcess takes the lock once the process exits, so wait unlocked
+	select {
+	case <-done:
+	case <-time.After(abortExitTimeout):
+	}
+	return true
+}
+
+// CancelStart kills a process that hasn't become ready yet
+func (m *Manager) CancelStart(name string) error {
+	m.mu.RLock()
+	mp, exists := m.processes[name]
+	m.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("process %s not found", name)
+	}
+
+	if !m.abortStart(mp, models.ProcessStatusStopped, "start cancelled") {
+		return fmt.Errorf("process %s is not starting", name)
+	}
+	return nil
+}
+
+// WaitReady waits until a started process is running. It fails if the process
+// leaves the starting state any other way, such as crashing or timing out.
+func (m *Manager) WaitReady(name string) error {
+	m.mu.RLock()
+	mp, exists := m.processes[name]
+	m.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("process %s not found", name)
+	}
+
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+
+	for {
+		mp.mu.Lock()
+		status := mp.Process.Status
+		mp.mu.Unlock()
+
+		switch status {
+		case models.ProcessStatusRunning:
+			return nil
+		case models.ProcessStatusStarting:
+			<-ticker.C
+		default:
+			return fmt.Errorf("process %s did not become ready (status %s)", name, status)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package process
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxScrollback is how many bytes of raw PTY output are kept per process
+const maxScrollback = 256 * 1024
+
+// byteRing keeps the last maxScrollback bytes written to it
+type byteRing struct {
+	mu   sync.Mutex
+	buf  []byte // Allocated on first write
+	head int    // Next write position once full
+	full bool
+}
+
+func (r *byteRing) Write(p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(p) >= maxScrollback {
+		r.buf = append(r.buf[:0], p[len(p)-maxScrollback:]...)
+		r.head = 0
+		r.full = true
+		return
+	}
+
+	if !r.full {
+		if len(r.buf)+len(p) <= maxScrollback {
+			r.buf = append(r.buf, p...)
+			return
+		}
+		// Fill up to capacity, then wrap
+		n := maxScrollback - len(r.buf)
+		r.buf = append(r.buf, p[:n]...)
+		p = p[n:]
+		r.full = true
+		r.head = 0
+	}
+
+	n := copy(r.buf[r.head:], p)
+	copy(r.buf, p[n:])
+	r.head = (r.head + len(p)) % maxScrollback
+}
+
+// Tail returns up to maxBytes of the most recent output
+func (r *byteRing) Tail(maxBytes int) []byte {
+	r.mu.Lock()
+	var data []byte
+	if r.full {
+		data = make([]byte, 0, maxScrollback)
+		data = append(data, r.buf[r.head:]...)
+		data = append(data, r.buf[:r.head]...)
+	} else {
+		data = append([]byte(nil), r.buf...)
+	}
+	dropped := r.full
+	r.mu.Unlock()
+
+	if maxBytes > 0 && len(data) > maxBytes {
+		data = data[len(data)-maxBytes:]
+		dropped = true
+	}
+	if !dropped {
+		return data
+	}
+
+	// The cut can land inside an escape sequence or a character; starting
+	// on a line boundary keeps the terminal from rendering garbage
+	if i := bytes.IndexByte(data, '\n'); i >= 0 && i < len(data)-1 {
+		return data[i+1:]
+	}
+	for len(data) > 0 && !utf8.RuneStart(data[0]) {
+		data = data[1:]
+	}
+	return data
+}
+
+// Scrollback returns up to maxBytes of a process's most recent raw PTY
+// output, ANSI sequences included, so a terminal can repaint its history.
+// A maxBytes of zero or less returns everything kept.
+func (m *Manager) Scrollback(name string, maxBytes int) ([]byte, error) {
+	m.mu.RLock()
+	mp, exists := m.processes[name]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("process %s not found", name)
+	}
+
+	return mp.scrollback.Tail(maxBytes), nil
+}
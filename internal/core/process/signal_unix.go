@@ -0,0 +1,39 @@
+//go:build !windows
+
+package process
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup makes cmd lead its own process group, so the children it
+// spawns (puma workers, foreman's processes, watchers) can be signalled with
+// it. PTY processes get one from the session pty.Start creates.
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// suspendSignal pauses a process and its process group until resumeSignal
+// is sent
+func suspendSignal(p *os.Process) error {
+	return signalGroup(p, syscall.SIGSTOP)
+}
+
+// resumeSignal continues a process group paused by suspendSignal
+func resumeSignal(p *os.Process) error {
+	return signalGroup(p, syscall.SIGCONT)
+}
+
+// signalGroup sends sig to the process group p leads, or to p alone if it
+// doesn't lead one
+func signalGroup(p *os.Process, sig syscall.Signal) error {
+	if pgid, err := syscall.Getpgid(p.Pid); err == nil && pgid == p.Pid {
+		return syscall.Kill(-pgid, sig)
+	}
+	return p.Signal(sig)
+}
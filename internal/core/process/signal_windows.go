@@ -0,0 +1,22 @@
+//go:build windows
+
+package process
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op; process groups are a Unix concept
+func setProcessGroup(cmd *exec.Cmd) {}
+
+var errSuspendUnsupported = errors.New("suspending processes is not supported on Windows")
+
+func suspendSignal(p *os.Process) error {
+	return errSuspendUnsupported
+}
+
+func resumeSignal(p *os.Process) error {
+	return errSuspendUnsupported
+}
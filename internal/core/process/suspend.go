@@ -0,0 +1,63 @@
+package process
+
+import (
+	"fmt"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+// Suspend pauses a running process, and the children in its process group,
+// without stopping it. It keeps its memory
+// but uses no CPU until resumed.
+func (m *Manager) Suspend(name string) error {
+	m.mu.RLock()
+	mp, exists := m.processes[name]
+	m.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("process %s not found", name)
+	}
+
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	if mp.Process.Status != models.ProcessStatusRunning || mp.cmd == nil || mp.cmd.Process == nil {
+		return fmt.Errorf("process %s is not running", name)
+	}
+	if err := suspendSignal(mp.cmd.Process); err != nil {
+		return fmt.Errorf("failed to suspend %s: %w", name, err)
+	}
+
+	mp.Process.Status = models.ProcessStatusSuspended
+	mp.recordEvent(models.ProcessEvent{Type: models.ProcessEventSuspended})
+	m.emitStatusChange(name, models.ProcessStatusSuspended)
+
+	return nil
+}
+
+// Resume continues a suspended process
+func (m *Manager) Resume(name string) error {
+	m.mu.RLock()
+	mp, exists := m.processes[name]
+	m.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("process %s not found", name)
+	}
+
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	if mp.Process.Status != models.ProcessStatusSuspended {
+		return fmt.Errorf("process %s is not suspended", name)
+	}
+	if err := resumeSignal(mp.cmd.Process); err != nil {
+		return fmt.Errorf("failed to resume %s: %w", name, err)
+	}
+
+	mp.Process.Status = models.ProcessStatusRunning
+	mp.recordEvent(models.ProcessEvent{Type: models.ProcessEventResumed})
+	m.emitStatusChange(name, models.ProcessStatusRunning)
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package process
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/caboose-desktop/internal/core/security"
+	"github.com/caboose-desktop/internal/models"
+)
+
+// templateColors are handed out in turn to detected templates
+var templateColors = []string{
+	"#22c55e", // green
+	"#3b82f6", // blue
+	"#eab308", // yellow
+	"#a855f7", // purple
+	"#06b6d4", // cyan
+	"#f97316", // orange
+	"#ec4899", // pink
+	"#14b8a6", // teal
+}
+
+// packageJSON is the part of package.json templates are detected from
+type packageJSON struct {
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+// tailwindInputs pairs common Tailwind entry stylesheets with their build output
+var tailwindInputs = [][2]string{
+	{"app/assets/stylesheets/application.tailwind.css", "app/assets/builds/application.css"},
+	{"src/input.css", "dist/output.css"},
+	{"src/styles.css", "dist/styles.css"},
+}
+
+// DetectTemplates inspects a project's package.json and build config for
+// processes worth offering: a template per npm script, run with the
+// project's package manager, and the dev servers and watchers of the
+// bundlers it depends on. Templates whose command or args wouldn't pass
+// AddProcess validation are left out.
+func DetectTemplates(projectPath string) []models.ProcessTemplate {
+	var templates []models.ProcessTemplate
+	add := func(t models.ProcessTemplate) {
+		if security.ValidateCommand(t.Command) != nil || security.ValidateArguments(t.Args) != nil {
+			return
+		}
+		t.Color = templateColors[len(templates)%len(templateColors)]
+		t.UsePTY = true
+		t.AutoRestart = true
+		t.Source = "project"
+		templates = append(templates, t)
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(projectPath, name))
+		return err == nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectPath, "package.json"))
+	if err != nil {
+		return templates
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return templates
+	}
+	hasDep := func(name string) bool {
+		_, inDeps := pkg.Dependencies[name]
+		_, inDevDeps := pkg.DevDependencies[name]
+		return inDeps || inDevDeps
+	}
+
+	manager := packageManager(exists)
+	scripts := make([]string, 0, len(pkg.Scripts))
+	for name := range pkg.Scripts {
+		scripts = append(scripts, name)
+	}
+	sort.Strings(scripts)
+	for _, name := range scripts {
+		add(models.ProcessTemplate{
+			ID:          manager + ":" + name,
+			Name:        name,
+			Description: pkg.Scripts[name],
+			Command:     manager,
+			Args:        []string{"run", name},
+		})
+	}
+
+	if hasDep("vite") {
+		add(models.ProcessTemplate{
+			ID:          "vite",
+			Name:        "vite",
+			Description: "Vite dev server",
+			Command:     "npx",
+			Args:        []string{"vite"},
+			Port:        5173,
+		})
+	}
+	if hasDep("webpack-dev-server") {
+		add(models.ProcessTemplate{
+			ID:          "webpack-dev-server",
+			Name:        "webpack",
+			Description: "Webpack dev server",
+			Command:     "npx",
+			Args:        []string{"webpack", "serve"},
+			Port:        8080,
+		})
+	}
+	if hasDep("esbuild") {
+		for _, config := range []string{"esbuild.config.mjs", "esbuild.config.js"} {
+			if exists(config) {
+				add(models.ProcessTemplate{
+					ID:          "esbuild",
+					Name:        "esbuild",
+					Description: "Rebuild JavaScript on change (" + config + ")",
+					Command:     "node",
+					Args:        []string{config, "--watch"},
+				})
+				break
+			}
+		}
+	}
+	if hasDep("tailwindcss") {
+		for _, paths := range tailwindInputs {
+			if exists(paths[0]) {
+				add(models.ProcessTemplate{
+					ID:          "tailwind",
+					Name:        "tailwind",
+					Description: "Rebuild Tailwind CSS on change",
+					Command:     "npx",
+					Args:        []string{"tailwindcss", "-i", "./" + paths[0], "-o", "./" + paths[1], "--watch"},
+				})
+				break
+			}
+		}
+	}
+
+	return templates
+}
+
+// packageManager picks npm, yarn or pnpm from the lockfile present
+func packageManager(exists func(string) bool) string {
+	switch {
+	case exists("pnpm-lock.yaml"):
+		return "pnpm"
+	case exists("yarn.lock"):
+		return "yarn"
+	}
+	return "npm"
+}
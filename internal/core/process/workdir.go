@@ -0,0 +1,99 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/caboose-desktop/internal/core/security"
+)
+
+// projectDirVar is replaced with the project root in a working directory
+const projectDirVar = "{{projectDir}}"
+
+// $VAR or ${VAR}
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// SetProjectDir sets the project root {{projectDir}} expands to and that
+// templated working directories must stay inside
+func (m *Manager) SetProjectDir(dir string) {
+	m.mu.Lock()
+	m.projectDir = dir
+	m.mu.Unlock()
+}
+
+// IsWorkingDirTemplate reports whether a working directory is expanded at
+// start rather than used as is: it references {{projectDir}} or environment
+// variables, or is relative to the project root
+func IsWorkingDirTemplate(dir string) bool {
+	return strings.Contains(dir, projectDirVar) || envRefPattern.MatchString(dir) ||
+		(dir != "" && !filepath.IsAbs(dir))
+}
+
+// ExpandWorkingDir resolves a process working directory. {{projectDir}} is
+// the project root, and $VAR or ${VAR} is looked up in env, then in the app
+// environment; a relative result is taken from the project root, and an empty
+// one is the root itself. A template's result must stay within the project
+// root, so configs can be shared across machines without pointing processes
+// elsewhere. A literal absolute path is returned unchanged.
+func ExpandWorkingDir(dir, projectDir string, env map[string]string) (string, error) {
+	if !IsWorkingDirTemplate(dir) {
+		if dir == "" {
+			return projectDir, nil
+		}
+		return dir, nil
+	}
+	if projectDir == "" {
+		return "", fmt.Errorf("working directory %q needs a project directory", dir)
+	}
+
+	var missing []string
+	expanded := strings.ReplaceAll(dir, projectDirVar, projectDir)
+	expanded = envRefPattern.ReplaceAllStringFunc(expanded, func(ref string) string {
+		groups := envRefPattern.FindStringSubmatch(ref)
+		name := groups[1] + groups[2]
+		if value, ok := env[name]; ok {
+			return value
+		}
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		missing = append(missing, name)
+		return ""
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("working directory %q: environment variable not set: %s", dir, strings.Join(missing, ", "))
+	}
+
+	if !filepath.IsAbs(expanded) {
+		expanded = filepath.Join(projectDir, expanded)
+	}
+
+	// SECURITY: Compare resolved paths so neither .. nor a symlink can leave
+	// the project
+	resolved, err := security.ValidateProjectPath(expanded)
+	if err != nil {
+		return "", fmt.Errorf("invalid working directory %q: %w", dir, err)
+	}
+	root, err := security.ValidateProjectPath(projectDir)
+	if err != nil {
+		return "", fmt.Errorf("invalid project directory: %w", err)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("working directory %q resolves outside the project: %s", dir, resolved)
+	}
+
+	return resolved, nil
+}
+
+// resolveWorkingDir expands the process's working directory for a run
+func (m *Manager) resolveWorkingDir(mp *ManagedProcess) (string, error) {
+	m.mu.RLock()
+	projectDir := m.projectDir
+	m.mu.RUnlock()
+
+	return ExpandWorkingDir(mp.Config.WorkingDir, projectDir, mp.Config.Environment)
+}
@@ -0,0 +1,388 @@
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// dialTimeout bounds connecting to the server
+	dialTimeout = 5 * time.Second
+
+	// commandTimeout bounds a single command round trip
+	commandTimeout = 10 * time.Second
+
+	// scanCount is the COUNT hint passed to each SCAN step
+	scanCount = 1000
+
+	// maxScanSteps bounds the SCAN calls made for one Keys call, so a
+	// pattern matching almost nothing can't walk a huge keyspace forever
+	maxScanSteps = 1000
+
+	// maxPreviewItems bounds the elements returned for a list, set, hash or
+	// sorted set value
+	maxPreviewItems = 100
+
+	// maxStringBytes bounds the bytes returned for a string value
+	maxStringBytes = 1024 * 1024
+)
+
+// ConnectionConfig holds Redis connection settings
+type ConnectionConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username,omitempty"` // Redis 6 ACL user; empty for the default user
+	Password string `json:"password,omitempty"`
+	DB       int    `json:"db"`
+}
+
+// KeyScan is a page of keys found with SCAN
+type KeyScan struct {
+	Keys      []string `json:"keys"`
+	Truncated bool     `json:"truncated"` // The limit or scan budget ran out before the whole keyspace was seen
+}
+
+// Value is a key's value, with collections cut to maxPreviewItems
+type Value struct {
+	Key       string      `json:"key"`
+	Type      string      `json:"type"` // string, list, set, zset, hash, stream or none
+	Value     interface{} `json:"value,omitempty"`
+	Size      int64       `json:"size"` // Bytes for strings, elements for collections
+	TTL       int64       `json:"ttl"`  // Seconds; -1 without expiry, -2 if the key doesn't exist
+	Truncated bool        `json:"truncated"`
+}
+
+// Client is a minimal Redis client speaking RESP2 over one connection.
+// Commands are serialized, which is plenty for browsing.
+type Client struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewClient creates a new Redis client
+func NewClient() *Client {
+	return &Client{}
+}
+
+// Connect dials the server, authenticates and selects the database
+func (c *Client) Connect(config ConnectionConfig) error {
+	address := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	conn, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+	c.mu.Unlock()
+
+	if config.Password != "" {
+		args := []string{"AUTH", config.Password}
+		if config.Username != "" {
+			args = []string{"AUTH", config.Username, config.Password}
+		}
+		if _, err := c.do(args...); err != nil {
+			c.Close()
+			return fmt.Errorf("redis authentication failed: %w", err)
+		}
+	}
+	if config.DB != 0 {
+		if _, err := c.do("SELECT", strconv.Itoa(config.DB)); err != nil {
+			c.Close()
+			return fmt.Errorf("failed to select redis database %d: %w", config.DB, err)
+		}
+	}
+	if _, err := c.do("PING"); err != nil {
+		c.Close()
+		return fmt.Errorf("redis ping failed: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	c.reader = nil
+	return err
+}
+
+// IsConnected reports whether the client has an open connection
+func (c *Client) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn != nil
+}
+
+// do sends a command and reads its reply. A broken connection is closed so
+// later calls fail fast instead of reading a stale reply.
+func (c *Client) do(args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil, fmt.Errorf("not connected to redis")
+	}
+
+	c.conn.SetDeadline(time.Now().Add(commandTimeout))
+	if err := writeCommand(c.conn, args); err != nil {
+		c.dropLocked()
+		return nil, err
+	}
+
+	reply, err := readReply(c.reader)
+	var replyErr Error
+	if err != nil && !errors.Is(err, errNil) && !errors.As(err, &replyErr) {
+		c.dropLocked()
+	}
+	return reply, err
+}
+
+func (c *Client) dropLocked() {
+	c.conn.Close()
+	c.conn = nil
+	c.reader = nil
+}
+
+// Keys returns up to limit keys matching a glob pattern, walking the
+// keyspace with SCAN so the server is never blocked the way KEYS * would
+func (c *Client) Keys(pattern string, limit int) (*KeyScan, error) {
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	result := &KeyScan{Keys: []string{}}
+	seen := make(map[string]bool)
+	cursor := "0"
+	for step := 0; ; step++ {
+		if step == maxScanSteps {
+			result.Truncated = true
+			break
+		}
+
+		reply, err := c.do("SCAN", cursor, "MATCH", pattern, "COUNT", strconv.Itoa(scanCount))
+		if err != nil {
+			return nil, err
+		}
+		page, ok := reply.([]interface{})
+		if !ok || len(page) != 2 {
+			return nil, fmt.Errorf("unexpected SCAN reply")
+		}
+		cursor, _ = page[0].(string)
+		keys, _ := page[1].([]interface{})
+
+		// SCAN may return a key more than once
+		for _, k := range keys {
+			key, _ := k.(string)
+			if seen[key] {
+				continue
+			}
+			if len(result.Keys) == limit {
+				result.Truncated = true
+				return result, nil
+			}
+			seen[key] = true
+			result.Keys = append(result.Keys, key)
+		}
+
+		if cursor == "0" {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// TTL returns a key's remaining time to live in seconds: -1 if it has no
+// expiry, -2 if it doesn't exist
+func (c *Client) TTL(key string) (int64, error) {
+	reply, err := c.do("TTL", key)
+	if err != nil {
+		return 0, err
+	}
+	ttl, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected TTL reply")
+	}
+	return ttl, nil
+}
+
+// Get returns a key's type, size, TTL and value. Collections are read a
+// bounded number of elements at a time, and long strings are cut short.
+func (c *Client) Get(key string) (*Value, error) {
+	reply, err := c.do("TYPE", key)
+	if err != nil {
+		return nil, err
+	}
+	value := &Value{Key: key}
+	value.Type, _ = reply.(string)
+
+	if value.TTL, err = c.TTL(key); err != nil {
+		return nil, err
+	}
+
+	limit := strconv.Itoa(maxPreviewItems - 1)
+	switch value.Type {
+	case "none":
+		return value, nil
+
+	case "string":
+		if value.Size, err = c.integer("STRLEN", key); err != nil {
+			return nil, err
+		}
+		reply, err = c.do("GETRANGE", key, "0", strconv.Itoa(maxStringBytes-1))
+		value.Truncated = value.Size > maxStringBytes
+
+	case "list":
+		if value.Size, err = c.integer("LLEN", key); err != nil {
+			return nil, err
+		}
+		reply, err = c.do("LRANGE", key, "0", limit)
+
+	case "set":
+		if value.Size, err = c.integer("SCARD", key); err != nil {
+			return nil, err
+		}
+		reply, err = c.scanMembers("SSCAN", key)
+
+	case "hash":
+		if value.Size, err = c.integer("HLEN", key); err != nil {
+			return nil, err
+		}
+		reply, err = c.scanMembers("HSCAN", key)
+		if items, ok := reply.([]interface{}); ok {
+			reply = pairs(items)
+		}
+
+	case "zset":
+		if value.Size, err = c.integer("ZCARD", key); err != nil {
+			return nil, err
+		}
+		reply, err = c.do("ZRANGE", key, "0", limit, "WITHSCORES")
+		if items, ok := reply.([]interface{}); ok {
+			reply = pairs(items)
+		}
+
+	case "stream":
+		if value.Size, err = c.integer("XLEN", key); err != nil {
+			return nil, err
+		}
+		reply, err = c.do("XRANGE", key, "-", "+", "COUNT", strconv.Itoa(maxPreviewItems))
+
+	default:
+		// Module types can't be read generically
+		return value, nil
+	}
+	if errors.Is(err, errNil) {
+		return value, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	value.Value = reply
+	if value.Type != "string" {
+		value.Truncated = value.Size > maxPreviewItems
+	}
+	return value, nil
+}
+
+func (c *Client) integer(args ...string) (int64, error) {
+	reply, err := c.do(args...)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected %s reply", args[0])
+	}
+	return n, nil
+}
+
+// scanMembers collects up to maxPreviewItems elements of a set (SSCAN) or
+// field/value pairs of a hash (HSCAN)
+func (c *Client) scanMembers(command, key string) ([]interface{}, error) {
+	want := maxPreviewItems
+	if command == "HSCAN" {
+		want *= 2 // Fields and values alternate
+	}
+
+	items := []interface{}{}
+	cursor := "0"
+	for step := 0; step < maxScanSteps && len(items) < want; step++ {
+		reply, err := c.do(command, key, cursor, "COUNT", strconv.Itoa(maxPreviewItems))
+		if err != nil {
+			return nil, err
+		}
+		page, ok := reply.([]interface{})
+		if !ok || len(page) != 2 {
+			return nil, fmt.Errorf("unexpected %s reply", command)
+		}
+		cursor, _ = page[0].(string)
+		members, _ := page[1].([]interface{})
+		items = append(items, members...)
+		if cursor == "0" {
+			break
+		}
+	}
+
+	return items[:min(len(items), want)], nil
+}
+
+// pairs turns an alternating field, value reply into a list of two-element
+// pairs, keeping order (sorted sets are ordered by score)
+func pairs(items []interface{}) [][2]interface{} {
+	result := make([][2]interface{}, 0, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		result = append(result, [2]interface{}{items[i], items[i+1]})
+	}
+	return result
+}
+
+// Info returns the server's INFO output as section name to field to value,
+// e.g. info["memory"]["used_memory_human"]
+func (c *Client) Info() (map[string]map[string]string, error) {
+	reply, err := c.do("INFO")
+	if err != nil {
+		return nil, err
+	}
+	text, _ := reply.(string)
+
+	info := make(map[string]map[string]string)
+	section := "default"
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "# ") {
+			section = strings.ToLower(strings.TrimPrefix(line, "# "))
+			continue
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if info[section] == nil {
+			info[section] = make(map[string]string)
+		}
+		info[section][field] = value
+	}
+
+	return info, nil
+}
@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxBulkLength bounds a single bulk string read from the server
+const maxBulkLength = 64 * 1024 * 1024
+
+// Error is an error reply from the server, e.g. "WRONGTYPE ..."
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+// errNil is a nil reply: a missing key or an empty bulk string
+var errNil = errors.New("redis: nil reply")
+
+// writeCommand encodes a command as a RESP array of bulk strings
+func writeCommand(w io.Writer, args []string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// readReply decodes one RESP2 reply. Simple and bulk strings become string,
+// integers int64 and arrays []interface{}. Nil replies return errNil.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply line")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, errNil
+		}
+		if n > maxBulkLength {
+			return nil, fmt.Errorf("redis: bulk reply of %d bytes is too large", n)
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad array length %q", line)
+		}
+		if n < 0 {
+			return nil, errNil
+		}
+		items := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			item, err := readReply(r)
+			if errors.Is(err, errNil) {
+				items = append(items, nil)
+				continue
+			}
+			// An error inside an array (e.g. from EXEC) doesn't break the stream
+			var replyErr Error
+			if errors.As(err, &replyErr) {
+				items = append(items, replyErr)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("redis: unknown reply type %q", line[:1])
+}
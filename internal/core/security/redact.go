@@ -0,0 +1,102 @@
+package security
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// RedactedValue replaces the value of a sensitive environment variable
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactPatterns are the environment variable name patterns whose
+// values are masked when no list is configured. Patterns are globs matched
+// against the upper-cased name.
+var DefaultRedactPatterns = []string{
+	"*_KEY",
+	"*_SECRET",
+	"*_TOKEN",
+	"*PASSWORD*",
+	"*PASSWD*",
+	"*CREDENTIALS*",
+	"*_DSN",
+	"DATABASE_URL",
+	"REDIS_URL",
+	"SECRET_KEY_BASE",
+}
+
+// Redactor masks the values of environment variables whose names look like
+// they hold secrets, for anything logged or shown to the user. The real
+// values are only ever passed to the spawned process.
+type Redactor struct {
+	patterns []string
+}
+
+// NewRedactor creates a redactor for the given name patterns, or for
+// DefaultRedactPatterns when the list is empty. Malformed patterns are
+// skipped.
+func NewRedactor(patterns []string) *Redactor {
+	if len(patterns) == 0 {
+		patterns = DefaultRedactPatterns
+	}
+
+	r := &Redactor{}
+	for _, pattern := range patterns {
+		pattern = strings.ToUpper(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			continue
+		}
+		r.patterns = append(r.patterns, pattern)
+	}
+	return r
+}
+
+// IsSensitive reports whether an environment variable's value should be masked
+func (r *Redactor) IsSensitive(name string) bool {
+	name = strings.ToUpper(name)
+	for _, pattern := range r.patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactEnv returns a copy of env with sensitive values masked
+func (r *Redactor) RedactEnv(env map[string]string) map[string]string {
+	if env == nil {
+		return nil
+	}
+
+	redacted := make(map[string]string, len(env))
+	for name, value := range env {
+		if r.IsSensitive(name) {
+			value = RedactedValue
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// FormatEnv renders env as sorted NAME=value pairs with sensitive values
+// masked, for audit logs
+func (r *Redactor) FormatEnv(env map[string]string) string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		value := env[name]
+		if r.IsSensitive(name) {
+			value = RedactedValue
+		}
+		pairs[i] = name + "=" + value
+	}
+	return "[" + strings.Join(pairs, " ") + "]"
+}
@@ -2,6 +2,7 @@ package security
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -29,6 +30,58 @@ var AllowedCommands = map[string]bool{
 	"docker-compose": true,
 }
 
+// AllowedRailsSubcommands is the whitelist of one-off rails subcommands.
+// Long-running and interactive commands (server, console) have dedicated processes.
+var AllowedRailsSubcommands = map[string]bool{
+	"runner":            true,
+	"routes":            true,
+	"about":             true,
+	"stats":             true,
+	"notes":             true,
+	"test":              true,
+	"middleware":        true,
+	"initializers":      true,
+	"time:zones":        true,
+	"zeitwerk:check":    true,
+	"db:create":         true,
+	"db:prepare":        true,
+	"db:migrate":        true,
+	"db:migrate:status": true,
+	"db:rollback":       true,
+	"db:seed":           true,
+	"db:version":        true,
+	"db:schema:dump":    true,
+	"db:structure:dump": true,
+
+	"db:abort_if_pending_migrations": true,
+
+	"assets:precompile": true,
+	"assets:clobber":    true,
+	"tmp:clear":         true,
+	"log:clear":         true,
+}
+
+// DestructiveRailsTasks are tasks that drop, truncate or reload data. They
+// only run as one-offs with explicit confirmation.
+var DestructiveRailsTasks = map[string]bool{
+	"db:drop":           true,
+	"db:drop:all":       true,
+	"db:reset":          true,
+	"db:purge":          true,
+	"db:purge:all":      true,
+	"db:setup":          true,
+	"db:schema:load":    true,
+	"db:structure:load": true,
+	"db:migrate:reset":  true,
+	"db:migrate:redo":   true,
+	"db:seed:replant":   true,
+	"db:fixtures:load":  true,
+	"db:truncate_all":   true,
+	"db:test:purge":     true,
+	"db:test:prepare":   true,
+	"db:test:load":      true,
+}
+
 // Shell metacharacters that could be dangerous
 var shellMetachars = regexp.MustCompile(`[;&|<>$` + "`" + `(){}]`)
 
@@ -44,6 +97,34 @@ func ValidateCommand(command string) error {
 	return nil
 }
 
+// ValidateRailsSubcommand checks a one-off rails subcommand is whitelisted, or
+// is a destructive task the user confirmed
+func ValidateRailsSubcommand(subcommand string, confirmed bool) error {
+	if AllowedRailsSubcommands[subcommand] {
+		return nil
+	}
+
+	if DestructiveRailsTasks[subcommand] {
+		if !confirmed {
+			return fmt.Errorf("rails task %s destroys data and requires confirmation", subcommand)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("rails subcommand not allowed: %s (not in whitelist)", subcommand)
+}
+
+// envNamePattern is a portable environment variable name
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateEnvName checks an environment variable name is portable
+func ValidateEnvName(name string) error {
+	if !envNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid environment variable name: %q", name)
+	}
+	return nil
+}
+
 // ValidateArguments checks arguments for shell metacharacters
 func ValidateArguments(args []string) error {
 	for i, arg := range args {
@@ -94,22 +175,66 @@ func SanitizePTYInput(input string) (string, error) {
 	return input, nil
 }
 
-// IsDestructiveQuery checks if a SQL query is destructive
-func IsDestructiveQuery(query string) bool {
-	queryUpper := strings.ToUpper(strings.TrimSpace(query))
+// DefaultDestructiveKeywords are the statement keywords treated as destructive
+// when no list is configured
+var DefaultDestructiveKeywords = []string{
+	"DROP",
+	"DELETE",
+	"TRUNCATE",
+	"UPDATE",
+	"ALTER",
+	"INSERT",
+	"CREATE",
+}
+
+// alwaysDestructiveKeywords are flagged whatever the configuration says
+var alwaysDestructiveKeywords = []string{"DROP", "DELETE", "TRUNCATE"}
+
+var defaultQueryGuard = NewQueryGuard(nil)
+
+// QueryGuard decides which SQL statements need explicit confirmation
+type QueryGuard struct {
+	keywords []string
+}
 
-	destructive := []string{
-		"DROP ",
-		"DELETE ",
-		"TRUNCATE ",
-		"UPDATE ",
-		"ALTER ",
-		"INSERT ",
-		"CREATE ",
+// NewQueryGuard creates a guard for the given statement keywords, or for
+// DefaultDestructiveKeywords when the list is empty. DROP, DELETE and
+// TRUNCATE are always included so they can't be configured away.
+func NewQueryGuard(keywords []string) *QueryGuard {
+	if len(keywords) == 0 {
+		keywords = DefaultDestructiveKeywords
 	}
 
-	for _, keyword := range destructive {
-		if strings.HasPrefix(queryUpper, keyword) {
+	seen := make(map[string]bool)
+	g := &QueryGuard{}
+	for _, keyword := range append(append([]string{}, alwaysDestructiveKeywords...), keywords...) {
+		keyword = strings.Join(strings.Fields(strings.ToUpper(keyword)), " ")
+		if keyword == "" || seen[keyword] {
+			continue
+		}
+		seen[keyword] = true
+		g.keywords = append(g.keywords, keyword)
+	}
+
+	return g
+}
+
+// Keywords returns the keywords the guard flags
+func (g *QueryGuard) Keywords() []string {
+	return append([]string(nil), g.keywords...)
+}
+
+// IsDestructive checks if a SQL query starts with one of the guard's keywords
+func (g *QueryGuard) IsDestructive(query string) bool {
+	queryUpper := strings.Join(strings.Fields(strings.ToUpper(query)), " ")
+
+	for _, keyword := range g.keywords {
+		if !strings.HasPrefix(queryUpper, keyword) {
+			continue
+		}
+		// Whole words only, so a SET keyword doesn't flag SETTINGS
+		rest := queryUpper[len(keyword):]
+		if rest == "" || !isIdentifierChar(rest[0]) {
 			return true
 		}
 	}
@@ -117,6 +242,15 @@ func IsDestructiveQuery(query string) bool {
 	return false
 }
 
+// IsDestructiveQuery checks if a SQL query is destructive using the default keywords
+func IsDestructiveQuery(query string) bool {
+	return defaultQueryGuard.IsDestructive(query)
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
 // ValidateSSLMode ensures only secure SSL modes are used
 func ValidateSSLMode(mode string) error {
 	allowed := map[string]bool{
@@ -135,6 +269,68 @@ func ValidateSSLMode(mode string) error {
 	return nil
 }
 
+// ValidateRelativePath checks a path is relative and doesn't climb out of its base directory
+func ValidateRelativePath(path string) error {
+	if path == "" {
+		return fmt.Errorf("path is empty")
+	}
+	if filepath.IsAbs(path) {
+		return fmt.Errorf("path must be relative: %s", path)
+	}
+
+	clean := filepath.Clean(path)
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path escapes the base directory: %s", path)
+	}
+
+	return nil
+}
+
+// maxRemoteCommandLength bounds a one-off remote command
+const maxRemoteCommandLength = 4096
+
+// ValidateRemoteCommand checks a command to run on a remote host. Shell syntax
+// is allowed since it runs in the user's remote shell, but the command must be
+// a single line so the audit log shows exactly what ran.
+func ValidateRemoteCommand(command string) error {
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("command is empty")
+	}
+	if len(command) > maxRemoteCommandLength {
+		return fmt.Errorf("command too long (max %d characters)", maxRemoteCommandLength)
+	}
+	if strings.ContainsAny(command, "\x00\r\n") {
+		return fmt.Errorf("command must be a single line")
+	}
+
+	return nil
+}
+
+// ValidateCertFile checks a TLS certificate or key path is absolute and
+// points to a readable regular file
+func ValidateCertFile(path string) (string, error) {
+	resolved, err := ValidateProjectPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", fmt.Errorf("certificate file not found: %s", path)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("certificate path is not a file: %s", path)
+	}
+
+	f, err := os.Open(resolved)
+	if err != nil {
+		return "", fmt.Errorf("certificate file is not readable: %s", path)
+	}
+	f.Close()
+
+	return resolved, nil
+}
+
 // SanitizeError removes sensitive information from errors
 func SanitizeError(err error, internal bool) error {
 	if err == nil {
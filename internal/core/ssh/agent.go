@@ -1,6 +1,7 @@
 package ssh
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
@@ -28,7 +29,8 @@ func GetSSHAgent() (ssh.AuthMethod, error) {
 	return ssh.PublicKeysCallback(agentClient.Signers), nil
 }
 
-// LoadPrivateKey loads an SSH private key from a file
+// LoadPrivateKey loads an SSH private key from a file. A passphrase
+// protected key fails with a PassphraseRequiredError.
 func LoadPrivateKey(path string) (ssh.AuthMethod, error) {
 	key, err := os.ReadFile(path)
 	if err != nil {
@@ -37,12 +39,34 @@ func LoadPrivateKey(path string) (ssh.AuthMethod, error) {
 
 	signer, err := ssh.ParsePrivateKey(key)
 	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, &PassphraseRequiredError{KeyPath: path}
+		}
 		return nil, fmt.Errorf("failed to parse private key: %w", err)
 	}
 
 	return ssh.PublicKeys(signer), nil
 }
 
+// UnknownHostKeyError is returned when a host's key isn't in known_hosts.
+// The key is kept so it can be trusted once the user has checked the
+// fingerprint.
+type UnknownHostKeyError struct {
+	Hostname string
+	Remote   net.Addr
+	Key      ssh.PublicKey
+}
+
+func (e *UnknownHostKeyError) Error() string {
+	return fmt.Sprintf("unknown host %s (fingerprint: %s). Add to known_hosts to connect", e.Hostname, e.Fingerprint())
+}
+
+// Fingerprint returns the SHA256 fingerprint of the host key, as ssh prints it
+func (e *UnknownHostKeyError) Fingerprint() string {
+	return ssh.FingerprintSHA256(e.Key)
+}
+
 // GetKnownHostsCallback creates a HostKeyCallback for known_hosts verification
 func GetKnownHostsCallback(knownHostsPath string) (ssh.HostKeyCallback, error) {
 	// Use default known_hosts if no path specified
@@ -94,9 +118,8 @@ func GetKnownHostsCallback(knownHostsPath string) (ssh.HostKeyCallback, error) {
 					"host", hostname,
 					"fingerprint", ssh.FingerprintSHA256(key))
 
-				// For now, we'll return an error and require manual acceptance
-				// In the future, we can add a callback to prompt the user
-				return fmt.Errorf("unknown host %s (fingerprint: %s). Add to known_hosts to connect", hostname, ssh.FingerprintSHA256(key))
+				// Require manual acceptance, see Manager.TrustHostKey
+				return &UnknownHostKeyError{Hostname: hostname, Remote: remote, Key: key}
 			}
 			return err
 		}
@@ -106,8 +129,10 @@ func GetKnownHostsCallback(knownHostsPath string) (ssh.HostKeyCallback, error) {
 	}, nil
 }
 
-// AddHostToKnownHosts adds a host key to the known_hosts file
-func AddHostToKnownHosts(knownHostsPath, hostname string, remote net.Addr, key ssh.PublicKey) error {
+// AddHostToKnownHosts adds a host key to the known_hosts file. With hash set
+// the hostname is written in OpenSSH's hashed |1|salt|hash form, which
+// knownhosts still matches when verifying.
+func AddHostToKnownHosts(knownHostsPath, hostname string, remote net.Addr, key ssh.PublicKey, hash bool) error {
 	// Use default known_hosts if no path specified
 	if knownHostsPath == "" {
 		home, err := os.UserHomeDir()
@@ -118,7 +143,13 @@ func AddHostToKnownHosts(knownHostsPath, hostname string, remote net.Addr, key s
 	}
 
 	// Create the known_hosts entry
-	entry := knownhosts.Line([]string{hostname}, key)
+	address := hostname
+	if hash {
+		// Hash the normalized form ([host]:port for non-standard ports),
+		// since that's what lookups hash
+		address = knownhosts.HashHostname(knownhosts.Normalize(hostname))
+	}
+	entry := knownhosts.Line([]string{address}, key)
 
 	// Append to known_hosts file
 	f, err := os.OpenFile(knownHostsPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
@@ -0,0 +1,130 @@
+package ssh
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/caboose-desktop/internal/core/security"
+	"github.com/caboose-desktop/internal/models"
+)
+
+const (
+	// commandTimeout bounds how long a one-off remote command may run
+	commandTimeout = 60 * time.Second
+
+	// maxCommandOutput is the most output kept per stream of a remote command
+	maxCommandOutput = 1 << 20
+)
+
+// cappedBuffer keeps the first limit bytes written and discards the rest
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// String returns the captured output, marked if it was cut off
+func (b *cappedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "\n[output truncated]"
+	}
+	return b.buf.String()
+}
+
+// RunCommand runs a single non-interactive command over an existing session's
+// connection and returns its output. The interactive shell is not affected.
+// A non-zero exit status is reported through exitCode, not err.
+func (m *Manager) RunCommand(sessionID, command string) (stdout, stderr string, exitCode int, err error) {
+	m.mu.RLock()
+	session, exists := m.sessions[sessionID]
+	m.mu.RUnlock()
+
+	if !exists {
+		return "", "", -1, fmt.Errorf("session %s not found", sessionID)
+	}
+
+	if err := security.ValidateRemoteCommand(command); err != nil {
+		return "", "", -1, err
+	}
+
+	return session.Run(command)
+}
+
+// Run executes command in a fresh channel on the session's client
+func (s *Session) Run(command string) (stdout, stderr string, exitCode int, err error) {
+	s.mu.Lock()
+	client := s.Client
+	if client != nil {
+		s.lastActivity = time.Now()
+		s.addLogEntry(models.SSHSessionLog{
+			SessionID: s.ID,
+			Timestamp: time.Now(),
+			Server:    s.Server.Name,
+			Type:      "command",
+			Content:   command,
+		})
+	}
+	s.mu.Unlock()
+
+	if client == nil {
+		return "", "", -1, fmt.Errorf("session not connected")
+	}
+
+	channel, err := client.NewSession()
+	if err != nil {
+		return "", "", -1, fmt.Errorf("failed to open command channel: %w", err)
+	}
+	defer channel.Close()
+
+	outBuf := &cappedBuffer{limit: maxCommandOutput}
+	errBuf := &cappedBuffer{limit: maxCommandOutput}
+	channel.Stdout = outBuf
+	channel.Stderr = errBuf
+
+	done := make(chan error, 1)
+	go func() {
+		done <- channel.Run(command)
+	}()
+
+	var runErr error
+	select {
+	case runErr = <-done:
+	case <-time.After(commandTimeout):
+		channel.Signal(ssh.SIGKILL)
+		channel.Close()
+		<-done
+		return outBuf.String(), errBuf.String(), -1, fmt.Errorf("command timed out after %s", commandTimeout)
+	}
+
+	var exitErr *ssh.ExitError
+	switch {
+	case runErr == nil:
+		exitCode = 0
+	case errors.As(runErr, &exitErr):
+		exitCode = exitErr.ExitStatus()
+	default:
+		return outBuf.String(), errBuf.String(), -1, fmt.Errorf("command failed: %w", runErr)
+	}
+
+	slog.Debug("SSH command finished",
+		"session_id", s.ID,
+		"exit_code", exitCode)
+
+	return outBuf.String(), errBuf.String(), exitCode, nil
+}
@@ -0,0 +1,102 @@
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// jumpConn is a connection tunneled through a jump host. Closing it also
+// closes the jump host's client, so the target client owns the whole chain.
+type jumpConn struct {
+	net.Conn
+	jump *ssh.Client
+}
+
+func (c *jumpConn) Close() error {
+	err := c.Conn.Close()
+	c.jump.Close()
+	return err
+}
+
+// dialServer connects to addr, through the comma-separated ProxyJump hops if
+// any. Every hop uses the target's auth methods and host key checks; a hop
+// without a user connects as the target's user.
+func dialServer(addr, proxyJump string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	if proxyJump == "" {
+		return ssh.Dial("tcp", addr, config)
+	}
+
+	var client *ssh.Client
+	for _, spec := range strings.Split(proxyJump, ",") {
+		hopAddr, hopConfig, err := parseJumpHost(strings.TrimSpace(spec), config)
+		if err != nil {
+			if client != nil {
+				client.Close()
+			}
+			return nil, err
+		}
+
+		next, err := dialVia(client, hopAddr, hopConfig)
+		if err != nil {
+			return nil, fmt.Errorf("jump host %s: %w", hopAddr, err)
+		}
+		client = next
+	}
+
+	return dialVia(client, addr, config)
+}
+
+// dialVia opens an SSH client to addr, tunneled through jump unless it is nil.
+// On failure jump is closed.
+func dialVia(jump *ssh.Client, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	if jump == nil {
+		return ssh.Dial("tcp", addr, config)
+	}
+
+	conn, err := jump.Dial("tcp", addr)
+	if err != nil {
+		jump.Close()
+		return nil, err
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(&jumpConn{Conn: conn, jump: jump}, addr, config)
+	if err != nil {
+		conn.Close()
+		jump.Close()
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// parseJumpHost parses a ProxyJump hop, [user@]host[:port] or
+// ssh://[user@]host[:port], into an address and a client config for it
+func parseJumpHost(spec string, base *ssh.ClientConfig) (string, *ssh.ClientConfig, error) {
+	spec = strings.TrimPrefix(spec, "ssh://")
+	if spec == "" {
+		return "", nil, fmt.Errorf("empty jump host")
+	}
+
+	config := *base
+	if at := strings.LastIndex(spec, "@"); at != -1 {
+		config.User = spec[:at]
+		spec = spec[at+1:]
+	}
+
+	host, port := spec, 22
+	if h, p, err := net.SplitHostPort(spec); err == nil {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 || n > 65535 {
+			return "", nil, fmt.Errorf("invalid jump host port: %s", p)
+		}
+		host, port = h, n
+	}
+	if host == "" {
+		return "", nil, fmt.Errorf("invalid jump host: %s", spec)
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port)), &config, nil
+}
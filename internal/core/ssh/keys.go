@@ -0,0 +1,96 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// PassphraseRequiredError is returned when connecting with a private key
+// that is passphrase protected and hasn't been unlocked
+type PassphraseRequiredError struct {
+	KeyPath string
+}
+
+func (e *PassphraseRequiredError) Error() string {
+	return fmt.Sprintf("private key %s is passphrase protected; unlock it to connect", e.KeyPath)
+}
+
+// keyring holds private keys unlocked with their passphrase, by path. The
+// parsed signers only ever live in memory; neither they nor the passphrase
+// are written anywhere.
+type keyring struct {
+	mu      sync.Mutex
+	signers map[string]ssh.Signer
+}
+
+func newKeyring() *keyring {
+	return &keyring{signers: make(map[string]ssh.Signer)}
+}
+
+// authMethod returns public key auth with the key at path, using the
+// unlocked signer if there is one
+func (k *keyring) authMethod(path string) (ssh.AuthMethod, error) {
+	k.mu.Lock()
+	signer, ok := k.signers[path]
+	k.mu.Unlock()
+	if ok {
+		return ssh.PublicKeys(signer), nil
+	}
+	return LoadPrivateKey(path)
+}
+
+// unlock parses the passphrase protected key at path and keeps its signer
+func (k *keyring) unlock(path, passphrase string) error {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if !errors.As(err, &missing) {
+			return fmt.Errorf("failed to parse private key: %w", err)
+		}
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(passphrase))
+		if err != nil {
+			// x509.IncorrectPasswordError for PEM keys, a decryption
+			// failure for OpenSSH ones; neither says more than this
+			return fmt.Errorf("incorrect passphrase for %s", path)
+		}
+	}
+
+	k.mu.Lock()
+	k.signers[path] = signer
+	k.mu.Unlock()
+	return nil
+}
+
+// clear forgets every unlocked key
+func (k *keyring) clear() {
+	k.mu.Lock()
+	k.signers = make(map[string]ssh.Signer)
+	k.mu.Unlock()
+}
+
+// keyAuth returns public key auth with the server's private key
+func (s *Session) keyAuth() (ssh.AuthMethod, error) {
+	if s.keys == nil {
+		return LoadPrivateKey(s.Server.PrivateKeyPath)
+	}
+	return s.keys.authMethod(s.Server.PrivateKeyPath)
+}
+
+// UnlockKey decrypts a passphrase protected private key and keeps it in
+// memory for sessions that use it, until the manager shuts down. A key
+// without a passphrase is accepted as is.
+func (m *Manager) UnlockKey(path, passphrase string) error {
+	if path == "" {
+		return fmt.Errorf("private key path is required")
+	}
+	return m.keys.unlock(path, passphrase)
+}
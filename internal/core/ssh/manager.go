@@ -1,36 +1,56 @@
 package ssh
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/caboose-desktop/internal/core/config"
 	"github.com/caboose-desktop/internal/models"
 )
 
+// A rejected host key can be trusted for unknownHostTTL, and at most
+// maxUnknownHosts are kept
+const (
+	unknownHostTTL  = 10 * time.Minute
+	maxUnknownHosts = 32
+)
+
+// rejectedHostKey is a host key a connection was refused for, and when
+type rejectedHostKey struct {
+	err *UnknownHostKeyError
+	at  time.Time
+}
+
 // Manager handles all SSH connections
 type Manager struct {
 	mu            sync.RWMutex
 	sessions      map[string]*Session
 	config        *config.SSHConfig
+	keys          *keyring                   // Private keys unlocked with a passphrase
+	unknownHosts  map[string]rejectedHostKey // Host keys rejected as unknown, by fingerprint
 	cleanupTicker *time.Ticker
 	cleanupStop   chan struct{}
 
 	OnOutput       func(sessionID, data string)
-	OnDisconnect   func(sessionID string)
+	OnDisconnect   func(sessionID, reason string)
 	OnHealthUpdate func(sessionID string, health models.SSHHealth)
 }
 
 // NewManager creates a new SSH manager
 func NewManager(cfg *config.SSHConfig) *Manager {
 	m := &Manager{
-		sessions:    make(map[string]*Session),
-		config:      cfg,
-		cleanupStop: make(chan struct{}),
+		sessions:     make(map[string]*Session),
+		config:       cfg,
+		keys:         newKeyring(),
+		unknownHosts: make(map[string]rejectedHostKey),
+		cleanupStop:  make(chan struct{}),
 	}
 
 	// Start cleanup goroutine
@@ -43,9 +63,19 @@ func NewManager(cfg *config.SSHConfig) *Manager {
 	return m
 }
 
-// startCleanup starts periodic cleanup of stale sessions
+// startCleanup starts periodic cleanup of stale and idle sessions
 func (m *Manager) startCleanup() {
-	m.cleanupTicker = time.NewTicker(5 * time.Minute)
+	interval := 5 * time.Minute
+
+	// Check often enough to honor short idle timeouts
+	if idle := m.idleTimeout(); idle > 0 && idle < interval {
+		interval = idle / 2
+		if interval < 10*time.Second {
+			interval = 10 * time.Second
+		}
+	}
+
+	m.cleanupTicker = time.NewTicker(interval)
 
 	go func() {
 		defer m.cleanupTicker.Stop()
@@ -54,6 +84,7 @@ func (m *Manager) startCleanup() {
 			select {
 			case <-m.cleanupTicker.C:
 				m.cleanupStaleSessions()
+				m.closeIdleSessions()
 
 			case <-m.cleanupStop:
 				slog.Debug("SSH manager cleanup stopped")
@@ -84,6 +115,42 @@ func (m *Manager) cleanupStaleSessions() {
 	}
 }
 
+// idleTimeout returns the configured idle timeout (0 = never)
+func (m *Manager) idleTimeout() time.Duration {
+	if m.config == nil || m.config.IdleTimeout <= 0 {
+		return 0
+	}
+	return time.Duration(m.config.IdleTimeout) * time.Second
+}
+
+// closeIdleSessions closes sessions with no activity beyond the idle timeout
+func (m *Manager) closeIdleSessions() {
+	timeout := m.idleTimeout()
+	if timeout == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	idle := make([]*Session, 0)
+	for id, session := range m.sessions {
+		if session.IdleFor() > timeout {
+			delete(m.sessions, id)
+			idle = append(idle, session)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, session := range idle {
+		slog.Info("closing idle SSH session",
+			"session_id", session.ID,
+			"server", session.Server.Name,
+			"idle_timeout", timeout)
+
+		session.notifyDisconnect("idle timeout")
+		session.Close()
+	}
+}
+
 // CreateSession creates a new SSH session
 func (m *Manager) CreateSession(server models.SSHServer) (string, error) {
 	// Check session limit
@@ -117,6 +184,7 @@ func (m *Manager) CreateSession(server models.SSHServer) (string, error) {
 		ID:     sessionID,
 		Server: server,
 		Config: m.config,
+		keys:   m.keys,
 		logs:   []models.SSHSessionLog{},
 	}
 
@@ -126,9 +194,9 @@ func (m *Manager) CreateSession(server models.SSHServer) (string, error) {
 		}
 	}
 
-	session.onDisconnect = func() {
+	session.onDisconnect = func(reason string) {
 		if m.OnDisconnect != nil {
-			m.OnDisconnect(sessionID)
+			m.OnDisconnect(sessionID, reason)
 		}
 	}
 
@@ -144,6 +212,12 @@ func (m *Manager) CreateSession(server models.SSHServer) (string, error) {
 			"session_id", sessionID,
 			"server", server.Name,
 			"error", err.Error())
+
+		// Keep the rejected key so TrustHostKey can add exactly what was shown
+		var unknown *UnknownHostKeyError
+		if errors.As(err, &unknown) {
+			m.rememberUnknownHost(unknown)
+		}
 		return "", err
 	}
 
@@ -251,6 +325,55 @@ func (m *Manager) GetAllSessions() []models.SSHSession {
 	return sessions
 }
 
+// AddKnownHost records a host key in the configured known_hosts file,
+// hashing the hostname when HashKnownHosts is set
+func (m *Manager) AddKnownHost(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	return AddHostToKnownHosts(m.config.KnownHostsFile, hostname, remote, key, m.config.HashKnownHosts)
+}
+
+// TrustHostKey adds a host key that a connection was refused for to
+// known_hosts. Only keys a host actually presented can be trusted, picked
+// by the fingerprint the user confirmed; it returns the host's name.
+func (m *Manager) TrustHostKey(fingerprint string) (string, error) {
+	m.mu.Lock()
+	rejected, ok := m.unknownHosts[fingerprint]
+	m.mu.Unlock()
+	if !ok || time.Since(rejected.at) > unknownHostTTL {
+		return "", fmt.Errorf("no connection was refused for host key %s", fingerprint)
+	}
+	unknown := rejected.err
+
+	if err := m.AddKnownHost(unknown.Hostname, unknown.Remote, unknown.Key); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	delete(m.unknownHosts, fingerprint)
+	m.mu.Unlock()
+	return unknown.Hostname, nil
+}
+
+// rememberUnknownHost keeps a rejected host key for TrustHostKey. Keys
+// expire after unknownHostTTL, and past maxUnknownHosts the oldest is
+// dropped, so repeated refusals can't grow the map without bound.
+func (m *Manager) rememberUnknownHost(unknown *UnknownHostKeyError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldest := ""
+	for fingerprint, rejected := range m.unknownHosts {
+		if time.Since(rejected.at) > unknownHostTTL {
+			delete(m.unknownHosts, fingerprint)
+		} else if oldest == "" || rejected.at.Before(m.unknownHosts[oldest].at) {
+			oldest = fingerprint
+		}
+	}
+	if _, exists := m.unknownHosts[unknown.Fingerprint()]; !exists && len(m.unknownHosts) >= maxUnknownHosts {
+		delete(m.unknownHosts, oldest)
+	}
+	m.unknownHosts[unknown.Fingerprint()] = rejectedHostKey{err: unknown, at: time.Now()}
+}
+
 // Shutdown closes all SSH sessions
 func (m *Manager) Shutdown() {
 	slog.Info("shutting down SSH manager")
@@ -272,6 +395,7 @@ func (m *Manager) Shutdown() {
 		session.Close()
 	}
 	m.sessions = make(map[string]*Session)
+	m.keys.clear()
 
 	slog.Info("SSH manager shutdown complete", "closed_sessions", sessionCount)
 }
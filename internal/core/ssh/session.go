@@ -1,6 +1,7 @@
 package ssh
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -10,6 +11,7 @@ import (
 	"golang.org/x/crypto/ssh"
 
 	"github.com/caboose-desktop/internal/core/config"
+	"github.com/caboose-desktop/internal/core/crash"
 	"github.com/caboose-desktop/internal/models"
 )
 
@@ -22,7 +24,7 @@ type Session struct {
 	Session         *ssh.Session
 	mu              sync.Mutex
 	onOutput        func(data string)
-	onDisconnect    func()
+	onDisconnect    func(reason string)
 	onHealthUpdate  func(health models.SSHHealth)
 	logs            []models.SSHSessionLog
 	stdin           io.WriteCloser
@@ -33,6 +35,9 @@ type Session struct {
 	lastLatency     time.Duration
 	avgLatency      time.Duration
 	latencySamples  []time.Duration
+	lastActivity    time.Time
+	disconnectOnce  sync.Once
+	keys            *keyring // Unlocked private keys, shared with the manager
 }
 
 // Connect establishes the SSH connection with retry logic
@@ -64,6 +69,10 @@ func (s *Session) Connect() error {
 
 		err := s.connectOnce()
 		if err == nil {
+			s.mu.Lock()
+			s.lastActivity = time.Now()
+			s.mu.Unlock()
+
 			slog.Info("SSH connection established",
 				"server", s.Server.Name,
 				"host", s.Server.Host,
@@ -81,6 +90,13 @@ func (s *Session) Connect() error {
 			return nil
 		}
 
+		// Retrying can't help until the key is unlocked or the host trusted
+		var locked *PassphraseRequiredError
+		var unknown *UnknownHostKeyError
+		if errors.As(err, &locked) || errors.As(err, &unknown) {
+			return err
+		}
+
 		lastErr = err
 		slog.Warn("SSH connection attempt failed",
 			"attempt", attempt+1,
@@ -126,7 +142,7 @@ func (s *Session) connectOnce() error {
 		sshConfig.Auth = []ssh.AuthMethod{authMethod}
 		slog.Debug("using SSH agent for authentication", "server", s.Server.Name)
 	} else if s.Server.PrivateKeyPath != "" {
-		authMethod, err := LoadPrivateKey(s.Server.PrivateKeyPath)
+		authMethod, err := s.keyAuth()
 		if err != nil {
 			return err
 		}
@@ -138,7 +154,7 @@ func (s *Session) connectOnce() error {
 
 	// Connect to SSH server
 	addr := fmt.Sprintf("%s:%d", s.Server.Host, s.Server.Port)
-	client, err := ssh.Dial("tcp", addr, sshConfig)
+	client, err := dialServer(addr, s.Server.ProxyJump, sshConfig)
 	if err != nil {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
@@ -212,6 +228,7 @@ func (s *Session) Write(data []byte) error {
 	}
 
 	_, err := s.stdin.Write(data)
+	s.lastActivity = time.Now()
 
 	// Log input with rotation
 	s.addLogEntry(models.SSHSessionLog{
@@ -258,6 +275,8 @@ func (s *Session) Resize(rows, cols int) error {
 
 // readOutput reads from SSH session and emits to frontend
 func (s *Session) readOutput(reader io.Reader) {
+	defer crash.Guard("ssh output: " + s.ID)
+
 	buf := make([]byte, 4096)
 	for {
 		n, err := reader.Read(buf)
@@ -266,9 +285,7 @@ func (s *Session) readOutput(reader io.Reader) {
 				slog.Info("SSH session disconnected (EOF)",
 					"session_id", s.ID,
 					"server", s.Server.Name)
-				if s.onDisconnect != nil {
-					s.onDisconnect()
-				}
+				s.notifyDisconnect("connection closed")
 			} else {
 				slog.Error("error reading SSH output",
 					"session_id", s.ID,
@@ -285,6 +302,7 @@ func (s *Session) readOutput(reader io.Reader) {
 
 			// Log output with rotation
 			s.mu.Lock()
+			s.lastActivity = time.Now()
 			s.addLogEntry(models.SSHSessionLog{
 				SessionID: s.ID,
 				Timestamp: time.Now(),
@@ -329,9 +347,7 @@ func (s *Session) startKeepalive() {
 						"error", err.Error())
 
 					// Connection is dead, trigger disconnect
-					if s.onDisconnect != nil {
-						s.onDisconnect()
-					}
+					s.notifyDisconnect("keepalive failed")
 					return
 				}
 
@@ -445,6 +461,26 @@ func (s *Session) measureLatency() {
 	}
 }
 
+// notifyDisconnect reports the disconnect once, even if several readers hit EOF
+func (s *Session) notifyDisconnect(reason string) {
+	s.disconnectOnce.Do(func() {
+		if s.onDisconnect != nil {
+			s.onDisconnect(reason)
+		}
+	})
+}
+
+// IdleFor returns how long the session has had no input or output
+func (s *Session) IdleFor() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastActivity.IsZero() {
+		return 0
+	}
+	return time.Since(s.lastActivity)
+}
+
 // Close closes the SSH session
 func (s *Session) Close() error {
 	s.mu.Lock()
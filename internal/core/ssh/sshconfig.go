@@ -0,0 +1,279 @@
+package ssh
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+// maxIncludeDepth bounds nested Include directives in an ssh_config file
+const maxIncludeDepth = 16
+
+// hostBlock is a Host section of an ssh_config file. Options keep file order
+// because the first value found for a keyword wins.
+type hostBlock struct {
+	patterns []string
+	options  [][2]string // Lowercased keyword, value
+}
+
+// configParser collects the Host blocks of an ssh_config file and its includes
+type configParser struct {
+	home    string
+	blocks  []*hostBlock
+	current *hostBlock
+}
+
+// ParseSSHConfig reads an OpenSSH client config such as ~/.ssh/config and
+// returns a server for every concrete alias named on a Host line. Wildcard
+// blocks (including "Host *") aren't servers themselves but supply defaults
+// to the aliases they match, and negated patterns are honored. Match blocks
+// are skipped since their conditions can only be evaluated by ssh itself.
+// The returned servers have no ID yet.
+func ParseSSHConfig(configPath string) ([]models.SSHServer, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	// Options before the first Host line apply to every host
+	p := &configParser{home: home}
+	p.current = &hostBlock{patterns: []string{"*"}}
+	p.blocks = append(p.blocks, p.current)
+
+	if err := p.parseFile(configPath, 0); err != nil {
+		return nil, err
+	}
+
+	servers := []models.SSHServer{}
+	seen := make(map[string]bool)
+	for _, block := range p.blocks {
+		for _, alias := range block.patterns {
+			if seen[alias] || strings.ContainsAny(alias, "*?!") {
+				continue
+			}
+			seen[alias] = true
+			servers = append(servers, p.server(alias))
+		}
+	}
+
+	return servers, nil
+}
+
+func (p *configParser) parseFile(configPath string, depth int) error {
+	f, err := os.Open(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read SSH config: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Keywords are separated from values by whitespace and/or "="
+		end := strings.IndexAny(line, " \t=")
+		if end == -1 {
+			continue
+		}
+		keyword := strings.ToLower(line[:end])
+		value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line[end:]), "="))
+
+		switch keyword {
+		case "host":
+			p.current = &hostBlock{patterns: splitConfigArgs(value)}
+			p.blocks = append(p.blocks, p.current)
+
+		case "match":
+			// A block that matches no alias, so its options are ignored
+			p.current = &hostBlock{}
+			p.blocks = append(p.blocks, p.current)
+
+		case "include":
+			if depth >= maxIncludeDepth {
+				return fmt.Errorf("SSH config includes nested too deeply: %s", configPath)
+			}
+			for _, pattern := range splitConfigArgs(value) {
+				pattern = p.expandHome(pattern)
+				if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(p.home, ".ssh", pattern)
+				}
+				matches, _ := filepath.Glob(pattern)
+				for _, included := range matches {
+					if err := p.parseFile(included, depth+1); err != nil {
+						return err
+					}
+				}
+			}
+
+		default:
+			p.current.options = append(p.current.options, [2]string{keyword, unquoteConfigValue(value)})
+		}
+	}
+
+	return scanner.Err()
+}
+
+// options returns the first value of each option across the blocks
+// matching an alias
+func (p *configParser) options(alias string) map[string]string {
+	options := make(map[string]string)
+	for _, block := range p.blocks {
+		if !matchesHost(block.patterns, alias) {
+			continue
+		}
+		for _, option := range block.options {
+			if _, set := options[option[0]]; !set {
+				options[option[0]] = option[1]
+			}
+		}
+	}
+	return options
+}
+
+// endpoint resolves an alias to the host, user and port ssh would connect
+// to: its HostName, User (else the local user) and Port (else 22)
+func (p *configParser) endpoint(alias string, options map[string]string) (host, username string, port int) {
+	host, username, port = alias, options["user"], 22
+	if username == "" {
+		if current, err := user.Current(); err == nil {
+			username = current.Username
+		}
+	}
+	if hostname := options["hostname"]; hostname != "" {
+		host = strings.ReplaceAll(hostname, "%h", alias)
+	}
+	if n, err := strconv.Atoi(options["port"]); err == nil && n > 0 && n <= 65535 {
+		port = n
+	}
+	return host, username, port
+}
+
+// server builds the server for an alias from the options matching it
+func (p *configParser) server(alias string) models.SSHServer {
+	options := p.options(alias)
+
+	server := models.SSHServer{Name: alias}
+	server.Host, server.Username, server.Port = p.endpoint(alias, options)
+	if jump := options["proxyjump"]; jump != "" && !strings.EqualFold(jump, "none") {
+		server.ProxyJump = p.resolveJump(jump)
+	}
+
+	if identity := options["identityfile"]; identity != "" && !strings.EqualFold(identity, "none") {
+		identity = strings.NewReplacer("%d", p.home, "%h", server.Host, "%r", server.Username).Replace(identity)
+		server.AuthMethod = "key"
+		server.PrivateKeyPath = p.expandHome(identity)
+	} else {
+		server.AuthMethod = "agent"
+		server.UseAgent = true
+	}
+
+	return server
+}
+
+// resolveJump rewrites each comma-separated ProxyJump hop, which is usually
+// an alias from the same config, as the user@host:port it stands for. A user
+// or port given in the hop overrides the alias's.
+func (p *configParser) resolveJump(jump string) string {
+	hops := strings.Split(jump, ",")
+	for i, hop := range hops {
+		spec := strings.TrimPrefix(strings.TrimSpace(hop), "ssh://")
+		hopUser := ""
+		if at := strings.LastIndex(spec, "@"); at != -1 {
+			hopUser, spec = spec[:at], spec[at+1:]
+		}
+		hopPort := 0
+		if h, port, err := net.SplitHostPort(spec); err == nil {
+			if n, err := strconv.Atoi(port); err == nil {
+				spec, hopPort = h, n
+			}
+		}
+		if spec == "" {
+			continue // Left for dialServer to reject
+		}
+
+		host, username, port := p.endpoint(spec, p.options(spec))
+		if hopUser != "" {
+			username = hopUser
+		}
+		if hopPort != 0 {
+			port = hopPort
+		}
+		address := net.JoinHostPort(host, strconv.Itoa(port))
+		if username != "" {
+			address = username + "@" + address
+		}
+		hops[i] = address
+	}
+	return strings.Join(hops, ",")
+}
+
+// expandHome expands a leading ~/ to the home directory
+func (p *configParser) expandHome(s string) string {
+	if s == "~" || strings.HasPrefix(s, "~/") {
+		return filepath.Join(p.home, s[1:])
+	}
+	return s
+}
+
+// matchesHost reports whether an alias matches a Host line's patterns: at
+// least one pattern must match and no negated pattern may
+func matchesHost(patterns []string, alias string) bool {
+	alias = strings.ToLower(alias)
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.ToLower(strings.TrimPrefix(pattern, "!"))
+
+		if ok, _ := path.Match(pattern, alias); ok {
+			if negated {
+				return false
+			}
+			matched = true
+		}
+	}
+	return matched
+}
+
+// splitConfigArgs splits a value into whitespace-separated arguments, keeping
+// double-quoted arguments whole
+func splitConfigArgs(value string) []string {
+	var args []string
+	var current strings.Builder
+	inQuotes := false
+	for _, r := range value {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if current.Len() > 0 {
+				args = append(args, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// unquoteConfigValue strips double quotes around a whole value
+func unquoteConfigValue(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
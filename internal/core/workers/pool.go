@@ -3,16 +3,26 @@ package workers
 import (
 	"context"
 	"fmt"
+	"log"
 	"runtime"
 	"sync"
 	"time"
+
+	"github.com/caboose-desktop/internal/core/crash"
 )
 
+// TaskTimeout is how long a task may run before the pool gives up on it
+const TaskTimeout = 30 * time.Second
+
+// maxFailedTasks is how many recent failures the pool keeps for FailedTasks
+const maxFailedTasks = 100
+
 // Task represents a unit of work to be executed by the worker pool
 type Task struct {
 	ID      string
 	Execute func(ctx context.Context) (interface{}, error)
 	Result  chan TaskResult
+	Timeout time.Duration // TaskTimeout when zero
 }
 
 // TaskResult contains the result of task execution
@@ -33,6 +43,19 @@ type Pool struct {
 	mu         sync.RWMutex
 	stats      PoolStats
 	closed     bool
+
+	// Ring of the most recent failures, guarded by mu
+	failed      [maxFailedTasks]FailedTask
+	failedHead  int
+	failedCount int
+}
+
+// FailedTask records a task that returned an error or timed out
+type FailedTask struct {
+	ID        string    `json:"id"`
+	Error     string    `json:"error"`
+	Duration  int64     `json:"duration"` // in milliseconds
+	Timestamp time.Time `json:"timestamp"`
 }
 
 // PoolStats tracks pool performance metrics
@@ -99,8 +122,13 @@ func (p *Pool) worker(id int) {
 func (p *Pool) processTask(task Task) {
 	startTime := time.Now()
 
+	timeout := task.Timeout
+	if timeout <= 0 {
+		timeout = TaskTimeout
+	}
+
 	// Execute task with context and timeout
-	taskCtx, cancel := context.WithTimeout(p.ctx, 30*time.Second)
+	taskCtx, cancel := context.WithTimeout(p.ctx, timeout)
 	defer cancel()
 
 	// SECURITY: Enforce timeout with monitoring channel
@@ -110,6 +138,17 @@ func (p *Pool) processTask(task Task) {
 	}, 1)
 
 	go func() {
+		// A panicking task fails like one returning an error
+		defer func() {
+			if r := recover(); r != nil {
+				crash.Capture("worker task: "+task.ID, r)
+				done <- struct {
+					data interface{}
+					err  error
+				}{nil, fmt.Errorf("task %s panicked: %v", task.ID, r)}
+			}
+		}()
+
 		taskData, taskErr := task.Execute(taskCtx)
 		done <- struct {
 			data interface{}
@@ -127,10 +166,14 @@ func (p *Pool) processTask(task Task) {
 		err = result.err
 	case <-taskCtx.Done():
 		// Task timed out
-		err = fmt.Errorf("task timeout after 30s: %w", taskCtx.Err())
+		err = fmt.Errorf("task timeout after %s: %w", timeout, taskCtx.Err())
 	}
 
 	duration := time.Since(startTime)
+	if err != nil {
+		// FailedTasks is sanitized for the frontend, so this is the full record
+		log.Printf("[ERROR] Task %s failed after %s: %v", task.ID, duration, err)
+	}
 
 	// Update stats
 	p.mu.Lock()
@@ -139,6 +182,16 @@ func (p *Pool) processTask(task Task) {
 	p.stats.AverageDuration = time.Duration(int64(p.stats.TotalDuration) / p.stats.TasksCompleted)
 	if err != nil {
 		p.stats.TasksFailed++
+		p.failed[p.failedHead] = FailedTask{
+			ID:        task.ID,
+			Error:     err.Error(),
+			Duration:  duration.Milliseconds(),
+			Timestamp: time.Now(),
+		}
+		p.failedHead = (p.failedHead + 1) % maxFailedTasks
+		if p.failedCount < maxFailedTasks {
+			p.failedCount++
+		}
 	}
 	p.mu.Unlock()
 
@@ -158,6 +211,11 @@ func (p *Pool) processTask(task Task) {
 
 // Submit submits a task to the pool for execution
 func (p *Pool) Submit(task Task) error {
+	return p.submit(context.Background(), task)
+}
+
+// submit queues a task, giving up if ctx is cancelled while the queue is full
+func (p *Pool) submit(ctx context.Context, task Task) error {
 	p.mu.RLock()
 	if p.closed {
 		p.mu.RUnlock()
@@ -165,15 +223,16 @@ func (p *Pool) Submit(task Task) error {
 	}
 	p.mu.RUnlock()
 
-	p.mu.Lock()
-	p.stats.TasksSubmitted++
-	p.mu.Unlock()
-
 	select {
 	case p.tasks <- task:
+		p.mu.Lock()
+		p.stats.TasksSubmitted++
+		p.mu.Unlock()
 		return nil
 	case <-p.ctx.Done():
 		return fmt.Errorf("pool is shutting down")
+	case <-ctx.Done():
+		return cancelledError(ctx, task.ID)
 	}
 }
 
@@ -202,12 +261,19 @@ func (p *Pool) SubmitWithCallback(id string, fn func(ctx context.Context) (inter
 
 // SubmitAndWait submits a task and waits for the result
 func (p *Pool) SubmitAndWait(id string, fn func(ctx context.Context) (interface{}, error)) TaskResult {
+	return p.SubmitAndWaitTimeout(id, TaskTimeout, fn)
+}
+
+// SubmitAndWaitTimeout submits a task that may run for up to timeout, for
+// work known to outlast TaskTimeout, and waits for the result
+func (p *Pool) SubmitAndWaitTimeout(id string, timeout time.Duration, fn func(ctx context.Context) (interface{}, error)) TaskResult {
 	resultChan := make(chan TaskResult, 1)
 
 	task := Task{
 		ID:      id,
 		Execute: fn,
 		Result:  resultChan,
+		Timeout: timeout,
 	}
 
 	if err := p.Submit(task); err != nil {
@@ -227,6 +293,19 @@ func (p *Pool) Stats() PoolStats {
 	return p.stats
 }
 
+// FailedTasks returns the most recent failed tasks, oldest first
+func (p *Pool) FailedTasks() []FailedTask {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	result := make([]FailedTask, 0, p.failedCount)
+	start := (p.failedHead - p.failedCount + maxFailedTasks) % maxFailedTasks
+	for i := 0; i < p.failedCount; i++ {
+		result = append(result, p.failed[(start+i)%maxFailedTasks])
+	}
+	return result
+}
+
 // Close gracefully shuts down the pool
 func (p *Pool) Close() {
 	p.mu.Lock()
@@ -261,15 +340,42 @@ func (p *Pool) CloseWithTimeout(timeout time.Duration) error {
 
 // Batch processes multiple tasks concurrently and returns results
 func (p *Pool) Batch(tasks []Task) []TaskResult {
+	return p.BatchWithContext(context.Background(), tasks)
+}
+
+// BatchWithContext processes tasks concurrently like Batch, passing each a
+// context that is also cancelled with ctx. Once ctx is cancelled the batch
+// stops waiting: tasks that finished keep their results and the rest get an
+// error wrapping ctx.Err(). Tasks still queued return without running, but
+// one already running only stops early if it watches its context.
+func (p *Pool) BatchWithContext(ctx context.Context, tasks []Task) []TaskResult {
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	results := make([]TaskResult, len(tasks))
 	var wg sync.WaitGroup
 
 	for i, task := range tasks {
+		// An abandoned task's result must not block the worker sending it
+		if cap(task.Result) == 0 {
+			task.Result = make(chan TaskResult, 1)
+		}
+		execute := task.Execute
+		task.Execute = func(taskCtx context.Context) (interface{}, error) {
+			if batchCtx.Err() != nil {
+				return nil, cancelledError(batchCtx, task.ID)
+			}
+			taskCtx, stop := context.WithCancel(taskCtx)
+			defer stop()
+			defer context.AfterFunc(batchCtx, stop)()
+			return execute(taskCtx)
+		}
+
 		wg.Add(1)
 		go func(index int, t Task) {
 			defer wg.Done()
 
-			if err := p.Submit(t); err != nil {
+			if err := p.submit(batchCtx, t); err != nil {
 				results[index] = TaskResult{
 					ID:    t.ID,
 					Error: err,
@@ -277,7 +383,14 @@ func (p *Pool) Batch(tasks []Task) []TaskResult {
 				return
 			}
 
-			results[index] = <-t.Result
+			select {
+			case results[index] = <-t.Result:
+			case <-batchCtx.Done():
+				results[index] = TaskResult{
+					ID:    t.ID,
+					Error: cancelledError(batchCtx, t.ID),
+				}
+			}
 		}(i, task)
 	}
 
@@ -285,6 +398,11 @@ func (p *Pool) Batch(tasks []Task) []TaskResult {
 	return results
 }
 
+// cancelledError reports a task given up on because ctx was cancelled
+func cancelledError(ctx context.Context, id string) error {
+	return fmt.Errorf("task %s cancelled: %w", id, ctx.Err())
+}
+
 // ResizePool dynamically adjusts the number of workers
 func (p *Pool) ResizePool(newSize int) {
 	if newSize <= 0 {
@@ -0,0 +1,21 @@
+package models
+
+// DependencyStatus says whether a project's packages for one package manager
+// are installed, and how to install them
+type DependencyStatus struct {
+	Manager  string `json:"manager"`  // "bundler", "npm", "yarn" or "pnpm"
+	Manifest string `json:"manifest"` // e.g. Gemfile or package.json
+
+	// Satisfied is true when everything the manifest lists is installed
+	Satisfied bool `json:"satisfied"`
+
+	// Missing names the packages not installed, when the check lists them
+	Missing []string `json:"missing,omitempty"`
+
+	// Message explains an unsatisfied check, e.g. that the tool itself is
+	// not installed
+	Message string `json:"message,omitempty"`
+
+	// InstallCommand installs the missing packages
+	InstallCommand []string `json:"installCommand"`
+}
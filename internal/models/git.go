@@ -29,10 +29,22 @@ type GitDiff struct {
 	NewMode   string        `json:"newMode,omitempty"`
 	Hunks     []GitDiffHunk `json:"hunks"`
 	IsBinary  bool          `json:"isBinary"`
+	TooLarge  bool          `json:"tooLarge,omitempty"` // Too big to show; no hunks
 	OldSHA    string        `json:"oldSha,omitempty"`
 	NewSHA    string        `json:"newSha,omitempty"`
 }
 
+// GitDiffContext represents a range of unchanged lines used to expand diff context
+type GitDiffContext struct {
+	FilePath   string   `json:"filePath"`
+	Ref        string   `json:"ref,omitempty"` // Empty for the working tree
+	StartLine  int      `json:"startLine"`     // 1-based, inclusive
+	EndLine    int      `json:"endLine"`       // 1-based, inclusive (clamped to file length)
+	Lines      []string `json:"lines"`
+	TotalLines int      `json:"totalLines"`
+	Exists     bool     `json:"exists"` // False if the file doesn't exist at this revision
+}
+
 // GitBlameLine represents a line with blame information
 type GitBlameLine struct {
 	LineNumber    int       `json:"lineNumber"`
@@ -69,20 +81,24 @@ type GitCommit struct {
 
 // GitBranch represents a git branch
 type GitBranch struct {
-	Name      string `json:"name"`
-	Current   bool   `json:"current"`
-	Remote    string `json:"remote,omitempty"`
-	Upstream  string `json:"upstream,omitempty"`
+	Name       string `json:"name"`
+	Current    bool   `json:"current"`
+	IsRemote   bool   `json:"isRemote"` // A remotes/... tracking branch
+	Remote     string `json:"remote,omitempty"`
+	Upstream   string `json:"upstream,omitempty"`
+	Ahead      int    `json:"ahead"`  // Commits ahead of Upstream
+	Behind     int    `json:"behind"` // Commits behind Upstream
 	CommitHash string `json:"commitHash"`
 }
 
 // GitStatus represents the status of the repository
 type GitStatus struct {
-	CurrentBranch string          `json:"currentBranch"`
-	Ahead         int             `json:"ahead"` // Commits ahead of upstream
-	Behind        int             `json:"behind"` // Commits behind upstream
-	Files         []GitFileStatus `json:"files"`
-	HasConflicts  bool            `json:"hasConflicts"`
+	CurrentBranch   string          `json:"currentBranch"`
+	Ahead           int             `json:"ahead"`  // Commits ahead of upstream
+	Behind          int             `json:"behind"` // Commits behind upstream
+	Files           []GitFileStatus `json:"files"`
+	HasConflicts    bool            `json:"hasConflicts"`
+	RepositoryState string          `json:"repositoryState"` // "clean", "merging", "rebasing", "applying-patches", "cherry-picking", "reverting", "bisecting", "detached"
 }
 
 // GitConflictFile represents a file with merge conflicts
@@ -103,6 +119,19 @@ type GitConflictRegion struct {
 	BaseLines   []string `json:"baseLines,omitempty"`
 }
 
+// GitRegionChoice picks how one conflict region of a file is resolved
+type GitRegionChoice struct {
+	Region     int    `json:"region"`     // Index into the file's conflict regions
+	Resolution string `json:"resolution"` // "ours", "theirs" or "both" (ours then theirs)
+}
+
+// GitConflictPreview is a file's content with some conflict regions resolved
+type GitConflictPreview struct {
+	Path       string `json:"path"`
+	Content    string `json:"content"`
+	Unresolved int    `json:"unresolved"` // Regions left with their conflict markers
+}
+
 // GitStash represents a stash entry
 type GitStash struct {
 	Index   int    `json:"index"`
@@ -131,6 +160,16 @@ type GitCommitOptions struct {
 	Author  string   `json:"author,omitempty"` // Format: "Name <email>"
 }
 
+// GitCommitResult represents the result of a commit. A commit rejected by a
+// pre-commit, prepare-commit-msg or commit-msg hook has the hook's output in
+// HookOutput.
+type GitCommitResult struct {
+	Success    bool   `json:"success"`
+	Hash       string `json:"hash,omitempty"`
+	HookOutput string `json:"hookOutput,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
 // GitMergeResult represents the result of a merge operation
 type GitMergeResult struct {
 	Success   bool              `json:"success"`
@@ -138,6 +177,16 @@ type GitMergeResult struct {
 	Message   string            `json:"message"`
 }
 
+// GitRebaseAction is a commit in an interactive rebase and what to do with
+// it: pick, reword, squash, fixup or drop
+type GitRebaseAction struct {
+	Hash      string `json:"hash"`
+	ShortHash string `json:"shortHash"`
+	Summary   string `json:"summary"`
+	Action    string `json:"action"`
+	Message   string `json:"message,omitempty"` // New commit message, for reword
+}
+
 // GitDiffOptions represents options for generating diffs
 type GitDiffOptions struct {
 	FilePath  string `json:"filePath,omitempty"`
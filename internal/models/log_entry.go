@@ -13,6 +13,15 @@ const (
 	LogLevelFatal   LogLevel = "fatal"
 )
 
+// LogStream identifies which output stream a log line came from
+type LogStream string
+
+const (
+	LogStreamStdout LogStream = "stdout"
+	LogStreamStderr LogStream = "stderr"
+	LogStreamPTY    LogStream = "pty" // PTY merges stdout and stderr
+)
+
 // LogEntry represents a parsed log line
 type LogEntry struct {
 	// ID is the unique identifier for this log entry
@@ -21,6 +30,10 @@ type LogEntry struct {
 	// Timestamp is when this log was generated
 	Timestamp time.Time `json:"timestamp"`
 
+	// HasTimestamp is true when Timestamp was read from the line itself
+	// rather than set to the time the line was parsed
+	HasTimestamp bool `json:"hasTimestamp,omitempty"`
+
 	// Raw is the original unparsed log line
 	Raw string `json:"raw"`
 
@@ -33,6 +46,9 @@ type LogEntry struct {
 	// ProcessName is the name of the process that generated this log
 	ProcessName string `json:"processName"`
 
+	// Stream is the output stream the line was read from (stdout, stderr, pty)
+	Stream LogStream `json:"stream,omitempty"`
+
 	// RequestID groups logs from the same request (framework-specific)
 	RequestID string `json:"requestId,omitempty"`
 
@@ -60,14 +76,17 @@ type SQLLog struct {
 
 // RequestLog represents HTTP request information
 type RequestLog struct {
-	Method     string        `json:"method"`
-	Path       string        `json:"path"`
-	Controller string        `json:"controller,omitempty"`
-	Action     string        `json:"action,omitempty"`
-	Status     int           `json:"status,omitempty"`
-	Duration   float64       `json:"duration,omitempty"` // in milliseconds
-	IP         string        `json:"ip,omitempty"`
-	Params     []interface{} `json:"params,omitempty"`
+	Method      string        `json:"method"`
+	Path        string        `json:"path"`
+	Controller  string        `json:"controller,omitempty"`
+	Action      string        `json:"action,omitempty"`
+	Status      int           `json:"status,omitempty"`
+	Duration    float64       `json:"duration,omitempty"` // in milliseconds
+	ViewTime    float64       `json:"viewTime,omitempty"` // in milliseconds, spent rendering views
+	DbTime      float64       `json:"dbTime,omitempty"`   // in milliseconds, spent in ActiveRecord
+	Allocations int64         `json:"allocations,omitempty"`
+	IP          string        `json:"ip,omitempty"`
+	Params      []interface{} `json:"params,omitempty"`
 }
 
 // ExceptionLog represents exception/error information
@@ -11,6 +11,12 @@ const (
 	ProcessStatusRunning  ProcessStatus = "running"
 	ProcessStatusCrashed  ProcessStatus = "crashed"
 	ProcessStatusStopping ProcessStatus = "stopping"
+
+	// ProcessStatusStartTimeout is a process killed for not becoming ready in time
+	ProcessStatusStartTimeout ProcessStatus = "start_timeout"
+
+	// ProcessStatusSuspended is a process paused with SIGSTOP until resumed
+	ProcessStatusSuspended ProcessStatus = "suspended"
 )
 
 // Process represents a managed process
@@ -30,6 +36,9 @@ type Process struct {
 	// Environment variables for this process
 	Environment map[string]string `json:"environment,omitempty"`
 
+	// EnvFiles are dotenv files loaded at start, under Environment
+	EnvFiles []string `json:"envFiles,omitempty"`
+
 	// Status is the current process status
 	Status ProcessStatus `json:"status"`
 
@@ -45,6 +54,9 @@ type Process struct {
 	// AutoRestart determines if the process should auto-restart on crash
 	AutoRestart bool `json:"autoRestart"`
 
+	// AutoStart starts the process, after its dependencies, when the project loads
+	AutoStart bool `json:"autoStart"`
+
 	// RestartCount tracks how many times this process has restarted
 	RestartCount int `json:"restartCount"`
 
@@ -53,6 +65,66 @@ type Process struct {
 
 	// UsePTY determines if the process should run in a pseudo-terminal
 	UsePTY bool `json:"usePty"`
+
+	// HealthCheck decides when the process counts as started, if set
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+
+	// DependsOn names processes that must be ready before this one starts
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Port is the TCP port the process listens on, checked to be free before it starts
+	Port int `json:"port,omitempty"`
+}
+
+// HealthCheck decides when a started process is ready. Until then it stays
+// "starting", and it is killed if it isn't ready within Timeout.
+type HealthCheck struct {
+	// Port is a local TCP port that accepts connections once the process is ready
+	Port int `json:"port,omitempty" toml:"port,omitempty"`
+
+	// LogPattern is a regular expression matched against output lines, e.g. "Listening on"
+	LogPattern string `json:"logPattern,omitempty" toml:"log_pattern,omitempty"`
+
+	// Timeout in seconds to become ready (default 60)
+	Timeout int `json:"timeout,omitempty" toml:"timeout,omitempty"`
+}
+
+// ProcessEventType is the kind of lifecycle event recorded for a process
+type ProcessEventType string
+
+const (
+	ProcessEventStarted   ProcessEventType = "started"
+	ProcessEventStopped   ProcessEventType = "stopped"
+	ProcessEventCrashed   ProcessEventType = "crashed"
+	ProcessEventRestarted ProcessEventType = "restarted"
+	ProcessEventSuspended ProcessEventType = "suspended"
+	ProcessEventResumed   ProcessEventType = "resumed"
+)
+
+// ProcessEvent is a single entry in a process's lifecycle history
+type ProcessEvent struct {
+	// Type is the event kind
+	Type ProcessEventType `json:"type"`
+
+	// Timestamp is when the event happened
+	Timestamp time.Time `json:"timestamp"`
+
+	// ExitCode is set for exits, so a clean exit(0) can be told apart from a crash
+	ExitCode *int `json:"exitCode,omitempty"`
+
+	// PID is the process ID for started events
+	PID int `json:"pid,omitempty"`
+
+	// Message holds extra detail (start error, restart backoff)
+	Message string `json:"message,omitempty"`
+}
+
+// ProcessLogStats is a process's output rate over the last few seconds
+type ProcessLogStats struct {
+	Name            string  `json:"name"`
+	LinesPerSecond  float64 `json:"linesPerSecond"`
+	ErrorsPerSecond float64 `json:"errorsPerSecond"`
+	Flooding        bool    `json:"flooding"` // Over a configured flood threshold
 }
 
 // ProcessConfig represents the configuration for a process from .caboose.toml
@@ -62,7 +134,36 @@ type ProcessConfig struct {
 	Args        []string          `toml:"args,omitempty"`
 	WorkingDir  string            `toml:"working_dir,omitempty"`
 	Environment map[string]string `toml:"environment,omitempty"`
+	EnvFiles    []string          `toml:"env_files,omitempty"` // dotenv files, relative to WorkingDir
 	AutoRestart bool              `toml:"auto_restart"`
+	AutoStart   bool              `toml:"auto_start,omitempty"` // Started with its dependencies when the project loads
 	UsePTY      bool              `toml:"use_pty"`
 	Color       string            `toml:"color,omitempty"`
+	HealthCheck *HealthCheck      `toml:"health_check,omitempty"`
+	DependsOn   []string          `toml:"depends_on,omitempty"` // Started after these are ready
+	Port        int               `toml:"port,omitempty"`       // Listening port, checked before start
+}
+
+// ProcessTemplate is a ready-made process the add-process form can offer.
+// Its JSON fields match the ones AddProcess reads.
+type ProcessTemplate struct {
+	// ID is unique within a catalog, e.g. "sidekiq" or "npm:dev"
+	ID string `json:"id"`
+
+	// Name is the suggested process name
+	Name string `json:"name"`
+
+	// Description says what the process does
+	Description string `json:"description"`
+
+	Command     string   `json:"command"`
+	Args        []string `json:"args"`
+	Color       string   `json:"color"`
+	Port        int      `json:"port,omitempty"`
+	UsePTY      bool     `json:"usePty"`
+	AutoRestart bool     `json:"autoRestart"`
+
+	// Source is where the template came from: "framework" for the detected
+	// plugin, "project" for the project's own files
+	Source string `json:"source"`
 }
@@ -22,6 +22,35 @@ type QueryAnalysis struct {
 
 	// SlowQueries contains queries exceeding the threshold
 	SlowQueries []QueryInfo `json:"slowQueries,omitempty"`
+
+	// DuplicateQueries contains repeated queries not reported as N+1 patterns
+	DuplicateQueries []DuplicateQuery `json:"duplicateQueries,omitempty"`
+}
+
+// DuplicateQuery is a query run more than once in the same request, such as
+// a record reloaded instead of reused, that is too infrequent to be an N+1
+type DuplicateQuery struct {
+	// Fingerprint is the repeated query pattern
+	Fingerprint string `json:"fingerprint"`
+
+	// SQL is the first occurrence of the query
+	SQL string `json:"sql"`
+
+	// Table is the table being queried
+	Table string `json:"table"`
+
+	// Count is how many times the query ran in the request
+	Count int `json:"count"`
+
+	// TotalDuration is the total time spent on these queries in ms
+	TotalDuration float64 `json:"totalDuration"`
+
+	// Caller is the source location that issued the query, when logged
+	Caller string `json:"caller,omitempty"`
+
+	// RequestID and Endpoint identify the request the queries ran in
+	RequestID string `json:"requestId,omitempty"`
+	Endpoint  string `json:"endpoint,omitempty"`
 }
 
 // QueryInfo represents information about a single query
@@ -49,6 +78,9 @@ type QueryInfo struct {
 
 	// HasSelectStar indicates if this uses SELECT *
 	HasSelectStar bool `json:"hasSelectStar"`
+
+	// Caller is the source location that issued the query, when logged
+	Caller string `json:"caller,omitempty"`
 }
 
 // N1Warning represents a detected N+1 query pattern
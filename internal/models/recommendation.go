@@ -84,6 +84,17 @@ type QueryExecution struct {
 	PerformanceScore int     `json:"performanceScore"` // 0-100
 }
 
+// OptimizationSession is a saved original/optimized query pair with its comparison.
+// Saved sessions keep the metrics but not the EXPLAIN output.
+type OptimizationSession struct {
+	ID           string          `json:"id"`
+	OriginalSQL  string          `json:"originalSql"`
+	OptimizedSQL string          `json:"optimizedSql"`
+	Note         string          `json:"note,omitempty"`
+	CreatedAt    string          `json:"createdAt"`
+	Comparison   QueryComparison `json:"comparison"`
+}
+
 // Improvement shows the improvement metrics between two query executions
 type Improvement struct {
 	TimeReduction  float64 `json:"timeReduction"`  // Percentage
@@ -12,6 +12,7 @@ type SSHServer struct {
 	AuthMethod     string            `json:"authMethod" toml:"auth_method"` // "agent", "key", "password"
 	PrivateKeyPath string            `json:"privateKeyPath,omitempty" toml:"private_key_path,omitempty"`
 	UseAgent       bool              `json:"useAgent" toml:"use_agent"`
+	ProxyJump      string            `json:"proxyJump,omitempty" toml:"proxy_jump,omitempty"` // Comma-separated [user@]host[:port] jump hosts
 	Tags           []string          `json:"tags,omitempty" toml:"tags,omitempty"`
 	Environment    map[string]string `json:"environment,omitempty" toml:"environment,omitempty"`
 	Color          string            `json:"color,omitempty" toml:"color,omitempty"`
@@ -47,7 +48,7 @@ type SSHSessionLog struct {
 	SessionID string    `json:"sessionId" csv:"session_id"`
 	Timestamp time.Time `json:"timestamp" csv:"timestamp"`
 	Server    string    `json:"server" csv:"server"`
-	Type      string    `json:"type" csv:"type"` // "input", "output"
+	Type      string    `json:"type" csv:"type"` // "input", "output", "command"
 	Content   string    `json:"content" csv:"content"`
 }
 
@@ -60,3 +61,11 @@ type SSHHealth struct {
 	PacketLoss   float64 `json:"packetLoss"` // percentage
 	LastCheckAt  string  `json:"lastCheckAt"`
 }
+
+// SSHCommandResult is the output of a one-off command run over an SSH session
+type SSHCommandResult struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exitCode"`
+	Duration int64  `json:"duration"` // in milliseconds
+}
@@ -0,0 +1,30 @@
+package models
+
+// TestResult is one test that failed or was skipped in a run
+type TestResult struct {
+	Name     string  `json:"name"`
+	File     string  `json:"file,omitempty"`
+	Line     int     `json:"line,omitempty"`
+	Status   string  `json:"status"` // "passed", "failed", "pending"
+	Duration float64 `json:"duration,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// TestSummary is the outcome of a test run as read from the runner's output
+type TestSummary struct {
+	Total    int          `json:"total"`
+	Passed   int          `json:"passed"`
+	Failed   int          `json:"failed"`
+	Pending  int          `json:"pending"`
+	Duration float64      `json:"duration"` // in seconds
+	Results  []TestResult `json:"results"`
+}
+
+// TestFailureSource is the source around the line a test failed on
+type TestFailureSource struct {
+	File       string   `json:"file"`      // Relative to the project root
+	Line       int      `json:"line"`      // 1-based line of the failure
+	StartLine  int      `json:"startLine"` // 1-based line of Lines[0]
+	Lines      []string `json:"lines"`
+	TotalLines int      `json:"totalLines"`
+}
@@ -1,6 +1,10 @@
 package plugin
 
-import "github.com/caboose-desktop/internal/models"
+import (
+	"context"
+
+	"github.com/caboose-desktop/internal/models"
+)
 
 // FrameworkPlugin defines the interface that all framework plugins must implement
 type FrameworkPlugin interface {
@@ -26,6 +30,24 @@ type FrameworkPlugin interface {
 	GetTestRunner() *TestRunner
 }
 
+// SQLSourceProvider is implemented by plugins that can collect SQL from
+// outside Caboose-managed processes, such as a server the user runs themselves
+type SQLSourceProvider interface {
+	// SQLSourceTypes lists the source types the plugin offers (e.g., "development-log")
+	SQLSourceTypes() []string
+
+	// NewSQLSource creates a source of the given type for a project
+	NewSQLSource(sourceType, projectPath string) (SQLSource, error)
+}
+
+// SQLSource streams parsed log entries into query analysis. Entries with SQL
+// are recorded as query executions; request start and completion entries let
+// the queries be grouped by request.
+type SQLSource interface {
+	// Run calls emit for each entry until ctx is cancelled
+	Run(ctx context.Context, emit func(*models.LogEntry)) error
+}
+
 // DebugConfig holds debugger configuration for a framework
 type DebugConfig struct {
 	// Type is the debugger type (e.g., "ruby-debug-ide", "debugpy", "xdebug")
@@ -55,3 +77,39 @@ type TestRunner struct {
 	// FilePattern is the glob pattern to match test files
 	FilePattern string
 }
+
+// ProcessTemplateProvider is implemented by plugins that suggest processes
+// commonly run alongside the framework
+type ProcessTemplateProvider interface {
+	// ProcessTemplates returns the templates that apply to a project
+	ProcessTemplates(projectPath string) []models.ProcessTemplate
+}
+
+// TestOutputParser is implemented by plugins that can read their test
+// runner's output
+type TestOutputParser interface {
+	// ParseTestOutput parses the output of the named TestRunner
+	ParseTestOutput(runner, output string) *models.TestSummary
+}
+
+// TestStream reads a test runner's output line by line as it runs
+type TestStream interface {
+	// Feed parses the next line, returning the failed and pending tests it
+	// completed and, at the end of a run, the run's summary
+	Feed(line string) ([]models.TestResult, *models.TestSummary)
+}
+
+// TestStreamParser is implemented by plugins that can follow their test
+// runner's watch mode output
+type TestStreamParser interface {
+	// NewTestStream returns a parser for the named TestRunner's output
+	NewTestStream(runner string) TestStream
+}
+
+// DependencyChecker is implemented by plugins that can tell whether the
+// framework's packages are installed
+type DependencyChecker interface {
+	// CheckDependencies checks the project's packages, one status per
+	// package manager in use
+	CheckDependencies(projectPath string) []models.DependencyStatus
+}
@@ -0,0 +1,314 @@
+package generic
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caboose-desktop/internal/models"
+	"github.com/google/uuid"
+)
+
+// Parser parses log lines from projects without a detected framework using
+// common conventions: JSON lines, logfmt, bracketed levels and syslog
+type Parser struct {
+	// Compiled regex patterns
+	timestampPattern *regexp.Regexp
+	syslogPattern    *regexp.Regexp
+	bracketPattern   *regexp.Regexp
+	prefixPattern    *regexp.Regexp
+	logfmtPattern    *regexp.Regexp
+}
+
+// Timestamp layouts tried in order for prefix timestamps
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05.000Z0700",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05.000000",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05,000",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006/01/02 15:04:05",
+}
+
+// JSON keys commonly used by structured loggers
+var (
+	jsonMessageKeys = []string{"msg", "message", "text", "event"}
+	jsonLevelKeys   = []string{"level", "severity", "lvl", "log.level", "levelname"}
+	jsonTimeKeys    = []string{"time", "timestamp", "ts", "@timestamp", "datetime"}
+)
+
+// NewParser creates a new generic log parser
+func NewParser() *Parser {
+	return &Parser{
+		// 2024-01-15T10:30:00Z, [2024-01-15 10:30:00.123], 2024/01/15 10:30:00
+		timestampPattern: regexp.MustCompile(
+			`^\[?(\d{4}[-/]\d{2}[-/]\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?)\]?\s*`,
+		),
+		// Jan 15 10:30:00 hostname app[123]:
+		syslogPattern: regexp.MustCompile(
+			`^([A-Z][a-z]{2}\s+\d{1,2}\s\d{2}:\d{2}:\d{2})\s+(\S+)\s+([^:\s\[]+)(?:\[(\d+)\])?:\s*`,
+		),
+		// [ERROR], [warn], <err>
+		bracketPattern: regexp.MustCompile(
+			`(?i)^[\[<](debug|trace|info|notice|warn|warning|error|err|crit|critical|alert|emerg|fatal|panic)[\]>]:?\s*`,
+		),
+		// ERROR: message, WARN message
+		prefixPattern: regexp.MustCompile(
+			`(?i)^(debug|trace|info|notice|warn|warning|error|err|crit|critical|fatal|panic)\b:?\s+`,
+		),
+		// key=value or key="quoted value"
+		logfmtPattern: regexp.MustCompile(
+			`([\w.]+)=("(?:[^"\\]|\\.)*"|\S*)`,
+		),
+	}
+}
+
+// Parse parses a log line into a LogEntry
+func (p *Parser) Parse(line string) *models.LogEntry {
+	entry := &models.LogEntry{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Raw:       line,
+		Level:     models.LogLevelInfo,
+		Message:   line,
+	}
+
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return entry
+	}
+
+	if p.parseJSON(trimmed, entry) {
+		return entry
+	}
+
+	rest := p.parseTimestamp(trimmed, entry)
+
+	if strings.Contains(rest, "level=") || strings.Contains(rest, "lvl=") {
+		if p.parseLogfmt(rest, entry) {
+			return entry
+		}
+	}
+
+	if matches := p.bracketPattern.FindStringSubmatch(rest); matches != nil {
+		entry.Level = levelFromString(matches[1])
+		entry.Message = rest[len(matches[0]):]
+		return entry
+	}
+
+	if matches := p.prefixPattern.FindStringSubmatch(rest); matches != nil {
+		entry.Level = levelFromString(matches[1])
+		entry.Message = rest[len(matches[0]):]
+		return entry
+	}
+
+	entry.Message = rest
+	entry.Level = detectLevel(rest)
+	return entry
+}
+
+// parseJSON handles whole-line JSON logs (zap, logrus, pino, bunyan, ...)
+func (p *Parser) parseJSON(line string, entry *models.LogEntry) bool {
+	if !strings.HasPrefix(line, "{") || !strings.HasSuffix(line, "}") {
+		return false
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return false
+	}
+
+	if key, value := firstString(fields, jsonMessageKeys); key != "" {
+		entry.Message = value
+		delete(fields, key)
+	}
+
+	if key, value := firstString(fields, jsonLevelKeys); key != "" {
+		entry.Level = levelFromString(value)
+		delete(fields, key)
+	} else if level, ok := fields["level"].(float64); ok {
+		// bunyan/pino numeric levels
+		entry.Level = levelFromNumber(int(level))
+		delete(fields, "level")
+	}
+
+	for _, key := range jsonTimeKeys {
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+		if ts, ok := parseTimeValue(value); ok {
+			entry.Timestamp = ts
+			entry.HasTimestamp = true
+			delete(fields, key)
+		}
+		break
+	}
+
+	if len(fields) > 0 {
+		entry.Metadata = fields
+	}
+	return true
+}
+
+// parseTimestamp strips a leading timestamp, returning the rest of the line
+func (p *Parser) parseTimestamp(line string, entry *models.LogEntry) string {
+	if matches := p.timestampPattern.FindStringSubmatch(line); matches != nil {
+		if ts, ok := parseTimestampString(matches[1]); ok {
+			entry.Timestamp = ts
+			entry.HasTimestamp = true
+			return line[len(matches[0]):]
+		}
+	}
+
+	if matches := p.syslogPattern.FindStringSubmatch(line); matches != nil {
+		if ts, err := time.ParseInLocation(time.Stamp, matches[1], time.Local); err == nil {
+			// Syslog timestamps omit the year
+			entry.Timestamp = ts.AddDate(time.Now().Year(), 0, 0)
+			entry.HasTimestamp = true
+			entry.Metadata = map[string]interface{}{
+				"host":    matches[2],
+				"program": matches[3],
+			}
+			if matches[4] != "" {
+				entry.Metadata["pid"] = matches[4]
+			}
+			return line[len(matches[0]):]
+		}
+	}
+
+	return line
+}
+
+// parseLogfmt handles key=value logs (level=error msg="..." user=42)
+func (p *Parser) parseLogfmt(line string, entry *models.LogEntry) bool {
+	pairs := p.logfmtPattern.FindAllStringSubmatch(line, -1)
+	if len(pairs) == 0 {
+		return false
+	}
+
+	fields := make(map[string]interface{})
+	for _, pair := range pairs {
+		value := pair[2]
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+
+		switch pair[1] {
+		case "level", "lvl":
+			entry.Level = levelFromString(value)
+		case "msg", "message":
+			entry.Message = value
+		case "time", "ts", "timestamp":
+			if ts, ok := parseTimestampString(value); ok {
+				entry.Timestamp = ts
+			} else {
+				fields[pair[1]] = value
+			}
+		default:
+			fields[pair[1]] = value
+		}
+	}
+
+	if len(fields) > 0 {
+		if entry.Metadata == nil {
+			entry.Metadata = fields
+		} else {
+			for key, value := range fields {
+				entry.Metadata[key] = value
+			}
+		}
+	}
+	return true
+}
+
+// detectLevel falls back to keyword matching when no explicit level is present
+func detectLevel(line string) models.LogLevel {
+	lineLower := strings.ToLower(line)
+
+	switch {
+	case strings.Contains(lineLower, "fatal") || strings.Contains(lineLower, "panic"):
+		return models.LogLevelFatal
+	case strings.Contains(lineLower, "error") || strings.Contains(lineLower, "exception"):
+		return models.LogLevelError
+	case strings.Contains(lineLower, "warn"):
+		return models.LogLevelWarning
+	}
+
+	return models.LogLevelInfo
+}
+
+// levelFromString maps level names (including syslog severities) to a LogLevel
+func levelFromString(level string) models.LogLevel {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug", "trace", "verbose":
+		return models.LogLevelDebug
+	case "warn", "warning":
+		return models.LogLevelWarning
+	case "error", "err":
+		return models.LogLevelError
+	case "fatal", "panic", "crit", "critical", "alert", "emerg", "emergency", "dpanic":
+		return models.LogLevelFatal
+	default:
+		// info, notice and anything unrecognised
+		return models.LogLevelInfo
+	}
+}
+
+// levelFromNumber maps bunyan/pino numeric levels to a LogLevel
+func levelFromNumber(level int) models.LogLevel {
+	switch {
+	case level >= 60:
+		return models.LogLevelFatal
+	case level >= 50:
+		return models.LogLevelError
+	case level >= 40:
+		return models.LogLevelWarning
+	case level >= 30:
+		return models.LogLevelInfo
+	default:
+		return models.LogLevelDebug
+	}
+}
+
+// firstString returns the first key present in fields with a string value
+func firstString(fields map[string]interface{}, keys []string) (string, string) {
+	for _, key := range keys {
+		if value, ok := fields[key].(string); ok {
+			return key, value
+		}
+	}
+	return "", ""
+}
+
+// parseTimeValue parses a JSON timestamp that is either a string or a Unix
+// time in seconds or milliseconds
+func parseTimeValue(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		return parseTimestampString(v)
+	case float64:
+		if v > 1e12 {
+			return time.UnixMilli(int64(v)), true
+		}
+		sec := int64(v)
+		return time.Unix(sec, int64((v-float64(sec))*1e9)), true
+	}
+	return time.Time{}, false
+}
+
+// parseTimestampString tries each known timestamp layout
+func parseTimestampString(value string) (time.Time, bool) {
+	for _, layout := range timestampLayouts {
+		if ts, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
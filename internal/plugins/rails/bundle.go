@@ -0,0 +1,67 @@
+package rails
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+// bundleCheckTimeout bounds `bundle check`, which loads the whole Gemfile
+const bundleCheckTimeout = 30 * time.Second
+
+// bundle check: " * rails (7.1.3)"
+var bundleMissingPattern = regexp.MustCompile(`^\s*\*\s+(\S+)`)
+
+// CheckDependencies checks that the gems in the Gemfile are installed
+func (p *Plugin) CheckDependencies(projectPath string) []models.DependencyStatus {
+	if _, err := os.Stat(filepath.Join(projectPath, "Gemfile")); err != nil {
+		return nil
+	}
+	return []models.DependencyStatus{checkBundle(projectPath)}
+}
+
+// checkBundle runs `bundle check`, which resolves the Gemfile against the
+// installed gems, vendor/bundle included, without changing anything
+func checkBundle(projectPath string) models.DependencyStatus {
+	status := models.DependencyStatus{
+		Manager:        "bundler",
+		Manifest:       "Gemfile",
+		InstallCommand: []string{"bundle", "install"},
+	}
+
+	if _, err := exec.LookPath("bundle"); err != nil {
+		status.Message = "bundler is not installed (gem install bundler)"
+		return status
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bundleCheckTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "bundle", "check")
+	cmd.Dir = projectPath
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		status.Satisfied = true
+		return status
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		status.Message = "bundle check timed out"
+		return status
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if matches := bundleMissingPattern.FindStringSubmatch(line); matches != nil {
+			status.Missing = append(status.Missing, matches[1])
+		} else if status.Message == "" && strings.TrimSpace(line) != "" {
+			status.Message = strings.TrimSpace(line)
+		}
+	}
+	return status
+}
@@ -0,0 +1,79 @@
+package rails
+
+import "github.com/caboose-desktop/internal/core/exceptions"
+
+// guidanceRules explain common Rails exceptions
+var guidanceRules = []exceptions.GuidanceRule{
+	{
+		Type:            `^ActiveRecord::RecordNotFound$`,
+		Title:           "Record not found",
+		LikelyCause:     "find or find_by! was called with an ID that doesn't exist, often a stale link, a deleted record, or a record scoped to another user.",
+		SuggestedAction: "Check the ID in the request params. Use find_by and handle nil where a missing record is expected; Rails renders a 404 for this in production.",
+	},
+	{
+		Type:            `^ActiveRecord::PendingMigrationError$`,
+		Title:           "Pending migrations",
+		LikelyCause:     "The schema is behind the migrations in db/migrate.",
+		SuggestedAction: "Run db:migrate, then restart the server.",
+	},
+	{
+		Type:            `^ActiveRecord::RecordInvalid$`,
+		Title:           "Validation failed",
+		LikelyCause:     "save! or create! was called on a record that fails its model validations.",
+		SuggestedAction: "Read the validation messages in the exception, and use save with errors handling where invalid input is expected.",
+	},
+	{
+		Type:            `^ActiveRecord::RecordNotUnique$`,
+		Title:           "Duplicate record",
+		LikelyCause:     "A unique index rejected an insert or update, often from two requests creating the same record at once.",
+		SuggestedAction: "Add a uniqueness validation that mirrors the index and rescue the error where concurrent creates are possible.",
+	},
+	{
+		Type:            `^ActiveRecord::(Deadlocked|LockWaitTimeout)$`,
+		Title:           "Database lock contention",
+		LikelyCause:     "Transactions are waiting on or deadlocking over the same rows.",
+		SuggestedAction: "Retry the transaction, keep transactions short, and lock rows in a consistent order.",
+	},
+	{
+		Type:            `^ActiveRecord::ConnectionTimeoutError$`,
+		Title:           "Connection pool exhausted",
+		LikelyCause:     "All connections in the pool were checked out, usually because the pool is smaller than the number of threads.",
+		SuggestedAction: "Set pool in config/database.yml to at least the Puma/Sidekiq thread count.",
+	},
+	{
+		Type:            `^ActionController::ParameterMissing$`,
+		Title:           "Required parameter missing",
+		LikelyCause:     "params.require was called for a key the request didn't send, often a form field name mismatch.",
+		SuggestedAction: "Compare the form or API payload keys with the strong parameters in the controller.",
+	},
+	{
+		Type:            `^ActionController::InvalidAuthenticityToken$`,
+		Title:           "Invalid CSRF token",
+		LikelyCause:     "The request was sent without a valid authenticity token, e.g. a cached form, an expired session, or a JS request missing the X-CSRF-Token header.",
+		SuggestedAction: "Make sure the layout includes csrf_meta_tags and that JS requests send the token.",
+	},
+	{
+		Type:            `^ActionController::RoutingError$`,
+		Title:           "No route matches",
+		LikelyCause:     "The request path or HTTP verb doesn't match any route.",
+		SuggestedAction: "Check config/routes.rb (rails routes) for the path and verb.",
+	},
+	{
+		Type:            `^ActionView::Template::Error$`,
+		Title:           "Error while rendering a view",
+		LikelyCause:     "Code in a template raised; the underlying error is in the message.",
+		SuggestedAction: "Look at the template line in the backtrace, commonly a method called on nil.",
+	},
+	{
+		Type:            `^NoMethodError$`,
+		Message:         `for nil`,
+		Title:           "Method called on nil",
+		LikelyCause:     "A value expected to be an object was nil, e.g. a missing association or a find_by that returned nothing.",
+		SuggestedAction: "Trace where the receiver comes from in the backtrace and guard it or use the safe navigation operator (&.).",
+	},
+}
+
+// GuidanceRules returns the exception guidance rules for Rails
+func (p *Plugin) GuidanceRules() []exceptions.GuidanceRule {
+	return guidanceRules
+}
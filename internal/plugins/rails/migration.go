@@ -0,0 +1,197 @@
+package rails
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+// MigrationFile represents a generated Rails migration (or code snippet) for a recommendation
+type MigrationFile struct {
+	// FileName is the migration file name (e.g. 20240115103000_add_index_to_users_on_email.rb)
+	FileName string `json:"fileName,omitempty"`
+
+	// ClassName is the migration class name
+	ClassName string `json:"className,omitempty"`
+
+	// Content is the generated file body or snippet
+	Content string `json:"content"`
+
+	// IsMigration is false when the fix could not be expressed as a migration
+	IsMigration bool `json:"isMigration"`
+
+	// Path is where the migration was written (empty if not written)
+	Path string `json:"path,omitempty"`
+}
+
+// CREATE [UNIQUE] INDEX idx_name ON table (col1, col2)
+var createIndexPattern = regexp.MustCompile(
+	"(?i)CREATE\\s+(UNIQUE\\s+)?INDEX\\s+[\"`]?(\\w+)[\"`]?\\s+ON\\s+[\"`]?(\\w+)[\"`]?\\s*\\(([^)]+)\\)",
+)
+
+// Gemfile.lock entry for the rails gem: "    rails (7.1.3)"
+var railsLockPattern = regexp.MustCompile(`^\s{4}rails \((\d+)\.(\d+)`)
+
+// GenerateMigration converts a recommendation fix into a Rails migration.
+// Index fixes become add_index/remove_index migrations; any other fix is
+// returned as a commented code snippet.
+func GenerateMigration(rec models.SmartRecommendation, railsVersion string, now time.Time) *MigrationFile {
+	matches := createIndexPattern.FindStringSubmatch(rec.Fix.Code)
+	if rec.Fix.Type != "sql-index" || matches == nil {
+		return &MigrationFile{
+			Content:     commentedSnippet(rec),
+			IsMigration: false,
+		}
+	}
+
+	unique := matches[1] != ""
+	indexName := matches[2]
+	table := matches[3]
+
+	columns := make([]string, 0)
+	for _, col := range strings.Split(matches[4], ",") {
+		col = strings.Trim(strings.TrimSpace(col), "\"`")
+		if col != "" {
+			columns = append(columns, col)
+		}
+	}
+
+	baseName := fmt.Sprintf("add_index_to_%s_on_%s", table, strings.Join(columns, "_and_"))
+	className := camelize(baseName)
+
+	if railsVersion == "" {
+		railsVersion = "7.0"
+	}
+
+	symbols := make([]string, len(columns))
+	for i, col := range columns {
+		symbols[i] = ":" + col
+	}
+
+	options := fmt.Sprintf("name: %q", indexName)
+	if unique {
+		options += ", unique: true"
+	}
+
+	var b strings.Builder
+	if rec.Title != "" {
+		b.WriteString(fmt.Sprintf("# %s\n", rec.Title))
+	}
+	if rec.Fix.Explanation != "" {
+		b.WriteString(fmt.Sprintf("# %s\n", rec.Fix.Explanation))
+	}
+	b.WriteString(fmt.Sprintf("class %s < ActiveRecord::Migration[%s]\n", className, railsVersion))
+	b.WriteString("  def up\n")
+	b.WriteString(fmt.Sprintf("    add_index :%s, [%s], %s\n", table, strings.Join(symbols, ", "), options))
+	b.WriteString("  end\n\n")
+	b.WriteString("  def down\n")
+	b.WriteString(fmt.Sprintf("    remove_index :%s, name: %q\n", table, indexName))
+	b.WriteString("  end\n")
+	b.WriteString("end\n")
+
+	return &MigrationFile{
+		FileName:    fmt.Sprintf("%s_%s.rb", now.UTC().Format("20060102150405"), baseName),
+		ClassName:   className,
+		Content:     b.String(),
+		IsMigration: true,
+	}
+}
+
+// WriteMigration writes a generated migration into the project's db/migrate directory
+func WriteMigration(projectPath string, migration *MigrationFile) (string, error) {
+	if migration == nil || !migration.IsMigration {
+		return "", fmt.Errorf("recommendation cannot be written as a migration")
+	}
+
+	migrateDir := filepath.Join(projectPath, "db", "migrate")
+	info, err := os.Stat(migrateDir)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("migrations directory not found: %s", migrateDir)
+	}
+
+	// SECURITY: File name is generated, but make sure it cannot escape db/migrate
+	if filepath.Base(migration.FileName) != migration.FileName {
+		return "", fmt.Errorf("invalid migration file name: %s", migration.FileName)
+	}
+
+	// Refuse to create a second migration with the same class name
+	entries, err := os.ReadDir(migrateDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+	suffix := migration.FileName[strings.Index(migration.FileName, "_"):]
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), suffix) {
+			return "", fmt.Errorf("migration already exists: %s", entry.Name())
+		}
+	}
+
+	path := filepath.Join(migrateDir, migration.FileName)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to create migration: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(migration.Content); err != nil {
+		return "", fmt.Errorf("failed to write migration: %w", err)
+	}
+
+	migration.Path = path
+	return path, nil
+}
+
+// DetectMigrationVersion returns the ActiveRecord::Migration version (e.g. "7.1")
+// from the project's Gemfile.lock, or an empty string if it cannot be determined
+func DetectMigrationVersion(projectPath string) string {
+	file, err := os.Open(filepath.Join(projectPath, "Gemfile.lock"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if matches := railsLockPattern.FindStringSubmatch(scanner.Text()); matches != nil {
+			return matches[1] + "." + matches[2]
+		}
+	}
+
+	return ""
+}
+
+// commentedSnippet renders a non-migration fix as a commented code snippet
+func commentedSnippet(rec models.SmartRecommendation) string {
+	var b strings.Builder
+	if rec.Title != "" {
+		b.WriteString(fmt.Sprintf("# %s\n", rec.Title))
+	}
+	if rec.Description != "" {
+		b.WriteString(fmt.Sprintf("# %s\n", rec.Description))
+	}
+	if rec.Fix.Explanation != "" {
+		b.WriteString(fmt.Sprintf("#\n# %s\n", rec.Fix.Explanation))
+	}
+	b.WriteString(rec.Fix.Code)
+	if !strings.HasSuffix(rec.Fix.Code, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// camelize converts snake_case to CamelCase (add_index_to_users -> AddIndexToUsers)
+func camelize(s string) string {
+	parts := strings.Split(s, "_")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -10,16 +10,21 @@ import (
 	"github.com/google/uuid"
 )
 
+// railsTimestampLayout is the time format of "Started GET ... at" lines
+const railsTimestampLayout = "2006-01-02 15:04:05 -0700"
+
 // Parser parses Rails log lines into structured entries
 type Parser struct {
 	// Compiled regex patterns
 	requestStartPattern  *regexp.Regexp
 	processingPattern    *regexp.Regexp
 	completedPattern     *regexp.Regexp
+	timingPattern        *regexp.Regexp
 	sqlPattern           *regexp.Regexp
 	renderPattern        *regexp.Regexp
 	exceptionPattern     *regexp.Regexp
 	parameterPattern     *regexp.Regexp
+	queryCallerPattern   *regexp.Regexp
 }
 
 // NewParser creates a new Rails log parser
@@ -33,9 +38,13 @@ func NewParser() *Parser {
 		processingPattern: regexp.MustCompile(
 			`Processing\s+by\s+(\w+)#(\w+)\s+as\s+(\w+)`,
 		),
-		// Completed 200 OK in 50ms (Views: 30.0ms | ActiveRecord: 10.0ms)
+		// Completed 200 OK in 50ms (Views: 30.0ms | ActiveRecord: 10.0ms | Allocations: 1234)
 		completedPattern: regexp.MustCompile(
-			`Completed\s+(\d+)\s+\w+\s+in\s+([\d\.]+)ms`,
+			`Completed\s+(\d+)\s+.*?\bin\s+([\d\.]+)ms(?:\s+\((.*)\))?`,
+		),
+		// Views: 30.0ms, ActiveRecord: 10.0ms (5 queries, 1 cached), Allocations: 1234
+		timingPattern: regexp.MustCompile(
+			`(Views|ActiveRecord|Allocations):\s+([\d\.]+)`,
 		),
 		// User Load (0.5ms)  SELECT "users".* FROM "users" WHERE ...
 		sqlPattern: regexp.MustCompile(
@@ -53,6 +62,10 @@ func NewParser() *Parser {
 		parameterPattern: regexp.MustCompile(
 			`Parameters:\s+(\{.+\})`,
 		),
+		//   ↳ app/controllers/users_controller.rb:5:in `index'
+		queryCallerPattern: regexp.MustCompile(
+			`^\s*↳\s+(.+)$`,
+		),
 	}
 }
 
@@ -84,6 +97,9 @@ func (p *Parser) Parse(line string) *models.LogEntry {
 	if p.parseException(line, entry) {
 		return entry
 	}
+	if p.parseQueryCaller(line, entry) {
+		return entry
+	}
 
 	// Default: treat as plain message
 	entry.Message = strings.TrimSpace(line)
@@ -132,6 +148,10 @@ func (p *Parser) parseRequestStart(line string, entry *models.LogEntry) bool {
 	}
 	entry.Metadata["type"] = "request_start"
 	entry.Metadata["timestamp_str"] = matches[4]
+	if ts, err := time.Parse(railsTimestampLayout, strings.TrimSpace(matches[4])); err == nil {
+		entry.Timestamp = ts
+		entry.HasTimestamp = true
+	}
 
 	return true
 }
@@ -177,6 +197,18 @@ func (p *Parser) parseCompleted(line string, entry *models.LogEntry) bool {
 	entry.Request.Status = status
 	entry.Request.Duration = duration
 
+	// The breakdown's parts are each optional, e.g. Views is missing for redirects
+	for _, timing := range p.timingPattern.FindAllStringSubmatch(matches[3], -1) {
+		switch timing[1] {
+		case "Views":
+			entry.Request.ViewTime, _ = strconv.ParseFloat(timing[2], 64)
+		case "ActiveRecord":
+			entry.Request.DbTime, _ = strconv.ParseFloat(timing[2], 64)
+		case "Allocations":
+			entry.Request.Allocations, _ = strconv.ParseInt(timing[2], 10, 64)
+		}
+	}
+
 	if entry.Metadata == nil {
 		entry.Metadata = make(map[string]interface{})
 	}
@@ -279,6 +311,25 @@ func (p *Parser) parseException(line string, entry *models.LogEntry) bool {
 	return true
 }
 
+// parseQueryCaller parses the source location Rails logs after a query when
+// verbose query logs are enabled
+func (p *Parser) parseQueryCaller(line string, entry *models.LogEntry) bool {
+	matches := p.queryCallerPattern.FindStringSubmatch(line)
+	if matches == nil {
+		return false
+	}
+
+	entry.Message = line
+
+	if entry.Metadata == nil {
+		entry.Metadata = make(map[string]interface{})
+	}
+	entry.Metadata["type"] = "query_caller"
+	entry.Metadata["caller"] = strings.TrimSpace(matches[1])
+
+	return true
+}
+
 // fingerprintSQL normalizes a SQL query for comparison
 func fingerprintSQL(sql string) string {
 	// Replace literal values with placeholders
@@ -14,6 +14,7 @@ type Plugin struct {
 	query         *QueryAnalyzer
 	testDetector  *TestDetector
 	debugDetector *DebugDetector
+	requests      *requestTracker
 	projectPath   string
 }
 
@@ -24,6 +25,7 @@ func New() *Plugin {
 		query:         NewQueryAnalyzer(),
 		testDetector:  NewTestDetector(),
 		debugDetector: NewDebugDetector(),
+		requests:      newRequestTracker(),
 	}
 }
 
@@ -32,6 +34,18 @@ func (p *Plugin) SetProjectPath(path string) {
 	p.projectPath = path
 }
 
+// SetSlowQueryThreshold sets the threshold in milliseconds above which analyzed queries are slow
+func (p *Plugin) SetSlowQueryThreshold(threshold float64) {
+	if threshold > 0 {
+		p.query.SetSlowThreshold(threshold)
+	}
+}
+
+// SetN1Detection enables or disables N+1 query pattern detection
+func (p *Plugin) SetN1Detection(enabled bool) {
+	p.query.SetN1Detection(enabled)
+}
+
 // Name returns the plugin name
 func (p *Plugin) Name() string {
 	return "rails"
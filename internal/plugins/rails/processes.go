@@ -0,0 +1,184 @@
+package rails
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+// Gemfile.lock spec entry: "    sidekiq (7.2.1)"
+var gemLockPattern = regexp.MustCompile(`^\s{4}([A-Za-z0-9_.-]+) \(`)
+
+// Gemfile declaration: gem "sidekiq", "~> 7.0"
+var gemfilePattern = regexp.MustCompile(`^\s*gem\s+["']([A-Za-z0-9_.-]+)["']`)
+
+// gemProcess is a process template offered when the project bundles a gem
+type gemProcess struct {
+	gem      string
+	template models.ProcessTemplate
+}
+
+// gemProcesses are the job runners and asset watchers commonly run next to
+// the Rails server
+var gemProcesses = []gemProcess{
+	{"sidekiq", models.ProcessTemplate{
+		ID:          "sidekiq",
+		Name:        "sidekiq",
+		Description: "Sidekiq background job worker",
+		Command:     "bundle",
+		Args:        []string{"exec", "sidekiq"},
+		Color:       "#f97316", // orange
+	}},
+	{"good_job", models.ProcessTemplate{
+		ID:          "good_job",
+		Name:        "good_job",
+		Description: "GoodJob background job worker",
+		Command:     "bundle",
+		Args:        []string{"exec", "good_job", "start"},
+		Color:       "#a855f7", // purple
+	}},
+	{"solid_queue", models.ProcessTemplate{
+		ID:          "solid_queue",
+		Name:        "jobs",
+		Description: "Solid Queue supervisor and workers",
+		Command:     "bundle",
+		Args:        []string{"exec", "rails", "solid_queue:start"},
+		Color:       "#8b5cf6", // violet
+	}},
+	{"delayed_job", models.ProcessTemplate{
+		ID:          "delayed_job",
+		Name:        "delayed_job",
+		Description: "Delayed::Job worker",
+		Command:     "bundle",
+		Args:        []string{"exec", "rails", "jobs:work"},
+		Color:       "#ec4899", // pink
+	}},
+	{"tailwindcss-rails", models.ProcessTemplate{
+		ID:          "tailwindcss-rails",
+		Name:        "tailwind",
+		Description: "Rebuild Tailwind CSS on change (tailwindcss-rails)",
+		Command:     "bundle",
+		Args:        []string{"exec", "rails", "tailwindcss:watch"},
+		Color:       "#06b6d4", // cyan
+	}},
+	{"dartsass-rails", models.ProcessTemplate{
+		ID:          "dartsass-rails",
+		Name:        "css",
+		Description: "Rebuild Sass stylesheets on change (dartsass-rails)",
+		Command:     "bundle",
+		Args:        []string{"exec", "rails", "dartsass:watch"},
+		Color:       "#db2777", // rose
+	}},
+	{"vite_rails", models.ProcessTemplate{
+		ID:          "vite_ruby",
+		Name:        "vite",
+		Description: "Vite dev server (vite_rails)",
+		Command:     "bundle",
+		Args:        []string{"exec", "vite", "dev"},
+		Color:       "#8b5cf6", // violet
+		Port:        3036,
+	}},
+}
+
+// devServerBinstubs are the webpack dev server binstubs Webpacker and
+// Shakapacker install, newest first
+var devServerBinstubs = []string{
+	"shakapacker-dev-server",
+	"webpacker-dev-server",
+	"webpack-dev-server",
+}
+
+// ProcessTemplates returns the Rails server variants plus templates for the
+// job runners and asset watchers the project bundles
+func (p *Plugin) ProcessTemplates(projectPath string) []models.ProcessTemplate {
+	templates := []models.ProcessTemplate{
+		{
+			ID:          "rails",
+			Name:        "rails",
+			Description: "Rails server on all interfaces",
+			Command:     "bundle",
+			Args:        []string{"exec", "rails", "server", "-b", "0.0.0.0"},
+			Color:       "#ef4444", // red
+			Port:        3000,
+		},
+		{
+			ID:          "rails-local",
+			Name:        "rails",
+			Description: "Rails server on localhost only",
+			Command:     "bundle",
+			Args:        []string{"exec", "rails", "server", "-b", "127.0.0.1"},
+			Color:       "#ef4444", // red
+			Port:        3000,
+		},
+		{
+			ID:          "rails-alt-port",
+			Name:        "rails-3001",
+			Description: "Second Rails server on port 3001",
+			Command:     "bundle",
+			Args:        []string{"exec", "rails", "server", "-b", "0.0.0.0", "-p", "3001", "-P", "tmp/pids/server-3001.pid"},
+			Color:       "#dc2626", // dark red
+			Port:        3001,
+		},
+	}
+
+	gems := bundledGems(projectPath)
+	for _, gp := range gemProcesses {
+		if gems[gp.gem] {
+			templates = append(templates, gp.template)
+		}
+	}
+
+	for _, binstub := range devServerBinstubs {
+		if _, err := os.Stat(filepath.Join(projectPath, "bin", binstub)); err == nil {
+			templates = append(templates, models.ProcessTemplate{
+				ID:          "webpack-dev-server",
+				Name:        "webpack",
+				Description: "Webpack dev server (bin/" + binstub + ")",
+				Command:     "bundle",
+				Args:        []string{"exec", "bin/" + binstub},
+				Color:       "#3b82f6", // blue
+				Port:        3035,
+			})
+			break
+		}
+	}
+
+	for i := range templates {
+		templates[i].UsePTY = true
+		templates[i].AutoRestart = true
+		templates[i].Source = "framework"
+	}
+	return templates
+}
+
+// bundledGems returns the gems in Gemfile.lock, including dependencies, or
+// the ones declared in the Gemfile when nothing has been bundled yet
+func bundledGems(projectPath string) map[string]bool {
+	gems := readGemNames(filepath.Join(projectPath, "Gemfile.lock"), gemLockPattern)
+	if len(gems) == 0 {
+		gems = readGemNames(filepath.Join(projectPath, "Gemfile"), gemfilePattern)
+	}
+	return gems
+}
+
+// readGemNames collects the first capture of pattern from each line of a file
+func readGemNames(path string, pattern *regexp.Regexp) map[string]bool {
+	gems := make(map[string]bool)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return gems
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if matches := pattern.FindStringSubmatch(scanner.Text()); matches != nil {
+			gems[matches[1]] = true
+		}
+	}
+	return gems
+}
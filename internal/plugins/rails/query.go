@@ -2,6 +2,7 @@ package rails
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/caboose-desktop/internal/models"
@@ -10,12 +11,14 @@ import (
 // QueryAnalyzer analyzes SQL queries for performance issues
 type QueryAnalyzer struct {
 	slowThreshold float64 // in milliseconds
+	n1Detection   bool
 }
 
 // NewQueryAnalyzer creates a new query analyzer
 func NewQueryAnalyzer() *QueryAnalyzer {
 	return &QueryAnalyzer{
 		slowThreshold: 100.0, // 100ms default
+		n1Detection:   true,
 	}
 }
 
@@ -24,6 +27,16 @@ func (qa *QueryAnalyzer) SetSlowThreshold(threshold float64) {
 	qa.slowThreshold = threshold
 }
 
+// SetN1Detection enables or disables N+1 pattern detection
+func (qa *QueryAnalyzer) SetN1Detection(enabled bool) {
+	qa.n1Detection = enabled
+}
+
+// N1DetectionEnabled reports whether N+1 pattern detection is enabled
+func (qa *QueryAnalyzer) N1DetectionEnabled() bool {
+	return qa.n1Detection
+}
+
 // Analyze analyzes a single SQL query
 func (qa *QueryAnalyzer) Analyze(sql string, duration float64) *models.QueryAnalysis {
 	fingerprint := fingerprintSQL(sql)
@@ -77,12 +90,55 @@ func (qa *QueryAnalyzer) AnalyzeRequest(requestID string, queries []models.Query
 	}
 
 	// Detect N+1 patterns
-	analysis.N1Warnings = qa.detectN1Patterns(fingerprints)
+	if qa.n1Detection {
+		analysis.N1Warnings = qa.detectN1Patterns(fingerprints)
+	}
 	analysis.DuplicateCount = qa.countDuplicates(fingerprints)
+	analysis.DuplicateQueries = qa.detectDuplicates(requestID, fingerprints, analysis.N1Warnings)
 
 	return analysis
 }
 
+// detectDuplicates lists queries repeated within a request that weren't
+// already reported as N+1 patterns, most repeated first
+func (qa *QueryAnalyzer) detectDuplicates(requestID string, fingerprints map[string][]models.QueryInfo, n1Warnings []models.N1Warning) []models.DuplicateQuery {
+	reported := make(map[string]bool, len(n1Warnings))
+	for _, w := range n1Warnings {
+		reported[w.Fingerprint] = true
+	}
+
+	var duplicates []models.DuplicateQuery
+	for fingerprint, queries := range fingerprints {
+		if len(queries) < 2 || reported[fingerprint] {
+			continue
+		}
+
+		dup := models.DuplicateQuery{
+			Fingerprint: fingerprint,
+			SQL:         queries[0].SQL,
+			Table:       queries[0].Table,
+			Count:       len(queries),
+			RequestID:   requestID,
+		}
+		for _, q := range queries {
+			dup.TotalDuration += q.Duration
+			if dup.Caller == "" {
+				dup.Caller = q.Caller
+			}
+		}
+		duplicates = append(duplicates, dup)
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Count != duplicates[j].Count {
+			return duplicates[i].Count > duplicates[j].Count
+		}
+		return duplicates[i].TotalDuration > duplicates[j].TotalDuration
+	})
+
+	return duplicates
+}
+
 // detectN1Patterns identifies N+1 query patterns
 func (qa *QueryAnalyzer) detectN1Patterns(fingerprints map[string][]models.QueryInfo) []models.N1Warning {
 	var warnings []models.N1Warning
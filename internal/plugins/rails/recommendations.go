@@ -1,6 +1,8 @@
 package rails
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
 	"sort"
 	"strings"
@@ -22,6 +24,11 @@ func NewRecommendationEngine() *RecommendationEngine {
 	}
 }
 
+// SetN1Detection enables or disables N+1 recommendations
+func (re *RecommendationEngine) SetN1Detection(enabled bool) {
+	re.analyzer.SetN1Detection(enabled)
+}
+
 // GenerateRecommendations analyzes queries and generates prioritized recommendations
 func (re *RecommendationEngine) GenerateRecommendations(
 	stats []database.QueryStatistic,
@@ -31,20 +38,16 @@ func (re *RecommendationEngine) GenerateRecommendations(
 	recommendations := []models.SmartRecommendation{}
 
 	// Generate N+1 recommendations
+	if !re.analyzer.N1DetectionEnabled() {
+		n1Warnings = nil
+	}
 	for _, warning := range n1Warnings {
 		rec := re.generateN1Recommendation(warning)
 		recommendations = append(recommendations, rec)
 	}
 
 	// Generate index recommendations from EXPLAIN results
-	for queryID, explainResult := range explainResults {
-		if len(explainResult.Recommendations) > 0 {
-			for _, indexRec := range explainResult.Recommendations {
-				rec := re.generateIndexRecommendation(queryID, indexRec, explainResult)
-				recommendations = append(recommendations, rec)
-			}
-		}
-	}
+	recommendations = append(recommendations, re.IndexRecommendations(explainResults)...)
 
 	// Generate slow query recommendations
 	for _, stat := range stats {
@@ -115,6 +118,41 @@ func (re *RecommendationEngine) generateN1Recommendation(warning models.N1Warnin
 	}
 }
 
+// IndexRecommendations turns the index suggestions of EXPLAIN results, by
+// query, into recommendations. An index suggested for several queries is one
+// recommendation, and its ID comes from the CREATE INDEX statement so it is
+// the same every time the recommendations are generated.
+func (re *RecommendationEngine) IndexRecommendations(explainResults map[string]*database.ExplainResult) []models.SmartRecommendation {
+	byID := make(map[string]*models.SmartRecommendation)
+	for queryID, explainResult := range explainResults {
+		if explainResult == nil {
+			continue
+		}
+		for _, indexRec := range explainResult.Recommendations {
+			rec := re.generateIndexRecommendation(queryID, indexRec, explainResult)
+			if existing, ok := byID[rec.ID]; ok {
+				existing.AffectedQueries = append(existing.AffectedQueries, queryID)
+				continue
+			}
+			byID[rec.ID] = &rec
+		}
+	}
+
+	recommendations := make([]models.SmartRecommendation, 0, len(byID))
+	for _, rec := range byID {
+		sort.Strings(rec.AffectedQueries)
+		recommendations = append(recommendations, *rec)
+	}
+	sort.Slice(recommendations, func(i, j int) bool { return recommendations[i].ID < recommendations[j].ID })
+	return recommendations
+}
+
+// indexRecommendationID identifies an index recommendation by what it creates
+func indexRecommendationID(indexRec database.IndexRecommendation) string {
+	sum := sha1.Sum([]byte(strings.ToLower(strings.Join(strings.Fields(indexRec.SQL), " "))))
+	return "index-" + hex.EncodeToString(sum[:8])
+}
+
 // generateIndexRecommendation creates a recommendation for missing indexes
 func (re *RecommendationEngine) generateIndexRecommendation(
 	queryID string,
@@ -145,7 +183,7 @@ func (re *RecommendationEngine) generateIndexRecommendation(
 	}
 
 	return models.SmartRecommendation{
-		ID:              uuid.New().String(),
+		ID:              indexRecommendationID(indexRec),
 		Type:            "index",
 		Severity:        indexRec.Severity,
 		Title:           fmt.Sprintf("Add Index on %s.%s", indexRec.Table, strings.Join(indexRec.Columns, ", ")),
@@ -0,0 +1,64 @@
+package rails
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caboose-desktop/internal/core/database"
+)
+
+func TestExportIndexRecommendationAsMigration(t *testing.T) {
+	index := database.IndexRecommendation{
+		Table:    "users",
+		Columns:  []string{"email"},
+		Reason:   "Full table scan filtering on email",
+		Severity: "high",
+		SQL:      "CREATE INDEX idx_users_email ON users (email);",
+	}
+	explains := map[string]*database.ExplainResult{
+		"select * from users where email = ?": {
+			Recommendations: []database.IndexRecommendation{index},
+		},
+		"select id from users where email = ? limit ?": {
+			Recommendations: []database.IndexRecommendation{index},
+		},
+	}
+
+	engine := NewRecommendationEngine()
+	recommendations := engine.IndexRecommendations(explains)
+	if len(recommendations) != 1 {
+		t.Fatalf("got %d recommendations, want the shared index once", len(recommendations))
+	}
+	rec := recommendations[0]
+	if rec.Fix.Type != "sql-index" {
+		t.Fatalf("fix type = %q, want sql-index", rec.Fix.Type)
+	}
+	if len(rec.AffectedQueries) != 2 {
+		t.Errorf("affected queries = %v, want both queries", rec.AffectedQueries)
+	}
+
+	// The export looks the recommendation up again by ID
+	again := engine.IndexRecommendations(explains)
+	if len(again) != 1 || again[0].ID != rec.ID {
+		t.Fatalf("ID changed between analyses: %q, then %v", rec.ID, again)
+	}
+
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	migration := GenerateMigration(again[0], "7.1", now)
+	if !migration.IsMigration {
+		t.Fatalf("not exported as a migration:\n%s", migration.Content)
+	}
+	if want := "20240115103000_add_index_to_users_on_email.rb"; migration.FileName != want {
+		t.Errorf("file name = %q, want %q", migration.FileName, want)
+	}
+	for _, want := range []string{
+		"class AddIndexToUsersOnEmail < ActiveRecord::Migration[7.1]",
+		`add_index :users, [:email], name: "idx_users_email"`,
+		`remove_index :users, name: "idx_users_email"`,
+	} {
+		if !strings.Contains(migration.Content, want) {
+			t.Errorf("migration is missing %q:\n%s", want, migration.Content)
+		}
+	}
+}
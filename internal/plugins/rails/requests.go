@@ -0,0 +1,114 @@
+package rails
+
+import (
+	"sync"
+
+	"github.com/caboose-desktop/internal/models"
+)
+
+const (
+	// maxRequestQueries bounds the queries collected for a single request
+	maxRequestQueries = 1000
+
+	// maxDuplicateHistory bounds the duplicate queries kept from recent requests
+	maxDuplicateHistory = 200
+)
+
+// openRequest collects the queries of a request that hasn't completed yet
+type openRequest struct {
+	id       string
+	endpoint string
+	queries  []models.QueryInfo
+}
+
+// requestTracker groups SQL log lines by request and analyzes each request's
+// queries together once it completes. Rails doesn't tag plain log lines with
+// a request ID, so lines are attributed to the request most recently started
+// by the same process.
+type requestTracker struct {
+	mu         sync.Mutex
+	open       map[string]*openRequest // Keyed by process name
+	duplicates []models.DuplicateQuery // Oldest first
+}
+
+func newRequestTracker() *requestTracker {
+	return &requestTracker{
+		open: make(map[string]*openRequest),
+	}
+}
+
+// ObserveLog feeds a parsed log line from a process into per-request query analysis
+func (p *Plugin) ObserveLog(processName string, entry *models.LogEntry) {
+	if entry == nil {
+		return
+	}
+
+	t := p.requests
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	req := t.open[processName]
+
+	switch entry.Metadata["type"] {
+	case "request_start":
+		req = &openRequest{id: entry.RequestID}
+		if entry.Request != nil {
+			req.endpoint = entry.Request.Method + " " + entry.Request.Path
+		}
+		t.open[processName] = req
+
+	case "processing":
+		if req != nil && entry.Request != nil {
+			req.endpoint = entry.Request.Controller + "#" + entry.Request.Action
+		}
+
+	case "sql":
+		if req == nil || entry.SQL == nil || len(req.queries) >= maxRequestQueries {
+			return
+		}
+		req.queries = append(req.queries, models.QueryInfo{
+			SQL:         entry.SQL.Query,
+			Fingerprint: entry.SQL.Fingerprint,
+			Duration:    entry.SQL.Duration,
+			Table:       entry.SQL.Table,
+			Operation:   entry.SQL.Operation,
+			Count:       1,
+			IsSlow:      entry.SQL.Duration > p.query.slowThreshold,
+		})
+
+	case "query_caller":
+		if req != nil && len(req.queries) > 0 {
+			caller, _ := entry.Metadata["caller"].(string)
+			req.queries[len(req.queries)-1].Caller = caller
+		}
+
+	case "completed":
+		if req == nil {
+			return
+		}
+		delete(t.open, processName)
+
+		analysis := p.query.AnalyzeRequest(req.id, req.queries)
+		for _, dup := range analysis.DuplicateQueries {
+			dup.Endpoint = req.endpoint
+			t.duplicates = append(t.duplicates, dup)
+		}
+		if len(t.duplicates) > maxDuplicateHistory {
+			t.duplicates = t.duplicates[len(t.duplicates)-maxDuplicateHistory:]
+		}
+	}
+}
+
+// DuplicateQueries returns the duplicate queries found in recently completed
+// requests, newest first
+func (p *Plugin) DuplicateQueries() []models.DuplicateQuery {
+	t := p.requests
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]models.DuplicateQuery, 0, len(t.duplicates))
+	for i := len(t.duplicates) - 1; i >= 0; i-- {
+		result = append(result, t.duplicates[i])
+	}
+	return result
+}
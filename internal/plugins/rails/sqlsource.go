@@ -0,0 +1,159 @@
+package rails
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/caboose-desktop/internal/models"
+	"github.com/caboose-desktop/internal/plugin"
+)
+
+// developmentLogSource tails log/development.log, for servers Caboose didn't start
+const developmentLogSource = "development-log"
+
+const (
+	// logPollInterval is how often a tailed log is checked for new lines
+	logPollInterval = 500 * time.Millisecond
+
+	// maxTailLine bounds an unterminated line buffered from a tailed log
+	maxTailLine = 64 * 1024
+)
+
+// Rails colorizes SQL lines in development logs
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// SQLSourceTypes lists the SQL sources the Rails plugin offers
+func (p *Plugin) SQLSourceTypes() []string {
+	return []string{developmentLogSource}
+}
+
+// NewSQLSource creates a SQL source for a Rails project
+func (p *Plugin) NewSQLSource(sourceType, projectPath string) (plugin.SQLSource, error) {
+	if sourceType != developmentLogSource {
+		return nil, fmt.Errorf("unknown SQL source: %s", sourceType)
+	}
+	if projectPath == "" {
+		return nil, fmt.Errorf("no project directory set")
+	}
+
+	return &logTail{
+		path:   filepath.Join(projectPath, "log", "development.log"),
+		parser: NewParser(),
+	}, nil
+}
+
+// logTail follows a Rails log file like tail -F
+type logTail struct {
+	path   string
+	parser *Parser
+
+	file    *os.File
+	reader  *bufio.Reader
+	offset  int64
+	pending string
+}
+
+// Run emits entries for lines appended to the log. Lines already in the log
+// when the source starts are skipped. The file may not exist yet, and if it is
+// truncated or replaced (log:clear, rotation) it is read again from the top.
+func (t *logTail) Run(ctx context.Context, emit func(*models.LogEntry)) error {
+	ticker := time.NewTicker(logPollInterval)
+	defer ticker.Stop()
+	defer t.close()
+
+	t.open(true)
+	for {
+		if t.file == nil {
+			t.open(false)
+		} else if t.replaced() {
+			t.close()
+			t.open(false)
+		}
+
+		if t.file != nil {
+			t.readLines(emit)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// open opens the log, at its end if fromEnd is set. A missing log is left
+// closed and retried on the next poll.
+func (t *logTail) open(fromEnd bool) {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return
+	}
+
+	t.offset = 0
+	if fromEnd {
+		if t.offset, err = file.Seek(0, io.SeekEnd); err != nil {
+			file.Close()
+			return
+		}
+	}
+
+	t.file = file
+	t.reader = bufio.NewReader(file)
+	t.pending = ""
+}
+
+// replaced reports whether the path no longer names the open file, or the
+// file was truncated below what has been read
+func (t *logTail) replaced() bool {
+	current, err := os.Stat(t.path)
+	if err != nil {
+		return true
+	}
+	opened, err := t.file.Stat()
+	if err != nil {
+		return true
+	}
+
+	return !os.SameFile(opened, current) || current.Size() < t.offset
+}
+
+// readLines emits every complete line written since the last read
+func (t *logTail) readLines(emit func(*models.LogEntry)) {
+	for {
+		chunk, err := t.reader.ReadString('\n')
+		t.offset += int64(len(chunk))
+		t.pending += chunk
+		if err != nil {
+			break
+		}
+
+		line := ansiEscape.ReplaceAllString(strings.TrimRight(t.pending, "\r\n"), "")
+		t.pending = ""
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if entry := t.parser.Parse(line); entry != nil {
+			emit(entry)
+		}
+	}
+
+	// A line this long without a newline isn't a log line worth waiting for
+	if len(t.pending) > maxTailLine {
+		t.pending = ""
+	}
+}
+
+func (t *logTail) close() {
+	if t.file != nil {
+		t.file.Close()
+		t.file = nil
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/caboose-desktop/internal/models"
 	"github.com/caboose-desktop/internal/plugin"
 )
 
@@ -96,24 +98,10 @@ func (td *TestDetector) GetTestRunner(projectPath string) *plugin.TestRunner {
 }
 
 // TestResult represents a single test result
-type TestResult struct {
-	Name     string
-	File     string
-	Line     int
-	Status   string // "passed", "failed", "pending"
-	Duration float64
-	Error    string
-}
+type TestResult = models.TestResult
 
 // TestSummary represents a test run summary
-type TestSummary struct {
-	Total    int
-	Passed   int
-	Failed   int
-	Pending  int
-	Duration float64
-	Results  []TestResult
-}
+type TestSummary = models.TestSummary
 
 // TestParser parses test output
 type TestParser struct {
@@ -121,14 +109,42 @@ type TestParser struct {
 	rspecPattern     *regexp.Regexp
 	rspecFailPattern *regexp.Regexp
 	minitestPattern  *regexp.Regexp
+
+	// Position in the output while parsing
+	section string // "failures" or "pending" (RSpec), "failed" or "pending" (Minitest)
+	current int    // Index in Results of the test being described, or -1
 }
 
+// RSpec: "10 examples, 2 failures, 1 pending"
+var rspecCountsPattern = regexp.MustCompile(`^(\d+) examples?, (\d+) failures?(?:, (\d+) pending)?`)
+
+// RSpec: "# ./spec/models/user_spec.rb:12:in `block (2 levels)'"
+var rspecLocationPattern = regexp.MustCompile(`^#\s+\.?/?([^:\s]+):(\d+)`)
+
+// RSpec: "rspec ./spec/models/user_spec.rb:10 # User#email validates format"
+var rspecRerunPattern = regexp.MustCompile(`^rspec \.?/?([^:\s]+):(\d+) # (.+)$`)
+
+// Minitest: "10 runs, 15 assertions, 2 failures, 0 errors, 1 skips"
+var minitestCountsPattern = regexp.MustCompile(`(\d+) (?:runs|tests), \d+ assertions, (\d+) failures, (\d+) errors, (\d+) skips`)
+
+// Minitest: "Failure:", "Error:" or "Skipped:", numbered by plain Minitest ("  1) Failure:")
+var minitestHeaderPattern = regexp.MustCompile(`^\s*(?:\d+\) )?(Failure|Error|Skipped):$`)
+
+// Minitest: "UserTest#test_email [/app/test/models/user_test.rb:12]:" or "UserTest#test_boom:"
+var minitestNamePattern = regexp.MustCompile(`^(\S+#\S+?)(?: \[([^\]]+):(\d+)\])?:$`)
+
+// Minitest: "rails test test/models/user_test.rb:10"
+var minitestRerunPattern = regexp.MustCompile(`^(?:bin/)?rails test (\S+):(\d+)`)
+
+// Minitest backtrace: "test/models/user_test.rb:20:in `block in <class:UserTest>'"
+var minitestBacktracePattern = regexp.MustCompile(`^\s*([^:\s]+_test\.rb):(\d+)`)
+
 // NewTestParser creates a new test parser
 func NewTestParser(framework string) *TestParser {
 	return &TestParser{
 		framework: framework,
 		// RSpec: "Finished in 1.23 seconds (files took 0.5 seconds to load)"
-		rspecPattern: regexp.MustCompile(`Finished in ([\d\.]+) seconds`),
+		rspecPattern: regexp.MustCompile(`Finished in ([\d\.]+) seconds?`),
 		// RSpec: "1) User#email validates format"
 		rspecFailPattern: regexp.MustCompile(`^\s*\d+\)\s+(.+)`),
 		// Minitest: "Finished in 1.23s, 10 runs/s, 15 assertions/s."
@@ -141,67 +157,256 @@ func (tp *TestParser) ParseOutput(output string) *TestSummary {
 	summary := &TestSummary{
 		Results: make([]TestResult, 0),
 	}
+	tp.section = ""
+	tp.current = -1
 
 	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
 	for scanner.Scan() {
-		line := scanner.Text()
-
-		if tp.framework == "rspec" {
-			tp.parseRSpecLine(line, summary)
-		} else if tp.framework == "minitest" {
-			tp.parseMinitestLine(line, summary)
-		}
+		tp.parseLine(scanner.Text(), summary)
 	}
 
+	summary.Passed = max(summary.Total-summary.Failed-summary.Pending, 0)
 	return summary
 }
 
-// parseRSpecLine parses a single RSpec output line
+// parseLine strips terminal colors from a line and parses it. It returns
+// the cleaned line.
+func (tp *TestParser) parseLine(line string, summary *TestSummary) string {
+	line = strings.TrimRight(ansiEscape.ReplaceAllString(line, ""), "\r")
+
+	if tp.framework == "rspec" {
+		tp.parseRSpecLine(line, summary)
+	} else if tp.framework == "minitest" {
+		tp.parseMinitestLine(line, summary)
+	}
+	return line
+}
+
+// TestStream parses a test runner's output as it arrives. In watch mode the
+// runner reports one run after another, so each run gets its own summary.
+type TestStream struct {
+	parser  *TestParser
+	summary *TestSummary
+	emitted int // Results of the current run already returned by Feed
+}
+
+// NewTestStream creates a stream parser for a framework's output
+func NewTestStream(framework string) *TestStream {
+	tp := NewTestParser(framework)
+	tp.current = -1
+	return &TestStream{
+		parser:  tp,
+		summary: &TestSummary{Results: make([]TestResult, 0)},
+	}
+}
+
+// Feed parses the next line of output. It returns the failed and pending
+// tests the line completed and, when the line is a run's totals, that run's
+// summary; the lines after it belong to the next run.
+func (ts *TestStream) Feed(line string) ([]TestResult, *TestSummary) {
+	tp := ts.parser
+	line = tp.parseLine(line, ts.summary)
+
+	var ended bool
+	switch tp.framework {
+	case "rspec":
+		ended = rspecCountsPattern.MatchString(strings.TrimSpace(line))
+	case "minitest":
+		ended = minitestCountsPattern.MatchString(line)
+	}
+
+	// The test being described may still get more of its message
+	done := len(ts.summary.Results)
+	if tp.current >= 0 && !ended {
+		done = tp.current
+	}
+	var completed []TestResult
+	if done > ts.emitted {
+		completed = append(completed, ts.summary.Results[ts.emitted:done]...)
+		ts.emitted = done
+	}
+	if !ended {
+		return completed, nil
+	}
+
+	summary := ts.summary
+	summary.Passed = max(summary.Total-summary.Failed-summary.Pending, 0)
+	ts.summary = &TestSummary{Results: make([]TestResult, 0)}
+	ts.emitted = 0
+	tp.section, tp.current = "", -1
+	return completed, summary
+}
+
+// parseRSpecLine parses a single RSpec output line. Failures and pending
+// examples are listed as "N) description" under "Failures:" and "Pending:",
+// followed by their indented message and a "# ./file:line" location.
 func (tp *TestParser) parseRSpecLine(line string, summary *TestSummary) {
-	// Check for duration
+	trimmed := strings.TrimSpace(line)
+
+	switch {
+	case strings.HasPrefix(line, "Failures:"):
+		tp.section, tp.current = "failures", -1
+		return
+	case strings.HasPrefix(line, "Pending:"):
+		tp.section, tp.current = "pending", -1
+		return
+	}
+
 	if matches := tp.rspecPattern.FindStringSubmatch(line); matches != nil {
-		// Parse duration
-		// Duration is in matches[1]
-	}
-
-	// Check for test counts
-	// "10 examples, 2 failures, 1 pending"
-	if strings.Contains(line, "examples") {
-		parts := strings.Split(line, ",")
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-			if strings.Contains(part, "examples") {
-				// Total count
-			} else if strings.Contains(part, "failures") {
-				// Failed count
-			} else if strings.Contains(part, "pending") {
-				// Pending count
+		summary.Duration, _ = strconv.ParseFloat(matches[1], 64)
+		tp.section, tp.current = "", -1
+		return
+	}
+
+	if matches := rspecCountsPattern.FindStringSubmatch(trimmed); matches != nil {
+		summary.Total, _ = strconv.Atoi(matches[1])
+		summary.Failed, _ = strconv.Atoi(matches[2])
+		summary.Pending, _ = strconv.Atoi(matches[3])
+		return
+	}
+
+	// "Failed examples:" lists each failure with the line of its example,
+	// only used when the failure block had no location of its own
+	if matches := rspecRerunPattern.FindStringSubmatch(trimmed); matches != nil {
+		lineNo, _ := strconv.Atoi(matches[2])
+		for i := range summary.Results {
+			result := &summary.Results[i]
+			if result.Status == "failed" && result.Name == matches[3] {
+				if result.File == "" {
+					result.File, result.Line = matches[1], lineNo
+				}
+				break
 			}
 		}
+		return
+	}
+
+	if tp.section == "" {
+		return
+	}
+
+	// Numbered entries are indented less than their message lines
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	if matches := tp.rspecFailPattern.FindStringSubmatch(line); matches != nil && indent < 5 {
+		status := "failed"
+		if tp.section == "pending" {
+			status = "pending"
+		}
+		summary.Results = append(summary.Results, TestResult{Name: matches[1], Status: status})
+		tp.current = len(summary.Results) - 1
+		return
 	}
+
+	if tp.current < 0 || trimmed == "" {
+		return
+	}
+	result := &summary.Results[tp.current]
+	if matches := rspecLocationPattern.FindStringSubmatch(trimmed); matches != nil {
+		// The first location is where the expectation failed; later ones
+		// are the backtrace
+		if result.File == "" {
+			result.File = matches[1]
+			result.Line, _ = strconv.Atoi(matches[2])
+		}
+		return
+	}
+	if strings.HasPrefix(trimmed, "#") {
+		if result.Status == "pending" && result.Error == "" {
+			result.Error = strings.TrimSpace(strings.TrimPrefix(trimmed, "#"))
+		}
+		return
+	}
+	result.Error = appendLine(result.Error, trimmed)
 }
 
-// parseMinitestLine parses a single Minitest output line
+// parseMinitestLine parses a single Minitest output line. Each failure,
+// error or skip is a "Failure:"/"Error:"/"Skipped:" header, a "Class#test
+// [file:line]:" line, the message, and with rails test a rerun command.
 func (tp *TestParser) parseMinitestLine(line string, summary *TestSummary) {
-	// Minitest format: "10 runs, 15 assertions, 2 failures, 0 errors, 1 skips"
-	if strings.Contains(line, "runs") && strings.Contains(line, "assertions") {
-		parts := strings.Split(line, ",")
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-			if strings.Contains(part, "runs") {
-				// Total count
-			} else if strings.Contains(part, "failures") {
-				// Failed count
-			} else if strings.Contains(part, "errors") {
-				// Error count
-			} else if strings.Contains(part, "skips") {
-				// Skipped count
-			}
+	trimmed := strings.TrimSpace(line)
+
+	if matches := minitestCountsPattern.FindStringSubmatch(line); matches != nil {
+		summary.Total, _ = strconv.Atoi(matches[1])
+		failures, _ := strconv.Atoi(matches[2])
+		errors, _ := strconv.Atoi(matches[3])
+		summary.Failed = failures + errors
+		summary.Pending, _ = strconv.Atoi(matches[4])
+		tp.section, tp.current = "", -1
+		return
+	}
+
+	if matches := tp.minitestPattern.FindStringSubmatch(line); matches != nil {
+		summary.Duration, _ = strconv.ParseFloat(matches[1], 64)
+		tp.section, tp.current = "", -1
+		return
+	}
+
+	if matches := minitestHeaderPattern.FindStringSubmatch(line); matches != nil {
+		tp.section, tp.current = "failed", -1
+		if matches[1] == "Skipped" {
+			tp.section = "pending"
 		}
+		return
 	}
+
+	if tp.section == "" {
+		return
+	}
+
+	if tp.current < 0 {
+		if matches := minitestNamePattern.FindStringSubmatch(trimmed); matches != nil {
+			result := TestResult{Name: matches[1], Status: tp.section, File: matches[2]}
+			result.Line, _ = strconv.Atoi(matches[3])
+			summary.Results = append(summary.Results, result)
+			tp.current = len(summary.Results) - 1
+		}
+		return
+	}
+
+	result := &summary.Results[tp.current]
+	if matches := minitestRerunPattern.FindStringSubmatch(trimmed); matches != nil {
+		// The rerun line points at the test's definition; keep the
+		// assertion's location when the failure gave one
+		if result.File == "" {
+			result.File = matches[1]
+			result.Line, _ = strconv.Atoi(matches[2])
+		}
+		tp.section, tp.current = "", -1
+		return
+	}
+	if trimmed == "" {
+		return
+	}
+	if matches := minitestBacktracePattern.FindStringSubmatch(line); matches != nil {
+		if result.File == "" {
+			result.File = matches[1]
+			result.Line, _ = strconv.Atoi(matches[2])
+		}
+		return
+	}
+	result.Error = appendLine(result.Error, trimmed)
+}
+
+// appendLine adds a line to a multi-line message
+func appendLine(text, line string) string {
+	if text == "" {
+		return line
+	}
+	return text + "\n" + line
 }
 
 // IsSlowTest checks if a test is slow (>1 second)
 func IsSlowTest(duration float64) bool {
 	return duration > 1.0
 }
+
+// ParseTestOutput reads the test runner's output into a summary
+func (p *Plugin) ParseTestOutput(runner, output string) *models.TestSummary {
+	return NewTestParser(runner).ParseOutput(output)
+}
+
+// NewTestStream returns a parser for the watch command's streaming output
+func (p *Plugin) NewTestStream(runner string) plugin.TestStream {
+	return NewTestStream(runner)
+}